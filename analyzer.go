@@ -31,22 +31,44 @@
 // Suppress false positives with:
 //
 //	//gormreuse:ignore           - Suppress for next line or same line
+//	//gormreuse:ignore-file      - Suppress every diagnostic in the file (before the package clause)
+//	//gormreuse:allow-reuse      - Mark a reuse on the next line or same line as intentional
 //	//gormreuse:pure             - Mark function as not polluting *gorm.DB args
 //	//gormreuse:immutable-return - Mark function as returning immutable *gorm.DB
+//	//gormreuse:finisher         - Mark function as a terminal use of its *gorm.DB receiver
+//	//gormreuse:sink             - Mark function as intentionally consuming its *gorm.DB args
 package gormreuse
 
 import (
+	"bytes"
+	"cmp"
+	"errors"
+	"fmt"
 	"go/ast"
 	"go/token"
+	"io"
+	"maps"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
 
 	"golang.org/x/tools/go/analysis"
 	"golang.org/x/tools/go/analysis/passes/buildssa"
 
 	"github.com/mpyw/gormreuse/internal"
-	"github.com/mpyw/gormreuse/internal/directive"
+	"github.com/mpyw/gormreuse/internal/debug"
+	"github.com/mpyw/gormreuse/internal/fix"
+	"github.com/mpyw/gormreuse/internal/ssa/handler"
+	"github.com/mpyw/gormreuse/internal/ssa/pollution"
+	"github.com/mpyw/gormreuse/internal/typeutil"
 )
 
-// Analyzer is the main analyzer for gormreuse.
+// Analyzer is the main analyzer for gormreuse, configured by command-line
+// flags. NewAnalyzer builds analyzers configured programmatically instead.
 //
 // It requires the buildssa analyzer to build SSA form of the code,
 // then performs reuse detection via pollution tracking.
@@ -58,77 +80,704 @@ import (
 // Usage programmatically:
 //
 //	analysis.Run([]*analysis.Analyzer{gormreuse.Analyzer}, pkgs)
-var Analyzer = &analysis.Analyzer{
-	Name:     "gormreuse",
-	Doc:      "detects unsafe *gorm.DB instance reuse after chain methods",
-	Requires: []*analysis.Analyzer{buildssa.Analyzer},
-	Run:      run,
+var Analyzer = newAnalyzer(defaultName, defaultDoc, &flagOptions)
+
+const (
+	defaultName = "gormreuse"
+	defaultDoc  = "detects unsafe *gorm.DB instance reuse after chain methods"
+)
+
+// Options configures an analyzer built by NewAnalyzer. Each field but Name and
+// Doc mirrors a flag of Analyzer; DefaultOptions returns the flag defaults.
+type Options struct {
+	// Name and Doc are the analyzer's name and documentation. Empty values
+	// default to those of Analyzer.
+	Name string
+	Doc  string
+
+	// ReportRootGraph is a file path that receives a Graphviz DOT graph of the
+	// mutable roots, branches and pollution events (-report-root-graph).
+	ReportRootGraph string
+
+	// ListRootsJSON is a file path that receives, per package, a line of JSON
+	// listing each function's mutable roots (-list-roots-json).
+	ListRootsJSON string
+
+	// Summary is a file path that receives, per package, a table counting the
+	// diagnostics by category and by enclosing function (-summary).
+	Summary string
+
+	// SummaryOnly reports nothing but the Summary table (-summary-only).
+	SummaryOnly bool
+
+	// Baseline is a file path listing known diagnostics, which are not
+	// reported, so only new ones are (-baseline). A diagnostic is known by its
+	// file, enclosing function, category and the content of its line, so it
+	// stays known when lines are added or removed above it.
+	Baseline string
+
+	// WriteBaseline reports nothing, but writes every diagnostic to the
+	// Baseline file, replacing the entries of the analyzed files and keeping
+	// the others (-write-baseline). Files are named relative to their module
+	// root, so packages analyzed by separate go vet processes update the same
+	// entries.
+	WriteBaseline bool
+
+	// FixDiff reports nothing, but writes to standard output a unified diff
+	// applying the first suggested fix of every diagnostic (-fix-diff).
+	FixDiff bool
+
+	// TraceValue is a file:line position, such as repo/find.go:12. How the
+	// *gorm.DB receiver of each gorm method call on that line resolves to its
+	// roots is written to standard error, step by step, for debugging
+	// (-trace-value).
+	TraceValue string
+
+	// WarnUnhandledSSA writes to standard error, once per kind and package,
+	// each SSA instruction kind involving a *gorm.DB that the analysis does
+	// not account for, such as one added for a newer language feature, for
+	// debugging (-warn-unhandled-ssa). Such instructions are otherwise ignored silently.
+	WarnUnhandledSSA bool
+
+	// FixComplexity annotates each reuse diagnostic with the estimated effort
+	// of fixing it: trivial, moderate or manual (-fix-complexity).
+	FixComplexity bool
+
+	// ChainThreshold is the number of method calls a reused chain must exceed
+	// for the fix making its root immutable with Session to be offered, as the
+	// recommended fix, besides the reassignment fix. 0 disables it
+	// (-chain-threshold).
+	ChainThreshold int
+
+	// CoalesceRoots lists every polluted root a Phi merges into a reused
+	// receiver as related information of its diagnostic (-coalesce-roots).
+	CoalesceRoots bool
+
+	// StrictIgnoreFile reports ignore-file directives that suppress nothing
+	// (-strict-ignore-file).
+	StrictIgnoreFile bool
+
+	// RequireIgnoreReason reports //gormreuse:ignore directives giving no
+	// reason, as in //gormreuse:ignore: <reason> (-require-ignore-reason).
+	RequireIgnoreReason bool
+
+	// SuggestPure reports unannotated helpers that never pollute their
+	// *gorm.DB argument and could be marked //gormreuse:pure (-suggest-pure).
+	SuggestPure bool
+
+	// WarnUnvalidatedPure reports, once per function at its first call, each
+	// //gormreuse:pure function of another package whose source cannot be
+	// read, so its contract is trusted by its fact without validating its body
+	// (-warn-unvalidated-pure).
+	WarnUnvalidatedPure bool
+
+	// StrictInterface reports each conversion of a mutable *gorm.DB to an
+	// interface, such as passing it as an interface{} argument, as an ESCAPE
+	// diagnostic at the conversion (-strict-interface).
+	StrictInterface bool
+
+	// AssumePureHelpers inverts the default for non-gorm functions receiving
+	// a *gorm.DB: they are assumed pure, leaving their arguments unpolluted,
+	// unless marked //gormreuse:impure or //gormreuse:sink
+	// (-assume-pure-helpers). Closures and function values still pollute.
+	AssumePureHelpers bool
+
+	// NoTestHelpers suppresses reuse diagnostics whose finisher is nested
+	// inside a call to a TestHelperPkgs assertion function (-no-test-helpers).
+	NoTestHelpers bool
+
+	// TestHelperPkgs are the import paths of the assertion packages honored
+	// by NoTestHelpers (-test-helper-pkgs).
+	TestHelperPkgs []string
+
+	// GormTypes are fully-qualified names of additional types treated as
+	// gorm.DB, such as a vendored copy or a wrapper (-gorm-type).
+	GormTypes []string
+
+	// BuilderTypes are fully-qualified names of wrapper types holding a
+	// *gorm.DB whose values are tracked like *gorm.DB (-builder-type).
+	BuilderTypes []string
+
+	// GormTypeUnderlying also treats named types whose underlying type is
+	// gorm.DB or *gorm.DB as *gorm.DB (-gorm-type-underlying).
+	GormTypeUnderlying bool
+
+	// ImmutableMethods are names of methods of gorm.DB or a GormTypes type
+	// that return an immutable copy like Session, e.g. a ReadOnly method of a
+	// vendored GORM (-immutable-method).
+	ImmutableMethods []string
+
+	// Severity maps diagnostic categories to a level, "error" or "warning",
+	// prefixed to their messages (-severity).
+	Severity map[string]string
+
+	// PurePkgs are the import paths of packages whose functions are assumed
+	// not to pollute their *gorm.DB arguments, as if each were marked
+	// //gormreuse:pure (-pure-pkg).
+	PurePkgs []string
+
+	// EnableOnly lists the diagnostic categories to report. Empty reports
+	// every category (-enable-only).
+	EnableOnly []string
+
+	// Parallel is the number of functions of a package analyzed at once, or
+	// 0 for GOMAXPROCS. Diagnostics do not depend on it (-parallel).
+	Parallel int
+
+	// Exclude are globs of files to skip like generated ones, such as
+	// "third_party" or "*_mock.go". A glob matches a file when it matches its
+	// path, or the trailing elements of its path or of a parent directory's
+	// (-exclude).
+	Exclude []string
 }
 
-func run(pass *analysis.Pass) (any, error) {
-	ssaInfo := pass.ResultOf[buildssa.Analyzer].(*buildssa.SSA)
+// DefaultOptions returns the options of Analyzer when no flag is set.
+func DefaultOptions() Options {
+	return Options{
+		CoalesceRoots:  true,
+		TestHelperPkgs: []string{"github.com/stretchr/testify/require", "github.com/stretchr/testify/assert"},
+		PurePkgs:       []string{"github.com/stretchr/testify/require", "github.com/stretchr/testify/assert"},
+	}
+}
 
-	// Build set of files to skip
-	skipFiles := buildSkipFiles(pass)
+// NewAnalyzer returns an analyzer configured by opts rather than by flags, for
+// embedding gormreuse in another driver, possibly several times under
+// different names:
+//
+//	opts := gormreuse.DefaultOptions()
+//	opts.Name = "gormreuse_strict"
+//	opts.Severity = map[string]string{"BRANCH": "error"}
+//	analyzer := gormreuse.NewAnalyzer(opts)
+//
+// The options are copied, so later changes to opts do not affect the analyzer.
+// Invalid options, such as an unknown category, fail every run of it.
+func NewAnalyzer(opts Options) *analysis.Analyzer {
+	name, doc := cmp.Or(opts.Name, defaultName), cmp.Or(opts.Doc, defaultDoc)
+	opts.TestHelperPkgs = slices.Clone(opts.TestHelperPkgs)
+	opts.GormTypes = slices.Clone(opts.GormTypes)
+	opts.BuilderTypes = slices.Clone(opts.BuilderTypes)
+	opts.ImmutableMethods = slices.Clone(opts.ImmutableMethods)
+	opts.Severity = maps.Clone(opts.Severity)
+	opts.EnableOnly = slices.Clone(opts.EnableOnly)
+	opts.PurePkgs = slices.Clone(opts.PurePkgs)
+	opts.Exclude = slices.Clone(opts.Exclude)
+	return newAnalyzer(name, doc, &opts)
+}
 
-	// Build ignore maps for each file (excluding skipped files)
-	ignoreMaps := make(map[string]directive.IgnoreMap)
-	funcIgnores := make(map[string]map[token.Pos]directive.FunctionIgnoreEntry)
-	pureFuncs := directive.NewPureFuncSet(pass.Fset, pass.TypesInfo)
-	immutableReturnFuncs := directive.NewImmutableReturnFuncSet(pass.Fset, pass.TypesInfo)
-	immutableParamFuncs := directive.NewImmutableParamFuncSet(pass.Fset, pass.TypesInfo)
-	immutableInputSet := directive.NewImmutableInputSet(pass.Fset, pass.TypesInfo)
+// newAnalyzer returns an analyzer running with *opts as of each run, so the
+// flags of Analyzer take effect however late they are set.
+func newAnalyzer(name, doc string, opts *Options) *analysis.Analyzer {
+	return &analysis.Analyzer{
+		Name:     name,
+		Doc:      doc,
+		Requires: []*analysis.Analyzer{buildssa.Analyzer, FactsAnalyzer},
+		Run:      opts.run,
+	}
+}
 
-	pkgPath := pass.Pkg.Path()
-	for _, file := range pass.Files {
-		filename := pass.Fset.Position(file.Pos()).Filename
-		if skipFiles[filename] {
-			continue
+// flagOptions holds the options of Analyzer, which its flags set.
+var flagOptions = DefaultOptions()
+
+// stringList is a flag.Value collecting every occurrence of a repeatable flag.
+type stringList []string
+
+func (l *stringList) String() string { return strings.Join(*l, ",") }
+
+func (l *stringList) Set(v string) error {
+	*l = append(*l, v)
+	return nil
+}
+
+// commaList is a flag.Value replacing its list with the comma-separated
+// entries of each value.
+type commaList []string
+
+func (l *commaList) String() string { return strings.Join(*l, ",") }
+
+func (l *commaList) Set(v string) error {
+	*l = splitList(v)
+	return nil
+}
+
+// severityMap is a flag.Value parsing comma-separated category=level pairs
+// into a map from category to level. Categories are the ViolationKind names
+// and levels are "error" or "warning". Repeating the flag adds pairs; an empty
+// value clears them.
+type severityMap map[string]string
+
+func (m *severityMap) String() string {
+	pairs := make([]string, 0, len(*m))
+	for category, level := range *m {
+		pairs = append(pairs, category+"="+level)
+	}
+	sort.Strings(pairs)
+	return strings.Join(pairs, ",")
+}
+
+func (m *severityMap) Set(v string) error {
+	if v == "" {
+		*m = nil
+		return nil
+	}
+	for _, pair := range splitList(v) {
+		category, level, ok := strings.Cut(pair, "=")
+		if !ok {
+			return fmt.Errorf("invalid severity %q: want category=level", pair)
 		}
-		ignoreMaps[filename] = directive.BuildIgnoreMap(pass.Fset, file)
-		funcIgnores[filename] = directive.BuildFunctionIgnoreSet(pass.Fset, file)
+		if err := checkSeverity(category, level); err != nil {
+			return fmt.Errorf("invalid severity %q: %w", pair, err)
+		}
+		if *m == nil {
+			*m = make(severityMap)
+		}
+		(*m)[category] = level
+	}
+	return nil
+}
 
-		// Add original file to sets (for position-correct directive detection)
-		pureFuncs.AddFile(file)
-		immutableReturnFuncs.AddFile(file)
-		immutableParamFuncs.AddFile(file)
+// checkSeverity reports whether category=level is a valid severity.
+func checkSeverity(category, level string) error {
+	if _, ok := pollution.ParseViolationKind(category); !ok {
+		return fmt.Errorf("unknown category %q", category)
+	}
+	if level != "error" && level != "warning" {
+		return errors.New("level must be error or warning")
+	}
+	return nil
+}
+
+// categoryList is a flag.Value parsing a comma-separated list of diagnostic
+// categories (the ViolationKind names). Repeating the flag adds categories;
+// an empty value clears them.
+type categoryList []string
+
+func (l *categoryList) String() string {
+	categories := slices.Clone(*l)
+	slices.Sort(categories)
+	return strings.Join(categories, ",")
+}
+
+func (l *categoryList) Set(v string) error {
+	if v == "" {
+		*l = nil
+		return nil
+	}
+	for _, category := range splitList(v) {
+		if _, ok := pollution.ParseViolationKind(category); !ok {
+			return fmt.Errorf("unknown category %q", category)
+		}
+		if !slices.Contains(*l, category) {
+			*l = append(*l, category)
+		}
+	}
+	return nil
+}
+
+// globList is a flag.Value collecting every occurrence of a repeatable glob
+// flag, rejecting malformed globs.
+type globList []string
+
+func (l *globList) String() string { return strings.Join(*l, ",") }
+
+func (l *globList) Set(v string) error {
+	if _, err := path.Match(v, ""); err != nil {
+		return fmt.Errorf("invalid glob %q: %w", v, err)
+	}
+	*l = append(*l, v)
+	return nil
+}
+
+func init() {
+	o := &flagOptions
+	Analyzer.Flags.StringVar(&o.ReportRootGraph, "report-root-graph", "",
+		"write a Graphviz DOT graph of mutable *gorm.DB roots, their branches and pollution events to this file (one digraph per package)")
+	Analyzer.Flags.StringVar(&o.ListRootsJSON, "list-roots-json", "",
+		"write a JSON listing of mutable *gorm.DB roots to this file: per function, each root's position, creator, pollution, first use and reuse sites (one line per package)")
+	Analyzer.Flags.StringVar(&o.Summary, "summary", "",
+		"write a table counting diagnostics by category and by enclosing function, with their total, to this file (one table per package)")
+	Analyzer.Flags.BoolVar(&o.SummaryOnly, "summary-only", false,
+		"with -summary, report diagnostics in the summary table only")
+	Analyzer.Flags.StringVar(&o.Baseline, "baseline", "",
+		"read known diagnostics from this file and report only the others; a diagnostic is matched by file, enclosing function, category and line content")
+	Analyzer.Flags.BoolVar(&o.WriteBaseline, "write-baseline", false,
+		"instead of reporting diagnostics, write them to the -baseline file, replacing the entries of the analyzed files")
+	Analyzer.Flags.BoolVar(&o.FixDiff, "fix-diff", false,
+		"instead of reporting diagnostics, write a unified diff applying their suggested fixes to stdout, like gofmt -d (apply with patch -p0)")
+	Analyzer.Flags.StringVar(&o.TraceValue, "trace-value", "",
+		"debug: write to stderr how the *gorm.DB receiver of each gorm call at this file:line (e.g. repo/find.go:12) is traced to its roots")
+	Analyzer.Flags.BoolVar(&o.WarnUnhandledSSA, "warn-unhandled-ssa", false,
+		"debug: write to stderr, once per kind and package, each SSA instruction kind involving a *gorm.DB that the analysis ignores")
+	Analyzer.Flags.BoolVar(&o.FixComplexity, "fix-complexity", false,
+		"append the estimated fix complexity to reuse diagnostics: trivial (Session/reassign), moderate (loop restructure) or manual (closure/goroutine/escape)")
+	Analyzer.Flags.IntVar(&o.ChainThreshold, "chain-threshold", 0,
+		"offer the fix making the root immutable with Session, labeled recommended, for reuse in a chain of more method calls than this; 0 disables")
+	Analyzer.Flags.BoolVar(&o.CoalesceRoots, "coalesce-roots", o.CoalesceRoots,
+		"when a reused receiver merges several polluted roots (if/else assignment), report one diagnostic listing every root as related information; false lists only the first")
+	Analyzer.Flags.Var((*severityMap)(&o.Severity), "severity",
+		"comma-separated category=level pairs prefixing the diagnostics of a category with its level, e.g. BRANCH=warning,PURE=error (levels: error, warning)")
+	Analyzer.Flags.Var((*categoryList)(&o.EnableOnly), "enable-only",
+		"comma-separated diagnostic categories to report, e.g. PURE; the others are dropped, and reuse detection is skipped when only PURE is enabled (default: all)")
+	Analyzer.Flags.BoolVar(&o.SuggestPure, "suggest-pure", false,
+		"report unannotated helpers that never pollute their *gorm.DB argument, suggesting //gormreuse:pure (category SUGGEST-PURE)")
+	Analyzer.Flags.BoolVar(&o.WarnUnvalidatedPure, "warn-unvalidated-pure", false,
+		"report the first call of each //gormreuse:pure function of another package whose source cannot be read, so its contract is trusted without analyzing its body (category UNVALIDATED-PURE)")
+	Analyzer.Flags.BoolVar(&o.StrictInterface, "strict-interface", false,
+		"report each conversion of a mutable *gorm.DB to an interface, e.g. an interface{} argument, as an escape making later uses unsafe (category ESCAPE)")
+	Analyzer.Flags.BoolVar(&o.AssumePureHelpers, "assume-pure-helpers", false,
+		"assume non-gorm functions receiving a *gorm.DB do not pollute it unless marked //gormreuse:impure or //gormreuse:sink (laxer; default assumes they do)")
+	Analyzer.Flags.BoolVar(&o.StrictIgnoreFile, "strict-ignore-file", false,
+		"report //gormreuse:ignore-file directives in files without any diagnostic to suppress")
+	Analyzer.Flags.BoolVar(&o.RequireIgnoreReason, "require-ignore-reason", false,
+		"report //gormreuse:ignore directives without a reason, written //gormreuse:ignore: <reason> (category MISSING-REASON)")
+	Analyzer.Flags.BoolVar(&o.NoTestHelpers, "no-test-helpers", false,
+		"suppress reuse diagnostics whose finisher is an argument of a test assertion, e.g. require.NoError(t, tx.Create(&u).Error)")
+	Analyzer.Flags.Var((*commaList)(&o.TestHelperPkgs), "test-helper-pkgs",
+		"comma-separated import paths of the assertion packages honored by -no-test-helpers")
+	Analyzer.Flags.Var((*commaList)(&o.PurePkgs), "pure-pkg",
+		"comma-separated import paths of packages whose functions never pollute their *gorm.DB arguments, as if marked //gormreuse:pure; empty disables")
+	Analyzer.Flags.Var((*stringList)(&o.GormTypes), "gorm-type",
+		"additional type treated as gorm.DB, e.g. github.com/acme/db.Handle (repeatable)")
+	Analyzer.Flags.Var((*stringList)(&o.BuilderTypes), "builder-type",
+		"wrapper type holding a *gorm.DB whose methods chain (return the wrapper) or finish the query, e.g. github.com/acme/repo.Query (repeatable)")
+	Analyzer.Flags.BoolVar(&o.GormTypeUnderlying, "gorm-type-underlying", false,
+		"also treat named types whose underlying type is gorm.DB or *gorm.DB as *gorm.DB")
+	Analyzer.Flags.Var((*stringList)(&o.ImmutableMethods), "immutable-method",
+		"method of gorm.DB or a -gorm-type returning an immutable copy like Session, e.g. ReadOnly (repeatable)")
+	Analyzer.Flags.IntVar(&o.Parallel, "parallel", 0,
+		"number of functions of a package analyzed concurrently; 0 uses GOMAXPROCS and 1 analyzes them one at a time")
+	Analyzer.Flags.Var((*globList)(&o.Exclude), "exclude",
+		"glob of files to skip, matched against the trailing elements of each file path and of its directories, e.g. third_party or *_mock.go (repeatable)")
+}
 
-		// Build pure function set for this file
-		for key := range directive.BuildPureFunctionSet(file, pkgPath) {
-			pureFuncs.Add(key)
+// validate reports the first invalid severity, category, parallelism, chain
+// threshold, glob, baseline or trace setting of o. Flags are checked as they
+// are set, but for -parallel, -chain-threshold, -write-baseline and
+// -trace-value; options given to NewAnalyzer are checked here.
+func (o *Options) validate() error {
+	for _, category := range slices.Sorted(maps.Keys(o.Severity)) {
+		if err := checkSeverity(category, o.Severity[category]); err != nil {
+			return fmt.Errorf("invalid severity %s=%s: %w", category, o.Severity[category], err)
+		}
+	}
+	for _, category := range o.EnableOnly {
+		if _, ok := pollution.ParseViolationKind(category); !ok {
+			return fmt.Errorf("invalid enable-only: unknown category %q", category)
 		}
-		// Build immutable-return function set for this file
-		for key := range directive.BuildImmutableReturnFunctionSet(file, pkgPath) {
-			immutableReturnFuncs.Add(key)
+	}
+	if o.Parallel < 0 {
+		return fmt.Errorf("invalid parallel %d: must not be negative", o.Parallel)
+	}
+	if o.ChainThreshold < 0 {
+		return fmt.Errorf("invalid chain-threshold %d: must not be negative", o.ChainThreshold)
+	}
+	for _, glob := range o.Exclude {
+		if _, err := path.Match(glob, ""); err != nil {
+			return fmt.Errorf("invalid exclude %q: %w", glob, err)
 		}
-		// Build immutable-param function set for this file
-		for key := range directive.BuildImmutableParamFunctionSet(file, pkgPath) {
-			immutableParamFuncs.Add(key)
+	}
+	if o.WriteBaseline && o.Baseline == "" {
+		return errors.New("write-baseline requires a baseline file")
+	}
+	if o.TraceValue != "" {
+		if _, err := debug.ParseTarget(o.TraceValue); err != nil {
+			return fmt.Errorf("invalid trace-value %q: %w", o.TraceValue, err)
 		}
-		// Build immutable-input(name) callback declarations for this file
-		immutableInputSet.AddFile(file, pkgPath)
+	}
+	return nil
+}
+
+func (o *Options) run(pass *analysis.Pass) (any, error) {
+	if err := o.validate(); err != nil {
+		return nil, err
+	}
+	ssaInfo := pass.ResultOf[buildssa.Analyzer].(*buildssa.SSA)
+	facts := pass.ResultOf[FactsAnalyzer].(*Result)
+
+	matcher, err := o.matcher()
+	if err != nil {
+		return nil, err
+	}
+
+	// Build set of files to skip; a package of skipped files only is not
+	// analyzed at all.
+	skipFiles := buildSkipFiles(pass, o.Exclude)
+	if len(skipFiles) == len(pass.Files) {
+		return nil, nil
+	}
+
+	dirs := o.directives(pass, skipFiles, matcher, facts)
+	opts := o.internalOptions(matcher)
+	pass, out, err := o.setupOutputs(pass, &opts)
+	if err != nil {
+		return nil, err
 	}
 
 	// Run SSA-based analysis
-	internal.RunSSA(pass, ssaInfo, ignoreMaps, funcIgnores, pureFuncs, immutableReturnFuncs, immutableParamFuncs, immutableInputSet, skipFiles)
+	internal.RunSSA(pass, ssaInfo, dirs, opts)
 
+	if err := o.writeOutputs(pass.Fset, out); err != nil {
+		return nil, err
+	}
 	return nil, nil
 }
 
+// matcher parses the gorm and builder types and the immutable methods once;
+// the matcher is threaded through every component that recognizes *gorm.DB.
+// nil keeps the default gorm.io/gorm.DB and builtin methods only.
+func (o *Options) matcher() (*typeutil.Matcher, error) {
+	if len(o.GormTypes) == 0 && len(o.BuilderTypes) == 0 && len(o.ImmutableMethods) == 0 && !o.GormTypeUnderlying {
+		return nil, nil
+	}
+	return typeutil.NewMatcher(o.GormTypes, o.BuilderTypes, o.ImmutableMethods, o.GormTypeUnderlying)
+}
+
+// directives collects the directives of each analyzed file of pass. Exported
+// functions of imported packages are classified by the facts of
+// FactsAnalyzer rather than by re-parsing their source.
+func (o *Options) directives(pass *analysis.Pass, skipFiles map[string]bool, matcher *typeutil.Matcher, facts *Result) *internal.Directives {
+	dirs := internal.NewDirectives(pass, skipFiles, matcher)
+	for _, path := range o.PurePkgs {
+		dirs.PureFuncs.AddPackage(path)
+	}
+	for _, file := range pass.Files {
+		dirs.AddFile(file)
+	}
+	dirs.UseFacts(facts)
+	return dirs
+}
+
+// internalOptions returns the analysis settings of o; setupOutputs adds its
+// outputs.
+func (o *Options) internalOptions(matcher *typeutil.Matcher) internal.Options {
+	opts := internal.Options{
+		FixComplexity:       o.FixComplexity,
+		ChainThreshold:      o.ChainThreshold,
+		CoalesceRoots:       o.CoalesceRoots,
+		StrictIgnoreFile:    o.StrictIgnoreFile,
+		RequireIgnoreReason: o.RequireIgnoreReason,
+		SuggestPure:         o.SuggestPure,
+		WarnUnvalidatedPure: o.WarnUnvalidatedPure,
+		StrictInterface:     o.StrictInterface,
+		AssumePureHelpers:   o.AssumePureHelpers,
+		Severity:            o.Severity,
+		EnableOnly:          o.enableOnly(),
+		GormTypes:           matcher,
+		Parallel:            o.Parallel,
+	}
+	if o.NoTestHelpers {
+		opts.TestHelperPkgs = o.TestHelperPkgs
+	}
+	return opts
+}
+
+// outputs buffers the outputs of the analysis of a package, which are written
+// to their files and streams once it completes (see writeOutputs).
+type outputs struct {
+	rootGraph bytes.Buffer
+	rootList  bytes.Buffer
+	summary   bytes.Buffer
+	baseline  internal.BaselineUpdate
+	fixable   []analysis.Diagnostic
+	trace     bytes.Buffer
+	unhandled bytes.Buffer
+}
+
+// setupOutputs points opts at the output buffers o asks for and reads the
+// -baseline file to filter against. With -fix-diff it returns a copy of pass
+// collecting the diagnostics for their fixes instead of reporting them.
+func (o *Options) setupOutputs(pass *analysis.Pass, opts *internal.Options) (*analysis.Pass, *outputs, error) {
+	out := &outputs{}
+	if o.ReportRootGraph != "" {
+		opts.RootGraph = &out.rootGraph
+	}
+	if o.ListRootsJSON != "" {
+		opts.RootList = &out.rootList
+	}
+	if o.Summary != "" {
+		opts.Summary = &out.summary
+		opts.SummaryOnly = o.SummaryOnly
+	}
+	if o.WriteBaseline {
+		opts.BaselineOut = &out.baseline
+	} else if o.Baseline != "" {
+		var err error
+		if opts.Baseline, err = internal.ReadBaseline(o.Baseline); err != nil {
+			return nil, nil, fmt.Errorf("reading baseline: %w", err)
+		}
+	}
+	if o.TraceValue != "" {
+		target, _ := debug.ParseTarget(o.TraceValue) // checked by validate
+		opts.TraceValue = &target
+		opts.TraceValueOut = &out.trace
+	}
+	if o.WarnUnhandledSSA {
+		opts.UnhandledSSA = handler.NewUnhandledLog(&out.unhandled)
+	}
+	if o.FixDiff {
+		collecting := *pass
+		collecting.Report = func(d analysis.Diagnostic) { out.fixable = append(out.fixable, d) }
+		pass = &collecting
+	}
+	return pass, out, nil
+}
+
+// writeOutputs writes the outputs buffered by setupOutputs to their files and
+// streams.
+func (o *Options) writeOutputs(fset *token.FileSet, out *outputs) error {
+	for _, f := range []struct {
+		path, what string
+		data       *bytes.Buffer
+	}{
+		{o.ReportRootGraph, "root graph", &out.rootGraph},
+		{o.ListRootsJSON, "root list", &out.rootList},
+		{o.Summary, "summary", &out.summary},
+	} {
+		if f.path == "" {
+			continue
+		}
+		if err := appendOutput(f.path, f.data.Bytes()); err != nil {
+			return fmt.Errorf("writing %s: %w", f.what, err)
+		}
+	}
+	if o.WriteBaseline {
+		if err := out.baseline.Merge(o.Baseline); err != nil {
+			return fmt.Errorf("writing baseline: %w", err)
+		}
+	}
+	if o.FixDiff {
+		patch, err := fix.ComputePatch(fset, out.fixable)
+		if err != nil {
+			return fmt.Errorf("computing fix diff: %w", err)
+		}
+		if err := writeSerialized(fixDiffOutput, patch); err != nil {
+			return fmt.Errorf("writing fix diff: %w", err)
+		}
+	}
+	if err := writeSerialized(traceValueOutput, out.trace.Bytes()); err != nil {
+		return fmt.Errorf("writing value trace: %w", err)
+	}
+	if err := writeSerialized(unhandledSSAOutput, out.unhandled.Bytes()); err != nil {
+		return fmt.Errorf("writing unhandled SSA instructions: %w", err)
+	}
+	return nil
+}
+
+// enableOnly returns EnableOnly as a set, nil when every category is enabled.
+func (o *Options) enableOnly() map[string]bool {
+	if len(o.EnableOnly) == 0 {
+		return nil
+	}
+	set := make(map[string]bool, len(o.EnableOnly))
+	for _, category := range o.EnableOnly {
+		set[category] = true
+	}
+	return set
+}
+
+// splitList splits a comma-separated flag value, dropping empty entries.
+func splitList(s string) []string {
+	var list []string
+	for _, item := range strings.Split(s, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			list = append(list, item)
+		}
+	}
+	return list
+}
+
+// outputFiles serializes -report-root-graph, -list-roots-json and -summary
+// writes from packages analyzed concurrently. A file is
+// truncated the first time its path is written in this process; every package
+// then appends its own output.
+var outputFiles struct {
+	sync.Mutex
+	opened map[string]bool
+}
+
+func appendOutput(path string, data []byte) error {
+	outputFiles.Lock()
+	defer outputFiles.Unlock()
+
+	flag := os.O_WRONLY | os.O_CREATE | os.O_APPEND
+	if !outputFiles.opened[path] {
+		flag |= os.O_TRUNC
+		if outputFiles.opened == nil {
+			outputFiles.opened = make(map[string]bool)
+		}
+		outputFiles.opened[path] = true
+	}
+	f, err := os.OpenFile(path, flag, 0o644)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(data); err != nil {
+		_ = f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// fixDiffOutput receives the -fix-diff patches of every package.
+var fixDiffOutput io.Writer = os.Stdout
+
+// traceValueOutput receives the -trace-value traces of every package.
+var traceValueOutput io.Writer = os.Stderr
+
+// unhandledSSAOutput receives the -warn-unhandled-ssa instruction kinds of
+// every package.
+var unhandledSSAOutput io.Writer = os.Stderr
+
+// writeSerialized writes the output of a package to w, such as its -fix-diff
+// patch, serialized like appendOutput so the outputs of packages analyzed
+// concurrently do not interleave.
+func writeSerialized(w io.Writer, data []byte) error {
+	if len(data) == 0 {
+		return nil
+	}
+	outputFiles.Lock()
+	defer outputFiles.Unlock()
+	_, err := w.Write(data)
+	return err
+}
+
 // buildSkipFiles creates a set of filenames to skip.
-// Generated files are always skipped.
+// Generated files are always skipped, and so are files matching an exclude glob.
 // Test files can be skipped via the driver's built-in -test flag.
-func buildSkipFiles(pass *analysis.Pass) map[string]bool {
+func buildSkipFiles(pass *analysis.Pass, exclude []string) map[string]bool {
 	skipFiles := make(map[string]bool)
 
 	for _, file := range pass.Files {
 		filename := pass.Fset.Position(file.Pos()).Filename
 
 		// Always skip generated files
-		if ast.IsGenerated(file) {
+		if ast.IsGenerated(file) || isExcluded(filename, exclude) {
 			skipFiles[filename] = true
 		}
 	}
 
 	return skipFiles
 }
+
+// isExcluded reports whether an exclude glob matches filename, its trailing
+// elements, or those of one of its directories:
+//
+//	third_party      matches /repo/third_party/lib/lib.go
+//	internal/gen     matches /repo/internal/gen/models.go
+//	*_mock.go        matches /repo/store/user_mock.go
+func isExcluded(filename string, exclude []string) bool {
+	if len(exclude) == 0 {
+		return false
+	}
+	elems := strings.Split(filepath.ToSlash(filename), "/")
+	for end := len(elems); end > 0; end-- {
+		for start := end - 1; start >= 0; start-- {
+			sub := strings.Join(elems[start:end], "/")
+			for _, glob := range exclude {
+				if ok, _ := path.Match(glob, sub); ok {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
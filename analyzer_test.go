@@ -2,20 +2,58 @@ package gormreuse_test
 
 import (
 	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"go/ast"
+	"go/types"
+	"maps"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 
+	"golang.org/x/tools/go/analysis"
 	"golang.org/x/tools/go/analysis/analysistest"
+	"golang.org/x/tools/go/types/typeutil"
 
 	"github.com/mpyw/gormreuse"
 	"github.com/mpyw/gormreuse/internal/goldentest"
 )
 
+var update = flag.Bool("update", false, "regenerate the .diagnostics.golden snapshots and the -fix-diff golden patch")
+
 func TestAnalyzer(t *testing.T) {
 	t.Parallel()
 	testdata := analysistest.TestData()
-	analysistest.Run(t, testdata, gormreuse.Analyzer, "gormreuse")
+	results := analysistest.Run(t, testdata, gormreuse.Analyzer, "gormreuse")
+
+	// Every diagnostic carries the category of its kind, and the fixtures
+	// exercise each kind.
+	prefixes := map[string]string{
+		"BRANCH":             "*gorm.DB reused:",
+		"LATE-SESSION":       "*gorm.DB reused: Session on an already-polluted value",
+		"UNUSED-IGNORE":      "unused gormreuse:ignore directive",
+		"UNUSED-ALLOW-REUSE": "unused gormreuse:allow-reuse directive",
+	}
+	seen := make(map[string]bool)
+	for _, r := range results {
+		for _, d := range r.Action.Diagnostics {
+			if d.Category == "" {
+				t.Errorf("%v: diagnostic without category: %s", r.Action.Package.Fset.Position(d.Pos), d.Message)
+				continue
+			}
+			if p, ok := prefixes[d.Category]; ok && !strings.HasPrefix(d.Message, p) {
+				t.Errorf("%v: [%s] diagnostic has unexpected message: %s", r.Action.Package.Fset.Position(d.Pos), d.Category, d.Message)
+			}
+			seen[d.Category] = true
+		}
+	}
+	for _, want := range []string{"BRANCH", "PURE", "CONTRACT", "UNUSED-IGNORE", "UNUSED-ALLOW-REUSE", "UNUSED-DIRECTIVE", "SCOPES-SESSION", "LATE-SESSION"} {
+		if !seen[want] {
+			t.Errorf("no diagnostic with category %s", want)
+		}
+	}
 }
 
 func TestFileFilter(t *testing.T) {
@@ -46,6 +84,878 @@ func TestSuggestedFixesWithAlias(t *testing.T) {
 	analysistest.RunWithSuggestedFixes(t, testdata, gormreuse.Analyzer, "aliasimport")
 }
 
+// TestWorkspace verifies that directives declared in a sibling module of a
+// go.work workspace are honored. analysistest loads testdata/workspace in
+// module mode because it contains go.mod and go.work.
+func TestWorkspace(t *testing.T) {
+	// Workspace mode rejects -mod=mod, which developers may have in GOFLAGS.
+	t.Setenv("GOFLAGS", "")
+	testdata := filepath.Join(analysistest.TestData(), "workspace")
+	analysistest.Run(t, testdata, gormreuse.Analyzer, "./app")
+}
+
+// TestFixComplexity verifies that -fix-complexity labels straight-line reuse
+// as trivial, reuse inside a loop as moderate and reuse across a closure or
+// goroutine as manual. It mutates the analyzer flag, so it must not run in
+// parallel with other tests.
+func TestFixComplexity(t *testing.T) {
+	if err := gormreuse.Analyzer.Flags.Set("fix-complexity", "true"); err != nil {
+		t.Fatalf("Failed to set flag: %v", err)
+	}
+	defer func() { _ = gormreuse.Analyzer.Flags.Set("fix-complexity", "false") }()
+
+	testdata := analysistest.TestData()
+	analysistest.Run(t, testdata, gormreuse.Analyzer, "fixcomplexity")
+}
+
+// TestChainThreshold verifies that -chain-threshold offers the fix making the
+// root immutable, labeled as recommended, for reuse in a chain of more method
+// calls than the threshold, and only the reassignment fix for shorter chains.
+func TestChainThreshold(t *testing.T) {
+	t.Parallel()
+	opts := gormreuse.DefaultOptions()
+	opts.ChainThreshold = 3
+	testdata := analysistest.TestData()
+	analysistest.RunWithSuggestedFixes(t, testdata, gormreuse.NewAnalyzer(opts), "chainthreshold")
+
+	invalid := gormreuse.NewAnalyzer(gormreuse.Options{ChainThreshold: -1})
+	for _, r := range analysistest.Run(goldentest.NoopT{}, testdata, invalid, "noimport") {
+		if r.Err == nil || !strings.Contains(r.Err.Error(), "invalid chain-threshold -1") {
+			t.Errorf("negative chain-threshold: err = %v, want invalid chain-threshold", r.Err)
+		}
+	}
+}
+
+// TestSuggestPure verifies that -suggest-pure reports unannotated helpers the
+// pure validator proves never pollute their argument, and no others. It
+// mutates the analyzer flag, so it must not run in parallel with other tests.
+func TestSuggestPure(t *testing.T) {
+	if err := gormreuse.Analyzer.Flags.Set("suggest-pure", "true"); err != nil {
+		t.Fatalf("Failed to set flag: %v", err)
+	}
+	defer func() { _ = gormreuse.Analyzer.Flags.Set("suggest-pure", "false") }()
+
+	testdata := analysistest.TestData()
+	analysistest.RunWithSuggestedFixes(t, testdata, gormreuse.Analyzer, "suggestpure")
+}
+
+// TestWarnUnvalidatedPure verifies that -warn-unvalidated-pure reports the
+// first call of each //gormreuse:pure function of an imported package whose
+// source cannot be read, and neither those with readable source nor the pure
+// functions of the package itself.
+func TestWarnUnvalidatedPure(t *testing.T) {
+	t.Parallel()
+	opts := gormreuse.DefaultOptions()
+	opts.WarnUnvalidatedPure = true
+	testdata := analysistest.TestData()
+	analysistest.Run(t, testdata, gormreuse.NewAnalyzer(opts), "unvalidatedpure")
+}
+
+// TestNoTestHelpers verifies that -no-test-helpers suppresses reuse whose
+// finisher is an argument of a testify require/assert call, while bare reuse
+// still fires. It mutates the analyzer flag, so it must not run in parallel
+// with other tests.
+func TestNoTestHelpers(t *testing.T) {
+	if err := gormreuse.Analyzer.Flags.Set("no-test-helpers", "true"); err != nil {
+		t.Fatalf("Failed to set flag: %v", err)
+	}
+	defer func() { _ = gormreuse.Analyzer.Flags.Set("no-test-helpers", "false") }()
+
+	testdata := analysistest.TestData()
+	analysistest.Run(t, testdata, gormreuse.Analyzer, "testhelpers")
+}
+
+// TestExternalFacts verifies that functions of an imported package marked
+// //gormreuse:pure are classified by the facts of FactsAnalyzer.
+func TestExternalFacts(t *testing.T) {
+	t.Parallel()
+	testdata := analysistest.TestData()
+	analysistest.Run(t, testdata, gormreuse.Analyzer, "externalfacts")
+}
+
+// TestFacts verifies the facts FactsAnalyzer exports and that a dependent
+// analyzer sees them for imported functions through its Result.
+func TestFacts(t *testing.T) {
+	t.Parallel()
+	testdata := analysistest.TestData()
+	analysistest.Run(t, testdata, gormreuse.FactsAnalyzer, "purelib")
+
+	consumer := &analysis.Analyzer{
+		Name:     "factsconsumer",
+		Doc:      "reports calls of functions classified by gormreuse facts",
+		Requires: []*analysis.Analyzer{gormreuse.FactsAnalyzer},
+		Run: func(pass *analysis.Pass) (any, error) {
+			res := pass.ResultOf[gormreuse.FactsAnalyzer].(*gormreuse.Result)
+			for _, file := range pass.Files {
+				ast.Inspect(file, func(n ast.Node) bool {
+					call, ok := n.(*ast.CallExpr)
+					if !ok {
+						return true
+					}
+					fn, ok := typeutil.Callee(pass.TypesInfo, call).(*types.Func)
+					if !ok {
+						return true
+					}
+					if res.IsPure(fn) {
+						pass.Reportf(call.Pos(), "pure: %s", fn.Name())
+					}
+					if res.IsImmutableReturn(fn) {
+						pass.Reportf(call.Pos(), "immutable-return: %s", fn.Name())
+					}
+					return true
+				})
+			}
+			return nil, nil
+		},
+	}
+	analysistest.Run(t, testdata, consumer, "factsconsumer")
+}
+
+// TestStrictInterface verifies that -strict-interface reports a mutable
+// *gorm.DB converted to an interface at the conversion, and that without it
+// only the reuse the conversion causes is reported.
+func TestStrictInterface(t *testing.T) {
+	t.Parallel()
+	opts := gormreuse.DefaultOptions()
+	opts.StrictInterface = true
+	testdata := analysistest.TestData()
+	analysistest.Run(t, testdata, gormreuse.NewAnalyzer(opts), "strictinterface")
+	analysistest.Run(t, testdata, gormreuse.NewAnalyzer(gormreuse.DefaultOptions()), "strictinterface/off")
+}
+
+// TestAssumePureHelpers verifies that -assume-pure-helpers trusts
+// unannotated helpers as pure while //gormreuse:impure and //gormreuse:sink
+// helpers still pollute, and that without it unannotated helpers pollute.
+func TestAssumePureHelpers(t *testing.T) {
+	t.Parallel()
+	opts := gormreuse.DefaultOptions()
+	opts.AssumePureHelpers = true
+	testdata := analysistest.TestData()
+	analysistest.Run(t, testdata, gormreuse.NewAnalyzer(opts), "assumepure")
+	analysistest.Run(t, testdata, gormreuse.NewAnalyzer(gormreuse.DefaultOptions()), "assumepure/off")
+}
+
+// TestRequireIgnoreReason verifies that -require-ignore-reason reports
+// //gormreuse:ignore directives without a reason, and that without it a reason
+// is optional.
+func TestRequireIgnoreReason(t *testing.T) {
+	t.Parallel()
+	opts := gormreuse.DefaultOptions()
+	opts.RequireIgnoreReason = true
+	testdata := analysistest.TestData()
+	analysistest.Run(t, testdata, gormreuse.NewAnalyzer(opts), "ignorereason")
+	analysistest.Run(t, testdata, gormreuse.NewAnalyzer(gormreuse.DefaultOptions()), "ignorereason/off")
+}
+
+// TestStrictIgnoreFile verifies that //gormreuse:ignore-file suppresses every
+// diagnostic of its file and that -strict-ignore-file reports a directive with
+// nothing to suppress. It mutates the analyzer flag, so it must not run in
+// parallel with other tests.
+func TestStrictIgnoreFile(t *testing.T) {
+	if err := gormreuse.Analyzer.Flags.Set("strict-ignore-file", "true"); err != nil {
+		t.Fatalf("Failed to set flag: %v", err)
+	}
+	defer func() { _ = gormreuse.Analyzer.Flags.Set("strict-ignore-file", "false") }()
+
+	testdata := analysistest.TestData()
+	analysistest.Run(t, testdata, gormreuse.Analyzer, "ignorefile")
+}
+
+// TestSeverity verifies that -severity prefixes the diagnostics of each mapped
+// category with its level and leaves unmapped categories unchanged. It mutates
+// the analyzer flag, so it must not run in parallel with other tests.
+func TestSeverity(t *testing.T) {
+	if err := gormreuse.Analyzer.Flags.Set("severity", "BRANCH=warning,PURE=error"); err != nil {
+		t.Fatalf("Failed to set flag: %v", err)
+	}
+	defer func() { _ = gormreuse.Analyzer.Flags.Set("severity", "") }()
+
+	testdata := analysistest.TestData()
+	analysistest.Run(t, testdata, gormreuse.Analyzer, "severity")
+}
+
+// TestSeverityFlag verifies that -severity only accepts known categories and
+// the error and warning levels. It mutates the analyzer flag, so it must not
+// run in parallel with other tests.
+func TestSeverityFlag(t *testing.T) {
+	defer func() { _ = gormreuse.Analyzer.Flags.Set("severity", "") }()
+
+	tests := []struct {
+		value   string
+		wantErr bool
+	}{
+		{"BRANCH=warning", false},
+		{"PURE=error,CONTRACT=warning", false},
+		{"UNUSED-IGNORE=warning, UNUSED-DIRECTIVE=warning", false},
+		{"SCOPES-SESSION=error", false},
+		{"BRANCH", true},
+		{"branch=warning", true},
+		{"NOPE=warning", true},
+		{"BRANCH=info", true},
+	}
+	for _, tt := range tests {
+		err := gormreuse.Analyzer.Flags.Set("severity", tt.value)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("Set(%q) error = %v, wantErr %v", tt.value, err, tt.wantErr)
+		}
+	}
+
+	if err := gormreuse.Analyzer.Flags.Set("severity", ""); err != nil {
+		t.Fatalf("Failed to reset flag: %v", err)
+	}
+	if err := gormreuse.Analyzer.Flags.Set("severity", "PURE=error,BRANCH=warning"); err != nil {
+		t.Fatalf("Failed to set flag: %v", err)
+	}
+	if got, want := gormreuse.Analyzer.Flags.Lookup("severity").Value.String(), "BRANCH=warning,PURE=error"; got != want {
+		t.Errorf("String() = %q, want %q", got, want)
+	}
+}
+
+// TestEnableOnly verifies that -enable-only=PURE reports only PURE
+// diagnostics. It mutates the analyzer flag, so it must not run in parallel
+// with other tests.
+func TestEnableOnly(t *testing.T) {
+	if err := gormreuse.Analyzer.Flags.Set("enable-only", "PURE"); err != nil {
+		t.Fatalf("Failed to set flag: %v", err)
+	}
+	defer func() { _ = gormreuse.Analyzer.Flags.Set("enable-only", "") }()
+
+	testdata := analysistest.TestData()
+	analysistest.Run(t, testdata, gormreuse.Analyzer, "enableonly")
+}
+
+// TestEnableOnlyFlag verifies that -enable-only only accepts known categories.
+// It mutates the analyzer flag, so it must not run in parallel with other
+// tests.
+func TestEnableOnlyFlag(t *testing.T) {
+	defer func() { _ = gormreuse.Analyzer.Flags.Set("enable-only", "") }()
+
+	tests := []struct {
+		value   string
+		wantErr bool
+	}{
+		{"PURE", false},
+		{"BRANCH, CONTRACT", false},
+		{"pure", true},
+		{"PURE,NOPE", true},
+	}
+	for _, tt := range tests {
+		err := gormreuse.Analyzer.Flags.Set("enable-only", tt.value)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("Set(%q) error = %v, wantErr %v", tt.value, err, tt.wantErr)
+		}
+	}
+
+	if err := gormreuse.Analyzer.Flags.Set("enable-only", ""); err != nil {
+		t.Fatalf("Failed to reset flag: %v", err)
+	}
+	if err := gormreuse.Analyzer.Flags.Set("enable-only", "PURE,BRANCH"); err != nil {
+		t.Fatalf("Failed to set flag: %v", err)
+	}
+	if got, want := gormreuse.Analyzer.Flags.Lookup("enable-only").Value.String(), "BRANCH,PURE"; got != want {
+		t.Errorf("String() = %q, want %q", got, want)
+	}
+}
+
+// TestNewAnalyzer verifies that analyzers built by NewAnalyzer carry their own
+// name and options: one maps severities, the other reports PURE only, and
+// neither is affected by the flags of Analyzer or by later changes to the
+// options they were built from.
+func TestNewAnalyzer(t *testing.T) {
+	t.Parallel()
+	testdata := analysistest.TestData()
+
+	severityOpts := gormreuse.DefaultOptions()
+	severityOpts.Name = "gormreuse_severity"
+	severityOpts.Severity = map[string]string{"BRANCH": "warning", "PURE": "error"}
+	severityAnalyzer := gormreuse.NewAnalyzer(severityOpts)
+	severityOpts.Severity["BRANCH"] = "error"
+
+	pureOpts := gormreuse.DefaultOptions()
+	pureOpts.Name = "gormreuse_pure"
+	pureOpts.EnableOnly = []string{"PURE"}
+	pureAnalyzer := gormreuse.NewAnalyzer(pureOpts)
+
+	for _, a := range []*analysis.Analyzer{severityAnalyzer, pureAnalyzer} {
+		if err := a.Flags.Set("severity", "BRANCH=error"); err == nil {
+			t.Errorf("%s: options are settable by flag", a.Name)
+		}
+	}
+	if got := []string{severityAnalyzer.Name, pureAnalyzer.Name}; got[0] != "gormreuse_severity" || got[1] != "gormreuse_pure" {
+		t.Errorf("names = %q", got)
+	}
+	if gormreuse.NewAnalyzer(gormreuse.Options{}).Name != gormreuse.Analyzer.Name {
+		t.Errorf("empty Name does not default to %q", gormreuse.Analyzer.Name)
+	}
+
+	analysistest.Run(t, testdata, severityAnalyzer, "severity")
+	analysistest.Run(t, testdata, pureAnalyzer, "enableonly")
+
+	// Invalid options are reported when the analyzer runs.
+	invalid := gormreuse.NewAnalyzer(gormreuse.Options{EnableOnly: []string{"NOPE"}})
+	for _, r := range analysistest.Run(goldentest.NoopT{}, testdata, invalid, "enableonly") {
+		if r.Err == nil || !strings.Contains(r.Err.Error(), `unknown category "NOPE"`) {
+			t.Errorf("invalid options: err = %v, want unknown category", r.Err)
+		}
+	}
+}
+
+// TestPurePkg verifies that functions of the -pure-pkg packages, testify's
+// require and assert by default, do not pollute their *gorm.DB arguments, and
+// that a configured list replaces the default.
+func TestPurePkg(t *testing.T) {
+	t.Parallel()
+	testdata := analysistest.TestData()
+	analysistest.Run(t, testdata, gormreuse.Analyzer, "purepkg")
+
+	opts := gormreuse.DefaultOptions()
+	opts.PurePkgs = []string{"github.com/acme/check"}
+	analysistest.Run(t, testdata, gormreuse.NewAnalyzer(opts), "purepkgcustom")
+}
+
+// TestGormType verifies that -gorm-type tracks a vendored GORM type and that
+// -gorm-type-underlying tracks named types over gorm.DB, while aliases of
+// gorm.DB match by default. It mutates the analyzer flags, so it must not run
+// in parallel with other tests.
+func TestGormType(t *testing.T) {
+	if err := gormreuse.Analyzer.Flags.Set("gorm-type", "github.com/acme/db.Handle"); err != nil {
+		t.Fatalf("Failed to set flag: %v", err)
+	}
+	if err := gormreuse.Analyzer.Flags.Set("gorm-type-underlying", "true"); err != nil {
+		t.Fatalf("Failed to set flag: %v", err)
+	}
+	defer func() {
+		gormreuse.ResetGormTypes()
+		_ = gormreuse.Analyzer.Flags.Set("gorm-type-underlying", "false")
+	}()
+
+	testdata := analysistest.TestData()
+	analysistest.Run(t, testdata, gormreuse.Analyzer, "gormtype")
+}
+
+// TestImmutableMethod verifies that -immutable-method makes a configured
+// method of a GORM type return an immutable copy, leaving a same-named method
+// of another type alone.
+func TestImmutableMethod(t *testing.T) {
+	t.Parallel()
+	opts := gormreuse.DefaultOptions()
+	opts.GormTypes = []string{"github.com/acme/db.Handle"}
+	opts.ImmutableMethods = []string{"ReadOnly"}
+	testdata := analysistest.TestData()
+	analysistest.Run(t, testdata, gormreuse.NewAnalyzer(opts), "immutablemethod")
+}
+
+// TestExclude verifies that -exclude skips the files matching a glob, by file
+// name or by a directory on their path, while their siblings are analyzed.
+func TestExclude(t *testing.T) {
+	t.Parallel()
+	opts := gormreuse.DefaultOptions()
+	opts.Exclude = []string{"legacy_*.go", "third_party"}
+	testdata := analysistest.TestData()
+	analysistest.Run(t, testdata, gormreuse.NewAnalyzer(opts), "exclude", "exclude/third_party/lib")
+
+	invalid := gormreuse.NewAnalyzer(gormreuse.Options{Exclude: []string{"["}})
+	for _, r := range analysistest.Run(goldentest.NoopT{}, testdata, invalid, "exclude") {
+		if r.Err == nil || !strings.Contains(r.Err.Error(), `invalid exclude "["`) {
+			t.Errorf("invalid glob: err = %v, want invalid exclude", r.Err)
+		}
+	}
+	if err := gormreuse.Analyzer.Flags.Lookup("exclude").Value.Set("["); err == nil {
+		t.Error("-exclude=[ should be rejected")
+	}
+}
+
+// TestBuilderType verifies that -builder-type tracks a value-receiver wrapper
+// around *gorm.DB, and a gorm-like interface implemented by such wrappers, like
+// *gorm.DB itself. It mutates the analyzer flags, so it must not run in
+// parallel with other tests.
+func TestBuilderType(t *testing.T) {
+	for _, name := range []string{"builder.Query", "builder.Querier"} {
+		if err := gormreuse.Analyzer.Flags.Set("builder-type", name); err != nil {
+			t.Fatalf("Failed to set flag: %v", err)
+		}
+	}
+	defer gormreuse.ResetGormTypes()
+
+	testdata := analysistest.TestData()
+	analysistest.Run(t, testdata, gormreuse.Analyzer, "builder")
+}
+
+// TestReportRootGraph verifies that -report-root-graph writes a DOT digraph
+// with a node per root and edges for branches and derivations. It mutates the
+// analyzer flag, so it must not run in parallel with other tests.
+func TestReportRootGraph(t *testing.T) {
+	out := filepath.Join(t.TempDir(), "roots.dot")
+	if err := gormreuse.Analyzer.Flags.Set("report-root-graph", out); err != nil {
+		t.Fatalf("Failed to set flag: %v", err)
+	}
+	defer func() { _ = gormreuse.Analyzer.Flags.Set("report-root-graph", "") }()
+
+	testdata := analysistest.TestData()
+	analysistest.Run(t, testdata, gormreuse.Analyzer, "rootgraph")
+
+	data, err := os.ReadFile(out)
+	if err != nil {
+		t.Fatalf("Failed to read root graph: %v", err)
+	}
+	dot := string(data)
+
+	for _, want := range []string{
+		`digraph "gormreuse: rootgraph" {`,
+		`label="rootgraph.reuse";`,
+		`label="rootgraph.derive";`,
+		// reuse: q is derived from db and branched twice; the second branch is a violation
+		`"f0_r0" [shape=box, label="param db\nrootgraph.go:8"];`,
+		`"f0_r1" [shape=box, label="Where\nrootgraph.go:9"];`,
+		`"f0_u0" -> "f0_r1" [style=dashed, label="derives"];`,
+		`"f0_r1" -> "f0_u1" [label="branch"];`,
+		`"f0_r1" -> "f0_u2" [label="branch", color=red, fontcolor=red];`,
+		// derive: each extension is an assignment that derives the next root
+		`"f1_r1" [shape=box, label="Where\nrootgraph.go:16"];`,
+		`"f1_r2" [shape=box, label="Where\nrootgraph.go:17"];`,
+		`"f1_r1" -> "f1_u1" [label="assign"];`,
+		`"f1_u1" -> "f1_r2" [style=dashed, label="derives"];`,
+		`"f1_r2" -> "f1_u3" [label="branch"];`,
+	} {
+		if !strings.Contains(dot, want) {
+			t.Errorf("root graph missing %s", want)
+		}
+	}
+	if t.Failed() {
+		t.Logf("got:\n%s", dot)
+	}
+}
+
+// TestListRootsJSON verifies that -list-roots-json writes one JSON document per
+// package listing each function's roots, their first use and reuse sites. It
+// mutates the analyzer flag, so it must not run in parallel with other tests.
+func TestListRootsJSON(t *testing.T) {
+	out := filepath.Join(t.TempDir(), "roots.json")
+	if err := gormreuse.Analyzer.Flags.Set("list-roots-json", out); err != nil {
+		t.Fatalf("Failed to set flag: %v", err)
+	}
+	defer func() { _ = gormreuse.Analyzer.Flags.Set("list-roots-json", "") }()
+
+	testdata := analysistest.TestData()
+	analysistest.Run(t, testdata, gormreuse.Analyzer, "rootlist")
+
+	data, err := os.ReadFile(out)
+	if err != nil {
+		t.Fatalf("Failed to read root list: %v", err)
+	}
+
+	type root struct {
+		RootPos     string   `json:"rootPos"`
+		CreatedBy   string   `json:"createdBy"`
+		Polluted    bool     `json:"polluted"`
+		FirstUsePos string   `json:"firstUsePos"`
+		ReuseSites  []string `json:"reuseSites"`
+	}
+	var got struct {
+		Package   string `json:"package"`
+		Functions []struct {
+			Function string `json:"function"`
+			Roots    []root `json:"roots"`
+		} `json:"functions"`
+	}
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatalf("Failed to decode root list: %v\n%s", err, data)
+	}
+
+	if got.Package != "rootlist" {
+		t.Errorf("package = %q, want %q", got.Package, "rootlist")
+	}
+	if len(got.Functions) != 1 || got.Functions[0].Function != "rootlist.reuse" {
+		t.Fatalf("functions = %+v, want only rootlist.reuse", got.Functions)
+	}
+
+	var where *root
+	for i, r := range got.Functions[0].Roots {
+		if r.CreatedBy == "Where" {
+			where = &got.Functions[0].Roots[i]
+		}
+	}
+	if where == nil {
+		t.Fatalf("roots = %+v, want a root created by Where", got.Functions[0].Roots)
+	}
+	// q := db.Where("x") at line 9, q.Find at line 10, q.Count at line 11.
+	if !strings.HasSuffix(where.RootPos, "rootlist.go:9:15") {
+		t.Errorf("rootPos = %q, want rootlist.go:9:15", where.RootPos)
+	}
+	if !where.Polluted {
+		t.Error("polluted = false, want true")
+	}
+	if !strings.HasSuffix(where.FirstUsePos, "rootlist.go:10:8") {
+		t.Errorf("firstUsePos = %q, want rootlist.go:10:8", where.FirstUsePos)
+	}
+	if len(where.ReuseSites) != 1 || !strings.HasSuffix(where.ReuseSites[0], "rootlist.go:11:9") {
+		t.Errorf("reuseSites = %q, want [rootlist.go:11:9]", where.ReuseSites)
+	}
+}
+
+// TestSummary verifies that -summary counts the diagnostics of a package by
+// category and by enclosing function, a closure's counting towards the
+// function declaring it, and that -summary-only counts them without reporting
+// them.
+func TestSummary(t *testing.T) {
+	t.Parallel()
+	testdata := analysistest.TestData()
+
+	// parse reads the lines of a summary table as "kind name" -> count.
+	parse := func(path string) map[string]string {
+		t.Helper()
+		data, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatalf("Failed to read summary: %v", err)
+		}
+		rows := make(map[string]string)
+		for _, line := range strings.Split(strings.TrimSpace(string(data)), "\n") {
+			fields := strings.Fields(line)
+			switch len(fields) {
+			case 2:
+				rows[fields[0]] = fields[1]
+			case 3:
+				rows[fields[0]+" "+fields[1]] = fields[2]
+			default:
+				t.Fatalf("malformed summary line %q in\n%s", line, data)
+			}
+		}
+		return rows
+	}
+
+	opts := gormreuse.DefaultOptions()
+	opts.Summary = filepath.Join(t.TempDir(), "summary.txt")
+	analysistest.Run(t, testdata, gormreuse.NewAnalyzer(opts), "summary")
+	got := parse(opts.Summary)
+	want := map[string]string{
+		"package":                    "summary",
+		"category BRANCH":            "3",
+		"category UNUSED-IGNORE":     "1",
+		"function summary.twice":     "2",
+		"function summary.inClosure": "1",
+		"function (package)":         "1",
+		"total":                      "4",
+	}
+	if !maps.Equal(got, want) {
+		t.Errorf("summary = %v, want %v", got, want)
+	}
+
+	opts = gormreuse.DefaultOptions()
+	opts.Summary = filepath.Join(t.TempDir(), "summary.txt")
+	opts.SummaryOnly = true
+	analysistest.Run(t, testdata, gormreuse.NewAnalyzer(opts), "summary/only")
+	got = parse(opts.Summary)
+	want = map[string]string{
+		"package":                     "summary/only",
+		"category BRANCH":             "1",
+		"function summary/only.twice": "1",
+		"total":                       "1",
+	}
+	if !maps.Equal(got, want) {
+		t.Errorf("summary-only = %v, want %v", got, want)
+	}
+}
+
+func TestBaseline(t *testing.T) {
+	t.Parallel()
+	testdata := analysistest.TestData()
+
+	// baseline.txt predates lines added to legacy and an edit to edited: the
+	// drifted entries still match, the edited and introduced ones are new.
+	opts := gormreuse.DefaultOptions()
+	opts.Baseline = filepath.Join(testdata, "src", "baseline", "baseline.txt")
+	analysistest.Run(t, testdata, gormreuse.NewAnalyzer(opts), "baseline")
+
+	// A baseline written from the package suppresses all of its diagnostics.
+	// Writing replaces the entries of the analyzed file, stale ones included,
+	// keeps those of other files and, written again, stays the same.
+	const other = "other/other.go\t3\tother.f\tBRANCH\t0123456789abcdef\n"
+	opts = gormreuse.DefaultOptions()
+	opts.Baseline = filepath.Join(t.TempDir(), "baseline.txt")
+	opts.WriteBaseline = true
+	stale := other + "testdata/src/baseline/baseline.go\t1\tbaseline.gone\tBRANCH\t0123456789abcdef\n"
+	if err := os.WriteFile(opts.Baseline, []byte(stale), 0o644); err != nil {
+		t.Fatalf("Failed to write baseline: %v", err)
+	}
+	for range 2 {
+		for _, r := range analysistest.Run(goldentest.NoopT{}, testdata, gormreuse.NewAnalyzer(opts), "baseline") {
+			if r.Err != nil {
+				t.Fatalf("-write-baseline failed: %v", r.Err)
+			}
+			if len(r.Diagnostics) > 0 {
+				t.Errorf("-write-baseline reported %d diagnostics, want none", len(r.Diagnostics))
+			}
+		}
+	}
+	data, err := os.ReadFile(opts.Baseline)
+	if err != nil {
+		t.Fatalf("Failed to read baseline: %v", err)
+	}
+	if n := strings.Count(string(data), "testdata/src/baseline/baseline.go\t"); n != 4 || strings.Contains(string(data), "baseline.gone") {
+		t.Errorf("baseline has %d entries of baseline.go, want 4 fresh ones:\n%s", n, data)
+	}
+	if !strings.HasPrefix(string(data), other) {
+		t.Errorf("baseline lost the entry of another file:\n%s", data)
+	}
+	opts.WriteBaseline = false
+	for _, r := range analysistest.Run(goldentest.NoopT{}, testdata, gormreuse.NewAnalyzer(opts), "baseline") {
+		if r.Err != nil {
+			t.Fatalf("-baseline failed: %v", r.Err)
+		}
+		if len(r.Diagnostics) > 0 {
+			t.Errorf("-baseline reported %d diagnostics, want none:\n%s", len(r.Diagnostics), data)
+		}
+	}
+}
+
+// TestDiagnosticsSnapshot compares the full diagnostic output of each package,
+// including categories, related information and suggested fixes, with its
+// testdata/src/<pkg>/<pkg>.diagnostics.golden snapshot. Run with -update to
+// regenerate the snapshots.
+func TestDiagnosticsSnapshot(t *testing.T) {
+	t.Parallel()
+	testdata := analysistest.TestData()
+	for _, pkg := range []string{"gormreuse", "filefilter", "noimport", "aliasimport"} {
+		t.Run(pkg, func(t *testing.T) {
+			results := analysistest.Run(goldentest.NoopT{}, testdata, gormreuse.Analyzer, pkg)
+			got := goldentest.FormatDiagnostics(results)
+
+			golden := filepath.Join(testdata, "src", pkg, pkg+".diagnostics.golden")
+			if *update {
+				if err := os.WriteFile(golden, got, 0o644); err != nil {
+					t.Fatalf("Failed to write %s: %v", golden, err)
+				}
+				return
+			}
+			want, err := os.ReadFile(golden)
+			if err != nil {
+				t.Fatalf("Failed to read %s (run with -update to create it): %v", golden, err)
+			}
+			if !bytes.Equal(got, want) {
+				t.Errorf("diagnostics differ from %s (run with -update to regenerate):\n%s", golden, lineDiff(want, got))
+			}
+		})
+	}
+}
+
+// TestFixDiff verifies that -fix-diff reports nothing and writes the patch of
+// the suggested fixes pinned by testdata/src/fixdiff/fixdiff.diff. Run with
+// -update to regenerate it. The test swaps the patch output, so it must not
+// run in parallel.
+func TestFixDiff(t *testing.T) {
+	testdata := analysistest.TestData()
+	analysistest.Run(t, testdata, gormreuse.Analyzer, "fixdiff")
+
+	var got bytes.Buffer
+	defer gormreuse.SetFixDiffOutput(&got)()
+	opts := gormreuse.DefaultOptions()
+	opts.FixDiff = true
+	for _, r := range analysistest.Run(goldentest.NoopT{}, testdata, gormreuse.NewAnalyzer(opts), "fixdiff") {
+		if r.Err != nil {
+			t.Fatalf("-fix-diff failed: %v", r.Err)
+		}
+		if len(r.Diagnostics) > 0 {
+			t.Errorf("-fix-diff reported %d diagnostics, want none", len(r.Diagnostics))
+		}
+	}
+
+	golden := filepath.Join(testdata, "src", "fixdiff", "fixdiff.diff")
+	if *update {
+		if err := os.WriteFile(golden, got.Bytes(), 0o644); err != nil {
+			t.Fatalf("Failed to write %s: %v", golden, err)
+		}
+		return
+	}
+	want, err := os.ReadFile(golden)
+	if err != nil {
+		t.Fatalf("Failed to read %s (run with -update to create it): %v", golden, err)
+	}
+	if !bytes.Equal(got.Bytes(), want) {
+		t.Errorf("patch differs from %s (run with -update to regenerate):\n%s", golden, lineDiff(want, got.Bytes()))
+	}
+}
+
+// TestTraceValue compares the -trace-value output for a receiver reached
+// through a closure, an Alloc and a loop swap with its
+// testdata/src/tracevalue/tracevalue.trace snapshot. Run with -update to
+// regenerate it.
+func TestTraceValue(t *testing.T) {
+	var got bytes.Buffer
+	defer gormreuse.SetTraceValueOutput(&got)()
+	opts := gormreuse.DefaultOptions()
+	opts.TraceValue = "tracevalue/tracevalue.go:24"
+	testdata := analysistest.TestData()
+	analysistest.Run(t, testdata, gormreuse.NewAnalyzer(opts), "tracevalue")
+
+	golden := filepath.Join(testdata, "src", "tracevalue", "tracevalue.trace")
+	if *update {
+		if err := os.WriteFile(golden, got.Bytes(), 0o644); err != nil {
+			t.Fatalf("Failed to write %s: %v", golden, err)
+		}
+		return
+	}
+	want, err := os.ReadFile(golden)
+	if err != nil {
+		t.Fatalf("Failed to read %s (run with -update to create it): %v", golden, err)
+	}
+	if !bytes.Equal(got.Bytes(), want) {
+		t.Errorf("trace differs from %s (run with -update to regenerate):\n%s", golden, lineDiff(want, got.Bytes()))
+	}
+
+	opts.TraceValue = "tracevalue.go"
+	for _, r := range analysistest.Run(goldentest.NoopT{}, testdata, gormreuse.NewAnalyzer(opts), "tracevalue") {
+		if r.Err == nil || !strings.Contains(r.Err.Error(), "invalid trace-value") {
+			t.Errorf("-trace-value=tracevalue.go: err = %v, want an invalid trace-value error", r.Err)
+		}
+	}
+}
+
+// lineDiff lists the lines only in want (-) or only in got (+), which is
+// enough to locate a change in a snapshot of thousands of lines.
+func lineDiff(want, got []byte) string {
+	count := make(map[string]int)
+	for _, l := range strings.Split(string(want), "\n") {
+		count[l]++
+	}
+	for _, l := range strings.Split(string(got), "\n") {
+		count[l]--
+	}
+	var b strings.Builder
+	for _, l := range strings.Split(string(want), "\n") {
+		if count[l] > 0 {
+			count[l]--
+			b.WriteString("- " + l + "\n")
+		}
+	}
+	for _, l := range strings.Split(string(got), "\n") {
+		if count[l] < 0 {
+			count[l]++
+			b.WriteString("+ " + l + "\n")
+		}
+	}
+	return b.String()
+}
+
+// TestParallel verifies that analyzing functions concurrently reports the same
+// diagnostics, fixes included, as analyzing them one at a time. Run it with
+// -race to check the analysis shares no unguarded state between functions.
+func TestParallel(t *testing.T) {
+	t.Parallel()
+	testdata := analysistest.TestData()
+	run := func(parallel int) []byte {
+		opts := gormreuse.DefaultOptions()
+		opts.Parallel = parallel
+		return goldentest.FormatDiagnostics(analysistest.Run(goldentest.NoopT{}, testdata, gormreuse.NewAnalyzer(opts), "gormreuse"))
+	}
+	sequential := run(1)
+	if len(sequential) == 0 {
+		t.Fatal("no diagnostics reported")
+	}
+	if parallel := run(8); !bytes.Equal(parallel, sequential) {
+		t.Errorf("-parallel=8 differs from -parallel=1:\n%s", lineDiff(sequential, parallel))
+	}
+
+	invalid := gormreuse.NewAnalyzer(gormreuse.Options{Parallel: -1})
+	for _, r := range analysistest.Run(goldentest.NoopT{}, testdata, invalid, "noimport") {
+		if r.Err == nil || !strings.Contains(r.Err.Error(), "invalid parallel -1") {
+			t.Errorf("negative parallel: err = %v, want invalid parallel", r.Err)
+		}
+	}
+}
+
+// TestRelatedRoot verifies that a reuse diagnostic carries the definition of
+// its root as related information.
+func TestRelatedRoot(t *testing.T) {
+	t.Parallel()
+	results := analysistest.Run(t, analysistest.TestData(), gormreuse.Analyzer, "rootlist")
+
+	var found bool
+	for _, r := range results {
+		for _, d := range r.Diagnostics {
+			pos := r.Pass.Fset.Position(d.Pos)
+			if filepath.Base(pos.Filename) != "rootlist.go" || pos.Line != 11 {
+				continue
+			}
+			found = true
+			// q := db.Where("x") at line 9, reused by q.Count at line 11.
+			if len(d.Related) != 1 || d.Related[0].Message != "root defined here" {
+				t.Fatalf("related = %+v, want one \"root defined here\"", d.Related)
+			}
+			if rel := r.Pass.Fset.Position(d.Related[0].Pos); filepath.Base(rel.Filename) != "rootlist.go" || rel.Line != 9 || rel.Column != 15 {
+				t.Errorf("root defined at %v, want rootlist.go:9:15", rel)
+			}
+		}
+	}
+	if !found {
+		t.Fatal("no reuse diagnostic at rootlist.go:11")
+	}
+}
+
+// TestDiagnosticEnd verifies that a reuse diagnostic spans the offending call,
+// from its opening parenthesis to its end.
+func TestDiagnosticEnd(t *testing.T) {
+	t.Parallel()
+	results := analysistest.Run(t, analysistest.TestData(), gormreuse.Analyzer, "rootlist")
+
+	var found bool
+	for _, r := range results {
+		for _, d := range r.Diagnostics {
+			pos := r.Pass.Fset.Position(d.Pos)
+			if filepath.Base(pos.Filename) != "rootlist.go" || pos.Line != 11 {
+				continue
+			}
+			found = true
+			// q.Count(nil) at line 11: "(" at column 9, ")" at column 13.
+			if d.End <= d.Pos {
+				t.Fatalf("end = %d, want after pos %d", d.End, d.Pos)
+			}
+			if end := r.Pass.Fset.Position(d.End); end.Line != 11 || end.Column != 14 {
+				t.Errorf("end = %v, want rootlist.go:11:14", end)
+			}
+		}
+	}
+	if !found {
+		t.Fatal("no reuse diagnostic at rootlist.go:11")
+	}
+}
+
+// TestCoalesceRoots verifies that a reuse reached through a Phi of two
+// polluted roots is one diagnostic listing both roots, and only the reported
+// root with -coalesce-roots=false. It mutates the analyzer flag, so it must not
+// run in parallel with other tests.
+func TestCoalesceRoots(t *testing.T) {
+	defer func() { _ = gormreuse.Analyzer.Flags.Set("coalesce-roots", "true") }()
+
+	for _, tt := range []struct {
+		coalesce string
+		want     []string
+	}{
+		// q1 := db.Where("a") at line 6, q2 := db.Where("b") at line 7.
+		{"true", []string{"coalesce.go:7: root defined here", "coalesce.go:6: polluted root defined here"}},
+		{"false", []string{"coalesce.go:7: root defined here"}},
+	} {
+		if err := gormreuse.Analyzer.Flags.Set("coalesce-roots", tt.coalesce); err != nil {
+			t.Fatalf("Failed to set flag: %v", err)
+		}
+		results := analysistest.Run(t, analysistest.TestData(), gormreuse.Analyzer, "coalesce")
+
+		var got [][]string
+		for _, r := range results {
+			for _, d := range r.Diagnostics {
+				var related []string
+				for _, rel := range d.Related {
+					pos := r.Pass.Fset.Position(rel.Pos)
+					related = append(related, fmt.Sprintf("%s:%d: %s", filepath.Base(pos.Filename), pos.Line, rel.Message))
+				}
+				got = append(got, related)
+			}
+		}
+		if len(got) != 1 || strings.Join(got[0], "\n") != strings.Join(tt.want, "\n") {
+			t.Errorf("coalesce-roots=%s: related = %q, want one diagnostic with %q", tt.coalesce, got, tt.want)
+		}
+	}
+}
+
 func TestGenerateDiffFiles(t *testing.T) {
 	testdata := analysistest.TestData()
 	srcDir := filepath.Join(testdata, "src", "gormreuse")
@@ -0,0 +1,81 @@
+package gormreuse_test
+
+import (
+	"fmt"
+	"os"
+	"testing"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/analysis/analysistest"
+	"golang.org/x/tools/go/analysis/checker"
+	"golang.org/x/tools/go/packages"
+
+	"github.com/mpyw/gormreuse"
+)
+
+// BenchmarkEnableOnly analyzes the gormreuse fixture package with every
+// category enabled and with -enable-only=PURE, which skips reuse detection.
+// The package is loaded once; each iteration runs buildssa and the analyzer.
+func BenchmarkEnableOnly(b *testing.B) {
+	pkgs := loadFixture(b)
+
+	for _, enabled := range []string{"", "PURE"} {
+		name := enabled
+		if name == "" {
+			name = "all"
+		}
+		b.Run(name, func(b *testing.B) {
+			if err := gormreuse.Analyzer.Flags.Set("enable-only", enabled); err != nil {
+				b.Fatalf("Failed to set flag: %v", err)
+			}
+			defer func() { _ = gormreuse.Analyzer.Flags.Set("enable-only", "") }()
+
+			for b.Loop() {
+				if _, err := checker.Analyze([]*analysis.Analyzer{gormreuse.Analyzer}, pkgs, nil); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+// BenchmarkParallel analyzes the gormreuse fixture package with its functions
+// analyzed one at a time and with one worker per CPU (-parallel). Run it with
+// -race to check the workers share no unguarded state:
+//
+//	go test -race -run '^$' -bench Parallel -benchtime 3x .
+func BenchmarkParallel(b *testing.B) {
+	pkgs := loadFixture(b)
+
+	for _, parallel := range []int{1, 0} {
+		opts := gormreuse.DefaultOptions()
+		opts.Parallel = parallel
+		analyzer := gormreuse.NewAnalyzer(opts)
+		b.Run(fmt.Sprintf("parallel=%d", parallel), func(b *testing.B) {
+			for b.Loop() {
+				if _, err := checker.Analyze([]*analysis.Analyzer{analyzer}, pkgs, nil); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+// loadFixture loads the gormreuse fixture package with its syntax and types.
+func loadFixture(b *testing.B) []*packages.Package {
+	b.Helper()
+	testdata := analysistest.TestData()
+	cfg := &packages.Config{
+		Mode: packages.LoadAllSyntax,
+		Dir:  testdata,
+		Env:  append(os.Environ(), "GOPATH="+testdata, "GO111MODULE=off", "GOFLAGS="),
+	}
+	pkgs, err := packages.Load(cfg, "gormreuse")
+	if err != nil {
+		b.Fatalf("packages.Load: %v", err)
+	}
+	if packages.PrintErrors(pkgs) > 0 {
+		b.Fatal("packages had errors")
+	}
+	return pkgs
+}
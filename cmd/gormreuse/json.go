@@ -0,0 +1,215 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"go/token"
+	"io"
+	"slices"
+	"strings"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/analysis/checker"
+	"golang.org/x/tools/go/packages"
+
+	"github.com/mpyw/gormreuse"
+	"github.com/mpyw/gormreuse/internal"
+)
+
+// jsonDiagnostic is one line of -json output.
+type jsonDiagnostic struct {
+	File      string         `json:"file"`
+	Line      int            `json:"line"`
+	Column    int            `json:"column"`
+	EndLine   int            `json:"endLine,omitempty"`
+	EndColumn int            `json:"endColumn,omitempty"`
+	Category  string         `json:"category"`
+	Severity  string         `json:"severity"`
+	Message   string         `json:"message"`
+	Root      *jsonPosition  `json:"root,omitempty"`
+	Edits     []jsonTextEdit `json:"edits"`
+}
+
+type jsonPosition struct {
+	File   string `json:"file"`
+	Line   int    `json:"line"`
+	Column int    `json:"column"`
+}
+
+// jsonTextEdit is a suggested-fix edit replacing [start, end) with NewText.
+type jsonTextEdit struct {
+	Fix       string `json:"fix"`
+	File      string `json:"file"`
+	Line      int    `json:"line"`
+	Column    int    `json:"column"`
+	EndLine   int    `json:"endLine"`
+	EndColumn int    `json:"endColumn"`
+	NewText   string `json:"newText"`
+}
+
+// hasJSONFlag reports whether args request -json output. Like the flag
+// parser, it only looks at the flags before the first package pattern, and it
+// skips the values of analyzer flags without setting them.
+func hasJSONFlag(args []string) bool {
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		if arg == "--" || len(arg) < 2 || arg[0] != '-' {
+			return false
+		}
+		name, value, hasValue := strings.Cut(strings.TrimLeft(arg, "-"), "=")
+		if name == "json" {
+			return !hasValue || value == "true" || value == "1"
+		}
+		if f := gormreuse.Analyzer.Flags.Lookup(name); f != nil && !hasValue && !isBoolFlag(f) {
+			i++ // the value is the next argument
+		}
+	}
+	return false
+}
+
+// isBoolFlag reports whether f is a boolean flag, which takes no separate value.
+func isBoolFlag(f *flag.Flag) bool {
+	b, ok := f.Value.(interface{ IsBoolFlag() bool })
+	return ok && b.IsBoolFlag()
+}
+
+// newJSONFlagSet returns the flags accepted in -json mode: the analyzer's own
+// flags plus -json and -test.
+func newJSONFlagSet(output io.Writer) *flag.FlagSet {
+	fs := flag.NewFlagSet("gormreuse", flag.ContinueOnError)
+	fs.SetOutput(output)
+	fs.Bool("json", false, "emit one JSON object per diagnostic")
+	fs.Bool("test", true, "analyze test files")
+	gormreuse.Analyzer.Flags.VisitAll(func(f *flag.Flag) {
+		fs.Var(f.Value, f.Name, f.Usage)
+	})
+	return fs
+}
+
+// runJSON analyzes the packages named in args and writes one JSON object per
+// diagnostic to w, in internal.DiagnosticKey order. It returns the exit code: 0
+// once the packages were analyzed, whatever was reported, and 1 when they
+// could not be loaded or analyzed.
+func runJSON(args []string, w, errw io.Writer) int {
+	fs := newJSONFlagSet(errw)
+	if err := fs.Parse(args); err != nil {
+		return 1
+	}
+	tests := fs.Lookup("test").Value.(flag.Getter).Get().(bool)
+
+	cfg := &packages.Config{Mode: packages.LoadAllSyntax, Tests: tests}
+	pkgs, err := packages.Load(cfg, fs.Args()...)
+	if err != nil {
+		fmt.Fprintf(errw, "gormreuse: %v\n", err)
+		return 1
+	}
+	if packages.PrintErrors(pkgs) > 0 {
+		return 1
+	}
+
+	graph, err := checker.Analyze([]*analysis.Analyzer{gormreuse.Analyzer}, pkgs, nil)
+	if err != nil {
+		fmt.Fprintf(errw, "gormreuse: %v\n", err)
+		return 1
+	}
+
+	var diags []jsonDiagnostic
+	seen := make(map[string]bool)
+	for act := range graph.All() {
+		if !act.IsRoot || act.Analyzer != gormreuse.Analyzer {
+			continue
+		}
+		if act.Err != nil {
+			fmt.Fprintf(errw, "gormreuse: %s: %v\n", act.Package.PkgPath, act.Err)
+			return 1
+		}
+		for _, d := range act.Diagnostics {
+			jd := newJSONDiagnostic(act.Package.Fset, d)
+			// With -test, a package and its test variant report the same
+			// diagnostics for the shared files.
+			key := fmt.Sprintf("%s:%d:%d:%s", jd.File, jd.Line, jd.Column, jd.Message)
+			if seen[key] {
+				continue
+			}
+			seen[key] = true
+			diags = append(diags, jd)
+		}
+	}
+
+	slices.SortStableFunc(diags, func(a, b jsonDiagnostic) int {
+		return a.key().Compare(b.key())
+	})
+
+	enc := json.NewEncoder(w)
+	for _, d := range diags {
+		if err := enc.Encode(d); err != nil {
+			fmt.Fprintf(errw, "gormreuse: %v\n", err)
+			return 1
+		}
+	}
+	return 0
+}
+
+// key returns the key ordering d among the diagnostics of the output.
+func (d jsonDiagnostic) key() internal.DiagnosticKey {
+	return internal.DiagnosticKey{File: d.File, Line: d.Line, Column: d.Column, Category: d.Category, Message: d.Message}
+}
+
+// splitSeverity splits the "warning: " or "error: " prefix added by -severity
+// off message. Diagnostics without a prefix are errors, like every diagnostic
+// of a go/analysis driver.
+func splitSeverity(message string) (severity, rest string) {
+	for _, level := range []string{"error", "warning"} {
+		if rest, ok := strings.CutPrefix(message, level+": "); ok {
+			return level, rest
+		}
+	}
+	return "error", message
+}
+
+// newJSONDiagnostic converts d. The root is the "root defined here" related
+// location attached to reuse diagnostics, and the severity is the level that
+// -severity prefixed to the message, which is moved out of the message.
+func newJSONDiagnostic(fset *token.FileSet, d analysis.Diagnostic) jsonDiagnostic {
+	pos := fset.Position(d.Pos)
+	severity, message := splitSeverity(d.Message)
+	jd := jsonDiagnostic{
+		File:     pos.Filename,
+		Line:     pos.Line,
+		Column:   pos.Column,
+		Category: d.Category,
+		Severity: severity,
+		Message:  message,
+		Edits:    []jsonTextEdit{},
+	}
+	if d.End.IsValid() {
+		end := fset.Position(d.End)
+		jd.EndLine, jd.EndColumn = end.Line, end.Column
+	}
+	for _, r := range d.Related {
+		if r.Message == "root defined here" && r.Pos.IsValid() {
+			p := fset.Position(r.Pos)
+			jd.Root = &jsonPosition{File: p.Filename, Line: p.Line, Column: p.Column}
+			break
+		}
+	}
+	for _, fix := range d.SuggestedFixes {
+		for _, e := range fix.TextEdits {
+			start, end := fset.Position(e.Pos), fset.Position(e.Pos)
+			if e.End.IsValid() {
+				end = fset.Position(e.End)
+			}
+			jd.Edits = append(jd.Edits, jsonTextEdit{
+				Fix:       fix.Message,
+				File:      start.Filename,
+				Line:      start.Line,
+				Column:    start.Column,
+				EndLine:   end.Line,
+				EndColumn: end.Column,
+				NewText:   string(e.NewText),
+			})
+		}
+	}
+	return jd
+}
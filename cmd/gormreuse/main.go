@@ -8,14 +8,25 @@
 // Or as a vet tool:
 //
 //	go vet -vettool=$(which gormreuse) ./...
+//
+// With -json, each diagnostic is written as one JSON object per line with its
+// position, category, root definition and suggested-fix edits, for editor
+// integrations:
+//
+//	gormreuse -json ./...
 package main
 
 import (
+	"os"
+
 	"golang.org/x/tools/go/analysis/singlechecker"
 
 	"github.com/mpyw/gormreuse"
 )
 
 func main() {
+	if hasJSONFlag(os.Args[1:]) {
+		os.Exit(runJSON(os.Args[1:], os.Stdout, os.Stderr))
+	}
 	singlechecker.Main(gormreuse.Analyzer)
 }
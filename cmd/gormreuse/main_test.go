@@ -1,6 +1,9 @@
 package main_test
 
 import (
+	"bytes"
+	"encoding/json"
+	"flag"
 	"os"
 	"os/exec"
 	"path/filepath"
@@ -9,26 +12,14 @@ import (
 	"testing"
 )
 
+var update = flag.Bool("update", false, "update the -json golden files")
+
 // TestSmoke builds the vettool and runs it against the known-bad gormreuse
 // fixture package, asserting it exits non-zero and prints the expected
 // diagnostic (issue #77 item 4). This is the only coverage of main.go's wiring
 // of singlechecker; the analysis itself is covered by the analysistest suite.
 func TestSmoke(t *testing.T) {
-	if _, err := exec.LookPath("go"); err != nil {
-		t.Skip("go toolchain not available")
-	}
-
-	bin := filepath.Join(t.TempDir(), "gormreuse")
-	if out, err := exec.Command("go", "build", "-o", bin, ".").CombinedOutput(); err != nil {
-		t.Fatalf("build failed: %v\n%s", err, out)
-	}
-
-	// The fixtures live under the module's testdata GOPATH root.
-	_, file, _, ok := runtime.Caller(0)
-	if !ok {
-		t.Fatal("runtime.Caller failed")
-	}
-	testdata := filepath.Join(filepath.Dir(file), "..", "..", "testdata")
+	bin, testdata := buildTool(t)
 
 	cmd := exec.Command(bin, "gormreuse")
 	cmd.Dir = testdata
@@ -43,3 +34,104 @@ func TestSmoke(t *testing.T) {
 		t.Errorf("expected reuse diagnostic, got:\n%s", out)
 	}
 }
+
+// TestJSON runs the vettool with -json against the converge fixture package
+// and compares the output with testdata/converge.json.golden. Absolute paths
+// are rewritten relative to the module's testdata directory. Run with -update
+// to regenerate the golden file.
+func TestJSON(t *testing.T) {
+	bin, testdata := buildTool(t)
+
+	cmd := exec.Command(bin, "-json", "converge")
+	cmd.Dir = testdata
+	cmd.Env = append(os.Environ(), "GOPATH="+testdata, "GO111MODULE=off")
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		t.Fatalf("gormreuse -json failed: %v\n%s", err, stderr.Bytes())
+	}
+
+	abs, err := filepath.Abs(testdata)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := bytes.ReplaceAll(out, []byte(abs+string(filepath.Separator)), nil)
+
+	golden := filepath.Join("testdata", "converge.json.golden")
+	if *update {
+		if err := os.WriteFile(golden, got, 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	want, err := os.ReadFile(golden)
+	if err != nil {
+		t.Fatalf("Failed to read golden file: %v", err)
+	}
+	if !bytes.Equal(got, want) {
+		t.Errorf("-json output differs from %s (run with -update to regenerate):\ngot:\n%s\nwant:\n%s", golden, got, want)
+	}
+}
+
+// TestJSONSeverity verifies that -json reports the level set by -severity as
+// the severity field, without the message prefix, and error otherwise.
+func TestJSONSeverity(t *testing.T) {
+	bin, testdata := buildTool(t)
+
+	for _, tt := range []struct {
+		args []string
+		want string
+	}{
+		{[]string{"-json", "converge"}, "error"},
+		{[]string{"-json", "-severity=BRANCH=warning", "converge"}, "warning"},
+		{[]string{"-severity", "BRANCH=error", "-json", "converge"}, "error"},
+	} {
+		cmd := exec.Command(bin, tt.args...)
+		cmd.Dir = testdata
+		cmd.Env = append(os.Environ(), "GOPATH="+testdata, "GO111MODULE=off")
+		var stderr bytes.Buffer
+		cmd.Stderr = &stderr
+		out, err := cmd.Output()
+		if err != nil {
+			t.Fatalf("gormreuse %v failed: %v\n%s", tt.args, err, stderr.Bytes())
+		}
+
+		lines := bytes.Split(bytes.TrimSpace(out), []byte("\n"))
+		for _, line := range lines {
+			var d struct {
+				Category string `json:"category"`
+				Severity string `json:"severity"`
+				Message  string `json:"message"`
+			}
+			if err := json.Unmarshal(line, &d); err != nil {
+				t.Fatalf("gormreuse %v: invalid JSON line %s: %v", tt.args, line, err)
+			}
+			if d.Severity != tt.want {
+				t.Errorf("gormreuse %v: [%s] severity = %q, want %q", tt.args, d.Category, d.Severity, tt.want)
+			}
+			if strings.HasPrefix(d.Message, "warning: ") || strings.HasPrefix(d.Message, "error: ") {
+				t.Errorf("gormreuse %v: message keeps the severity prefix: %s", tt.args, d.Message)
+			}
+		}
+	}
+}
+
+// buildTool builds the vettool into a temporary directory and returns its path
+// and the module's testdata GOPATH root, where the fixtures live.
+func buildTool(t *testing.T) (bin, testdata string) {
+	t.Helper()
+	if _, err := exec.LookPath("go"); err != nil {
+		t.Skip("go toolchain not available")
+	}
+
+	bin = filepath.Join(t.TempDir(), "gormreuse")
+	if out, err := exec.Command("go", "build", "-o", bin, ".").CombinedOutput(); err != nil {
+		t.Fatalf("build failed: %v\n%s", err, out)
+	}
+
+	_, file, _, ok := runtime.Caller(0)
+	if !ok {
+		t.Fatal("runtime.Caller failed")
+	}
+	return bin, filepath.Join(filepath.Dir(file), "..", "..", "testdata")
+}
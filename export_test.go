@@ -0,0 +1,26 @@
+package gormreuse
+
+import "io"
+
+// ResetGormTypes clears the repeatable -gorm-type and -builder-type flags,
+// which Flags.Set can only append to.
+func ResetGormTypes() {
+	flagOptions.GormTypes = nil
+	flagOptions.BuilderTypes = nil
+}
+
+// SetFixDiffOutput redirects the -fix-diff patches to w and returns a function
+// restoring standard output.
+func SetFixDiffOutput(w io.Writer) (restore func()) {
+	old := fixDiffOutput
+	fixDiffOutput = w
+	return func() { fixDiffOutput = old }
+}
+
+// SetTraceValueOutput redirects the -trace-value traces to w and returns a
+// function restoring standard error.
+func SetTraceValueOutput(w io.Writer) (restore func()) {
+	old := traceValueOutput
+	traceValueOutput = w
+	return func() { traceValueOutput = old }
+}
@@ -0,0 +1,169 @@
+package gormreuse
+
+import (
+	"go/ast"
+	"go/types"
+	"reflect"
+
+	"golang.org/x/tools/go/analysis"
+
+	"github.com/mpyw/gormreuse/internal/directive"
+)
+
+// FactsAnalyzer exports a fact for each directive on an exported function or
+// method: a PureFact for //gormreuse:pure, an ImmutableReturnFact for
+// //gormreuse:immutable-return, an ImmutableParamFact for
+// //gormreuse:immutable-param, a FinisherFact for //gormreuse:finisher, a
+// SinkFact for //gormreuse:sink and an ImpureFact for //gormreuse:impure.
+//
+// go/analysis runs an analyzer with facts on every dependency of the analyzed
+// packages, so the facts live in this lightweight analyzer rather than in
+// Analyzer, which requires it: only the directives are read, and the SSA
+// analysis still runs on the analyzed packages alone. Analyzer classifies the
+// functions of imported packages by these facts instead of re-parsing their
+// source, which stripped builds may not ship.
+//
+// go/analysis also keeps the facts of an analyzer to itself, so an analyzer
+// needing the classification requires FactsAnalyzer and reads its Result:
+//
+//	res := pass.ResultOf[gormreuse.FactsAnalyzer].(*gormreuse.Result)
+//	if res.IsPure(fn) { ... }
+var FactsAnalyzer = &analysis.Analyzer{
+	Name:       "gormreusefacts",
+	Doc:        "exports the gormreuse directive classification of functions as facts",
+	Run:        runFacts,
+	FactTypes:  []analysis.Fact{new(PureFact), new(ImmutableReturnFact), new(ImmutableParamFact), new(FinisherFact), new(SinkFact), new(ImpureFact)},
+	ResultType: reflect.TypeFor[*Result](),
+}
+
+// PureFact is exported for an exported function or method marked
+// //gormreuse:pure, which does not pollute its *gorm.DB arguments.
+type PureFact struct{}
+
+// AFact implements analysis.Fact.
+func (*PureFact) AFact() {}
+
+func (*PureFact) String() string { return "pure" }
+
+// ImmutableReturnFact is exported for an exported function or method marked
+// //gormreuse:immutable-return, whose *gorm.DB result is safe to branch.
+type ImmutableReturnFact struct{}
+
+// AFact implements analysis.Fact.
+func (*ImmutableReturnFact) AFact() {}
+
+func (*ImmutableReturnFact) String() string { return "immutable-return" }
+
+// ImmutableParamFact is exported for an exported function or method marked
+// //gormreuse:immutable-param, whose *gorm.DB parameters must be immutable.
+type ImmutableParamFact struct{}
+
+// AFact implements analysis.Fact.
+func (*ImmutableParamFact) AFact() {}
+
+func (*ImmutableParamFact) String() string { return "immutable-param" }
+
+// FinisherFact is exported for an exported method marked //gormreuse:finisher,
+// which finishes the *gorm.DB its receiver holds.
+type FinisherFact struct{}
+
+// AFact implements analysis.Fact.
+func (*FinisherFact) AFact() {}
+
+func (*FinisherFact) String() string { return "finisher" }
+
+// SinkFact is exported for an exported function or method marked
+// //gormreuse:sink, which finishes its *gorm.DB arguments.
+type SinkFact struct{}
+
+// AFact implements analysis.Fact.
+func (*SinkFact) AFact() {}
+
+func (*SinkFact) String() string { return "sink" }
+
+// ImpureFact is exported for an exported function or method marked
+// //gormreuse:impure, which pollutes its *gorm.DB arguments even under
+// -assume-pure-helpers.
+type ImpureFact struct{}
+
+// AFact implements analysis.Fact.
+func (*ImpureFact) AFact() {}
+
+func (*ImpureFact) String() string { return "impure" }
+
+// Result is the result of FactsAnalyzer. It classifies the exported functions
+// of the analyzed package and of the packages it imports.
+type Result struct {
+	importFact func(types.Object, analysis.Fact) bool
+}
+
+// IsPure reports whether fn is marked //gormreuse:pure (see PureFact).
+func (r *Result) IsPure(fn *types.Func) bool {
+	return r.importFact(fn.Origin(), new(PureFact))
+}
+
+// IsImmutableReturn reports whether fn is marked //gormreuse:immutable-return
+// (see ImmutableReturnFact).
+func (r *Result) IsImmutableReturn(fn *types.Func) bool {
+	return r.importFact(fn.Origin(), new(ImmutableReturnFact))
+}
+
+// IsImmutableParam reports whether fn is marked //gormreuse:immutable-param
+// (see ImmutableParamFact).
+func (r *Result) IsImmutableParam(fn *types.Func) bool {
+	return r.importFact(fn.Origin(), new(ImmutableParamFact))
+}
+
+// IsFinisher reports whether fn is marked //gormreuse:finisher (see
+// FinisherFact).
+func (r *Result) IsFinisher(fn *types.Func) bool {
+	return r.importFact(fn.Origin(), new(FinisherFact))
+}
+
+// IsSink reports whether fn is marked //gormreuse:sink (see SinkFact).
+func (r *Result) IsSink(fn *types.Func) bool {
+	return r.importFact(fn.Origin(), new(SinkFact))
+}
+
+// IsImpure reports whether fn is marked //gormreuse:impure (see ImpureFact).
+func (r *Result) IsImpure(fn *types.Func) bool {
+	return r.importFact(fn.Origin(), new(ImpureFact))
+}
+
+func runFacts(pass *analysis.Pass) (any, error) {
+	sets := []struct {
+		funcs   *directive.DirectiveFuncSet
+		newFact func() analysis.Fact
+	}{
+		{directive.NewPureFuncSet(pass.Fset, pass.TypesInfo, nil), func() analysis.Fact { return new(PureFact) }},
+		{directive.NewImmutableReturnFuncSet(pass.Fset, pass.TypesInfo, nil), func() analysis.Fact { return new(ImmutableReturnFact) }},
+		{directive.NewImmutableParamFuncSet(pass.Fset, pass.TypesInfo, nil), func() analysis.Fact { return new(ImmutableParamFact) }},
+		{directive.NewFinisherFuncSet(pass.Fset, pass.TypesInfo, nil), func() analysis.Fact { return new(FinisherFact) }},
+		{directive.NewSinkFuncSet(pass.Fset, pass.TypesInfo, nil), func() analysis.Fact { return new(SinkFact) }},
+		{directive.NewImpureFuncSet(pass.Fset, pass.TypesInfo, nil), func() analysis.Fact { return new(ImpureFact) }},
+	}
+	for _, file := range pass.Files {
+		if !directive.MayHaveDirectives(file) {
+			continue
+		}
+		for _, set := range sets {
+			set.funcs.AddFile(file)
+		}
+		for _, decl := range file.Decls {
+			fd, ok := decl.(*ast.FuncDecl)
+			if !ok || !fd.Name.IsExported() {
+				continue
+			}
+			obj, ok := pass.TypesInfo.Defs[fd.Name].(*types.Func)
+			if !ok {
+				continue
+			}
+			for _, set := range sets {
+				if set.funcs.DeclHasDirective(fd) {
+					pass.ExportObjectFact(obj, set.newFact())
+				}
+			}
+		}
+	}
+	return &Result{importFact: pass.ImportObjectFact}, nil
+}
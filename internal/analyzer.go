@@ -36,25 +36,146 @@ package internal
 
 import (
 	"fmt"
+	"go/ast"
 	"go/token"
+	"io"
 	"os"
+	"slices"
 
 	"golang.org/x/tools/go/analysis"
 	"golang.org/x/tools/go/analysis/passes/buildssa"
+	"golang.org/x/tools/go/ast/astutil"
 	"golang.org/x/tools/go/ssa"
 
+	"github.com/mpyw/gormreuse/internal/debug"
 	"github.com/mpyw/gormreuse/internal/directive"
 	"github.com/mpyw/gormreuse/internal/fix"
 	ssautil "github.com/mpyw/gormreuse/internal/ssa"
+	"github.com/mpyw/gormreuse/internal/ssa/handler"
 	"github.com/mpyw/gormreuse/internal/ssa/pollution"
 	"github.com/mpyw/gormreuse/internal/ssa/purity"
 	"github.com/mpyw/gormreuse/internal/ssa/tracer"
+	"github.com/mpyw/gormreuse/internal/typeutil"
 )
 
 // =============================================================================
 // Entry Point
 // =============================================================================
 
+// Options holds optional behavior configured through analyzer flags.
+// The zero value reproduces the default analysis.
+type Options struct {
+	// RootGraph, when non-nil, receives a Graphviz DOT digraph of the mutable
+	// roots, branches and pollution events of the package (-report-root-graph).
+	// Write errors are not reported; callers pass an in-memory buffer and
+	// persist it themselves.
+	RootGraph io.Writer
+
+	// RootList, when non-nil, receives a line of JSON listing the mutable roots
+	// of each function of the package with their first use and reuse sites
+	// (-list-roots-json). Write errors are not reported, as for RootGraph.
+	RootList io.Writer
+
+	// Summary, when non-nil, receives a table counting the diagnostics of the
+	// package by category and by enclosing function, with their total
+	// (-summary). Write errors are not reported, as for RootGraph.
+	Summary io.Writer
+
+	// SummaryOnly drops the diagnostics once Summary has counted them
+	// (-summary-only).
+	SummaryOnly bool
+
+	// Baseline, when non-nil, drops the diagnostics it lists, so only new ones
+	// are reported (-baseline).
+	Baseline *Baseline
+
+	// BaselineOut, when non-nil, records the baseline entries of the
+	// diagnostics of the package instead of their report, to be merged into
+	// the baseline file once the analysis completes (-write-baseline).
+	BaselineOut *BaselineUpdate
+
+	// TraceValue, when non-nil, makes TraceValueOut receive how the *gorm.DB
+	// receiver of each gorm method call on its line resolves to its roots,
+	// step by step (-trace-value). Write errors are not reported, as for
+	// RootGraph.
+	TraceValue    *debug.Target
+	TraceValueOut io.Writer
+
+	// UnhandledSSA, when non-nil, logs the SSA instruction kinds involving a
+	// *gorm.DB that the analysis does not account for (-warn-unhandled-ssa).
+	UnhandledSSA *handler.UnhandledLog
+
+	// Severity maps diagnostic categories to a level, "error" or "warning",
+	// which prefixes the message of each diagnostic of that category, e.g.
+	// "warning: *gorm.DB reused: ..." (-severity). go/analysis has no severity
+	// of its own, so the prefix is how drivers and -json tell levels apart.
+	// Unlisted categories are reported unchanged.
+	Severity map[string]string
+
+	// EnableOnly, when non-empty, is the set of diagnostic categories to report
+	// (-enable-only); diagnostics of other categories are dropped. When it holds
+	// PURE alone, only the pure contract validation runs and the reuse analysis
+	// is skipped.
+	EnableOnly map[string]bool
+
+	// StrictIgnoreFile reports //gormreuse:ignore-file directives of files with
+	// no diagnostic to suppress (-strict-ignore-file).
+	StrictIgnoreFile bool
+
+	// RequireIgnoreReason reports //gormreuse:ignore directives giving no
+	// reason (-require-ignore-reason).
+	RequireIgnoreReason bool
+
+	// CoalesceRoots lists, on a reuse diagnostic whose receiver merges several
+	// polluted roots through a Phi, every such root as related information
+	// (-coalesce-roots). When false only the root of the reported violation is
+	// listed. Either way a position is reported once.
+	CoalesceRoots bool
+
+	// ChainThreshold is the number of method calls a reused chain must exceed
+	// for the fix making its root immutable to be offered as recommended
+	// (-chain-threshold). 0 disables it.
+	ChainThreshold int
+
+	// FixComplexity appends the estimated fix complexity of each reuse
+	// violation to its message, e.g. "[fix: moderate]" (-fix-complexity).
+	FixComplexity bool
+
+	// TestHelperPkgs, when non-empty, suppresses reuse violations whose use is
+	// nested inside a call to a function of one of these packages, such as
+	// require.NoError(t, tx.Create(&u).Error) (-no-test-helpers).
+	TestHelperPkgs []string
+
+	// SuggestPure reports the unannotated helpers whose *gorm.DB parameter the
+	// pure validator proves is never polluted, suggesting //gormreuse:pure
+	// (-suggest-pure).
+	SuggestPure bool
+
+	// WarnUnvalidatedPure reports the first call in the package of each
+	// //gormreuse:pure function of another package whose source cannot be
+	// read, so its contract is trusted by its fact (-warn-unvalidated-pure).
+	WarnUnvalidatedPure bool
+
+	// StrictInterface reports each conversion of a mutable *gorm.DB to an
+	// interface as an ESCAPE diagnostic at the conversion (-strict-interface).
+	StrictInterface bool
+
+	// AssumePureHelpers trusts non-gorm functions receiving a *gorm.DB as
+	// pure unless marked //gormreuse:impure or //gormreuse:sink
+	// (-assume-pure-helpers).
+	AssumePureHelpers bool
+
+	// Parallel is the number of functions whose SSA analysis runs at once
+	// (-parallel). 0 uses GOMAXPROCS and 1 analyzes them one at a time. The
+	// diagnostics are the same either way.
+	Parallel int
+
+	// GormTypes, when non-nil, recognizes additional named types as *gorm.DB,
+	// such as a vendored copy or a wrapper (-gorm-type). nil matches only
+	// gorm.io/gorm.DB.
+	GormTypes *typeutil.Matcher
+}
+
 // RunSSA performs SSA-based analysis for GORM *gorm.DB reuse detection.
 //
 // This is the main entry point called from the public analyzer. It processes
@@ -67,15 +188,16 @@ import (
 //  4. Run SSA analysis and collect violations
 //  5. Report violations (unless suppressed by line-level ignore)
 //  6. Report unused ignore directives
-func RunSSA(
-	pass *analysis.Pass,
-	ssaInfo *buildssa.SSA,
-	ignoreMaps map[string]directive.IgnoreMap,
-	funcIgnores map[string]map[token.Pos]directive.FunctionIgnoreEntry,
-	pureFuncs, immutableReturnFuncs, immutableParamFuncs *directive.DirectiveFuncSet,
-	immutableInputSet *directive.ImmutableInputSet,
-	skipFiles map[string]bool,
-) {
+//
+// Diagnostics are emitted in DiagnosticKey order: by file, line and column,
+// then by category and message for diagnostics sharing a position.
+func RunSSA(pass *analysis.Pass, ssaInfo *buildssa.SSA, dirs *Directives, opts Options) {
+	// Diagnostics are filtered, then buffered and emitted in DiagnosticKey
+	// order once the analysis completes, whatever order functions were
+	// visited in.
+	pass, unfiltered, done := reportPipeline(pass, ssaInfo, dirs, opts)
+	defer done()
+
 	// Share a single reported map across all functions to deduplicate
 	// violations across parent functions and their closures.
 	// When a closure accesses a parent scope variable, the same violation
@@ -86,92 +208,63 @@ func RunSSA(
 	// across different violations that suggest the same edit.
 	globalSuggestedEdits := make(map[editKey]bool)
 
-	// skip reports whether a function is in an excluded file or wholly ignored.
-	// When markUsed is true it also records the function-level ignore directive
-	// as used; that side effect must happen exactly once per function, so only
-	// the analysis pass passes markUsed=true.
-	skip := func(fn *ssa.Function, markUsed bool) bool {
-		pos := fn.Pos()
-		if !pos.IsValid() {
-			return true
-		}
-		filename := pass.Fset.Position(pos).Filename
-		if skipFiles[filename] {
-			return true
-		}
-		if funcIgnoreSet, ok := funcIgnores[filename]; ok {
-			if entry, ignored := funcIgnoreSet[fn.Pos()]; ignored {
-				if markUsed {
-					if ignoreMap := ignoreMaps[filename]; ignoreMap != nil {
-						ignoreMap.MarkUsed(entry.DirectiveLine)
-					}
-				}
-				return true
-			}
-		}
-		return false
-	}
+	skip := dirs.skip
 
 	// PASS 1: validate pure function contracts and collect the functions that
 	// definitively leaked their argument. Such a //gormreuse:pure function must
 	// not be trusted as pure at its call sites (issue #66), so this must complete
 	// for ALL functions before the analysis pass runs — a caller may be visited
 	// before its callee.
-	failedPure := make(map[*ssa.Function]bool)
-	for _, fn := range ssaInfo.SrcFuncs {
-		if skip(fn, false) {
-			continue
-		}
-		if pureFuncs != nil && pureFuncs.Contains(fn) {
-			recoverPerFunction(fn, func() {
-				for _, v := range purity.ValidateFunction(fn, pureFuncs) {
-					pass.Reportf(v.Pos, "%s", v.Message)
-					// Only a definitive escape revokes pure-trust at call sites;
-					// conservative func-arg violations do not (avoids FP cascades).
-					if v.Leak {
-						failedPure[fn] = true
-					}
-				}
-			})
-		}
+	failedPure := validatePureContracts(pass, ssaInfo, dirs.PureFuncs, opts.GormTypes, skip)
+
+	if opts.SuggestPure {
+		suggestPureFunctions(pass, ssaInfo, dirs.PureFuncs, dirs.FinisherFuncs, dirs.SinkFuncs, opts.GormTypes, skip)
+	}
+	if opts.WarnUnvalidatedPure {
+		warnUnvalidatedPure(pass, ssaInfo, dirs.PureFuncs, skip)
+	}
+
+	// With only PURE enabled, nothing below reports a kept diagnostic.
+	if opts.pureOnly() {
+		return
 	}
 
 	// Collect Scopes/Preload callbacks once: their *gorm.DB parameter receives a
 	// mid-chain (clone==0) value, so reuse inside them must be detected (#60).
-	scopesCallbacks := tracer.CollectScopesCallbacks(ssaInfo.SrcFuncs)
+	scopesCallbacks := tracer.CollectScopesCallbacks(ssaInfo.SrcFuncs, opts.GormTypes)
 
 	// Collect immutable callbacks once: gorm's Transaction/Connection/FindInBatches
 	// hand their callback a fresh forkable (clone>0) handle, and a user function
 	// declared //gormreuse:immutable-input(cb) promises the same for cb. Their
 	// callback's tx parameter is therefore exempt from the Phase 1b
 	// mutable-by-default treatment (#60 SC103, #61, #62 case 2.2).
-	immutableCallbacks := tracer.CollectImmutableCallbacks(ssaInfo.SrcFuncs)
-	tracer.CollectImmutableInputCallbacks(ssaInfo.SrcFuncs, immutableInputSet, immutableCallbacks)
+	immutableCallbacks := tracer.CollectImmutableCallbacks(ssaInfo.SrcFuncs, opts.GormTypes)
+	tracer.CollectImmutableInputCallbacks(ssaInfo.SrcFuncs, dirs.ImmutableInputs, immutableCallbacks)
 
 	// Enforce the body-side immutable-input contract (#62 cases 2.3/2.4) and
 	// report unused immutable-input directives (U1-U3). Uses a tracer with the
 	// full context so FindMutableRoot classifies immutable sources correctly.
-	inputTracer := tracer.New(pureFuncs, immutableReturnFuncs, immutableParamFuncs, failedPure, scopesCallbacks, immutableCallbacks)
+	inputTracer := tracer.New(dirs.PureFuncs, dirs.ImmutableReturnFuncs, dirs.ImmutableParamFuncs, dirs.FinisherFuncs, dirs.SinkFuncs, failedPure, scopesCallbacks, immutableCallbacks, opts.GormTypes)
 	for _, fn := range ssaInfo.SrcFuncs {
 		if skip(fn, false) {
 			continue
 		}
 		recoverPerFunction(fn, func() {
-			for _, v := range purity.ValidateImmutableInputs(fn, immutableInputSet, inputTracer) {
-				pass.Reportf(v.Pos, "%s", v.Message)
+			for _, v := range purity.ValidateImmutableInputs(fn, dirs.ImmutableInputs, inputTracer) {
+				report(pass, v.Pos, pollution.KindContract, v.Message)
 			}
 		})
 	}
-	if immutableInputSet != nil {
-		for _, u := range immutableInputSet.GetUnused() {
-			pass.Reportf(u.Pos, "%s", u.Reason)
+	if dirs.ImmutableInputs != nil {
+		for _, u := range dirs.ImmutableInputs.GetUnused() {
+			report(pass, u.Pos, pollution.KindUnusedDirective, u.Reason)
 		}
 	}
 
 	// Enforce the body-side immutable-return contract. Reuses inputTracer, which
 	// carries the full pass context so other immutable-return / immutable-param
 	// functions are classified correctly.
-	reportImmutableReturnViolations(pass, ssaInfo, immutableReturnFuncs, inputTracer, skip)
+	reportImmutableReturnViolations(pass, ssaInfo, dirs.ImmutableReturnFuncs, inputTracer, skip)
 
 	// TEMPORARY (GORM bug go-gorm/gorm#7592): warn on Session/WithContext/Debug
 	// inside Scopes callbacks. Deletable by removing scopes_session_warning.go and
@@ -180,15 +273,15 @@ func RunSSA(
 		if skip(fn, false) {
 			continue
 		}
-		for _, w := range validateScopesCallback(fn) {
-			pass.Reportf(w.Pos, "%s", w.Message)
+		for _, w := range validateScopesCallback(fn, opts.GormTypes) {
+			report(pass, w.Pos, pollution.KindScopesSession, w.Message)
 		}
 	}
 
 	// Share a single fix generator across all violations (it caches AST
 	// inspectors). It needs scopesCallbacks to withhold the immutable-param fix on
 	// Scopes/Preload callbacks, whose parameters cannot be exempted (stage 2c).
-	fixGen := fix.New(pass, scopesCallbacks)
+	fixGen := fix.New(pass, scopesCallbacks, opts.GormTypes, opts.ChainThreshold)
 
 	// Determine which //gormreuse:immutable-param functions actually rely on
 	// immutability — they would reuse a *gorm.DB parameter if it were treated as
@@ -196,33 +289,199 @@ func RunSSA(
 	// contract check (stage 2b, passed into the checker below) and, by its
 	// complement, redundant-directive detection (a directive whose function does
 	// NOT reuse a param suppresses nothing).
-	needsImmutableParam := computeNeedsImmutableParam(ssaInfo, immutableParamFuncs, pureFuncs, immutableReturnFuncs, failedPure, scopesCallbacks, immutableCallbacks, skip)
+	needsImmutableParam := computeNeedsImmutableParam(ssaInfo, dirs.ImmutableParamFuncs, dirs.PureFuncs, dirs.ImmutableReturnFuncs, dirs.FinisherFuncs, dirs.SinkFuncs, failedPure, scopesCallbacks, immutableCallbacks, opts.GormTypes, skip)
+
+	testHelpers := testHelperPkgSet(opts.TestHelperPkgs)
 
-	// PASS 2: run SSA reuse analysis.
+	var graph *rootGraph
+	if opts.RootGraph != nil {
+		graph = newRootGraph(pass.Fset)
+	}
+	var list *rootList
+	if opts.RootList != nil {
+		list = newRootList(pass.Fset)
+	}
+
+	analysisOpts := ssautil.Options{
+		PureFuncs:            dirs.PureFuncs,
+		ImmutableReturnFuncs: dirs.ImmutableReturnFuncs,
+		ImmutableParamFuncs:  dirs.ImmutableParamFuncs,
+		FinisherFuncs:        dirs.FinisherFuncs,
+		SinkFuncs:            dirs.SinkFuncs,
+		FailedPure:           failedPure,
+		ScopesCallbacks:      scopesCallbacks,
+		ImmutableCallbacks:   immutableCallbacks,
+		NeedsImmutableParam:  needsImmutableParam,
+		GormTypes:            opts.GormTypes,
+		StrictInterface:      opts.StrictInterface,
+		AssumePureHelpers:    opts.AssumePureHelpers,
+		ImpureFuncs:          dirs.ImpureFuncs,
+		UnhandledSSA:         opts.UnhandledSSA,
+	}
+
+	// PASS 2: run SSA reuse analysis. Functions are analyzed concurrently
+	// (-parallel), then reported one after another in SrcFuncs order, so the
+	// shared deduplication maps see them in the same order as a sequential run.
+	var funcs []*ssa.Function
 	for _, fn := range ssaInfo.SrcFuncs {
-		if skip(fn, true) {
-			continue
+		if !skip(fn, true) {
+			funcs = append(funcs, fn)
 		}
+	}
+	if opts.TraceValue != nil {
+		traceValues(opts.TraceValueOut, pass.Fset, funcs, analysisOpts, *opts.TraceValue)
+	}
+	results := analyzeFunctions(funcs, analysisOpts, opts.Parallel, graph != nil || list != nil)
+	for i, fn := range funcs {
+		chk := newChecker(pass, dirs.IgnoreMaps[pass.Fset.Position(fn.Pos()).Filename], analysisOpts, globalReported, globalSuggestedEdits, fixGen)
+		chk.allowReuseMap = dirs.AllowReuseMaps[pass.Fset.Position(fn.Pos()).Filename]
+		chk.graph = graph
+		chk.list = list
+		chk.fixComplexity = opts.FixComplexity
+		chk.coalesceRoots = opts.CoalesceRoots
+		chk.testHelperPkgs = testHelpers
+		recoverPerFunction(fn, func() { chk.checkFunction(fn, results[i]) })
+	}
 
-		chk := newChecker(pass, ignoreMaps[pass.Fset.Position(fn.Pos()).Filename], pureFuncs, immutableReturnFuncs, immutableParamFuncs, failedPure, scopesCallbacks, immutableCallbacks, needsImmutableParam, globalReported, globalSuggestedEdits, fixGen)
-		recoverPerFunction(fn, func() { chk.checkFunction(fn) })
+	if graph != nil {
+		graph.writeDOT(opts.RootGraph, pass.Pkg.Path())
+	}
+	if list != nil {
+		list.writeJSON(opts.RootList, pass.Pkg.Path())
 	}
 
 	// Report immutable-param directives that are signature-valid but have no
 	// effect (no *gorm.DB parameter is reused).
-	reportRedundantImmutableParam(pass, ssaInfo, immutableParamFuncs, pureFuncs, needsImmutableParam, skip)
+	reportRedundantImmutableParam(pass, ssaInfo, dirs.ImmutableParamFuncs, dirs.PureFuncs, needsImmutableParam, opts.GormTypes, skip)
 
-	// Report unused ignore directives
-	for _, ignoreMap := range ignoreMaps {
-		if ignoreMap == nil {
+	dirs.reportUnusedIgnores(pass, unfiltered, opts)
+	reportUnusedDirectiveFuncs(pass, dirs.PureFuncs, dirs.ImmutableReturnFuncs, dirs.ImmutableParamFuncs, dirs.FinisherFuncs, dirs.SinkFuncs, dirs.ImpureFuncs)
+}
+
+// reportPipeline returns a copy of pass whose Report passes each diagnostic
+// through the ignore-file filter, the enabled categories and severities, the
+// baseline and the summary, in that order, and buffers it to be emitted in
+// DiagnosticKey order. unfiltered is the copy before the ignore-file filter.
+// done writes the summary and baseline and emits the diagnostics; it must be
+// called once the analysis completes.
+func reportPipeline(pass *analysis.Pass, ssaInfo *buildssa.SSA, dirs *Directives, opts Options) (filtered, unfiltered *analysis.Pass, done func()) {
+	pass, flush := sortReports(pass)
+	var writes []func()
+
+	// The summary counts what would be reported, past every filter below.
+	if opts.Summary != nil {
+		var sum *summary
+		pass, sum = summarize(pass, ssaInfo.SrcFuncs, opts.SummaryOnly)
+		writes = append(writes, func() { sum.write(opts.Summary, pass.Pkg.Path()) })
+	}
+
+	// The baseline sees the diagnostics that would be reported, and the
+	// summary counts the new ones only.
+	if opts.BaselineOut != nil {
+		pass = opts.BaselineOut.record(pass, ssaInfo.SrcFuncs, dirs.SkipFiles)
+	} else if opts.Baseline != nil {
+		pass = opts.Baseline.filter(pass, ssaInfo.SrcFuncs)
+	}
+
+	// Every diagnostic of this pass goes through pass.Report, so dropping the
+	// ones positioned in //gormreuse:ignore-file files there covers them all.
+	unfiltered = filterCategories(applySeverity(pass, opts.Severity), opts.EnableOnly)
+	done = func() {
+		for i := len(writes) - 1; i >= 0; i-- {
+			writes[i]()
+		}
+		flush()
+	}
+	return filterIgnoredFiles(unfiltered, dirs.IgnoreFiles), unfiltered, done
+}
+
+// pureOnly reports whether PURE is the only enabled category, so the analysis
+// can stop after validating pure contracts.
+func (o Options) pureOnly() bool {
+	return len(o.EnableOnly) == 1 && o.EnableOnly[pollution.KindPure.String()]
+}
+
+// validatePureContracts reports the //gormreuse:pure functions whose bodies
+// pollute their *gorm.DB argument and returns those that definitively leaked
+// it. It depends on no other pass, so it runs alone under -enable-only=PURE.
+func validatePureContracts(
+	pass *analysis.Pass,
+	ssaInfo *buildssa.SSA,
+	pureFuncs *directive.DirectiveFuncSet,
+	gormTypes *typeutil.Matcher,
+	skip func(*ssa.Function, bool) bool,
+) map[*ssa.Function]bool {
+	failedPure := make(map[*ssa.Function]bool)
+	if pureFuncs == nil {
+		return failedPure
+	}
+	for _, fn := range ssaInfo.SrcFuncs {
+		if skip(fn, false) || !pureFuncs.Contains(fn) {
 			continue
 		}
-		for _, pos := range ignoreMap.GetUnusedIgnores() {
-			pass.Reportf(pos, "unused gormreuse:ignore directive")
+		recoverPerFunction(fn, func() {
+			for _, v := range purity.ValidateFunction(fn, pureFuncs, gormTypes) {
+				report(pass, v.Pos, pollution.KindPure, v.Message)
+				// Only a definitive escape revokes pure-trust at call sites;
+				// conservative func-arg violations do not (avoids FP cascades).
+				if v.Leak {
+					failedPure[fn] = true
+				}
+			}
+		})
+	}
+	return failedPure
+}
+
+// filterCategories returns a copy of pass whose Report drops diagnostics of
+// categories missing from enabled. It returns pass itself when enabled is
+// empty.
+func filterCategories(pass *analysis.Pass, enabled map[string]bool) *analysis.Pass {
+	if len(enabled) == 0 {
+		return pass
+	}
+	filtered := *pass
+	filtered.Report = func(d analysis.Diagnostic) {
+		if enabled[d.Category] {
+			pass.Report(d)
+		}
+	}
+	return &filtered
+}
+
+// applySeverity returns a copy of pass whose Report prefixes the message of
+// each diagnostic with the level its category is mapped to in severity. It
+// returns pass itself when no category is mapped.
+func applySeverity(pass *analysis.Pass, severity map[string]string) *analysis.Pass {
+	if len(severity) == 0 {
+		return pass
+	}
+	leveled := *pass
+	leveled.Report = func(d analysis.Diagnostic) {
+		if level, ok := severity[d.Category]; ok {
+			d.Message = level + ": " + d.Message
 		}
+		pass.Report(d)
 	}
+	return &leveled
+}
 
-	reportUnusedDirectiveFuncs(pass, pureFuncs, immutableReturnFuncs, immutableParamFuncs)
+// filterIgnoredFiles returns a copy of pass whose Report drops diagnostics
+// positioned in a file with a //gormreuse:ignore-file directive, marking the
+// directive used. It returns pass itself when no file has the directive.
+func filterIgnoredFiles(pass *analysis.Pass, ignoreFiles map[string]*directive.IgnoreFile) *analysis.Pass {
+	if len(ignoreFiles) == 0 {
+		return pass
+	}
+	filtered := *pass
+	filtered.Report = func(d analysis.Diagnostic) {
+		if f := ignoreFiles[pass.Fset.Position(d.Pos).Filename]; f != nil {
+			f.MarkUsed()
+			return
+		}
+		pass.Report(d)
+	}
+	return &filtered
 }
 
 // reportImmutableReturnViolations enforces the body-side immutable-return
@@ -243,7 +502,7 @@ func reportImmutableReturnViolations(
 		}
 		recoverPerFunction(fn, func() {
 			for _, v := range purity.ValidateImmutableReturn(fn, immutableReturnFuncs, rt) {
-				pass.Reportf(v.Pos, "%s", v.Message)
+				report(pass, v.Pos, pollution.KindContract, v.Message)
 			}
 		})
 	}
@@ -275,8 +534,9 @@ func reportImmutableReturnViolations(
 // pattern; suppress with //gormreuse:ignore if intended.
 func computeNeedsImmutableParam(
 	ssaInfo *buildssa.SSA,
-	immutableParamFuncs, pureFuncs, immutableReturnFuncs *directive.DirectiveFuncSet,
+	immutableParamFuncs, pureFuncs, immutableReturnFuncs, finisherFuncs, sinkFuncs *directive.DirectiveFuncSet,
 	failedPure, scopesCallbacks, immutableCallbacks map[*ssa.Function]bool,
+	gormTypes *typeutil.Matcher,
 	skip func(*ssa.Function, bool) bool,
 ) map[*ssa.Function]bool {
 	needs := make(map[*ssa.Function]bool)
@@ -287,7 +547,7 @@ func computeNeedsImmutableParam(
 		if skip(fn, false) || !immutableParamFuncs.Contains(fn) {
 			continue
 		}
-		if fn.Signature == nil || !directive.HasGormDBParameter(fn.Signature) {
+		if fn.Signature == nil || !directive.HasGormDBParameter(fn.Signature, gormTypes) {
 			continue
 		}
 		if pureFuncs != nil && pureFuncs.Contains(fn) {
@@ -295,8 +555,17 @@ func computeNeedsImmutableParam(
 		}
 		recoverPerFunction(fn, func() {
 			// Counterfactual: analyze fn with its parameters treated as mutable.
-			cf := ssautil.NewAnalyzer(fn, pureFuncs, immutableReturnFuncs, nil, failedPure, scopesCallbacks, immutableCallbacks, nil)
-			for _, v := range cf.Analyze() {
+			cf := ssautil.Options{
+				PureFuncs:            pureFuncs,
+				ImmutableReturnFuncs: immutableReturnFuncs,
+				FinisherFuncs:        finisherFuncs,
+				SinkFuncs:            sinkFuncs,
+				FailedPure:           failedPure,
+				ScopesCallbacks:      scopesCallbacks,
+				ImmutableCallbacks:   immutableCallbacks,
+				GormTypes:            gormTypes,
+			}
+			for _, v := range ssautil.AnalyzeFunction(fn, cf) {
 				if p, ok := v.Root.(*ssa.Parameter); ok && p.Parent() == fn {
 					needs[fn] = true
 					break
@@ -326,6 +595,7 @@ func reportRedundantImmutableParam(
 	ssaInfo *buildssa.SSA,
 	immutableParamFuncs, pureFuncs *directive.DirectiveFuncSet,
 	needsImmutableParam map[*ssa.Function]bool,
+	gormTypes *typeutil.Matcher,
 	skip func(*ssa.Function, bool) bool,
 ) {
 	if immutableParamFuncs == nil {
@@ -335,24 +605,24 @@ func reportRedundantImmutableParam(
 		if skip(fn, false) || !immutableParamFuncs.Contains(fn) {
 			continue
 		}
-		if fn.Signature == nil || !directive.HasGormDBParameter(fn.Signature) {
+		if fn.Signature == nil || !directive.HasGormDBParameter(fn.Signature, gormTypes) {
 			continue // signature-invalid: handled by reportUnusedDirectiveFuncs
 		}
 		if pureFuncs != nil && pureFuncs.Contains(fn) {
 			continue // pure ⇒ param never branched ⇒ redundant by construction (not flagged)
 		}
 		if !needsImmutableParam[fn] {
-			pass.Reportf(fn.Pos(), "redundant gormreuse:immutable-param directive: no *gorm.DB parameter is reused")
+			report(pass, fn.Pos(), pollution.KindUnusedDirective, "redundant gormreuse:immutable-param directive: no *gorm.DB parameter is reused")
 		}
 	}
 }
 
-// reportUnusedDirectiveFuncs reports pure / immutable-return / immutable-param
-// directives that matched no valid function. For combined directives (e.g.
+// reportUnusedDirectiveFuncs reports pure / immutable-return / immutable-param /
+// finisher / sink / impure directives that matched no valid function. For combined directives (e.g.
 // //gormreuse:pure,immutable-return,immutable-param) a directive at a position
 // is "used" if ANY of its combined siblings is used, so each set is suppressed
 // when another set reports that position as used.
-func reportUnusedDirectiveFuncs(pass *analysis.Pass, pureFuncs, immutableReturnFuncs, immutableParamFuncs *directive.DirectiveFuncSet) {
+func reportUnusedDirectiveFuncs(pass *analysis.Pass, pureFuncs, immutableReturnFuncs, immutableParamFuncs, finisherFuncs, sinkFuncs, impureFuncs *directive.DirectiveFuncSet) {
 	usedByOther := func(pos token.Pos, others ...*directive.DirectiveFuncSet) bool {
 		for _, s := range others {
 			if s != nil && s.IsUsed(pos) {
@@ -369,13 +639,21 @@ func reportUnusedDirectiveFuncs(pass *analysis.Pass, pureFuncs, immutableReturnF
 			if usedByOther(pos, others...) {
 				continue
 			}
-			pass.Reportf(pos, "%s", message)
+			report(pass, pos, pollution.KindUnusedDirective, message)
 		}
 	}
 
-	report(pureFuncs, "unused gormreuse:pure directive", immutableReturnFuncs, immutableParamFuncs)
-	report(immutableReturnFuncs, "unused gormreuse:immutable-return directive", pureFuncs, immutableParamFuncs)
-	report(immutableParamFuncs, "unused gormreuse:immutable-param directive", pureFuncs, immutableReturnFuncs)
+	report(pureFuncs, "unused gormreuse:pure directive", immutableReturnFuncs, immutableParamFuncs, sinkFuncs)
+	report(immutableReturnFuncs, "unused gormreuse:immutable-return directive", pureFuncs, immutableParamFuncs, sinkFuncs)
+	report(immutableParamFuncs, "unused gormreuse:immutable-param directive", pureFuncs, immutableReturnFuncs, sinkFuncs)
+	report(finisherFuncs, "unused gormreuse:finisher directive")
+	report(sinkFuncs, "unused gormreuse:sink directive: no *gorm.DB parameter", pureFuncs, immutableReturnFuncs, immutableParamFuncs)
+	report(impureFuncs, "unused gormreuse:impure directive: no *gorm.DB parameter")
+}
+
+// report reports message at pos under the category of kind.
+func report(pass *analysis.Pass, pos token.Pos, kind pollution.ViolationKind, message string) {
+	pass.Report(analysis.Diagnostic{Pos: pos, Category: kind.String(), Message: message})
 }
 
 // recoverPerFunction runs work, recovering from any panic so that a single
@@ -404,27 +682,33 @@ func recoverPerFunction(fn *ssa.Function, work func()) {
 // It ensures:
 //   - Violations at the same position are only reported once
 //   - Line-level ignore directives suppress violations
+//   - Root-level ignore directives suppress every violation of that root
 //   - Violations are reported through the analysis.Pass
 type checker struct {
-	pass                 *analysis.Pass              // For reporting diagnostics
-	ignoreMap            directive.IgnoreMap         // Line-level ignore directives
-	pureFuncs            *directive.DirectiveFuncSet // Pure functions for analysis
-	immutableReturnFuncs *directive.DirectiveFuncSet // Immutable-return functions
-	immutableParamFuncs  *directive.DirectiveFuncSet // Immutable-param functions (params opt out of Phase 1b)
-	failedPure           map[*ssa.Function]bool      // Pure functions that failed contract validation
-	scopesCallbacks      map[*ssa.Function]bool      // Scopes/Preload callbacks (params are mutable roots)
-	immutableCallbacks   map[*ssa.Function]bool      // Transaction/Connection/FindInBatches callbacks (fresh tx)
-	needsImmutableParam  map[*ssa.Function]bool      // immutable-param fns that branch a param (2b caller check)
-	reported             map[token.Pos]bool          // Deduplication of reports
-	suggestedEdits       map[editKey]bool            // Global deduplication of suggested fixes
-	fixGen               *fix.Generator              // Cached fix generator for all violations
+	pass           *analysis.Pass            // For reporting diagnostics
+	ignoreMap      directive.IgnoreMap       // Line-level ignore directives
+	allowReuseMap  directive.IgnoreMap       // Line-level allow-reuse directives (reuse violations only)
+	opts           ssautil.Options           // Directive sets, callbacks and DB types for the analysis
+	reported       map[token.Pos]bool        // Deduplication of reports
+	suggestedEdits map[editKey]bool          // Global deduplication of suggested fixes
+	fixGen         *fix.Generator            // Cached fix generator for all violations
+	graph          *rootGraph                // Root graph collector (nil unless -report-root-graph)
+	list           *rootList                 // Root list collector (nil unless -list-roots-json)
+	fixComplexity  bool                      // Append fix complexity to messages (-fix-complexity)
+	coalesceRoots  bool                      // List every polluted Phi root as related information (-coalesce-roots)
+	polluters      map[token.Pos][]token.Pos // Root positions of the reuse violations at each position
+	testHelperPkgs map[string]bool           // Assertion packages suppressing nested uses (-no-test-helpers)
 }
 
 // editKey uniquely identifies an edit to avoid duplicates across violations.
+// The fix message is part of the key: alternative fixes are applied one at a
+// time, so an edit shared with another alternative must not be dropped from
+// either of them.
 type editKey struct {
-	pos  token.Pos
-	end  token.Pos
-	text string
+	message string
+	pos     token.Pos
+	end     token.Pos
+	text    string
 }
 
 // newChecker creates a new checker for a specific file.
@@ -432,27 +716,31 @@ type editKey struct {
 // across parent functions and their closures.
 // The suggestedEdits map is shared to avoid duplicate fix edits.
 // The fixGen is shared to avoid recreating the generator for each violation.
-func newChecker(pass *analysis.Pass, ignoreMap directive.IgnoreMap, pureFuncs, immutableReturnFuncs, immutableParamFuncs *directive.DirectiveFuncSet, failedPure, scopesCallbacks, immutableCallbacks, needsImmutableParam map[*ssa.Function]bool, reported map[token.Pos]bool, suggestedEdits map[editKey]bool, fixGen *fix.Generator) *checker {
+func newChecker(pass *analysis.Pass, ignoreMap directive.IgnoreMap, opts ssautil.Options, reported map[token.Pos]bool, suggestedEdits map[editKey]bool, fixGen *fix.Generator) *checker {
 	return &checker{
-		pass:                 pass,
-		ignoreMap:            ignoreMap,
-		pureFuncs:            pureFuncs,
-		immutableReturnFuncs: immutableReturnFuncs,
-		immutableParamFuncs:  immutableParamFuncs,
-		failedPure:           failedPure,
-		scopesCallbacks:      scopesCallbacks,
-		immutableCallbacks:   immutableCallbacks,
-		needsImmutableParam:  needsImmutableParam,
-		reported:             reported,
-		suggestedEdits:       suggestedEdits,
-		fixGen:               fixGen,
+		pass:           pass,
+		ignoreMap:      ignoreMap,
+		opts:           opts,
+		reported:       reported,
+		suggestedEdits: suggestedEdits,
+		fixGen:         fixGen,
 	}
 }
 
-// checkFunction runs SSA analysis on a single function and reports violations.
-func (c *checker) checkFunction(fn *ssa.Function) {
-	analyzer := ssautil.NewAnalyzer(fn, c.pureFuncs, c.immutableReturnFuncs, c.immutableParamFuncs, c.failedPure, c.scopesCallbacks, c.immutableCallbacks, c.needsImmutableParam)
-	violations := analyzer.Analyze()
+// checkFunction reports the violations the SSA analysis of fn found (see
+// analyzeFunctions).
+func (c *checker) checkFunction(fn *ssa.Function, res funcAnalysis) {
+	violations := res.violations
+	if c.graph != nil {
+		c.graph.add(fn, res.roots)
+	}
+	if c.list != nil {
+		c.list.add(fn, res.roots)
+	}
+
+	if c.coalesceRoots {
+		c.polluters = violationRoots(violations)
+	}
 
 	// Deduplicate violations by root to avoid generating duplicate fixes.
 	// Multiple violations from the same root (e.g., tripleUse) should only
@@ -484,11 +772,12 @@ func (c *checker) reportViolation(v pollution.Violation) {
 	}
 	c.reported[pos] = true
 
-	// Check if line is ignored
-	line := c.pass.Fset.Position(pos).Line
-	if c.ignoreMap != nil && c.ignoreMap.ShouldIgnore(line) {
+	if c.isIgnored(v) {
 		return // Suppressed by ignore directive
 	}
+	if c.inTestHelper(pos) {
+		return // Finisher wrapped in a test assertion
+	}
 
 	// Generate SuggestedFix if possible
 	suggestedFixes := c.fixGen.Generate(v)
@@ -501,31 +790,147 @@ func (c *checker) reportViolation(v pollution.Violation) {
 	// Report with diagnostic
 	c.pass.Report(analysis.Diagnostic{
 		Pos:            pos,
-		Message:        v.Message,
+		End:            c.violationEnd(pos),
+		Category:       v.Kind.String(),
+		Message:        c.message(v),
 		SuggestedFixes: suggestedFixes,
+		Related:        c.rootRelated(v),
 	})
 }
 
-// deduplicateFixes removes edits that have already been suggested by previous violations.
+// rootRelated points at the definition of v's mutable root, which may be far
+// above the reuse, so editors and structured output (-json) can jump to it. It
+// is nil when the root position is unknown or synthesized.
+//
+// With -coalesce-roots, the other polluted roots a Phi merges into the same
+// receiver follow, since the one diagnostic at the position stands for all of
+// their violations:
+//
+//	if cond { q = q1 } else { q = q2 } // q1 and q2 both finished above
+//	q.Count(nil)                       // related: q1's and q2's definitions
+func (c *checker) rootRelated(v pollution.Violation) []analysis.RelatedInformation {
+	var related []analysis.RelatedInformation
+	if v.RootPos.IsValid() {
+		related = append(related, analysis.RelatedInformation{Pos: v.RootPos, Message: "root defined here"})
+	}
+	for _, pos := range c.polluters[v.Pos] {
+		if pos != v.RootPos {
+			related = append(related, analysis.RelatedInformation{Pos: pos, Message: "polluted root defined here"})
+		}
+	}
+	return related
+}
+
+// violationEnd returns the end of the call or statement reported at pos, so
+// editors underline the whole offending use. A call is reported at its opening
+// parenthesis, a go or defer statement at its keyword:
+//
+//	q.Count(nil)      // Pos at "(", End after ")"
+//	go q.Count(nil)   // Pos at "go", End after ")"
+//
+// It returns token.NoPos when no such node starts at pos.
+func (c *checker) violationEnd(pos token.Pos) token.Pos {
+	file := c.fileAt(pos)
+	if file == nil {
+		return token.NoPos
+	}
+	path, _ := astutil.PathEnclosingInterval(file, pos, pos)
+	for _, n := range path {
+		switch n := n.(type) {
+		case *ast.CallExpr:
+			if n.Lparen == pos {
+				return n.End()
+			}
+		case *ast.GoStmt, *ast.DeferStmt, *ast.SendStmt, *ast.AssignStmt:
+			if n.Pos() == pos {
+				return n.End()
+			}
+		}
+	}
+	return token.NoPos
+}
+
+// violationRoots returns the definition positions of the distinct roots of
+// the reuse violations at each position, in source order.
+func violationRoots(violations []pollution.Violation) map[token.Pos][]token.Pos {
+	roots := make(map[token.Pos][]token.Pos)
+	for _, v := range violations {
+		if !v.Kind.IsReuse() || !v.RootPos.IsValid() || slices.Contains(roots[v.Pos], v.RootPos) {
+			continue
+		}
+		roots[v.Pos] = append(roots[v.Pos], v.RootPos)
+	}
+	for _, pos := range roots {
+		slices.Sort(pos)
+	}
+	return roots
+}
+
+// message returns the diagnostic text for v, annotated with its estimated fix
+// complexity when -fix-complexity is set.
+func (c *checker) message(v pollution.Violation) string {
+	if !c.fixComplexity {
+		return v.Message
+	}
+	return v.Message + " [fix: " + v.Complexity.String() + "]"
+}
+
+// isIgnored reports whether a directive suppresses the violation: either a
+// line-level directive covering the violation line, or a root-level directive
+// on the line defining the violation's root. A reuse violation is first matched
+// against //gormreuse:allow-reuse, so such a marker is the one marked used.
+func (c *checker) isIgnored(v pollution.Violation) bool {
+	if v.Kind.IsReuse() && c.suppresses(c.allowReuseMap, v) {
+		return true
+	}
+	return c.suppresses(c.ignoreMap, v)
+}
+
+// suppresses reports whether m has a directive covering v's line or its root's.
+func (c *checker) suppresses(m directive.IgnoreMap, v pollution.Violation) bool {
+	if m == nil {
+		return false
+	}
+	if m.ShouldIgnore(c.pass.Fset.Position(v.Pos).Line) {
+		return true
+	}
+	if !v.RootPos.IsValid() {
+		return false
+	}
+	rootPos := c.pass.Fset.Position(v.RootPos)
+	if rootPos.Filename != c.pass.Fset.Position(v.Pos).Filename {
+		return false
+	}
+	return m.ShouldIgnoreRoot(rootPos.Line)
+}
+
+// deduplicateFixes removes edits that have already been suggested by previous
+// violations in a fix of the same message. When nothing is left of the first
+// fix, the one drivers apply, the alternatives are dropped too rather than
+// promoted in its place.
 func (c *checker) deduplicateFixes(fixes []analysis.SuggestedFix) []analysis.SuggestedFix {
 	if len(fixes) == 0 {
 		return fixes
 	}
 
 	var result []analysis.SuggestedFix
-	for _, fix := range fixes {
+	for i, fix := range fixes {
 		var dedupedEdits []analysis.TextEdit
 		for _, edit := range fix.TextEdits {
 			key := editKey{
-				pos:  edit.Pos,
-				end:  edit.End,
-				text: string(edit.NewText),
+				message: fix.Message,
+				pos:     edit.Pos,
+				end:     edit.End,
+				text:    string(edit.NewText),
 			}
 			if !c.suggestedEdits[key] {
 				c.suggestedEdits[key] = true
 				dedupedEdits = append(dedupedEdits, edit)
 			}
 		}
+		if len(dedupedEdits) == 0 && i == 0 {
+			return nil
+		}
 		if len(dedupedEdits) > 0 {
 			result = append(result, analysis.SuggestedFix{
 				Message:   fix.Message,
@@ -548,15 +953,19 @@ func (c *checker) reportViolationWithoutFix(v pollution.Violation) {
 	}
 	c.reported[pos] = true
 
-	// Check if line is ignored
-	line := c.pass.Fset.Position(pos).Line
-	if c.ignoreMap != nil && c.ignoreMap.ShouldIgnore(line) {
+	if c.isIgnored(v) {
 		return // Suppressed by ignore directive
 	}
+	if c.inTestHelper(pos) {
+		return // Finisher wrapped in a test assertion
+	}
 
 	// Report without suggested fixes
 	c.pass.Report(analysis.Diagnostic{
-		Pos:     pos,
-		Message: v.Message,
+		Pos:      pos,
+		End:      c.violationEnd(pos),
+		Category: v.Kind.String(),
+		Message:  c.message(v),
+		Related:  c.rootRelated(v),
 	})
 }
@@ -1,13 +1,18 @@
 package internal
 
 import (
+	"fmt"
 	"go/token"
+	"slices"
+	"strings"
 	"testing"
 
+	"golang.org/x/tools/go/analysis"
 	"golang.org/x/tools/go/ssa"
 
 	"github.com/mpyw/gormreuse/internal/directive"
 	ssautil "github.com/mpyw/gormreuse/internal/ssa"
+	"github.com/mpyw/gormreuse/internal/ssa/pollution"
 )
 
 // TestRecoverPerFunction verifies that a panic in per-function analysis is
@@ -41,10 +46,10 @@ func TestRecoverPerFunction(t *testing.T) {
 func TestNewAnalyzer(t *testing.T) {
 	t.Parallel()
 
-	pureFuncs := directive.NewPureFuncSet(nil, nil)
+	pureFuncs := directive.NewPureFuncSet(nil, nil, nil)
 	pureFuncs.Add(directive.FuncKey{PkgPath: "test", FuncName: "Pure"})
-	immutableReturnFuncs := directive.NewImmutableReturnFuncSet(nil, nil)
-	analyzer := ssautil.NewAnalyzer(nil, pureFuncs, immutableReturnFuncs, nil, nil, nil, nil, nil)
+	immutableReturnFuncs := directive.NewImmutableReturnFuncSet(nil, nil, nil)
+	analyzer := ssautil.NewAnalyzer(nil, pureFuncs, immutableReturnFuncs, nil, nil, nil, nil, nil, nil, nil, nil)
 
 	if analyzer == nil {
 		t.Error("Expected analyzer to be initialized")
@@ -55,12 +60,13 @@ func TestNewChecker(t *testing.T) {
 	t.Parallel()
 
 	ignoreMap := make(directive.IgnoreMap)
-	pureFuncs := directive.NewPureFuncSet(nil, nil)
-	immutableReturnFuncs := directive.NewImmutableReturnFuncSet(nil, nil)
+	pureFuncs := directive.NewPureFuncSet(nil, nil, nil)
+	immutableReturnFuncs := directive.NewImmutableReturnFuncSet(nil, nil, nil)
 	reported := make(map[token.Pos]bool)
 	suggestedEdits := make(map[editKey]bool)
 
-	chk := newChecker(nil, ignoreMap, pureFuncs, immutableReturnFuncs, nil, nil, nil, nil, nil, reported, suggestedEdits, nil)
+	opts := ssautil.Options{PureFuncs: pureFuncs, ImmutableReturnFuncs: immutableReturnFuncs}
+	chk := newChecker(nil, ignoreMap, opts, reported, suggestedEdits, nil)
 
 	if chk == nil {
 		t.Error("Expected checker to be initialized")
@@ -70,7 +76,7 @@ func TestNewChecker(t *testing.T) {
 func TestAnalyzer_Analyze_NilFunction(t *testing.T) {
 	t.Parallel()
 
-	analyzer := ssautil.NewAnalyzer(nil, nil, nil, nil, nil, nil, nil, nil)
+	analyzer := ssautil.NewAnalyzer(nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil)
 
 	// Should not panic with nil function
 	violations := analyzer.Analyze()
@@ -83,10 +89,86 @@ func TestAnalyzer_Analyze_EmptyFunction(t *testing.T) {
 	t.Parallel()
 
 	fn := &ssa.Function{}
-	analyzer := ssautil.NewAnalyzer(fn, nil, nil, nil, nil, nil, nil, nil)
+	analyzer := ssautil.NewAnalyzer(fn, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil)
 
 	violations := analyzer.Analyze()
 	if len(violations) != 0 {
 		t.Errorf("Expected 0 violations for empty function, got %d", len(violations))
 	}
 }
+
+// TestRootRelated verifies that a reuse diagnostic points at its root's
+// definition, that the related location is omitted for synthesized roots
+// without a position, and that the other polluted roots at the same position
+// follow when they are coalesced.
+func TestRootRelated(t *testing.T) {
+	t.Parallel()
+
+	c := &checker{}
+	got := c.rootRelated(pollution.Violation{RootPos: token.Pos(42)})
+	if len(got) != 1 || got[0].Pos != token.Pos(42) || got[0].Message != "root defined here" {
+		t.Errorf("rootRelated() = %+v, want one \"root defined here\" at 42", got)
+	}
+	if got := c.rootRelated(pollution.Violation{RootPos: token.NoPos}); got != nil {
+		t.Errorf("rootRelated() with an invalid root position = %+v, want nil", got)
+	}
+
+	c.polluters = violationRoots([]pollution.Violation{
+		{Pos: token.Pos(100), RootPos: token.Pos(42)},
+		{Pos: token.Pos(100), RootPos: token.Pos(10)},
+		{Pos: token.Pos(100), RootPos: token.Pos(42)},
+		{Pos: token.Pos(100), RootPos: token.Pos(50), Kind: pollution.KindContract},
+	})
+	got = c.rootRelated(pollution.Violation{Pos: token.Pos(100), RootPos: token.Pos(42)})
+	if len(got) != 2 || got[1].Pos != token.Pos(10) || got[1].Message != "polluted root defined here" {
+		t.Errorf("rootRelated() coalesced = %+v, want the root at 42 then a polluted root at 10", got)
+	}
+}
+
+// TestSortReports verifies that diagnostics are emitted in DiagnosticKey order:
+// by file, line and column, then by category and message when several share a
+// position.
+func TestSortReports(t *testing.T) {
+	t.Parallel()
+
+	fset := token.NewFileSet()
+	a := fset.AddFile("a.go", -1, 100)
+	a.SetLines([]int{0, 10, 20})
+	b := fset.AddFile("b.go", -1, 100)
+	b.SetLines([]int{0, 10})
+
+	var got []string
+	pass := &analysis.Pass{Fset: fset, Report: func(d analysis.Diagnostic) {
+		p := fset.Position(d.Pos)
+		got = append(got, fmt.Sprintf("%s:%d:%d [%s] %s", p.Filename, p.Line, p.Column, d.Category, d.Message))
+	}}
+	sorted, flush := sortReports(pass)
+	for _, d := range []analysis.Diagnostic{
+		{Pos: b.Pos(12), Category: "BRANCH", Message: "z"},
+		{Pos: a.Pos(12), Category: "UNUSED-DIRECTIVE", Message: "unused"},
+		{Pos: a.Pos(12), Category: "BRANCH", Message: "reused: second"},
+		{Pos: a.Pos(12), Category: "BRANCH", Message: "reused: first"},
+		{Pos: a.Pos(5), Category: "PURE", Message: "pure"},
+		{Pos: a.Pos(25), Category: "BRANCH", Message: "later line"},
+		{Pos: a.Pos(11), Category: "UNUSED-IGNORE", Message: "earlier column"},
+	} {
+		sorted.Report(d)
+	}
+	if len(got) != 0 {
+		t.Fatalf("reported before flush: %q", got)
+	}
+	flush()
+
+	want := []string{
+		"a.go:1:6 [PURE] pure",
+		"a.go:2:2 [UNUSED-IGNORE] earlier column",
+		"a.go:2:3 [BRANCH] reused: first",
+		"a.go:2:3 [BRANCH] reused: second",
+		"a.go:2:3 [UNUSED-DIRECTIVE] unused",
+		"a.go:3:6 [BRANCH] later line",
+		"b.go:2:3 [BRANCH] z",
+	}
+	if !slices.Equal(got, want) {
+		t.Errorf("reported order =\n%s\nwant\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+}
@@ -0,0 +1,324 @@
+package internal
+
+import (
+	"bufio"
+	"bytes"
+	"cmp"
+	"errors"
+	"fmt"
+	"go/token"
+	"hash/fnv"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/ssa"
+)
+
+// =============================================================================
+// Baseline (-baseline, -write-baseline)
+// =============================================================================
+
+// baselineKey identifies a diagnostic independently of its line number: by
+// its file, its enclosing function (see enclosingFuncs), its category and a
+// hash of the source line it is reported on. Code added or removed above the
+// diagnostic, in its function or elsewhere in the file, leaves it unchanged;
+// editing the reported line makes it a new diagnostic.
+type baselineKey struct {
+	file     string
+	function string
+	category string
+	hash     string
+}
+
+// baselineEntry is a diagnostic as written to a baseline file, one per line
+// with tab-separated fields. The line number is informative only:
+//
+//	internal/repo.go	12	example.com/repo.find	BRANCH	5f1d3a9c2b7e4f60
+type baselineEntry struct {
+	baselineKey
+	line int
+}
+
+// Baseline is the set of known diagnostics of a baseline file. Filtering a
+// package against it drops each diagnostic it lists, as many times as it is
+// listed, and keeps the new ones.
+type Baseline struct {
+	known map[baselineKey]int
+}
+
+// ReadBaseline reads the baseline file at path, as written by
+// -write-baseline. Blank lines and lines starting with # are skipped.
+func ReadBaseline(path string) (*Baseline, error) {
+	entries, err := readBaselineEntries(path)
+	if err != nil {
+		return nil, err
+	}
+	b := &Baseline{known: make(map[baselineKey]int)}
+	for _, e := range entries {
+		b.known[e.baselineKey]++
+	}
+	return b, nil
+}
+
+// readBaselineEntries reads the entries of the baseline file at path.
+func readBaselineEntries(path string) ([]baselineEntry, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var entries []baselineEntry
+	sc := bufio.NewScanner(bytes.NewReader(data))
+	for n := 1; sc.Scan(); n++ {
+		text := sc.Text()
+		if strings.TrimSpace(text) == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+		fields := strings.Split(text, "\t")
+		if len(fields) != 5 {
+			return nil, fmt.Errorf("%s:%d: want 5 tab-separated fields, got %d", path, n, len(fields))
+		}
+		line, err := strconv.Atoi(fields[1])
+		if err != nil {
+			return nil, fmt.Errorf("%s:%d: invalid line number %q", path, n, fields[1])
+		}
+		entries = append(entries, baselineEntry{
+			baselineKey: baselineKey{file: fields[0], function: fields[2], category: fields[3], hash: fields[4]},
+			line:        line,
+		})
+	}
+	return entries, sc.Err()
+}
+
+// filter returns a copy of pass whose Report drops the diagnostics listed in
+// b. The counts are consumed, so b filters a single package.
+func (b *Baseline) filter(pass *analysis.Pass, srcFuncs []*ssa.Function) *analysis.Pass {
+	keys := newBaselineKeys(pass, srcFuncs)
+	filtered := *pass
+	filtered.Report = func(d analysis.Diagnostic) {
+		key := keys.entry(d).baselineKey
+		if b.known[key] > 0 {
+			b.known[key]--
+			return
+		}
+		pass.Report(d)
+	}
+	return &filtered
+}
+
+// BaselineUpdate collects the diagnostics of a package for -write-baseline.
+// Merge then updates the baseline file with them. The zero value is ready to
+// use.
+type BaselineUpdate struct {
+	files   map[string]bool // analyzed files, by baseline file name
+	entries []baselineEntry
+}
+
+// record returns a copy of pass whose Report records each diagnostic in u
+// instead of reporting it. The files of pass but those in skipFiles are
+// recorded as analyzed, even when they have no diagnostic.
+func (u *BaselineUpdate) record(pass *analysis.Pass, srcFuncs []*ssa.Function, skipFiles map[string]bool) *analysis.Pass {
+	if u.files == nil {
+		u.files = make(map[string]bool)
+	}
+	for _, file := range pass.Files {
+		if filename := pass.Fset.Position(file.Pos()).Filename; !skipFiles[filename] {
+			u.files[baselineFileName(filename)] = true
+		}
+	}
+	keys := newBaselineKeys(pass, srcFuncs)
+	recording := *pass
+	recording.Report = func(d analysis.Diagnostic) {
+		u.entries = append(u.entries, keys.entry(d))
+	}
+	return &recording
+}
+
+// baselineLockTimeout bounds the wait for the lock of a baseline file, which
+// a crashed run may have left behind.
+const baselineLockTimeout = time.Minute
+
+// baselineMu serializes the merges of the packages analyzed concurrently by a
+// process; the lock file serializes those of the processes run by go vet.
+var baselineMu sync.Mutex
+
+// Merge writes the recorded entries to the baseline file at path, replacing
+// the entries of the analyzed files and keeping those of the other files,
+// which other packages record. Each package merges its own entries, so a
+// baseline is written the same way by a single process analyzing every
+// package and by go vet analyzing each in its own process. The file is
+// replaced atomically; a path.lock file guards the update.
+func (u *BaselineUpdate) Merge(path string) error {
+	baselineMu.Lock()
+	defer baselineMu.Unlock()
+	unlock, err := lockBaseline(path)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	entries, err := readBaselineEntries(path)
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+	entries = slices.DeleteFunc(entries, func(e baselineEntry) bool { return u.files[e.file] })
+	entries = append(entries, u.entries...)
+	slices.SortFunc(entries, func(a, b baselineEntry) int {
+		return cmp.Or(cmp.Compare(a.file, b.file), cmp.Compare(a.line, b.line),
+			cmp.Compare(a.category, b.category), cmp.Compare(a.hash, b.hash))
+	})
+	var buf bytes.Buffer
+	for _, e := range entries {
+		fmt.Fprintf(&buf, "%s\t%d\t%s\t%s\t%s\n", e.file, e.line, e.function, e.category, e.hash)
+	}
+	return writeFileAtomic(path, buf.Bytes())
+}
+
+// lockBaseline creates the lock file of the baseline file at path, waiting
+// for up to baselineLockTimeout while another process holds it, and returns
+// the function removing it.
+func lockBaseline(path string) (unlock func(), err error) {
+	lock := path + ".lock"
+	deadline := time.Now().Add(baselineLockTimeout)
+	for {
+		f, err := os.OpenFile(lock, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o644)
+		if err == nil {
+			_ = f.Close()
+			return func() { _ = os.Remove(lock) }, nil
+		}
+		if !errors.Is(err, fs.ErrExist) {
+			return nil, err
+		}
+		if time.Now().After(deadline) {
+			return nil, fmt.Errorf("%s is still locked after %v; remove %s if no analysis is running", path, baselineLockTimeout, lock)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+// writeFileAtomic replaces the file at path with data through a temporary
+// file in the same directory, so readers never see a partial file.
+func writeFileAtomic(path string, data []byte) error {
+	f, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer func() { _ = os.Remove(f.Name()) }() // fails once renamed
+	if _, err := f.Write(data); err != nil {
+		_ = f.Close()
+		return err
+	}
+	if err := f.Chmod(0o644); err != nil {
+		_ = f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	return os.Rename(f.Name(), path)
+}
+
+// baselineKeys computes the baseline entries of the diagnostics of a package.
+type baselineKeys struct {
+	pass  *analysis.Pass
+	funcs enclosingFuncs
+	lines map[string][]string // source lines by file name, read on demand
+}
+
+func newBaselineKeys(pass *analysis.Pass, srcFuncs []*ssa.Function) *baselineKeys {
+	return &baselineKeys{pass: pass, funcs: newEnclosingFuncs(srcFuncs), lines: make(map[string][]string)}
+}
+
+// entry returns the baseline entry of d.
+func (k *baselineKeys) entry(d analysis.Diagnostic) baselineEntry {
+	pos := k.pass.Fset.Position(d.Pos)
+	return baselineEntry{
+		baselineKey: baselineKey{
+			file:     baselineFileName(pos.Filename),
+			function: k.funcs.name(k.funcs.enclosing(d.Pos)),
+			category: d.Category,
+			hash:     lineHash(k.line(pos)),
+		},
+		line: pos.Line,
+	}
+}
+
+// line returns the source line at pos without its surrounding white space,
+// or "" when the file cannot be read.
+func (k *baselineKeys) line(pos token.Position) string {
+	lines, ok := k.lines[pos.Filename]
+	if !ok {
+		readFile := k.pass.ReadFile
+		if readFile == nil {
+			readFile = os.ReadFile
+		}
+		if src, err := readFile(pos.Filename); err == nil {
+			lines = strings.Split(string(src), "\n")
+		}
+		k.lines[pos.Filename] = lines
+	}
+	if pos.Line < 1 || pos.Line > len(lines) {
+		return ""
+	}
+	return strings.TrimSpace(lines[pos.Line-1])
+}
+
+// lineHash returns the FNV-1a hash of a source line in hexadecimal.
+func lineHash(line string) string {
+	h := fnv.New64a()
+	_, _ = h.Write([]byte(line))
+	return fmt.Sprintf("%016x", h.Sum64())
+}
+
+// baselineFileName returns filename relative to the root of its module with
+// forward slashes, so a baseline matches on every machine and whatever the
+// working directory, which go vet sets to the directory of each package. A
+// file outside of any module is relative to the working directory instead,
+// and kept as is when it is not below it.
+func baselineFileName(filename string) string {
+	if abs, err := filepath.Abs(filename); err == nil {
+		filename = abs
+	}
+	root := moduleRoot(filepath.Dir(filename))
+	if root == "" {
+		wd, err := os.Getwd()
+		if err != nil {
+			return filepath.ToSlash(filename)
+		}
+		root = wd
+	}
+	rel, err := filepath.Rel(root, filename)
+	if err != nil || !filepath.IsLocal(rel) {
+		return filepath.ToSlash(filename)
+	}
+	return filepath.ToSlash(rel)
+}
+
+// moduleRoots caches moduleRoot by directory.
+var moduleRoots sync.Map
+
+// moduleRoot returns the nearest directory at or above dir holding a go.mod
+// file, or "" when there is none.
+func moduleRoot(dir string) string {
+	if root, ok := moduleRoots.Load(dir); ok {
+		return root.(string)
+	}
+	root := ""
+	for d := dir; ; d = filepath.Dir(d) {
+		if _, err := os.Stat(filepath.Join(d, "go.mod")); err == nil {
+			root = d
+			break
+		}
+		if filepath.Dir(d) == d {
+			break
+		}
+	}
+	moduleRoots.Store(dir, root)
+	return root
+}
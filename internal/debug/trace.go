@@ -0,0 +1,199 @@
+// Package debug renders the internal steps of the analysis for debugging. It
+// has no effect on diagnostics.
+package debug
+
+import (
+	"errors"
+	"fmt"
+	"go/token"
+	"io"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"golang.org/x/tools/go/ssa"
+)
+
+// =============================================================================
+// Value Trace (-trace-value)
+// =============================================================================
+
+// Target is the position given to -trace-value: a file and a line in it.
+type Target struct {
+	File string
+	Line int
+}
+
+// ParseTarget parses a file:line target. The file may be a base name or a
+// path of trailing elements, such as repo/find.go.
+func ParseTarget(s string) (Target, error) {
+	i := strings.LastIndexByte(s, ':')
+	if i <= 0 {
+		return Target{}, errors.New("want file:line")
+	}
+	line, err := strconv.Atoi(s[i+1:])
+	if err != nil || line < 1 {
+		return Target{}, fmt.Errorf("invalid line %q", s[i+1:])
+	}
+	return Target{File: filepath.ToSlash(s[:i]), Line: line}, nil
+}
+
+// Matches reports whether pos is on the target line of a file whose path is
+// the target file or ends with it.
+func (t Target) Matches(pos token.Position) bool {
+	if pos.Line != t.Line {
+		return false
+	}
+	name := filepath.ToSlash(pos.Filename)
+	return name == t.File || strings.HasSuffix(name, "/"+t.File)
+}
+
+// Node is a value visited by a trace, with the values traced to find its
+// roots.
+type Node struct {
+	Value    ssa.Value
+	Step     string // how the parent reached Value, e.g. "phi edge 1"; "" for its operand
+	Visited  bool   // Value was traced before, so this step found nothing
+	Roots    []ssa.Value
+	Children []*Node
+}
+
+// Recorder is a tracer.TraceSink building the tree of the values a trace
+// visits.
+type Recorder struct {
+	top   []*Node
+	stack []*Node
+}
+
+// Enter implements tracer.TraceSink.
+func (r *Recorder) Enter(v ssa.Value, step string, visited bool) {
+	n := &Node{Value: v, Step: step, Visited: visited}
+	if len(r.stack) == 0 {
+		r.top = append(r.top, n)
+	} else {
+		parent := r.stack[len(r.stack)-1]
+		parent.Children = append(parent.Children, n)
+	}
+	r.stack = append(r.stack, n)
+}
+
+// Leave implements tracer.TraceSink.
+func (r *Recorder) Leave(roots []ssa.Value) {
+	if len(r.stack) == 0 {
+		return
+	}
+	r.stack[len(r.stack)-1].Roots = roots
+	r.stack = r.stack[:len(r.stack)-1]
+}
+
+// Take returns the trees recorded since the last call and starts afresh.
+func (r *Recorder) Take() []*Node {
+	top := r.top
+	r.top, r.stack = nil, nil
+	return top
+}
+
+// Trace is the trace of the *gorm.DB receiver of a gorm method call, as
+// resolved by FindMutableRoot and by FindAllMutableRoots.
+type Trace struct {
+	Pos    token.Pos     // of the call
+	Fn     *ssa.Function // enclosing the call
+	Method string
+	Recv   ssa.Value
+
+	Root      ssa.Value // FindMutableRoot; nil for an immutable source
+	RootSteps []*Node
+	AllRoots  []ssa.Value // FindAllMutableRoots
+	AllSteps  []*Node
+}
+
+// Write renders traces as indented trees, one node per line with the roots
+// found for it after an arrow:
+//
+//	find.go:12:8: Find receiver t5 in repo.find
+//	  FindMutableRoot:
+//	    t5 = phi [1: t2, 3: t4] #q @ find.go:8:6 → t2
+//	      phi edge 0: t2 = (*gorm.io/gorm.DB).Where(db, t1, nil:[]interface{}...) @ find.go:9:14 → t2
+//	  root: t2 @ find.go:9:14
+//	  FindAllMutableRoots:
+//	    ...
+//	  roots: t2 @ find.go:9:14, t4 @ find.go:11:15
+//
+// Files are named by their base name. A value traced again is marked
+// (visited) and has no roots.
+func Write(w io.Writer, fset *token.FileSet, traces []Trace) {
+	for _, tr := range traces {
+		fmt.Fprintf(w, "%s: %s receiver %s in %s\n", position(fset, tr.Pos), tr.Method, tr.Recv.Name(), tr.Fn.String())
+		fmt.Fprintf(w, "  FindMutableRoot:\n")
+		writeNodes(w, fset, tr.RootSteps, 2)
+		if tr.Root == nil {
+			fmt.Fprintf(w, "  root: none\n")
+		} else {
+			fmt.Fprintf(w, "  root: %s\n", rootNames(fset, []ssa.Value{tr.Root}))
+		}
+		fmt.Fprintf(w, "  FindAllMutableRoots:\n")
+		writeNodes(w, fset, tr.AllSteps, 2)
+		fmt.Fprintf(w, "  roots: %s\n", rootNames(fset, tr.AllRoots))
+	}
+}
+
+func writeNodes(w io.Writer, fset *token.FileSet, nodes []*Node, depth int) {
+	for _, n := range nodes {
+		var b strings.Builder
+		b.WriteString(strings.Repeat("  ", depth))
+		if n.Step != "" {
+			b.WriteString(n.Step + ": ")
+		}
+		b.WriteString(describe(fset, n.Value))
+		if n.Visited {
+			b.WriteString(" (visited)")
+		} else {
+			b.WriteString(" → ")
+			if len(n.Roots) == 0 {
+				b.WriteString("none")
+			} else {
+				for i, root := range n.Roots {
+					if i > 0 {
+						b.WriteString(", ")
+					}
+					b.WriteString(root.Name())
+				}
+			}
+		}
+		fmt.Fprintln(w, b.String())
+		writeNodes(w, fset, n.Children, depth+1)
+	}
+}
+
+// describe renders v as its SSA instruction, t1 = ..., or as the value
+// itself, followed by its position when it has one.
+func describe(fset *token.FileSet, v ssa.Value) string {
+	s := v.String()
+	if _, ok := v.(ssa.Instruction); ok {
+		s = v.Name() + " = " + s
+	}
+	if pos := v.Pos(); pos.IsValid() {
+		s += " @ " + position(fset, pos)
+	}
+	return s
+}
+
+// rootNames renders roots by name and position, "none" when empty.
+func rootNames(fset *token.FileSet, roots []ssa.Value) string {
+	if len(roots) == 0 {
+		return "none"
+	}
+	names := make([]string, len(roots))
+	for i, root := range roots {
+		names[i] = root.Name()
+		if pos := root.Pos(); pos.IsValid() {
+			names[i] += " @ " + position(fset, pos)
+		}
+	}
+	return strings.Join(names, ", ")
+}
+
+func position(fset *token.FileSet, pos token.Pos) string {
+	p := fset.Position(pos)
+	return fmt.Sprintf("%s:%d:%d", filepath.Base(p.Filename), p.Line, p.Column)
+}
@@ -0,0 +1,48 @@
+package debug
+
+import (
+	"go/token"
+	"testing"
+)
+
+func TestTargetMatches(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		target   string
+		pos      token.Position
+		expected bool
+	}{
+		{"base name", "find.go:12", token.Position{Filename: "/src/repo/find.go", Line: 12}, true},
+		{"trailing path", "repo/find.go:12", token.Position{Filename: "/src/repo/find.go", Line: 12}, true},
+		{"full path", "/src/repo/find.go:12", token.Position{Filename: "/src/repo/find.go", Line: 12}, true},
+		{"other line", "find.go:12", token.Position{Filename: "/src/repo/find.go", Line: 13}, false},
+		{"other directory", "other/find.go:12", token.Position{Filename: "/src/repo/find.go", Line: 12}, false},
+		{"suffix of a name", "nd.go:12", token.Position{Filename: "/src/repo/find.go", Line: 12}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			target, err := ParseTarget(tt.target)
+			if err != nil {
+				t.Fatalf("ParseTarget(%q) failed: %v", tt.target, err)
+			}
+			if got := target.Matches(tt.pos); got != tt.expected {
+				t.Errorf("ParseTarget(%q).Matches(%v) = %v, want %v", tt.target, tt.pos, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestParseTargetInvalid(t *testing.T) {
+	t.Parallel()
+
+	for _, s := range []string{"", "find.go", ":12", "find.go:", "find.go:x", "find.go:0"} {
+		if _, err := ParseTarget(s); err == nil {
+			t.Errorf("ParseTarget(%q) succeeded, want an error", s)
+		}
+	}
+}
@@ -0,0 +1,71 @@
+package internal
+
+import (
+	"cmp"
+	"go/token"
+	"slices"
+
+	"golang.org/x/tools/go/analysis"
+)
+
+// =============================================================================
+// Diagnostic Ordering
+// =============================================================================
+
+// DiagnosticKey is what orders a diagnostic: its file, line and column, then
+// its category and message. Several diagnostics may share a position, e.g. a
+// reuse violation and an unused directive on the same call, so the category and
+// message break the tie and make the order total.
+type DiagnosticKey struct {
+	File     string
+	Line     int
+	Column   int
+	Category string
+	Message  string
+}
+
+// NewDiagnosticKey returns the key of d, whose position is resolved in fset.
+func NewDiagnosticKey(fset *token.FileSet, d analysis.Diagnostic) DiagnosticKey {
+	position := fset.Position(d.Pos)
+	return DiagnosticKey{
+		File:     position.Filename,
+		Line:     position.Line,
+		Column:   position.Column,
+		Category: d.Category,
+		Message:  d.Message,
+	}
+}
+
+// Compare orders k before o by file, line, column, category and then message.
+// It returns a negative number, zero or a positive number like cmp.Compare.
+func (k DiagnosticKey) Compare(o DiagnosticKey) int {
+	return cmp.Or(
+		cmp.Compare(k.File, o.File),
+		cmp.Compare(k.Line, o.Line),
+		cmp.Compare(k.Column, o.Column),
+		cmp.Compare(k.Category, o.Category),
+		cmp.Compare(k.Message, o.Message),
+	)
+}
+
+// sortReports returns a copy of pass whose Report buffers diagnostics, and a
+// flush function that reports them to pass in DiagnosticKey order. The
+// analysis visits functions, closures and passes in an order that has nothing
+// to do with positions, so emitting at the end keeps driver output stable.
+func sortReports(pass *analysis.Pass) (*analysis.Pass, func()) {
+	var buffered []analysis.Diagnostic
+	sorted := *pass
+	sorted.Report = func(d analysis.Diagnostic) {
+		buffered = append(buffered, d)
+	}
+	flush := func() {
+		slices.SortStableFunc(buffered, func(a, b analysis.Diagnostic) int {
+			return NewDiagnosticKey(pass.Fset, a).Compare(NewDiagnosticKey(pass.Fset, b))
+		})
+		for _, d := range buffered {
+			pass.Report(d)
+		}
+		buffered = nil
+	}
+	return &sorted, flush
+}
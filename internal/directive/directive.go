@@ -5,20 +5,32 @@
 // The package supports the following directives:
 //
 //	//gormreuse:ignore           - Suppress warnings for the next line or same line
+//	//gormreuse:ignore-file      - Suppress every diagnostic in the file
+//	//gormreuse:allow-reuse      - Mark the reuse on the next line or same line as intentional
 //	//gormreuse:pure             - Mark function/method as not polluting its *gorm.DB argument
 //	//gormreuse:immutable-return - Mark function/method as returning immutable *gorm.DB
+//	//gormreuse:finisher         - Mark function/method as a terminal use of its *gorm.DB receiver
+//	//gormreuse:sink             - Mark function/method as intentionally consuming its *gorm.DB argument
+//	//gormreuse:impure           - Mark function/method as polluting its *gorm.DB argument despite -assume-pure-helpers
+//
+// The golangci-lint forms //nolint and //nolint:gormreuse suppress the
+// diagnostics of their line like //gormreuse:ignore (see IsNolintDirective).
 //
 // Directives can be combined with commas:
 //
 //	//gormreuse:pure,immutable-return - Both pure and immutable-return
 //
+// A reason may follow a colon or a trailing comment:
+//
+//	//gormreuse:ignore: legacy report, see #12
+//
 // # Directive Placement
 //
 // Directives can be placed:
 //   - On the line before the affected code (most common)
 //   - On the same line as the affected code
 //   - On a function declaration (function-level ignore/pure)
-//   - Before the package declaration (file-level ignore)
+//   - Before the package declaration (file-level ignore, ignore-file)
 //
 // # Examples
 //
@@ -55,14 +67,23 @@
 //	}
 package directive
 
-import "strings"
+import (
+	"go/ast"
+	"strings"
+)
 
 const directivePrefix = "gormreuse:"
 
-// hasDirective checks if a comment contains the specified directive.
-// Supports comma-separated directives: "//gormreuse:pure,immutable-return".
-// Trailing comments use "//": "//gormreuse:ignore // reason here".
-func hasDirective(text, name string) bool {
+// splitDirective splits a gormreuse directive comment into its directives and
+// the reason given for them, which follows either a colon or a trailing "//"
+// comment:
+//
+//	//gormreuse:pure,immutable-return     -> "pure,immutable-return", ""
+//	//gormreuse:ignore: legacy query      -> "ignore", "legacy query"
+//	//gormreuse:ignore // legacy query    -> "ignore", "legacy query"
+//
+// ok is false when the comment is not a gormreuse directive.
+func splitDirective(text string) (directives, reason string, ok bool) {
 	// Accept both line (//gormreuse:...) and block (/*gormreuse:...*/) comment
 	// forms. Without the block form, `/*gormreuse:pure*/` was a silent no-op:
 	// neither applied nor reported as unused.
@@ -73,21 +94,40 @@ func hasDirective(text, name string) bool {
 	}
 	text = strings.TrimSpace(text)
 	if !strings.HasPrefix(text, directivePrefix) {
-		return false
+		return "", "", false
 	}
 	// Extract directive part after prefix
 	text = strings.TrimPrefix(text, directivePrefix)
 
-	// Split off trailing comment (// ...)
+	// Split off the reason (": ..." or "// ...")
 	// e.g., "ignore // reason" -> "ignore"
+	// e.g., "ignore: reason" -> "ignore"
 	// e.g., "pure,immutable-return // note" -> "pure,immutable-return"
-	if idx := strings.Index(text, "//"); idx != -1 {
-		text = text[:idx]
+	cut := len(text)
+	if idx := strings.Index(text, ":"); idx != -1 {
+		cut = idx
 	}
-	text = strings.TrimSpace(text)
+	if idx := strings.Index(text, "//"); idx != -1 && idx < cut {
+		cut = idx
+	}
+	if cut < len(text) {
+		rest := strings.TrimPrefix(strings.TrimPrefix(text[cut:], ":"), "//")
+		text, reason = text[:cut], strings.TrimSpace(rest)
+	}
+	return strings.TrimSpace(text), reason, true
+}
 
+// hasDirective checks if a comment contains the specified directive.
+// Supports comma-separated directives: "//gormreuse:pure,immutable-return".
+// A reason follows a colon or a trailing comment: "//gormreuse:ignore: reason"
+// or "//gormreuse:ignore // reason".
+func hasDirective(text, name string) bool {
+	directives, _, ok := splitDirective(text)
+	if !ok {
+		return false
+	}
 	// Split by comma and check each
-	for _, part := range strings.Split(text, ",") {
+	for _, part := range strings.Split(directives, ",") {
 		if strings.TrimSpace(part) == name {
 			return true
 		}
@@ -95,9 +135,64 @@ func hasDirective(text, name string) bool {
 	return false
 }
 
+// DirectiveReason returns the reason a directive comment gives after a colon
+// or in a trailing comment, or "" when it gives none (see splitDirective).
+func DirectiveReason(text string) string {
+	_, reason, _ := splitDirective(text)
+	return reason
+}
+
+// MayHaveDirectives reports whether any comment of file mentions a gormreuse
+// directive. It is a cheap filter for files with none, such as those of the
+// standard library.
+func MayHaveDirectives(file *ast.File) bool {
+	for _, cg := range file.Comments {
+		for _, c := range cg.List {
+			if strings.Contains(c.Text, directivePrefix) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
 // IsIgnoreDirective checks if a comment is an ignore directive.
 func IsIgnoreDirective(text string) bool { return hasDirective(text, "ignore") }
 
+// IsNolintDirective reports whether a comment is a golangci-lint suppression
+// covering gormreuse: a bare //nolint, or one naming gormreuse or all among
+// its linters. As in golangci-lint there is no space after the slashes, and an
+// explanation may follow in a trailing comment:
+//
+//	//nolint
+//	//nolint:errcheck,gormreuse // legacy report
+func IsNolintDirective(text string) bool {
+	rest, ok := strings.CutPrefix(text, "//nolint")
+	if !ok {
+		return false
+	}
+	if rest == "" || rest[0] == ' ' || rest[0] == '\t' {
+		return true
+	}
+	linters, ok := strings.CutPrefix(rest, ":")
+	if !ok {
+		return false
+	}
+	linters, _, _ = strings.Cut(linters, "//")
+	for _, linter := range strings.Split(linters, ",") {
+		if linter := strings.TrimSpace(linter); linter == "gormreuse" || linter == "all" {
+			return true
+		}
+	}
+	return false
+}
+
+// IsIgnoreFileDirective checks if a comment is an ignore-file directive.
+func IsIgnoreFileDirective(text string) bool { return hasDirective(text, "ignore-file") }
+
+// IsAllowReuseDirective checks if a comment is an allow-reuse directive.
+func IsAllowReuseDirective(text string) bool { return hasDirective(text, "allow-reuse") }
+
 // IsPureDirective checks if a comment contains the pure directive.
 // Pure functions don't pollute their *gorm.DB arguments.
 func IsPureDirective(text string) bool { return hasDirective(text, "pure") }
@@ -112,25 +207,32 @@ func IsImmutableReturnDirective(text string) bool { return hasDirective(text, "i
 // escape hatch for the default-mutable parameter treatment (Phase 1b, #61).
 func IsImmutableParamDirective(text string) bool { return hasDirective(text, "immutable-param") }
 
+// IsFinisherDirective checks if a comment contains the finisher directive.
+// Functions with this directive are terminal uses of their *gorm.DB receiver,
+// like Find, even when their result is assigned.
+func IsFinisherDirective(text string) bool { return hasDirective(text, "finisher") }
+
+// IsSinkDirective checks if a comment contains the sink directive.
+// Functions with this directive intentionally consume their *gorm.DB argument:
+// they pollute it exactly like an unannotated function, but document the intent.
+func IsSinkDirective(text string) bool { return hasDirective(text, "sink") }
+
+// IsImpureDirective checks if a comment contains the impure directive.
+// Functions with this directive pollute their *gorm.DB argument even under
+// -assume-pure-helpers, which otherwise trusts helpers as pure; without the
+// flag they are treated like unannotated functions.
+func IsImpureDirective(text string) bool { return hasDirective(text, "impure") }
+
 // ExtractImmutableInputParams returns the callback parameter names declared by
 // //gormreuse:immutable-input(name) directives in a comment. A comment may carry
 // several (comma-combinable with other directives), so it returns a slice; nil if
 // none. It accepts both line and block comment forms and ignores a trailing "//"
-// comment, mirroring hasDirective (#62).
+// comment or reason, mirroring hasDirective (#62).
 func ExtractImmutableInputParams(text string) []string {
-	if strings.HasPrefix(text, "/*") {
-		text = strings.TrimSuffix(strings.TrimPrefix(text, "/*"), "*/")
-	} else {
-		text = strings.TrimPrefix(text, "//")
-	}
-	text = strings.TrimSpace(text)
-	if !strings.HasPrefix(text, directivePrefix) {
+	text, _, ok := splitDirective(text)
+	if !ok {
 		return nil
 	}
-	text = strings.TrimPrefix(text, directivePrefix)
-	if idx := strings.Index(text, "//"); idx != -1 {
-		text = text[:idx]
-	}
 
 	var params []string
 	for _, part := range strings.Split(text, ",") {
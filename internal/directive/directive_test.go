@@ -20,6 +20,8 @@ func TestIsIgnoreDirective(t *testing.T) {
 		{"with space", "// gormreuse:ignore", true},
 		{"with extra spaces", "//  gormreuse:ignore", true},
 		{"with comment", "//gormreuse:ignore // reason", true},
+		{"with colon reason", "//gormreuse:ignore: reason", true},
+		{"with empty colon reason", "//gormreuse:ignore:", true},
 		{"wrong directive", "//gormreuse:pure", false},
 		{"random comment", "// some comment", false},
 		{"empty", "//", false},
@@ -36,6 +38,67 @@ func TestIsIgnoreDirective(t *testing.T) {
 	}
 }
 
+func TestIsNolintDirective(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		text     string
+		expected bool
+	}{
+		{"bare", "//nolint", true},
+		{"bare with explanation", "//nolint // legacy report", true},
+		{"gormreuse", "//nolint:gormreuse", true},
+		{"among linters", "//nolint:errcheck,gormreuse", true},
+		{"spaced linters", "//nolint:errcheck, gormreuse // legacy", true},
+		{"all", "//nolint:all", true},
+		{"other linter", "//nolint:errcheck", false},
+		{"similar name", "//nolint:gormreusex", false},
+		{"with space", "// nolint:gormreuse", false},
+		{"longer word", "//nolintfoo", false},
+		{"gormreuse directive", "//gormreuse:ignore", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := IsNolintDirective(tt.text); got != tt.expected {
+				t.Errorf("IsNolintDirective(%q) = %v, want %v", tt.text, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestDirectiveReason(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		text     string
+		expected string
+	}{
+		{"no reason", "//gormreuse:ignore", ""},
+		{"colon reason", "//gormreuse:ignore: legacy query", "legacy query"},
+		{"colon without space", "//gormreuse:ignore:legacy", "legacy"},
+		{"empty colon reason", "//gormreuse:ignore:  ", ""},
+		{"trailing comment", "//gormreuse:ignore // legacy query", "legacy query"},
+		{"block comment", "/*gormreuse:ignore: legacy query*/", "legacy query"},
+		{"combined directives", "//gormreuse:pure,immutable-return: wraps WithContext", "wraps WithContext"},
+		{"not a directive", "// ignore: legacy", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := DirectiveReason(tt.text); got != tt.expected {
+				t.Errorf("DirectiveReason(%q) = %q, want %q", tt.text, got, tt.expected)
+			}
+		})
+	}
+}
+
 func TestIsPureDirective(t *testing.T) {
 	t.Parallel()
 
@@ -92,6 +155,89 @@ func TestIsImmutableParamDirective(t *testing.T) {
 	}
 }
 
+func TestIsFinisherDirective(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		text     string
+		expected bool
+	}{
+		{"exact match", "//gormreuse:finisher", true},
+		{"with space", "// gormreuse:finisher", true},
+		{"block comment", "/*gormreuse:finisher*/", true},
+		{"trailing comment", "//gormreuse:finisher // wraps Find", true},
+		{"wrong directive", "//gormreuse:pure", false},
+		{"random comment", "// some comment", false},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if got := IsFinisherDirective(tt.text); got != tt.expected {
+				t.Errorf("IsFinisherDirective(%q) = %v, want %v", tt.text, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestIsSinkDirective(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		text     string
+		expected bool
+	}{
+		{"exact match", "//gormreuse:sink", true},
+		{"with space", "// gormreuse:sink", true},
+		{"block comment", "/*gormreuse:sink*/", true},
+		{"combined", "//gormreuse:sink,immutable-return", true},
+		{"trailing comment", "//gormreuse:sink // logs the query", true},
+		{"wrong directive", "//gormreuse:pure", false},
+		{"random comment", "// some comment", false},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if got := IsSinkDirective(tt.text); got != tt.expected {
+				t.Errorf("IsSinkDirective(%q) = %v, want %v", tt.text, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestIsImpureDirective(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		text     string
+		expected bool
+	}{
+		{"exact match", "//gormreuse:impure", true},
+		{"with space", "// gormreuse:impure", true},
+		{"block comment", "/*gormreuse:impure*/", true},
+		{"combined", "//gormreuse:impure,finisher", true},
+		{"trailing comment", "//gormreuse:impure // runs the query", true},
+		{"wrong directive", "//gormreuse:pure", false},
+		{"random comment", "// some comment", false},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if got := IsImpureDirective(tt.text); got != tt.expected {
+				t.Errorf("IsImpureDirective(%q) = %v, want %v", tt.text, got, tt.expected)
+			}
+		})
+	}
+}
+
 func TestIgnoreMapShouldIgnore(t *testing.T) {
 	t.Parallel()
 
@@ -129,6 +275,38 @@ func TestIgnoreMapShouldIgnore(t *testing.T) {
 	})
 }
 
+func TestIgnoreMapShouldIgnoreRoot(t *testing.T) {
+	t.Parallel()
+
+	t.Run("same line", func(t *testing.T) {
+		t.Parallel()
+
+		m := make(IgnoreMap)
+		m[10] = &ignoreEntry{pos: token.Pos(100), used: false}
+
+		if !m.ShouldIgnoreRoot(10) {
+			t.Error("ShouldIgnoreRoot(10) should return true (same line)")
+		}
+		if len(m.GetUnusedIgnores()) != 0 {
+			t.Error("Entry at line 10 should be marked as used")
+		}
+	})
+
+	t.Run("previous line does not apply", func(t *testing.T) {
+		t.Parallel()
+
+		m := make(IgnoreMap)
+		m[20] = &ignoreEntry{pos: token.Pos(200), used: false}
+
+		if m.ShouldIgnoreRoot(21) {
+			t.Error("ShouldIgnoreRoot(21) should return false (only same line counts)")
+		}
+		if len(m.GetUnusedIgnores()) != 1 {
+			t.Error("Entry at line 20 should remain unused")
+		}
+	})
+}
+
 func TestIgnoreMapFileLevel(t *testing.T) {
 	t.Parallel()
 
@@ -253,6 +431,135 @@ func foo() {}
 	}
 }
 
+func TestBuildIgnoreMapNolint(t *testing.T) {
+	t.Parallel()
+
+	src := `package test
+
+func foo() {
+	a() //nolint:gormreuse
+	b(1, //nolint
+		2)
+	c()
+	//nolint:gormreuse
+	d(1,
+		2)
+	e()
+	f() //nolint:gormreuse
+	g()
+	h(1,
+		2) //nolint:gormreuse
+	i()
+}
+
+// Doc comment.
+//
+//nolint:gormreuse
+func bar() {
+	a()
+	if true {
+		b()
+	}
+}
+
+func baz() {
+	a()
+}
+`
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, "test.go", src, parser.ParseComments)
+	if err != nil {
+		t.Fatalf("Failed to parse: %v", err)
+	}
+
+	m := BuildIgnoreMap(fset, file)
+	for line, want := range map[int]bool{
+		4: true, 5: true, 6: true, 7: false, 8: true, 9: true, 10: true, 11: false,
+		// A trailing //nolint does not reach the line below its statement.
+		12: true, 13: false, 14: false, 15: true, 16: false,
+		// One in the doc comment of a function covers the whole function.
+		22: true, 23: true, 24: true, 25: true, 27: true, 29: false, 30: false,
+	} {
+		if got := m.ShouldIgnore(line); got != want {
+			t.Errorf("ShouldIgnore(%d) = %v, want %v", line, got, want)
+		}
+	}
+	if m.ShouldIgnoreRoot(4) {
+		t.Error("Expected //nolint not to suppress the reuses of a root")
+	}
+	if unused := m.GetUnusedIgnores(); len(unused) != 0 {
+		t.Errorf("Expected //nolint never to be unused, got %v", unused)
+	}
+	if reasonless := m.GetReasonlessIgnores(); len(reasonless) != 0 {
+		t.Errorf("Expected //nolint never to be reasonless, got %v", reasonless)
+	}
+}
+
+func TestBuildAllowReuseMap(t *testing.T) {
+	t.Parallel()
+
+	src := `//gormreuse:allow-reuse
+package test
+
+func foo() {
+	//gormreuse:allow-reuse // single iteration
+	_ = 1 //gormreuse:ignore
+}
+`
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, "test.go", src, parser.ParseComments)
+	if err != nil {
+		t.Fatalf("Failed to parse: %v", err)
+	}
+
+	m := BuildAllowReuseMap(fset, file)
+	if _, ok := m[-1]; ok {
+		t.Error("allow-reuse before the package clause should not be file-level")
+	}
+	if !m.ShouldIgnore(6) {
+		t.Error("Expected allow-reuse on line 5 to cover line 6")
+	}
+	if m.ShouldIgnore(3) {
+		t.Error("Expected line 3 not to be covered")
+	}
+	if unused := m.GetUnusedIgnores(); len(unused) != 1 {
+		t.Errorf("Expected 1 unused allow-reuse (the one above the package clause), got %d", len(unused))
+	}
+}
+
+func TestBuildIgnoreFile(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		src  string
+		want bool
+	}{
+		{"doc comment", "// Package test.\n//\n//gormreuse:ignore-file\npackage test\n", true},
+		{"above license header", "//gormreuse:ignore-file\n\n// License text.\n\npackage test\n", true},
+		{"with reason", "//gormreuse:ignore-file // legacy\npackage test\n", true},
+		{"after package clause", "package test\n\n//gormreuse:ignore-file\nfunc f() {}\n", false},
+		{"plain ignore", "//gormreuse:ignore\npackage test\n", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			fset := token.NewFileSet()
+			file, err := parser.ParseFile(fset, "test.go", tt.src, parser.ParseComments)
+			if err != nil {
+				t.Fatalf("Failed to parse: %v", err)
+			}
+			got := BuildIgnoreFile(fset, file)
+			if (got != nil) != tt.want {
+				t.Fatalf("BuildIgnoreFile() = %v, want directive: %v", got, tt.want)
+			}
+			if got != nil && got.Used() {
+				t.Error("a new directive should not be used")
+			}
+		})
+	}
+}
+
 func TestBuildFunctionIgnoreSet(t *testing.T) {
 	t.Parallel()
 
@@ -307,6 +614,19 @@ func (r GenericReceiver[T]) pureGenericValueMethod() {}
 // gormreuse:pure
 func (r *GenericReceiver[T]) pureGenericPointerMethod() {}
 
+type Builder interface {
+	// 7. Interface method
+	// gormreuse:pure
+	pureInterfaceMethod()
+	notPureInterfaceMethod()
+}
+
+type Hooks struct {
+	// 8. Function-typed field
+	pureFuncField func() // gormreuse:pure
+	notPureField  func()
+}
+
 func notPure() {}
 `
 	fset := token.NewFileSet()
@@ -316,8 +636,8 @@ func notPure() {}
 	}
 
 	set := BuildPureFunctionSet(file, "test/pkg")
-	if len(set) != 6 {
-		t.Errorf("Expected 6 pure functions, got %d", len(set))
+	if len(set) != 8 {
+		t.Errorf("Expected 8 pure functions, got %d", len(set))
 	}
 
 	tests := []struct {
@@ -330,6 +650,8 @@ func notPure() {}
 		{"generic function", FuncKey{PkgPath: "test/pkg", FuncName: "pureGenericFunc"}},
 		{"generic value receiver method", FuncKey{PkgPath: "test/pkg", ReceiverType: "GenericReceiver", FuncName: "pureGenericValueMethod"}},
 		{"generic pointer receiver method", FuncKey{PkgPath: "test/pkg", ReceiverType: "GenericReceiver", FuncName: "pureGenericPointerMethod"}},
+		{"interface method", FuncKey{PkgPath: "test/pkg", ReceiverType: "Builder", FuncName: "pureInterfaceMethod"}},
+		{"function-typed field", FuncKey{PkgPath: "test/pkg", ReceiverType: "Hooks", FuncName: "pureFuncField"}},
 	}
 
 	for _, tt := range tests {
@@ -567,9 +889,53 @@ func TestContainsGormDB(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			t.Parallel()
 
-			got := containsGormDB(tt.typ)
+			got := containsGormDB(tt.typ, nil)
 			if got != tt.expected {
-				t.Errorf("containsGormDB(%s) = %v, want %v", tt.name, got, tt.expected)
+				t.Errorf("containsGormDB(%s, nil) = %v, want %v", tt.name, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestFinisherReceiverIndex(t *testing.T) {
+	t.Parallel()
+
+	gormPkg := types.NewPackage("gorm.io/gorm", "gorm")
+	dbTypeName := types.NewTypeName(0, gormPkg, "DB", nil)
+	dbType := types.NewNamed(dbTypeName, types.NewStruct(nil, nil), nil)
+	gormPkg.Scope().Insert(dbTypeName)
+	dbPtrType := types.NewPointer(dbType)
+
+	testPkg := types.NewPackage("test", "test")
+	repoType := types.NewNamed(types.NewTypeName(0, testPkg, "Repo", nil), types.NewStruct(nil, nil), nil)
+
+	v := func(name string, typ types.Type) *types.Var { return types.NewParam(0, nil, name, typ) }
+	sig := func(recv *types.Var, params ...*types.Var) *types.Signature {
+		return types.NewSignatureType(recv, nil, nil, types.NewTuple(params...), nil, false)
+	}
+	anyType := types.NewInterfaceType(nil, nil)
+
+	tests := []struct {
+		name    string
+		sig     *types.Signature
+		wantIdx int
+		wantOK  bool
+	}{
+		{"*gorm.DB receiver", sig(v("db", dbPtrType), v("dest", anyType)), 0, true},
+		{"function with *gorm.DB first param", sig(nil, v("db", dbPtrType), v("dest", anyType)), 0, true},
+		{"wrapper method with *gorm.DB first param", sig(v("r", repoType), v("db", dbPtrType)), 1, true},
+		{"*gorm.DB not first", sig(nil, v("dest", anyType), v("db", dbPtrType)), 0, false},
+		{"no params", sig(nil), 0, false},
+		{"wrapper method without *gorm.DB", sig(v("r", repoType), v("dest", anyType)), 0, false},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			idx, ok := FinisherReceiverIndex(tt.sig, nil)
+			if ok != tt.wantOK || (ok && idx != tt.wantIdx) {
+				t.Errorf("FinisherReceiverIndex() = (%d, %v), want (%d, %v)", idx, ok, tt.wantIdx, tt.wantOK)
 			}
 		})
 	}
@@ -591,8 +957,8 @@ func TestContainsGormDBDefinedType(t *testing.T) {
 	definedTypeName := types.NewTypeName(0, testPkg, "DefinedDB", nil)
 	definedType := types.NewNamed(definedTypeName, dbPtrType, nil)
 
-	if !containsGormDB(definedType) {
-		t.Error("containsGormDB(DefinedDB) should return true for type DefinedDB *gorm.DB")
+	if !containsGormDB(definedType, nil) {
+		t.Error("containsGormDB(DefinedDB, nil) should return true for type DefinedDB *gorm.DB")
 	}
 }
 
@@ -600,8 +966,8 @@ func TestContainsGormDBNilType(t *testing.T) {
 	t.Parallel()
 
 	// Test nil type
-	if containsGormDB(nil) {
-		t.Error("containsGormDB(nil) should return false")
+	if containsGormDB(nil, nil) {
+		t.Error("containsGormDB(nil, nil) should return false")
 	}
 }
 
@@ -623,8 +989,8 @@ func TestContainsGormDBCycleDetection(t *testing.T) {
 	recursiveType.SetUnderlying(actualStruct)
 
 	// Should not panic and should return false (no *gorm.DB)
-	got := containsGormDB(recursiveType)
+	got := containsGormDB(recursiveType, nil)
 	if got {
-		t.Error("containsGormDB(Recursive) should return false")
+		t.Error("containsGormDB(Recursive, nil) should return false")
 	}
 }
@@ -14,8 +14,12 @@ import (
 // ignoreEntry tracks an ignore directive and whether it was used.
 // Used to report "unused ignore directive" warnings.
 type ignoreEntry struct {
-	pos  token.Pos // Position of the ignore comment (for reporting unused)
-	used bool      // Whether this ignore was actually used to suppress a warning
+	pos    token.Pos // Position of the ignore comment (for reporting unused)
+	used   bool      // Whether this ignore was actually used to suppress a warning
+	reason string    // Reason given by the directive (see DirectiveReason)
+	nolint bool      // A golangci-lint //nolint, never reported unused or reasonless
+	line   int       // Line of a //nolint, which covers the lines of its statement too
+	alone  bool      // A //nolint with no code before it on its line, which covers the next line
 }
 
 // IgnoreMap tracks line numbers that have ignore comments.
@@ -43,8 +47,23 @@ type IgnoreMap map[int]*ignoreEntry
 // The returned map uses line numbers as keys:
 //   - Positive line: ignore directive for next line
 //   - Line -1: file-level ignore (all lines)
+//
+// A golangci-lint //nolint or //nolint:gormreuse (see IsNolintDirective)
+// suppresses its line like an ignore, and every line of the statement it
+// starts, as golangci-lint does:
+//
+//	q.Where("a = ?", a). //nolint:gormreuse
+//	    Find(nil)        // ignored too
+//	q.Count(nil)         // not ignored
+//
+// Only a //nolint alone on its line covers the line or statement below it, and
+// one in the doc comment of a function covers the whole function.
+//
+// Unlike an ignore it is never reported unused or reasonless, and it does not
+// suppress the reuses of a root defined on its line.
 func BuildIgnoreMap(fset *token.FileSet, file *ast.File) IgnoreMap {
 	m := make(IgnoreMap)
+	var nolints []*ast.Comment
 
 	// Get package declaration line for file-level ignore detection
 	packageLine := fset.Position(file.Package).Line
@@ -63,10 +82,33 @@ func BuildIgnoreMap(fset *token.FileSet, file *ast.File) IgnoreMap {
 				if pos.Line < packageLine {
 					// File-level ignore: mark all lines as ignored (line -1 marker).
 					// File-level ignores are always considered "used" (no warning).
-					m[-1] = &ignoreEntry{pos: c.Pos(), used: true}
+					m[-1] = &ignoreEntry{pos: c.Pos(), used: true, reason: DirectiveReason(c.Text)}
 				} else {
 					// Regular line-level ignore
-					m[pos.Line] = &ignoreEntry{pos: c.Pos(), used: false}
+					m[pos.Line] = &ignoreEntry{pos: c.Pos(), used: false, reason: DirectiveReason(c.Text)}
+				}
+			} else if IsNolintDirective(c.Text) {
+				nolints = append(nolints, c)
+			}
+		}
+	}
+	if len(nolints) > 0 {
+		stmtEnds := statementEnds(fset, file)
+		codeLines := codeLines(fset, file)
+		funcEnds := docFuncEnds(fset, file)
+		for _, c := range nolints {
+			line := fset.Position(c.Pos()).Line
+			start, hasCode := codeLines[line]
+			alone := !hasCode || start > c.Pos()
+			entry := &ignoreEntry{pos: c.Pos(), nolint: true, line: line, alone: alone}
+			end := max(line, stmtEnds[line], funcEnds[c.Pos()])
+			if alone {
+				end = max(end, stmtEnds[line+1])
+			}
+			for l := line; l <= end; l++ {
+				// An ignore on a line stays the entry reported unused.
+				if _, ok := m[l]; !ok {
+					m[l] = entry
 				}
 			}
 		}
@@ -78,7 +120,7 @@ func BuildIgnoreMap(fset *token.FileSet, file *ast.File) IgnoreMap {
 			if IsIgnoreDirective(c.Text) {
 				// File-level ignore: mark all lines as ignored
 				// File-level ignores are always considered "used" (no warning for them)
-				m[-1] = &ignoreEntry{pos: c.Pos(), used: true}
+				m[-1] = &ignoreEntry{pos: c.Pos(), used: true, reason: DirectiveReason(c.Text)}
 			}
 		}
 	}
@@ -86,6 +128,124 @@ func BuildIgnoreMap(fset *token.FileSet, file *ast.File) IgnoreMap {
 	return m
 }
 
+// statementEnds maps the first line of each simple statement of file, such as
+// an assignment or a call, to its last line. Blocks, ifs, loops, switches and
+// selects are left out so that a //nolint on their first line does not cover
+// everything inside them.
+func statementEnds(fset *token.FileSet, file *ast.File) map[int]int {
+	ends := make(map[int]int)
+	ast.Inspect(file, func(n ast.Node) bool {
+		switch n.(type) {
+		case *ast.AssignStmt, *ast.ExprStmt, *ast.ReturnStmt, *ast.DeclStmt,
+			*ast.DeferStmt, *ast.GoStmt, *ast.SendStmt, *ast.IncDecStmt:
+			start := fset.Position(n.Pos()).Line
+			ends[start] = max(ends[start], fset.Position(n.End()).Line)
+		}
+		return true
+	})
+	return ends
+}
+
+// codeLines maps each line of file holding code to the first position of code
+// on it, so that a comment on the line can tell whether code precedes it.
+func codeLines(fset *token.FileSet, file *ast.File) map[int]token.Pos {
+	lines := make(map[int]token.Pos)
+	record := func(pos token.Pos) {
+		line := fset.Position(pos).Line
+		if first, ok := lines[line]; !ok || pos < first {
+			lines[line] = pos
+		}
+	}
+	ast.Inspect(file, func(n ast.Node) bool {
+		switch n.(type) {
+		case nil, *ast.CommentGroup, *ast.Comment:
+			return false
+		}
+		record(n.Pos())
+		record(n.End() - 1)
+		return true
+	})
+	return lines
+}
+
+// docFuncEnds maps each //nolint comment in the doc comment of a function of
+// file to the last line of that function.
+func docFuncEnds(fset *token.FileSet, file *ast.File) map[token.Pos]int {
+	ends := make(map[token.Pos]int)
+	for _, decl := range file.Decls {
+		fn, ok := decl.(*ast.FuncDecl)
+		if !ok || fn.Doc == nil {
+			continue
+		}
+		for _, c := range fn.Doc.List {
+			if IsNolintDirective(c.Text) {
+				ends[c.Pos()] = fset.Position(fn.End()).Line
+			}
+		}
+	}
+	return ends
+}
+
+// BuildAllowReuseMap scans a file for allow-reuse comments and returns a map
+// with the same line semantics as a line-level or root-level ignore:
+//
+//	//gormreuse:allow-reuse      // Line 5 → map[5]
+//	q.Count(nil)                 // Line 6 → reuse allowed
+//
+//	q := db.Where("x") //gormreuse:allow-reuse // every reuse of q allowed
+//
+// Unlike ignore, allow-reuse documents a reuse confirmed to be safe, so it
+// only suppresses reuse violations and has no file-level or function-level
+// form. Its entries are tracked separately, so an unused allow-reuse is
+// reported on its own.
+func BuildAllowReuseMap(fset *token.FileSet, file *ast.File) IgnoreMap {
+	m := make(IgnoreMap)
+	for _, cg := range file.Comments {
+		for _, c := range cg.List {
+			if IsAllowReuseDirective(c.Text) {
+				m[fset.Position(c.Pos()).Line] = &ignoreEntry{pos: c.Pos()}
+			}
+		}
+	}
+	return m
+}
+
+// IgnoreFile is a //gormreuse:ignore-file directive. Unlike a file-level
+// //gormreuse:ignore, which only suppresses reuse violations, it suppresses
+// every diagnostic reported in its file, including directive and contract
+// problems. The file is still analyzed, so its directives keep applying to the
+// rest of the package.
+type IgnoreFile struct {
+	Pos  token.Pos // Position of the directive (for reporting it unused)
+	used bool
+}
+
+// BuildIgnoreFile returns the //gormreuse:ignore-file directive of a file, or
+// nil when it has none. Like a file-level ignore, the directive must be placed
+// before the package clause, typically in the file's doc comment:
+//
+//	// Code maintained by hand; reuse here is legacy.
+//	//
+//	//gormreuse:ignore-file
+//	package legacy
+func BuildIgnoreFile(fset *token.FileSet, file *ast.File) *IgnoreFile {
+	packageLine := fset.Position(file.Package).Line
+	for _, cg := range file.Comments {
+		for _, c := range cg.List {
+			if fset.Position(c.Pos()).Line < packageLine && IsIgnoreFileDirective(c.Text) {
+				return &IgnoreFile{Pos: c.Pos()}
+			}
+		}
+	}
+	return nil
+}
+
+// MarkUsed records that the directive suppressed a diagnostic.
+func (f *IgnoreFile) MarkUsed() { f.used = true }
+
+// Used reports whether the directive suppressed any diagnostic.
+func (f *IgnoreFile) Used() bool { return f.used }
+
 // ShouldIgnore returns true if the given line should be ignored.
 // It checks if:
 // - File-level ignore is active (marker at line -1)
@@ -102,7 +262,28 @@ func (m IgnoreMap) ShouldIgnore(line int) bool {
 		entry.used = true
 		return true
 	}
-	if entry, onPrevLine := m[line-1]; onPrevLine {
+	// Only a //nolint alone on the previous line reaches this line: one
+	// trailing code, or covering the previous line as part of a statement,
+	// does not reach past the statement.
+	if entry, onPrevLine := m[line-1]; onPrevLine && (!entry.nolint || entry.alone && entry.line == line-1) {
+		entry.used = true
+		return true
+	}
+	return false
+}
+
+// ShouldIgnoreRoot returns true if the root defined on the given line carries
+// a root-level ignore directive, i.e. an ignore comment on that same line:
+//
+//	q := db.Where("x") //gormreuse:ignore  // suppresses every reuse of q
+//	q.Find(nil)
+//	q.Count(nil)
+//
+// Only the same line counts: a directive on the previous line keeps its
+// line-level meaning and never widens to a root that happens to be defined
+// below it. When the directive is used, it marks the entry as used.
+func (m IgnoreMap) ShouldIgnoreRoot(line int) bool {
+	if entry, onSameLine := m[line]; onSameLine && !entry.nolint {
 		entry.used = true
 		return true
 	}
@@ -117,13 +298,28 @@ func (m IgnoreMap) GetUnusedIgnores() []token.Pos {
 			// Skip file-level ignores
 			continue
 		}
-		if !entry.used {
+		if !entry.used && !entry.nolint {
 			unused = append(unused, entry.pos)
 		}
 	}
 	return unused
 }
 
+// GetReasonlessIgnores returns the positions of ignore directives, file-level
+// ones included, that give no reason:
+//
+//	//gormreuse:ignore                  // reasonless
+//	//gormreuse:ignore: legacy report   // reason "legacy report"
+func (m IgnoreMap) GetReasonlessIgnores() []token.Pos {
+	var reasonless []token.Pos
+	for _, entry := range m {
+		if entry.reason == "" && !entry.nolint {
+			reasonless = append(reasonless, entry.pos)
+		}
+	}
+	return reasonless
+}
+
 // MarkUsed marks the ignore directive at the given line as used.
 func (m IgnoreMap) MarkUsed(line int) {
 	if entry, ok := m[line]; ok {
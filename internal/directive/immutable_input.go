@@ -7,6 +7,8 @@ import (
 	"go/types"
 
 	"golang.org/x/tools/go/ssa"
+
+	"github.com/mpyw/gormreuse/internal/typeutil"
 )
 
 // =============================================================================
@@ -41,15 +43,17 @@ type ImmutableInputSet struct {
 
 	fset      *token.FileSet
 	typesInfo *types.Info
+	gormTypes *typeutil.Matcher
 }
 
 // NewImmutableInputSet creates an empty set tied to the pass's FileSet and
-// TypesInfo.
-func NewImmutableInputSet(fset *token.FileSet, typesInfo *types.Info) *ImmutableInputSet {
+// TypesInfo, and to the configured DB types (nil for gorm.io/gorm.DB only).
+func NewImmutableInputSet(fset *token.FileSet, typesInfo *types.Info, gormTypes *typeutil.Matcher) *ImmutableInputSet {
 	return &ImmutableInputSet{
 		known:     make(map[FuncKey][]ImmutableInputCallback),
 		fset:      fset,
 		typesInfo: typesInfo,
+		gormTypes: gormTypes,
 	}
 }
 
@@ -112,7 +116,7 @@ func (s *ImmutableInputSet) processDirectiveTargets(fd *ast.FuncDecl, refs []par
 			})
 			continue
 		}
-		if !hasGormDBParameter(funcSig) {
+		if !hasGormDBParameter(funcSig, s.gormTypes) {
 			s.unused = append(s.unused, ImmutableInputUnused{
 				Pos:    ref.commentPos,
 				Reason: fmt.Sprintf("unused gormreuse:immutable-input directive: callback %q has no *gorm.DB parameter", ref.name),
@@ -52,7 +52,7 @@ func TestImmutableInputSet_AddFile_NoDirectives(t *testing.T) {
 
 	const src = "package demo\n\nfunc plain(cb func()) {}\n"
 	fset, file, info := parseFileWithTypes(t, src)
-	s := NewImmutableInputSet(fset, info)
+	s := NewImmutableInputSet(fset, info, nil)
 	s.AddFile(file, "demo")
 	if got := len(s.GetUnused()); got != 0 {
 		t.Errorf("GetUnused() with no directives = %d, want 0", got)
@@ -64,7 +64,7 @@ func TestImmutableInputSet_AddFile_ParamNotFound(t *testing.T) {
 
 	const src = "package demo\n\n//gormreuse:immutable-input(missing)\nfunc noSuchParam(cb func()) {}\n"
 	fset, file, info := parseFileWithTypes(t, src)
-	s := NewImmutableInputSet(fset, info)
+	s := NewImmutableInputSet(fset, info, nil)
 	s.AddFile(file, "demo")
 	assertOneUnused(t, s, `parameter "missing" not found`)
 }
@@ -74,7 +74,7 @@ func TestImmutableInputSet_AddFile_ParamNotFunctionType(t *testing.T) {
 
 	const src = "package demo\n\n//gormreuse:immutable-input(x)\nfunc notFn(x int) {}\n"
 	fset, file, info := parseFileWithTypes(t, src)
-	s := NewImmutableInputSet(fset, info)
+	s := NewImmutableInputSet(fset, info, nil)
 	s.AddFile(file, "demo")
 	assertOneUnused(t, s, `parameter "x" is not a function type`)
 }
@@ -86,7 +86,7 @@ func TestImmutableInputSet_AddFile_CallbackHasNoGormDB(t *testing.T) {
 
 	const src = "package demo\n\n//gormreuse:immutable-input(cb)\nfunc cbNoGormDB(cb func(int) int) {}\n"
 	fset, file, info := parseFileWithTypes(t, src)
-	s := NewImmutableInputSet(fset, info)
+	s := NewImmutableInputSet(fset, info, nil)
 	s.AddFile(file, "demo")
 	assertOneUnused(t, s, "no *gorm.DB parameter")
 }
@@ -99,7 +99,7 @@ func TestImmutableInputSet_AddFile_AnonymousParam(t *testing.T) {
 
 	const src = "package demo\n\n//gormreuse:immutable-input(missing)\nfunc anon(int, cb func()) {}\n"
 	fset, file, info := parseFileWithTypes(t, src)
-	s := NewImmutableInputSet(fset, info)
+	s := NewImmutableInputSet(fset, info, nil)
 	s.AddFile(file, "demo")
 	assertOneUnused(t, s, `parameter "missing" not found`)
 }
@@ -112,7 +112,7 @@ func TestImmutableInputSet_AddFile_NilTypesInfo(t *testing.T) {
 
 	const src = "package demo\n\n//gormreuse:immutable-input(cb)\nfunc noTypeInfo(cb func()) {}\n"
 	fset, file, _ := parseFileWithTypes(t, src)
-	s := NewImmutableInputSet(fset, nil) // no TypesInfo
+	s := NewImmutableInputSet(fset, nil, nil) // no TypesInfo
 	s.AddFile(file, "demo")
 	assertOneUnused(t, s, `parameter "cb" is not a function type`)
 }
@@ -6,10 +6,13 @@ import (
 	"go/token"
 	"go/types"
 	"strings"
+	"sync"
 
 	"golang.org/x/tools/go/ast/astutil"
 	"golang.org/x/tools/go/ast/inspector"
 	"golang.org/x/tools/go/ssa"
+
+	"github.com/mpyw/gormreuse/internal/typeutil"
 )
 
 // =============================================================================
@@ -70,7 +73,7 @@ type directiveChecker func(text string) bool
 // signatureValidator checks if a function signature is valid for the directive.
 // For pure: returns true if any parameter contains *gorm.DB
 // For immutable-return: returns true if any return value contains *gorm.DB
-type signatureValidator func(*types.Signature) bool
+type signatureValidator func(*types.Signature, *typeutil.Matcher) bool
 
 // DirectiveFuncSet is a generic set of functions matching a directive.
 // It supports both pre-built sets (for current package) and on-demand
@@ -78,15 +81,25 @@ type signatureValidator func(*types.Signature) bool
 // It also tracks which directives have invalid signatures to report unused directives.
 type DirectiveFuncSet struct {
 	known               map[FuncKey]struct{}
+	packages            map[string]struct{} // Import paths whose every function is in the set (AddPackage)
 	fset                *token.FileSet
 	typesInfo           *types.Info            // Type info for signature validation
+	gormTypes           *typeutil.Matcher      // Configured DB types for signature validation
 	files               map[string]*ast.File   // Original parsed files (from analysis)
 	cache               map[string]*ast.File   // Cache for external files (re-parsed)
 	isDirective         directiveChecker       // Checks if comment is this directive
 	validateSignature   signatureValidator     // Checks if signature is valid for this directive
 	invalidDirectives   map[token.Pos]struct{} // Directives on functions with invalid signatures
 	processedDirectives map[token.Pos]struct{} // All directive positions processed by this set
-
+	facts               func(*types.Func) bool // Fact lookup for exported functions of other packages (UseFacts)
+	members             bool                   // The directive also applies to interface methods and function-typed struct fields (see ContainsMember)
+	trusted             map[*types.Func]bool   // Functions found by their fact only, their source being unreadable, keyed by origin (see IsTrusted); guarded by mu
+
+	// mu guards the caches below, so that Contains may be called from several
+	// goroutines once the files are added. The caches of an added file are
+	// built by AddFile; only re-parsed external files and hasCodeBeforeComment
+	// results are filled lazily, under the write lock.
+	mu sync.RWMutex
 	// Cache for hasCodeBeforeComment results to avoid O(comments * nodes) complexity
 	codeBeforeCommentCache map[*ast.File]map[token.Pos]bool
 	// Cache for FuncLit line numbers per file to avoid O(nodes) lookup per line check
@@ -99,14 +112,16 @@ type DirectiveFuncSet struct {
 var (
 	funcDeclTypes = []ast.Node{(*ast.FuncDecl)(nil)}
 	funcLitTypes  = []ast.Node{(*ast.FuncLit)(nil)}
+	typeSpecTypes = []ast.Node{(*ast.TypeSpec)(nil)}
 )
 
 // newDirectiveFuncSet creates a new DirectiveFuncSet with the given directive checker and signature validator.
-func newDirectiveFuncSet(fset *token.FileSet, typesInfo *types.Info, isDirective directiveChecker, validateSignature signatureValidator) *DirectiveFuncSet {
+func newDirectiveFuncSet(fset *token.FileSet, typesInfo *types.Info, gormTypes *typeutil.Matcher, isDirective directiveChecker, validateSignature signatureValidator) *DirectiveFuncSet {
 	return &DirectiveFuncSet{
 		known:                  make(map[FuncKey]struct{}),
 		fset:                   fset,
 		typesInfo:              typesInfo,
+		gormTypes:              gormTypes,
 		files:                  make(map[string]*ast.File),
 		cache:                  make(map[string]*ast.File),
 		isDirective:            isDirective,
@@ -116,15 +131,25 @@ func newDirectiveFuncSet(fset *token.FileSet, typesInfo *types.Info, isDirective
 		codeBeforeCommentCache: make(map[*ast.File]map[token.Pos]bool),
 		funcLitLinesCache:      make(map[*ast.File]map[int]bool),
 		inspectorCache:         make(map[*ast.File]*inspector.Inspector),
+		trusted:                make(map[*types.Func]bool),
 	}
 }
 
 // getInspector returns a cached inspector for the given file.
 func (s *DirectiveFuncSet) getInspector(file *ast.File) *inspector.Inspector {
+	s.mu.RLock()
+	insp, ok := s.inspectorCache[file]
+	s.mu.RUnlock()
+	if ok {
+		return insp
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
 	if insp, ok := s.inspectorCache[file]; ok {
 		return insp
 	}
-	insp := inspector.New([]*ast.File{file})
+	insp = inspector.New([]*ast.File{file})
 	s.inspectorCache[file] = insp
 	return insp
 }
@@ -142,6 +167,12 @@ func (s *DirectiveFuncSet) AddFile(file *ast.File) {
 
 	// Collect all directive positions in this file for unused detection
 	s.collectDirectivePositions(file)
+
+	// Build the FuncLit lines eagerly, so lookups in added files only read.
+	lines := s.buildFuncLitLines(file)
+	s.mu.Lock()
+	s.funcLitLinesCache[file] = lines
+	s.mu.Unlock()
 }
 
 // collectDirectivePositions scans a file for all directives and validates their signatures.
@@ -204,9 +235,27 @@ func (s *DirectiveFuncSet) collectDirectivePositions(file *ast.File) {
 		}
 	}
 
+	// Check directives on interface methods and function-typed struct fields,
+	// for the sets that support them
+	associatedDirectives := make(map[token.Pos]bool)
+	if s.members {
+		insp.Preorder(typeSpecTypes, func(n ast.Node) {
+			for _, field := range memberFields(n.(*ast.TypeSpec)) {
+				pos := s.fieldDirective(field)
+				if !pos.IsValid() {
+					continue
+				}
+				associatedDirectives[pos] = true
+				s.processedDirectives[pos] = struct{}{}
+				if !s.validateFieldSignature(field) {
+					s.invalidDirectives[pos] = struct{}{}
+				}
+			}
+		})
+	}
+
 	// Find orphan directives (not associated with any function)
 	// These are always invalid
-	associatedDirectives := make(map[token.Pos]bool)
 
 	// Collect all directive positions that are associated with functions
 	insp.Preorder(funcDeclTypes, func(n ast.Node) {
@@ -257,7 +306,24 @@ func (s *DirectiveFuncSet) validateFuncDeclSignature(fd *ast.FuncDecl) bool {
 	if !ok {
 		return true
 	}
-	return s.validateSignature(sig)
+	return s.validateSignature(sig, s.gormTypes)
+}
+
+// validateFieldSignature checks if an interface method or a function-typed
+// struct field has a valid signature for this directive.
+func (s *DirectiveFuncSet) validateFieldSignature(field *ast.Field) bool {
+	if s.typesInfo == nil || s.validateSignature == nil || len(field.Names) == 0 {
+		return true // Can't validate without type info, assume valid
+	}
+	obj := s.typesInfo.Defs[field.Names[0]]
+	if obj == nil {
+		return true
+	}
+	sig, ok := obj.Type().Underlying().(*types.Signature)
+	if !ok {
+		return true
+	}
+	return s.validateSignature(sig, s.gormTypes)
 }
 
 // validateFuncLitSignature checks if a FuncLit has a valid signature for this directive.
@@ -273,7 +339,7 @@ func (s *DirectiveFuncSet) validateFuncLitSignature(fl *ast.FuncLit) bool {
 	if !ok {
 		return true
 	}
-	return s.validateSignature(sig)
+	return s.validateSignature(sig, s.gormTypes)
 }
 
 // Add adds a function key to the set.
@@ -283,6 +349,31 @@ func (s *DirectiveFuncSet) Add(key FuncKey) {
 	}
 }
 
+// AddPackage adds every function and method of the package with the given
+// import path to the set, as if each carried the directive. -pure-pkg uses it
+// for assertion packages, whose functions only read their arguments:
+//
+//	assert.NotNil(t, q)  // does not pollute q with github.com/stretchr/testify/assert added
+func (s *DirectiveFuncSet) AddPackage(path string) {
+	if s == nil {
+		return
+	}
+	if s.packages == nil {
+		s.packages = make(map[string]struct{})
+	}
+	s.packages[path] = struct{}{}
+}
+
+// UseFacts makes the set look up exported functions of other packages with
+// has, which reports the analysis fact exported for them when their package
+// was analyzed, rather than by re-parsing their source. Unexported functions,
+// which carry no facts, are still looked up in the source.
+func (s *DirectiveFuncSet) UseFacts(has func(*types.Func) bool) {
+	if s != nil {
+		s.facts = has
+	}
+}
+
 // GetUnusedDirectives returns the positions of directives on functions with invalid signatures.
 // A directive is "unused" if:
 //   - For pure: the function has no *gorm.DB in its parameters
@@ -322,7 +413,16 @@ func (s *DirectiveFuncSet) Contains(fn *ssa.Function) bool {
 		return false
 	}
 
-	// First, check the pre-built set (for current package)
+	// Every function of an added package is in the set (AddPackage)
+	if s != nil && len(s.packages) > 0 {
+		if obj := fn.Object(); obj != nil && obj.Pkg() != nil {
+			if _, exists := s.packages[obj.Pkg().Path()]; exists {
+				return true
+			}
+		}
+	}
+
+	// Then, check the pre-built set (for current package)
 	if s != nil && s.known != nil {
 		key := FuncKey{FuncName: fn.Name()}
 		if fn.Pkg != nil && fn.Pkg.Pkg != nil {
@@ -342,60 +442,123 @@ func (s *DirectiveFuncSet) Contains(fn *ssa.Function) bool {
 
 // hasDirective checks if an SSA function has the directive.
 func (s *DirectiveFuncSet) hasDirective(fn *ssa.Function) bool {
-	if fn == nil {
+	if s == nil || fn == nil {
 		return false
 	}
 
 	// Try getting syntax from the SSA function (works for current package)
 	switch syntax := fn.Syntax().(type) {
 	case *ast.FuncDecl:
-		// Check Doc comments (next-line pattern)
-		if syntax.Doc != nil {
-			for _, c := range syntax.Doc.List {
-				if s.isDirective(c.Text) {
-					return true
-				}
-			}
-		}
-		// Check same-line pattern (after opening brace)
-		if pos := s.findDirectiveAfterFuncDeclBrace(syntax); pos.IsValid() {
-			return true
-		}
+		return s.funcDeclHasDirective(syntax)
 	case *ast.FuncLit:
 		// Closures don't have Doc comments in Go, so we look for comments
 		// immediately before or after the opening brace.
-		if pos := s.findDirectiveForFuncLit(syntax); pos.IsValid() {
-			return true
+		return s.findDirectiveForFuncLit(syntax).IsValid()
+	}
+
+	// Fallback for external packages: the fact exported by their analysis,
+	// or else the directive of their re-parsed declaration
+	if s.facts != nil {
+		if obj, ok := fn.Object().(*types.Func); ok && obj.Exported() {
+			found := s.facts(obj.Origin())
+			if found && s.externalFuncDecl(fn) == nil {
+				s.trust(obj)
+			}
+			return found
 		}
 	}
+	if funcDecl := s.externalFuncDecl(fn); funcDecl != nil {
+		return s.funcDeclHasDirective(funcDecl)
+	}
+	return false
+}
 
-	// Fallback: parse the source file for external packages
-	if s == nil || s.fset == nil {
+// trust records obj as in the set by its fact although its source cannot be
+// read (see IsTrusted).
+func (s *DirectiveFuncSet) trust(obj *types.Func) {
+	s.mu.Lock()
+	s.trusted[obj.Origin()] = true
+	s.mu.Unlock()
+}
+
+// IsTrusted reports whether fn is in the set by the fact of another package
+// only, its source being unreadable, as in a stripped build or generated code
+// whose //line directives name files that do not exist. Its body can then be
+// validated nowhere, so the directive's contract is taken on trust. Functions
+// whose source is readable are validated when their own package is analyzed,
+// and functions of an added package (AddPackage) are not trusted in this
+// sense.
+func (s *DirectiveFuncSet) IsTrusted(fn *ssa.Function) bool {
+	if s == nil || fn == nil || !s.Contains(fn) {
 		return false
 	}
-	obj := fn.Object()
-	if obj == nil {
+	obj, ok := fn.Object().(*types.Func)
+	if !ok {
 		return false
 	}
-	pos := obj.Pos()
-	if !pos.IsValid() {
-		return false
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.trusted[obj.Origin()]
+}
+
+// DeclHasDirective reports whether the declaration fd of a file added with
+// AddFile carries the directive, in its doc comment or after its opening brace.
+func (s *DirectiveFuncSet) DeclHasDirective(fd *ast.FuncDecl) bool {
+	return s != nil && s.funcDeclHasDirective(fd)
+}
+
+// funcDeclHasDirective checks a function declaration for the directive, either
+// in its Doc comments (next-line pattern) or after its opening brace
+// (same-line pattern).
+func (s *DirectiveFuncSet) funcDeclHasDirective(funcDecl *ast.FuncDecl) bool {
+	if funcDecl.Doc != nil {
+		for _, c := range funcDecl.Doc.List {
+			if s.isDirective(c.Text) {
+				return true
+			}
+		}
 	}
-	filename := s.fset.Position(pos).Filename
-	if filename == "" {
-		return false
+	return s.findDirectiveAfterFuncDeclBrace(funcDecl).IsValid()
+}
+
+// externalFuncDecl returns the declaration of a function whose SSA form carries
+// no syntax (functions of other packages, including sibling modules of a
+// go.work workspace).
+//
+// The declaring file is resolved from the object's position in the file set
+// shared with the loaded packages, and the declaration is matched by position
+// rather than by name: an instantiated generic function is named F[int] and a
+// generic method's receiver is Repo[T], neither of which matches the source.
+func (s *DirectiveFuncSet) externalFuncDecl(fn *ssa.Function) *ast.FuncDecl {
+	if s == nil || s.fset == nil {
+		return nil
+	}
+	if origin := fn.Origin(); origin != nil {
+		fn = origin
 	}
-	file := s.parseFile(filename)
+	obj := fn.Object()
+	if obj == nil || !obj.Pos().IsValid() {
+		return nil
+	}
+	file := s.getFileForPos(obj.Pos())
 	if file == nil {
-		return false
+		return nil
 	}
 
-	funcName := fn.Name()
-	var receiverType string
-	if sig := fn.Signature; sig != nil && sig.Recv() != nil {
-		receiverType = formatReceiverType(sig.Recv().Type())
+	// A re-parsed file lives at different token.Pos offsets than the loaded
+	// package, so compare line and column instead.
+	want := s.fset.Position(obj.Pos())
+	for _, decl := range file.Decls {
+		funcDecl, ok := decl.(*ast.FuncDecl)
+		if !ok {
+			continue
+		}
+		got := s.fset.Position(funcDecl.Name.Pos())
+		if got.Line == want.Line && got.Column == want.Column {
+			return funcDecl
+		}
 	}
-	return s.hasDirectiveInFile(file, funcName, receiverType)
+	return nil
 }
 
 // hasMatchingDirective finds a directive comment in the file that satisfies the given predicate.
@@ -521,9 +684,13 @@ func (s *DirectiveFuncSet) findDirectiveForFuncLit(funcLit *ast.FuncLit) token.P
 }
 
 // getFileForNode returns the AST file containing the given node.
-// It first checks for original files (from analysis), then falls back to re-parsing.
 func (s *DirectiveFuncSet) getFileForNode(node ast.Node) *ast.File {
-	pos := node.Pos()
+	return s.getFileForPos(node.Pos())
+}
+
+// getFileForPos returns the AST file containing the given position.
+// It first checks for original files (from analysis), then falls back to re-parsing.
+func (s *DirectiveFuncSet) getFileForPos(pos token.Pos) *ast.File {
 	if !pos.IsValid() {
 		return nil
 	}
@@ -833,22 +1000,28 @@ func (s *DirectiveFuncSet) isDirectExprOrUnaryByPos(expr ast.Expr, targetPos, ta
 // hasFuncLitOnLine checks if there's any FuncLit that starts on the given line.
 // Results are cached per file to avoid O(nodes) traversal per line check.
 func (s *DirectiveFuncSet) hasFuncLitOnLine(file *ast.File, line int) bool {
-	// Build cache if not exists
-	if _, ok := s.funcLitLinesCache[file]; !ok {
-		s.buildFuncLitLinesCache(file)
+	// Build cache if not exists (a re-parsed file, not added by AddFile)
+	s.mu.RLock()
+	lines, ok := s.funcLitLinesCache[file]
+	s.mu.RUnlock()
+	if !ok {
+		lines = s.buildFuncLitLines(file)
+		s.mu.Lock()
+		s.funcLitLinesCache[file] = lines
+		s.mu.Unlock()
 	}
-	return s.funcLitLinesCache[file][line]
+	return lines[line]
 }
 
-// buildFuncLitLinesCache pre-computes all FuncLit line numbers for a file.
-func (s *DirectiveFuncSet) buildFuncLitLinesCache(file *ast.File) {
+// buildFuncLitLines pre-computes all FuncLit line numbers for a file.
+func (s *DirectiveFuncSet) buildFuncLitLines(file *ast.File) map[int]bool {
 	lines := make(map[int]bool)
 	insp := s.getInspector(file)
 	insp.Preorder(funcLitTypes, func(n ast.Node) {
 		fl := n.(*ast.FuncLit)
 		lines[s.fset.Position(fl.Pos()).Line] = true
 	})
-	s.funcLitLinesCache[file] = lines
+	return lines
 }
 
 // hasCodeBeforeComment checks if there's any code (non-whitespace) before the comment on the same line.
@@ -856,19 +1029,22 @@ func (s *DirectiveFuncSet) buildFuncLitLinesCache(file *ast.File) {
 // Results are cached per file to avoid O(comments * nodes) complexity.
 func (s *DirectiveFuncSet) hasCodeBeforeComment(file *ast.File, cg *ast.CommentGroup) bool {
 	// Check cache first
-	if fileCache, ok := s.codeBeforeCommentCache[file]; ok {
-		if result, ok := fileCache[cg.Pos()]; ok {
-			return result
-		}
+	s.mu.RLock()
+	result, ok := s.codeBeforeCommentCache[file][cg.Pos()]
+	s.mu.RUnlock()
+	if ok {
+		return result
 	}
 
+	// Compute result outside the lock; a concurrent caller computes the same.
+	result = s.computeCodeBeforeComment(file, cg)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
 	// Initialize cache for this file if not exists
 	if s.codeBeforeCommentCache[file] == nil {
 		s.codeBeforeCommentCache[file] = make(map[token.Pos]bool)
 	}
-
-	// Compute result
-	result := s.computeCodeBeforeComment(file, cg)
 	s.codeBeforeCommentCache[file][cg.Pos()] = result
 	return result
 }
@@ -940,8 +1116,18 @@ func (s *DirectiveFuncSet) hasNestedFuncLitBetween(parent *ast.FuncLit, startCol
 	return found
 }
 
-// parseFile parses a Go source file with caching.
+// parseFile parses a Go source file with caching. The write lock is held
+// while parsing, so that concurrent callers share a single parse of each file.
 func (s *DirectiveFuncSet) parseFile(filename string) *ast.File {
+	s.mu.RLock()
+	file, ok := s.cache[filename]
+	s.mu.RUnlock()
+	if ok {
+		return file
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
 	if file, ok := s.cache[filename]; ok {
 		return file
 	}
@@ -954,54 +1140,178 @@ func (s *DirectiveFuncSet) parseFile(filename string) *ast.File {
 	return file
 }
 
-// hasDirectiveInFile checks if a function in a file has the directive.
-func (s *DirectiveFuncSet) hasDirectiveInFile(file *ast.File, funcName, receiverType string) bool {
+// =============================================================================
+// Interface Methods and Function-Typed Fields
+// =============================================================================
+
+// ContainsMember reports whether the directive marks the method or field obj
+// of the named type recv: an interface method or a function-typed struct
+// field, which a call reaches dynamically rather than through an
+// *ssa.Function.
+//
+//	type QB interface {
+//	    //gormreuse:pure
+//	    Build(db *gorm.DB) *gorm.DB
+//	}
+//
+// Only sets created with member support (NewPureFuncSet) consult members.
+// The directive is an assertion about every implementation or every function
+// stored in the field, which the analysis cannot verify.
+func (s *DirectiveFuncSet) ContainsMember(recv *types.Named, obj types.Object) bool {
+	if s == nil || !s.members || recv == nil || obj == nil || obj.Pkg() == nil {
+		return false
+	}
+	if _, exists := s.packages[obj.Pkg().Path()]; exists {
+		return true
+	}
+	key := FuncKey{PkgPath: obj.Pkg().Path(), ReceiverType: recv.Obj().Name(), FuncName: obj.Name()}
+	if _, exists := s.known[key]; exists {
+		return true
+	}
+	field := s.memberField(recv, obj)
+	return field != nil && s.fieldDirective(field).IsValid()
+}
+
+// memberField returns the declaration of the member obj of recv, looked up in
+// the file declaring recv. Like externalFuncDecl, it matches by line and
+// column, since a re-parsed file lives at different token.Pos offsets.
+func (s *DirectiveFuncSet) memberField(recv *types.Named, obj types.Object) *ast.Field {
+	if s.fset == nil || !obj.Pos().IsValid() {
+		return nil
+	}
+	file := s.getFileForPos(recv.Obj().Pos())
+	if file == nil {
+		return nil
+	}
+	want := s.fset.Position(obj.Pos())
 	for _, decl := range file.Decls {
-		funcDecl, ok := decl.(*ast.FuncDecl)
-		if !ok || funcDecl.Name.Name != funcName {
+		gd, ok := decl.(*ast.GenDecl)
+		if !ok || gd.Tok != token.TYPE {
 			continue
 		}
-		declReceiverType := ""
-		if funcDecl.Recv != nil && len(funcDecl.Recv.List) > 0 {
-			declReceiverType = stripPointer(exprToString(funcDecl.Recv.List[0].Type))
+		for _, spec := range gd.Specs {
+			for _, field := range memberFields(spec.(*ast.TypeSpec)) {
+				for _, name := range field.Names {
+					got := s.fset.Position(name.Pos())
+					if got.Line == want.Line && got.Column == want.Column {
+						return field
+					}
+				}
+			}
 		}
-		if declReceiverType != receiverType {
+	}
+	return nil
+}
+
+// memberFields returns the members of ts the directive may mark: the methods
+// of an interface type and the function-typed fields of a struct type.
+func memberFields(ts *ast.TypeSpec) []*ast.Field {
+	var fields []*ast.Field
+	switch t := ts.Type.(type) {
+	case *ast.InterfaceType:
+		for _, field := range t.Methods.List {
+			if _, ok := field.Type.(*ast.FuncType); ok && len(field.Names) > 0 {
+				fields = append(fields, field)
+			}
+		}
+	case *ast.StructType:
+		for _, field := range t.Fields.List {
+			if _, ok := field.Type.(*ast.FuncType); ok && len(field.Names) > 0 {
+				fields = append(fields, field)
+			}
+		}
+	}
+	return fields
+}
+
+// fieldDirective returns the position of the directive in the doc comment of
+// field or in its trailing comment, or token.NoPos if it has none.
+func (s *DirectiveFuncSet) fieldDirective(field *ast.Field) token.Pos {
+	return fieldDirectivePos(field, s.isDirective)
+}
+
+func fieldDirectivePos(field *ast.Field, isDirective func(string) bool) token.Pos {
+	for _, cg := range []*ast.CommentGroup{field.Doc, field.Comment} {
+		if cg == nil {
 			continue
 		}
-		if funcDecl.Doc != nil {
-			for _, c := range funcDecl.Doc.List {
-				if s.isDirective(c.Text) {
-					return true
-				}
+		for _, c := range cg.List {
+			if isDirective(c.Text) {
+				return c.Pos()
 			}
 		}
 	}
-	return false
+	return token.NoPos
 }
 
 // NewPureFuncSet creates a DirectiveFuncSet for //gormreuse:pure.
-// The typesInfo parameter is used to validate that functions have *gorm.DB parameters.
-func NewPureFuncSet(fset *token.FileSet, typesInfo *types.Info) *DirectiveFuncSet {
-	return newDirectiveFuncSet(fset, typesInfo, IsPureDirective, hasGormDBParameter)
+// The typesInfo parameter is used to validate that functions have *gorm.DB parameters;
+// gormTypes (nil for gorm.io/gorm.DB only) decides what counts as *gorm.DB.
+// Unlike the other sets, it also covers interface methods and function-typed
+// struct fields (see ContainsMember).
+func NewPureFuncSet(fset *token.FileSet, typesInfo *types.Info, gormTypes *typeutil.Matcher) *DirectiveFuncSet {
+	s := newDirectiveFuncSet(fset, typesInfo, gormTypes, IsPureDirective, hasGormDBParameter)
+	s.members = true
+	return s
 }
 
 // NewImmutableReturnFuncSet creates a DirectiveFuncSet for //gormreuse:immutable-return.
 // The typesInfo parameter is used to validate that functions return *gorm.DB.
-func NewImmutableReturnFuncSet(fset *token.FileSet, typesInfo *types.Info) *DirectiveFuncSet {
-	return newDirectiveFuncSet(fset, typesInfo, IsImmutableReturnDirective, hasGormDBReturn)
+func NewImmutableReturnFuncSet(fset *token.FileSet, typesInfo *types.Info, gormTypes *typeutil.Matcher) *DirectiveFuncSet {
+	return newDirectiveFuncSet(fset, typesInfo, gormTypes, IsImmutableReturnDirective, hasGormDBReturn)
 }
 
 // NewImmutableParamFuncSet creates a DirectiveFuncSet for //gormreuse:immutable-param.
 // Like pure, the directive is only meaningful on a function with a *gorm.DB
 // parameter, so it reuses hasGormDBParameter for signature validation (an
 // immutable-param directive on a parameter-less function is reported unused).
-func NewImmutableParamFuncSet(fset *token.FileSet, typesInfo *types.Info) *DirectiveFuncSet {
-	return newDirectiveFuncSet(fset, typesInfo, IsImmutableParamDirective, hasGormDBParameter)
+func NewImmutableParamFuncSet(fset *token.FileSet, typesInfo *types.Info, gormTypes *typeutil.Matcher) *DirectiveFuncSet {
+	return newDirectiveFuncSet(fset, typesInfo, gormTypes, IsImmutableParamDirective, hasGormDBParameter)
 }
 
-// BuildPureFunctionSet builds a set of functions marked with //gormreuse:pure.
+// NewFinisherFuncSet creates a DirectiveFuncSet for //gormreuse:finisher.
+// The typesInfo parameter is used to validate that functions have a *gorm.DB
+// receiver (see FinisherReceiverIndex).
+func NewFinisherFuncSet(fset *token.FileSet, typesInfo *types.Info, gormTypes *typeutil.Matcher) *DirectiveFuncSet {
+	return newDirectiveFuncSet(fset, typesInfo, gormTypes, IsFinisherDirective, hasGormDBReceiver)
+}
+
+// NewSinkFuncSet creates a DirectiveFuncSet for //gormreuse:sink.
+// Like pure, the directive is only meaningful on a function with a *gorm.DB
+// parameter, so a sink directive on a parameter-less function is reported unused.
+func NewSinkFuncSet(fset *token.FileSet, typesInfo *types.Info, gormTypes *typeutil.Matcher) *DirectiveFuncSet {
+	return newDirectiveFuncSet(fset, typesInfo, gormTypes, IsSinkDirective, hasGormDBParameter)
+}
+
+// NewImpureFuncSet creates a DirectiveFuncSet for //gormreuse:impure.
+// Like sink, the directive is only meaningful on a function with a *gorm.DB
+// parameter, so an impure directive on a parameter-less function is reported
+// unused.
+func NewImpureFuncSet(fset *token.FileSet, typesInfo *types.Info, gormTypes *typeutil.Matcher) *DirectiveFuncSet {
+	return newDirectiveFuncSet(fset, typesInfo, gormTypes, IsImpureDirective, hasGormDBParameter)
+}
+
+// BuildPureFunctionSet builds a set of functions marked with //gormreuse:pure,
+// including interface methods and function-typed struct fields, keyed by
+// their type as ReceiverType.
 func BuildPureFunctionSet(file *ast.File, pkgPath string) map[FuncKey]struct{} {
-	return buildFunctionSet(file, pkgPath, IsPureDirective)
+	result := buildFunctionSet(file, pkgPath, IsPureDirective)
+	ast.Inspect(file, func(n ast.Node) bool {
+		ts, ok := n.(*ast.TypeSpec)
+		if !ok {
+			return true
+		}
+		for _, field := range memberFields(ts) {
+			if !fieldDirectivePos(field, IsPureDirective).IsValid() {
+				continue
+			}
+			for _, name := range field.Names {
+				result[FuncKey{PkgPath: pkgPath, ReceiverType: ts.Name.Name, FuncName: name.Name}] = struct{}{}
+			}
+		}
+		return true
+	})
+	return result
 }
 
 // BuildImmutableReturnFunctionSet builds a set of functions marked with //gormreuse:immutable-return.
@@ -1014,6 +1324,21 @@ func BuildImmutableParamFunctionSet(file *ast.File, pkgPath string) map[FuncKey]
 	return buildFunctionSet(file, pkgPath, IsImmutableParamDirective)
 }
 
+// BuildFinisherFunctionSet builds a set of functions marked with //gormreuse:finisher.
+func BuildFinisherFunctionSet(file *ast.File, pkgPath string) map[FuncKey]struct{} {
+	return buildFunctionSet(file, pkgPath, IsFinisherDirective)
+}
+
+// BuildSinkFunctionSet builds a set of functions marked with //gormreuse:sink.
+func BuildSinkFunctionSet(file *ast.File, pkgPath string) map[FuncKey]struct{} {
+	return buildFunctionSet(file, pkgPath, IsSinkDirective)
+}
+
+// BuildImpureFunctionSet builds a set of functions marked with //gormreuse:impure.
+func BuildImpureFunctionSet(file *ast.File, pkgPath string) map[FuncKey]struct{} {
+	return buildFunctionSet(file, pkgPath, IsImpureDirective)
+}
+
 // =============================================================================
 // Common Helper
 // =============================================================================
@@ -0,0 +1,210 @@
+package directive
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"golang.org/x/tools/go/packages"
+	"golang.org/x/tools/go/ssa"
+	"golang.org/x/tools/go/ssa/ssautil"
+)
+
+// TestDirectiveFuncSetUnreadableSource verifies that a function of another
+// package whose source can no longer be read, as in a stripped build, is
+// classified by its facts.
+func TestDirectiveFuncSetUnreadableSource(t *testing.T) {
+	t.Parallel()
+
+	src := `package lib
+
+//gormreuse:pure
+func Pure() {}
+
+//gormreuse:pure
+func pure() {}
+
+func Plain() {}
+`
+	// The file is parsed from memory and never written, so its recorded
+	// filename cannot be re-parsed.
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, filepath.Join(t.TempDir(), "lib.go"), src, parser.ParseComments)
+	if err != nil {
+		t.Fatalf("Failed to parse: %v", err)
+	}
+	pkg, err := new(types.Config).Check("example.com/lib", fset, []*ast.File{file}, nil)
+	if err != nil {
+		t.Fatalf("Failed to type-check: %v", err)
+	}
+
+	// Functions of a package created without syntax have no Syntax(), like
+	// those of an imported package.
+	prog := ssa.NewProgram(fset, 0)
+	lib := prog.CreatePackage(pkg, nil, nil, true)
+	prog.Build()
+
+	exported := lib.Func("Pure")
+	unexported := lib.Func("pure")
+	plain := lib.Func("Plain")
+
+	set := NewPureFuncSet(fset, nil, nil)
+	for _, fn := range []*ssa.Function{exported, unexported, plain} {
+		if set.Contains(fn) {
+			t.Errorf("%s: without facts, expected an unreadable source to classify nothing", fn.Name())
+		}
+	}
+
+	set.UseFacts(func(fn *types.Func) bool { return fn == exported.Object() })
+	if !set.Contains(exported) {
+		t.Errorf("Pure: expected the fact to classify it as pure")
+	}
+	if set.Contains(unexported) {
+		t.Errorf("pure: unexported functions carry no facts")
+	}
+	if set.Contains(plain) {
+		t.Errorf("Plain: expected no fact")
+	}
+
+	// The body of a function classified by its fact is not validated.
+	if !set.IsTrusted(exported) {
+		t.Errorf("Pure: expected it to be trusted, its body being unavailable")
+	}
+	if set.IsTrusted(plain) {
+		t.Errorf("Plain: expected a function outside the set not to be trusted")
+	}
+}
+
+// TestDirectiveFuncSetReadableSourceNotTrusted verifies that a function of
+// another package classified by its fact is not trusted when its source can
+// still be read: its own package validates its body.
+func TestDirectiveFuncSetReadableSourceNotTrusted(t *testing.T) {
+	t.Parallel()
+
+	src := `package lib
+
+//gormreuse:pure
+func Pure() {}
+`
+	filename := filepath.Join(t.TempDir(), "lib.go")
+	if err := os.WriteFile(filename, []byte(src), 0o644); err != nil {
+		t.Fatalf("Failed to write: %v", err)
+	}
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, filename, nil, parser.ParseComments)
+	if err != nil {
+		t.Fatalf("Failed to parse: %v", err)
+	}
+	pkg, err := new(types.Config).Check("example.com/lib", fset, []*ast.File{file}, nil)
+	if err != nil {
+		t.Fatalf("Failed to type-check: %v", err)
+	}
+	prog := ssa.NewProgram(fset, 0)
+	lib := prog.CreatePackage(pkg, nil, nil, true)
+	prog.Build()
+
+	exported := lib.Func("Pure")
+	set := NewPureFuncSet(fset, nil, nil)
+	set.UseFacts(func(fn *types.Func) bool { return fn == exported.Object() })
+	if !set.Contains(exported) {
+		t.Errorf("Pure: expected the fact to classify it as pure")
+	}
+	if set.IsTrusted(exported) {
+		t.Errorf("Pure: expected a function with readable source not to be trusted")
+	}
+}
+
+// TestDirectiveFuncSetConcurrentContains verifies that Contains classifies the
+// functions of the gormreuse fixture package and of the packages it imports the
+// same when called from several goroutines on a shared set as when called from
+// one. The imported functions carry no syntax, so the goroutines re-parse their
+// files and fill the caches concurrently. Run it with -race to check the caches
+// are guarded.
+func TestDirectiveFuncSetConcurrentContains(t *testing.T) {
+	t.Parallel()
+
+	testdata, err := filepath.Abs(filepath.Join("..", "..", "testdata"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg := &packages.Config{
+		Mode: packages.LoadAllSyntax,
+		Dir:  testdata,
+		Env:  append(os.Environ(), "GOPATH="+testdata, "GO111MODULE=off", "GOFLAGS="),
+	}
+	pkgs, err := packages.Load(cfg, "gormreuse")
+	if err != nil {
+		t.Fatalf("packages.Load: %v", err)
+	}
+	if packages.PrintErrors(pkgs) > 0 {
+		t.Fatal("packages had errors")
+	}
+	prog, ssaPkgs := ssautil.Packages(pkgs, ssa.InstantiateGenerics)
+	prog.Build()
+
+	var funcs []*ssa.Function
+	external := 0
+	for fn := range ssautil.AllFunctions(prog) {
+		if fn.Pkg == nil {
+			continue
+		}
+		funcs = append(funcs, fn)
+		if fn.Pkg != ssaPkgs[0] {
+			external++
+		}
+	}
+	if external == 0 {
+		t.Fatal("no function of an imported package found")
+	}
+
+	newSet := func() *DirectiveFuncSet {
+		set := NewPureFuncSet(pkgs[0].Fset, pkgs[0].TypesInfo, nil)
+		for _, file := range pkgs[0].Syntax {
+			set.AddFile(file)
+		}
+		return set
+	}
+
+	want := make([]bool, len(funcs))
+	pure := 0
+	sequential := newSet()
+	for i, fn := range funcs {
+		if want[i] = sequential.Contains(fn); want[i] {
+			pure++
+		}
+	}
+	if pure == 0 {
+		t.Fatal("no pure function found in the fixture")
+	}
+
+	shared := newSet()
+	got := make([][]bool, 8)
+	var wg sync.WaitGroup
+	for g := range got {
+		got[g] = make([]bool, len(funcs))
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			// Each goroutine starts at a different function so that they
+			// fill the caches of different files at the same time.
+			for j := range funcs {
+				i := (j + g*len(funcs)/len(got)) % len(funcs)
+				got[g][i] = shared.Contains(funcs[i])
+			}
+		}()
+	}
+	wg.Wait()
+
+	for g := range got {
+		for i, fn := range funcs {
+			if got[g][i] != want[i] {
+				t.Errorf("goroutine %d: Contains(%s) = %v, want %v", g, fn, got[g][i], want[i])
+			}
+		}
+	}
+}
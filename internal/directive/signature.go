@@ -1,6 +1,10 @@
 package directive
 
-import "go/types"
+import (
+	"go/types"
+
+	"github.com/mpyw/gormreuse/internal/typeutil"
+)
 
 // =============================================================================
 // Signature Validation
@@ -10,27 +14,55 @@ import "go/types"
 // containing *gorm.DB. It is the same predicate that decides whether a
 // //gormreuse:immutable-param directive has a valid signature, exported so
 // callers (e.g. redundant-directive detection) can distinguish a signature-valid
-// annotation from a signature-invalid one.
-func HasGormDBParameter(sig *types.Signature) bool { return hasGormDBParameter(sig) }
+// annotation from a signature-invalid one. gormTypes is the configured DB type
+// matcher (nil for gorm.io/gorm.DB only).
+func HasGormDBParameter(sig *types.Signature, gormTypes *typeutil.Matcher) bool {
+	return hasGormDBParameter(sig, gormTypes)
+}
 
 // hasGormDBParameter checks if a function signature has any parameter
 // containing *gorm.DB (directly or in struct fields).
-func hasGormDBParameter(sig *types.Signature) bool {
+func hasGormDBParameter(sig *types.Signature, gormTypes *typeutil.Matcher) bool {
 	params := sig.Params()
 	for i := 0; i < params.Len(); i++ {
-		if containsGormDB(params.At(i).Type()) {
+		if containsGormDB(params.At(i).Type(), gormTypes) {
 			return true
 		}
 	}
 	return false
 }
 
+// FinisherReceiverIndex returns the index, among the SSA call arguments, of the
+// *gorm.DB a //gormreuse:finisher function finishes: the method receiver when
+// it is *gorm.DB, otherwise the first parameter when it is *gorm.DB. Methods on
+// wrapper types take it as their first parameter, after the receiver argument.
+// It reports false when the signature has no such *gorm.DB receiver.
+func FinisherReceiverIndex(sig *types.Signature, gormTypes *typeutil.Matcher) (int, bool) {
+	if recv := sig.Recv(); recv != nil && gormTypes.IsGormDB(recv.Type()) {
+		return 0, true
+	}
+	if sig.Params().Len() == 0 || !gormTypes.IsGormDB(sig.Params().At(0).Type()) {
+		return 0, false
+	}
+	if sig.Recv() != nil {
+		return 1, true
+	}
+	return 0, true
+}
+
+// hasGormDBReceiver checks if a function signature has a *gorm.DB receiver
+// for //gormreuse:finisher (see FinisherReceiverIndex).
+func hasGormDBReceiver(sig *types.Signature, gormTypes *typeutil.Matcher) bool {
+	_, ok := FinisherReceiverIndex(sig, gormTypes)
+	return ok
+}
+
 // hasGormDBReturn checks if a function signature has any return value
 // containing *gorm.DB (directly or in struct fields).
-func hasGormDBReturn(sig *types.Signature) bool {
+func hasGormDBReturn(sig *types.Signature, gormTypes *typeutil.Matcher) bool {
 	results := sig.Results()
 	for i := 0; i < results.Len(); i++ {
-		if containsGormDB(results.At(i).Type()) {
+		if containsGormDB(results.At(i).Type(), gormTypes) {
 			return true
 		}
 	}
@@ -39,9 +71,9 @@ func hasGormDBReturn(sig *types.Signature) bool {
 
 // containsGormDB checks if a type contains *gorm.DB anywhere in its structure.
 // It recursively checks struct fields, slices, arrays, maps, and channels.
-func containsGormDB(t types.Type) bool {
+func containsGormDB(t types.Type, gormTypes *typeutil.Matcher) bool {
 	cache := make(map[types.Type]*cacheEntry)
-	return containsGormDBWithCache(t, cache)
+	return containsGormDBWithCache(t, gormTypes, cache)
 }
 
 // cacheEntry tracks the state of type checking to handle cycles.
@@ -51,7 +83,7 @@ type cacheEntry struct {
 }
 
 // containsGormDBWithCache performs the actual type checking with cycle detection.
-func containsGormDBWithCache(t types.Type, cache map[types.Type]*cacheEntry) bool {
+func containsGormDBWithCache(t types.Type, gormTypes *typeutil.Matcher, cache map[types.Type]*cacheEntry) bool {
 	if t == nil {
 		return false
 	}
@@ -70,14 +102,14 @@ func containsGormDBWithCache(t types.Type, cache map[types.Type]*cacheEntry) boo
 	cache[t] = &cacheEntry{inProgress: true}
 
 	// Direct *gorm.DB check
-	if isGormDB(t) {
+	if gormTypes.IsGormDB(t) {
 		cache[t] = &cacheEntry{inProgress: false, result: true}
 		return true
 	}
 
 	// Check underlying type (handles defined types like `type DefinedDB *gorm.DB`)
 	underlying := t.Underlying()
-	if isGormDB(underlying) {
+	if gormTypes.IsGormDB(underlying) {
 		cache[t] = &cacheEntry{inProgress: false, result: true}
 		return true
 	}
@@ -97,52 +129,24 @@ func containsGormDBWithCache(t types.Type, cache map[types.Type]*cacheEntry) boo
 		result = false
 	case *types.Struct:
 		for i := 0; i < typ.NumFields(); i++ {
-			if containsGormDBWithCache(typ.Field(i).Type(), cache) {
+			if containsGormDBWithCache(typ.Field(i).Type(), gormTypes, cache) {
 				result = true
 				break
 			}
 		}
 	case *types.Pointer:
-		result = containsGormDBWithCache(typ.Elem(), cache)
+		result = containsGormDBWithCache(typ.Elem(), gormTypes, cache)
 	case *types.Slice:
-		result = containsGormDBWithCache(typ.Elem(), cache)
+		result = containsGormDBWithCache(typ.Elem(), gormTypes, cache)
 	case *types.Array:
-		result = containsGormDBWithCache(typ.Elem(), cache)
+		result = containsGormDBWithCache(typ.Elem(), gormTypes, cache)
 	case *types.Map:
-		result = containsGormDBWithCache(typ.Key(), cache) || containsGormDBWithCache(typ.Elem(), cache)
+		result = containsGormDBWithCache(typ.Key(), gormTypes, cache) || containsGormDBWithCache(typ.Elem(), gormTypes, cache)
 	case *types.Chan:
-		result = containsGormDBWithCache(typ.Elem(), cache)
+		result = containsGormDBWithCache(typ.Elem(), gormTypes, cache)
 	}
 
 	// Cache the result
 	cache[t] = &cacheEntry{inProgress: false, result: result}
 	return result
 }
-
-// isGormDB checks if a type is *gorm.DB or gorm.DB.
-// Both are dangerous because gorm.DB contains *Statement which is shared on copy.
-func isGormDB(t types.Type) bool {
-	// Check for *gorm.DB
-	if ptr, ok := t.(*types.Pointer); ok {
-		if named, ok := ptr.Elem().(*types.Named); ok {
-			obj := named.Obj()
-			if obj != nil && obj.Pkg() != nil {
-				if obj.Name() == "DB" && obj.Pkg().Path() == "gorm.io/gorm" {
-					return true
-				}
-			}
-		}
-	}
-
-	// Check for gorm.DB (non-pointer, but still dangerous due to *Statement field)
-	if named, ok := t.(*types.Named); ok {
-		obj := named.Obj()
-		if obj != nil && obj.Pkg() != nil {
-			if obj.Name() == "DB" && obj.Pkg().Path() == "gorm.io/gorm" {
-				return true
-			}
-		}
-	}
-
-	return false
-}
@@ -0,0 +1,189 @@
+package internal
+
+import (
+	"go/ast"
+	"go/token"
+	"go/types"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/ssa"
+
+	"github.com/mpyw/gormreuse/internal/directive"
+	"github.com/mpyw/gormreuse/internal/ssa/pollution"
+	"github.com/mpyw/gormreuse/internal/typeutil"
+)
+
+// =============================================================================
+// Directives of a Package
+// =============================================================================
+
+// Directives holds the //gormreuse: directives and golangci-lint //nolint
+// comments of the analyzed files of a package, as collected file by file by
+// AddFile. Maps keyed by file name hold an entry per analyzed file.
+type Directives struct {
+	// SkipFiles are the files not analyzed: generated or excluded ones.
+	SkipFiles map[string]bool
+
+	// IgnoreMaps, AllowReuseMaps and FuncIgnores are the line-level and
+	// function-level ignore and allow-reuse directives of each file, and
+	// IgnoreFiles the //gormreuse:ignore-file directive of the files having
+	// one.
+	IgnoreMaps     map[string]directive.IgnoreMap
+	AllowReuseMaps map[string]directive.IgnoreMap
+	FuncIgnores    map[string]map[token.Pos]directive.FunctionIgnoreEntry
+	IgnoreFiles    map[string]*directive.IgnoreFile
+
+	// PureFuncs, ImmutableReturnFuncs, ImmutableParamFuncs, FinisherFuncs,
+	// SinkFuncs and ImpureFuncs are the functions marked with the
+	// corresponding directives, and ImmutableInputs the
+	// //gormreuse:immutable-input(name) callback declarations.
+	PureFuncs            *directive.DirectiveFuncSet
+	ImmutableReturnFuncs *directive.DirectiveFuncSet
+	ImmutableParamFuncs  *directive.DirectiveFuncSet
+	FinisherFuncs        *directive.DirectiveFuncSet
+	SinkFuncs            *directive.DirectiveFuncSet
+	ImpureFuncs          *directive.DirectiveFuncSet
+	ImmutableInputs      *directive.ImmutableInputSet
+
+	fset    *token.FileSet
+	pkgPath string
+}
+
+// NewDirectives returns the empty Directives of the package of pass, whose
+// files in skipFiles are not analyzed.
+func NewDirectives(pass *analysis.Pass, skipFiles map[string]bool, gormTypes *typeutil.Matcher) *Directives {
+	fset, info := pass.Fset, pass.TypesInfo
+	return &Directives{
+		SkipFiles:            skipFiles,
+		IgnoreMaps:           make(map[string]directive.IgnoreMap),
+		AllowReuseMaps:       make(map[string]directive.IgnoreMap),
+		FuncIgnores:          make(map[string]map[token.Pos]directive.FunctionIgnoreEntry),
+		IgnoreFiles:          make(map[string]*directive.IgnoreFile),
+		PureFuncs:            directive.NewPureFuncSet(fset, info, gormTypes),
+		ImmutableReturnFuncs: directive.NewImmutableReturnFuncSet(fset, info, gormTypes),
+		ImmutableParamFuncs:  directive.NewImmutableParamFuncSet(fset, info, gormTypes),
+		FinisherFuncs:        directive.NewFinisherFuncSet(fset, info, gormTypes),
+		SinkFuncs:            directive.NewSinkFuncSet(fset, info, gormTypes),
+		ImpureFuncs:          directive.NewImpureFuncSet(fset, info, gormTypes),
+		ImmutableInputs:      directive.NewImmutableInputSet(fset, info, gormTypes),
+		fset:                 fset,
+		pkgPath:              pass.Pkg.Path(),
+	}
+}
+
+// AddFile collects the directives of file, unless it is in SkipFiles.
+func (d *Directives) AddFile(file *ast.File) {
+	filename := d.fset.Position(file.Pos()).Filename
+	if d.SkipFiles[filename] {
+		return
+	}
+	d.IgnoreMaps[filename] = directive.BuildIgnoreMap(d.fset, file)
+	d.AllowReuseMaps[filename] = directive.BuildAllowReuseMap(d.fset, file)
+	d.FuncIgnores[filename] = directive.BuildFunctionIgnoreSet(d.fset, file)
+	if f := directive.BuildIgnoreFile(d.fset, file); f != nil {
+		d.IgnoreFiles[filename] = f
+	}
+
+	// The original file locates the directives by position, and each set adds
+	// the functions this file marks.
+	for _, s := range []struct {
+		set   *directive.DirectiveFuncSet
+		build func(*ast.File, string) map[directive.FuncKey]struct{}
+	}{
+		{d.PureFuncs, directive.BuildPureFunctionSet},
+		{d.ImmutableReturnFuncs, directive.BuildImmutableReturnFunctionSet},
+		{d.ImmutableParamFuncs, directive.BuildImmutableParamFunctionSet},
+		{d.FinisherFuncs, directive.BuildFinisherFunctionSet},
+		{d.SinkFuncs, directive.BuildSinkFunctionSet},
+		{d.ImpureFuncs, directive.BuildImpureFunctionSet},
+	} {
+		s.set.AddFile(file)
+		for key := range s.build(file, d.pkgPath) {
+			s.set.Add(key)
+		}
+	}
+	d.ImmutableInputs.AddFile(file, d.pkgPath)
+}
+
+// DirectiveFacts classifies the exported functions of imported packages by
+// the directives on them, as exported by the facts analyzer.
+type DirectiveFacts interface {
+	IsPure(fn *types.Func) bool
+	IsImmutableReturn(fn *types.Func) bool
+	IsImmutableParam(fn *types.Func) bool
+	IsFinisher(fn *types.Func) bool
+	IsSink(fn *types.Func) bool
+	IsImpure(fn *types.Func) bool
+}
+
+// UseFacts classifies the exported functions of imported packages by facts
+// rather than by re-parsing their source.
+func (d *Directives) UseFacts(facts DirectiveFacts) {
+	d.PureFuncs.UseFacts(facts.IsPure)
+	d.ImmutableReturnFuncs.UseFacts(facts.IsImmutableReturn)
+	d.ImmutableParamFuncs.UseFacts(facts.IsImmutableParam)
+	d.FinisherFuncs.UseFacts(facts.IsFinisher)
+	d.SinkFuncs.UseFacts(facts.IsSink)
+	d.ImpureFuncs.UseFacts(facts.IsImpure)
+}
+
+// skip reports whether fn is in a skipped file or wholly ignored. When
+// markUsed is true it also records the function-level ignore directive as
+// used; that side effect must happen exactly once per function, so only the
+// analysis pass passes markUsed=true.
+func (d *Directives) skip(fn *ssa.Function, markUsed bool) bool {
+	pos := fn.Pos()
+	if !pos.IsValid() {
+		return true
+	}
+	// An instance of a generic function shares the body, and so the
+	// positions, of its origin, which is analyzed itself. Analyzing it too
+	// would report each violation once per instantiation.
+	if fn.Origin() != nil {
+		return true
+	}
+	filename := d.fset.Position(pos).Filename
+	if d.SkipFiles[filename] {
+		return true
+	}
+	entry, ignored := d.FuncIgnores[filename][pos]
+	if ignored && markUsed {
+		if ignoreMap := d.IgnoreMaps[filename]; ignoreMap != nil {
+			ignoreMap.MarkUsed(entry.DirectiveLine)
+		}
+	}
+	return ignored
+}
+
+// reportUnusedIgnores reports the ignore and allow-reuse directives that
+// suppressed nothing and, with opts.RequireIgnoreReason, the ignores without a
+// reason. With opts.StrictIgnoreFile it reports the unused ignore-file
+// directives to unfiltered, past the ignore-file filter of pass: the directive
+// would otherwise suppress itself.
+func (d *Directives) reportUnusedIgnores(pass, unfiltered *analysis.Pass, opts Options) {
+	for _, ignoreMap := range d.IgnoreMaps {
+		if ignoreMap == nil {
+			continue
+		}
+		for _, pos := range ignoreMap.GetUnusedIgnores() {
+			report(pass, pos, pollution.KindUnusedIgnore, "unused gormreuse:ignore directive")
+		}
+		if opts.RequireIgnoreReason {
+			for _, pos := range ignoreMap.GetReasonlessIgnores() {
+				report(pass, pos, pollution.KindMissingReason, "gormreuse:ignore directive without a reason; write //gormreuse:ignore: <reason>")
+			}
+		}
+	}
+	for _, allowReuseMap := range d.AllowReuseMaps {
+		for _, pos := range allowReuseMap.GetUnusedIgnores() {
+			report(pass, pos, pollution.KindUnusedAllowReuse, "unused gormreuse:allow-reuse directive")
+		}
+	}
+	if opts.StrictIgnoreFile {
+		for _, f := range d.IgnoreFiles {
+			if !f.Used() {
+				report(unfiltered, f.Pos, pollution.KindUnusedIgnore, "unused gormreuse:ignore-file directive")
+			}
+		}
+	}
+}
@@ -33,11 +33,25 @@
 //	q.Where("b").Find()
 //	q = q.Where("c")
 //	q.Where("d").Find()
+//
+// # Alternative Fix
+//
+// When the violation is a loop use or a finisher reuse, a second fix makes the
+// root immutable where it is defined instead, unless it would be identical to
+// the first: q := db.Where("base").Session(&gorm.Session{}).
+//
+// When every use of the root is a direct finisher call, another fix isolates
+// each of them instead: q.Session(&gorm.Session{}).Count(nil).
+//
+// A reuse in a chain longer than the chain threshold (-chain-threshold) is
+// also offered the root fix, labeled as recommended: reassigning each step of
+// a long chain is worse than branching every query from an immutable base.
 package fix
 
 import (
 	"go/ast"
 	"go/token"
+	"slices"
 	"sort"
 	"strconv"
 
@@ -57,12 +71,17 @@ type Generator struct {
 	files           map[*token.File]*ast.File          // token.File -> ast.File mapping
 	inspectors      map[*ast.File]*inspector.Inspector // cached inspectors per file
 	scopesCallbacks map[*ssa.Function]bool             // Scopes/Preload callbacks (no immutable-param fix)
+	gormTypes       *typeutil.Matcher                  // Additional *gorm.DB types (-gorm-type)
+	chainThreshold  int                                // Chain length above which the root fix is recommended; 0 disables
 }
 
 // New creates a new fix Generator. scopesCallbacks lists Scopes/Preload callback
 // functions, whose *gorm.DB parameters cannot be made immutable-param, so the
-// parameter-root fix is withheld for them (stage 2c); it may be nil.
-func New(pass *analysis.Pass, scopesCallbacks map[*ssa.Function]bool) *Generator {
+// parameter-root fix is withheld for them (stage 2c); it may be nil. gormTypes
+// recognizes additional *gorm.DB types; nil matches only gorm.io/gorm.DB.
+// chainThreshold is the number of method calls a reused chain must exceed for
+// the root fix to be offered as recommended (see chainLength); 0 disables it.
+func New(pass *analysis.Pass, scopesCallbacks map[*ssa.Function]bool, gormTypes *typeutil.Matcher, chainThreshold int) *Generator {
 	// Build token.File -> ast.File mapping
 	files := make(map[*token.File]*ast.File)
 	for _, f := range pass.Files {
@@ -78,6 +97,8 @@ func New(pass *analysis.Pass, scopesCallbacks map[*ssa.Function]bool) *Generator
 		files:           files,
 		inspectors:      make(map[*ast.File]*inspector.Inspector),
 		scopesCallbacks: scopesCallbacks,
+		gormTypes:       gormTypes,
+		chainThreshold:  chainThreshold,
 	}
 }
 
@@ -99,6 +120,11 @@ func (g *Generator) Generate(v pollution.Violation) []analysis.SuggestedFix {
 		return nil // Cannot fix without root information
 	}
 
+	// A builder wrapper (-builder-type) has no Session() to insert.
+	if g.gormTypes.IsBuilder(root.Type()) {
+		return nil
+	}
+
 	// A parameter root has no definition site to Session(), so the "insert
 	// Session() at the root" model does not apply. Instead, suggest declaring the
 	// enclosing function //gormreuse:immutable-param — the parameter is then
@@ -173,12 +199,279 @@ func (g *Generator) Generate(v pollution.Violation) []analysis.SuggestedFix {
 		return edits[i].Pos < edits[j].Pos
 	})
 
-	return []analysis.SuggestedFix{
+	fixes := []analysis.SuggestedFix{
 		{
 			Message:   "Add reassignment and Session to fix reuse",
 			TextEdits: edits,
 		},
 	}
+	if alt := g.generateRootSessionFix(v, edits); alt != nil {
+		fixes = append(fixes, *alt)
+	}
+	if alt := g.generateSessionPerFinisherFix(v); alt != nil {
+		fixes = append(fixes, *alt)
+	}
+	return fixes
+}
+
+// generateSessionPerFinisherFix offers the alternative of isolating each use
+// instead of the root: q.Count(nil) becomes q.Session(&gorm.Session{}).Count(nil).
+// Every use of the root must be rewritten for the reuse to go away, so the fix
+// is offered only when each one is a statement-level finisher call directly on
+// the root's variable, or already a Session/WithContext/Debug call on it, which
+// is left as is. Any other use, such as a chain method or a use nested in an
+// argument, withholds the fix.
+func (g *Generator) generateSessionPerFinisherFix(v pollution.Violation) *analysis.SuggestedFix {
+	var edits []analysis.TextEdit
+	filesNeedingImport := make(map[*ast.File]bool)
+	for _, use := range v.AllUses {
+		if g.isImmutableReturningCall(use.Pos) {
+			continue
+		}
+		sel := g.directCallSelector(use.Pos)
+		if sel == nil || !typeutil.IsFinisherMethod(sel.Sel.Name) {
+			return nil
+		}
+		file := g.findFileContaining(use.Pos)
+		edits = append(edits, analysis.TextEdit{
+			Pos:     sel.X.End(),
+			End:     sel.X.End(),
+			NewText: []byte(".Session(&" + gormQualifier(file) + "Session{})"),
+		})
+		filesNeedingImport[file] = true
+	}
+	if len(edits) == 0 {
+		return nil
+	}
+	for file := range filesNeedingImport {
+		if importEdit := g.generateGormImportEdit(file); importEdit != nil {
+			edits = append(edits, *importEdit)
+		}
+	}
+	edits = g.deduplicateEdits(edits)
+	sort.Slice(edits, func(i, j int) bool {
+		return edits[i].Pos < edits[j].Pos
+	})
+	return &analysis.SuggestedFix{
+		Message:   "Insert Session before each finisher",
+		TextEdits: edits,
+	}
+}
+
+// isImmutableReturningCall reports whether the call whose '(' is at pos is a
+// Session, WithContext or Debug call on a *gorm.DB, e.g. the q.Session(...) of
+// q.Session(&gorm.Session{}).Find(nil), which leaves its receiver reusable.
+func (g *Generator) isImmutableReturningCall(pos token.Pos) bool {
+	call, _ := g.callAt(pos)
+	if call == nil || g.pass.TypesInfo == nil {
+		return false
+	}
+	sel, ok := call.Fun.(*ast.SelectorExpr)
+	return ok && g.gormTypes.IsImmutableReturningMethod(sel.Sel.Name, g.pass.TypesInfo.TypeOf(sel.X))
+}
+
+// callAt returns the call whose '(' is at pos together with the path of its
+// enclosing nodes, innermost first, or nil when there is none.
+func (g *Generator) callAt(pos token.Pos) (*ast.CallExpr, []ast.Node) {
+	file := g.findFileContaining(pos)
+	if file == nil {
+		return nil, nil
+	}
+	path, _ := astutil.PathEnclosingInterval(file, pos, pos)
+	for i, n := range path {
+		if call, ok := n.(*ast.CallExpr); ok && call.Lparen == pos {
+			return call, path[i:]
+		}
+	}
+	return nil, nil
+}
+
+// directCallSelector returns the selector of the statement-level method call
+// whose '(' is at pos, e.g. q.Count for the statement q.Count(nil). Like the
+// reassignment fix, it requires an expression statement calling a *gorm.DB
+// method on an assignable receiver; it returns nil for any other use,
+// including the inner calls of a chain such as q.Where("a") in
+// q.Where("a").Find(nil).
+func (g *Generator) directCallSelector(pos token.Pos) *ast.SelectorExpr {
+	file := g.findFileContaining(pos)
+	if file == nil {
+		return nil
+	}
+	exprStmt, ok := g.findStmtAtPos(file, pos).(*ast.ExprStmt)
+	if !ok {
+		return nil
+	}
+	call, ok := exprStmt.X.(*ast.CallExpr)
+	if !ok || call.Lparen != pos {
+		return nil
+	}
+	sel, ok := call.Fun.(*ast.SelectorExpr)
+	if !ok || g.pass.TypesInfo == nil {
+		return nil
+	}
+	recv := g.pass.TypesInfo.TypeOf(sel.X)
+	if !g.gormTypes.IsGormDB(recv) || g.gormTypes.IsBuilder(recv) {
+		return nil
+	}
+	if g.extractAssignableLHS(sel.X) == "" {
+		return nil
+	}
+	return sel
+}
+
+// generateRootSessionFix offers the alternative of making the whole chain
+// immutable at its root, q := db.Where("x").Session(&gorm.Session{}), instead
+// of reassigning each non-finisher use. Reassignment is wrong for a loop that
+// should build every query from the same base, and a finisher reuse cannot be
+// reassigned at all, so the alternative is offered for those two shapes. It is
+// also offered for a reuse in a chain longer than the chain threshold, and
+// labeled as recommended then. It is omitted when it would be identical to the
+// primary fix, which already Sessions the root when no reassignment is needed.
+func (g *Generator) generateRootSessionFix(v pollution.Violation, primary []analysis.TextEdit) *analysis.SuggestedFix {
+	root := v.Root
+	long := g.chainThreshold > 0 && g.chainLength(v.Pos) > g.chainThreshold
+	if !long && !g.isLoopUse(v.Pos, root.Pos()) && !g.isFinisherUse(v.Pos) {
+		return nil
+	}
+
+	edits := g.generateSessionEditsForRoot(root.Pos(), root)
+	if len(edits) == 0 {
+		return nil
+	}
+	if file := g.findFileContaining(root.Pos()); file != nil {
+		if importEdit := g.generateGormImportEdit(file); importEdit != nil {
+			edits = append(edits, *importEdit)
+		}
+	}
+	edits = g.deduplicateEdits(edits)
+	sort.Slice(edits, func(i, j int) bool {
+		return edits[i].Pos < edits[j].Pos
+	})
+
+	if slices.EqualFunc(edits, primary, func(a, b analysis.TextEdit) bool {
+		return a.Pos == b.Pos && a.End == b.End && string(a.NewText) == string(b.NewText)
+	}) {
+		return nil
+	}
+	message := "Make the root immutable with Session"
+	if long {
+		message += " (recommended for long chains)"
+	}
+	return &analysis.SuggestedFix{
+		Message:   message,
+		TextEdits: edits,
+	}
+}
+
+// chainLength returns the number of method calls in the chain the call whose
+// '(' is at pos belongs to, counted along the selector spine from the end of
+// the chain to its receiver:
+//
+//	q.Where("a").Order("b").Limit(1).Find(nil)  // 4, wherever pos is in it
+//
+// A field selector on the receiver, as in r.db.Find(nil), is not a call and
+// is not counted. It returns 0 when there is no call at pos.
+func (g *Generator) chainLength(pos token.Pos) int {
+	call, path := g.callAt(pos)
+	if call == nil {
+		return 0
+	}
+
+	// Climb to the end of the chain: each step up is a selector on the
+	// current expression or a call of it.
+	var top ast.Expr = call
+climb:
+	for _, n := range path[1:] {
+		switch p := n.(type) {
+		case *ast.SelectorExpr:
+			if p.X != top {
+				break climb
+			}
+		case *ast.CallExpr:
+			if p.Fun != top {
+				break climb
+			}
+		case *ast.ParenExpr:
+		default:
+			break climb
+		}
+		top = n.(ast.Expr)
+	}
+
+	// Walk back down the spine, counting the method calls.
+	length := 0
+	for {
+		switch e := top.(type) {
+		case *ast.CallExpr:
+			sel, ok := e.Fun.(*ast.SelectorExpr)
+			if !ok {
+				return length
+			}
+			length++
+			top = sel.X
+		case *ast.SelectorExpr:
+			top = e.X
+		case *ast.ParenExpr:
+			top = e.X
+		default:
+			return length
+		}
+	}
+}
+
+// isLoopUse reports whether the use at pos sits in the body of a for or range
+// loop that does not also contain rootPos, i.e. the root is defined outside the
+// loop and every iteration branches from it. A function literal ends the search:
+// a loop around a closure does not make the closure body a loop body.
+func (g *Generator) isLoopUse(pos, rootPos token.Pos) bool {
+	file := g.findFileContaining(pos)
+	if file == nil {
+		return false
+	}
+	path, _ := astutil.PathEnclosingInterval(file, pos, pos)
+	for _, n := range path {
+		var body *ast.BlockStmt
+		switch n := n.(type) {
+		case *ast.FuncLit, *ast.FuncDecl:
+			return false
+		case *ast.ForStmt:
+			body = n.Body
+		case *ast.RangeStmt:
+			body = n.Body
+		}
+		if body == nil || pos < body.Pos() || pos >= body.End() {
+			continue
+		}
+		if rootPos < body.Pos() || rootPos >= body.End() {
+			return true
+		}
+	}
+	return false
+}
+
+// isFinisherUse reports whether the call whose '(' is at pos, or a call chained
+// on it, is a finisher: q.Find(nil) and q.Where("a").Find(nil) both execute
+// the statement, so their reuse cannot be fixed by reassignment.
+func (g *Generator) isFinisherUse(pos token.Pos) bool {
+	_, path := g.callAt(pos)
+	// Walk up the receiver spine: call -> SelectorExpr -> CallExpr -> ...
+	for i := 0; i < len(path); i += 2 {
+		call, ok := path[i].(*ast.CallExpr)
+		if !ok {
+			return false
+		}
+		if sel, ok := call.Fun.(*ast.SelectorExpr); ok && typeutil.IsFinisherMethod(sel.Sel.Name) {
+			return true
+		}
+		if i+2 >= len(path) {
+			return false
+		}
+		sel, ok := path[i+1].(*ast.SelectorExpr)
+		if !ok || sel.X != call {
+			return false
+		}
+	}
+	return false
 }
 
 // generateImmutableParamFix suggests annotating the enclosing function with
@@ -290,7 +583,7 @@ func (g *Generator) isNonFinisherExprStmt(pos token.Pos) bool {
 	// This prevents false positives for non-GORM methods like require.NoError
 	if g.pass.TypesInfo != nil {
 		receiverType := g.pass.TypesInfo.TypeOf(sel.X)
-		if receiverType == nil || !typeutil.IsGormDB(receiverType) {
+		if receiverType == nil || !g.gormTypes.IsGormDB(receiverType) {
 			return false
 		}
 	}
@@ -304,33 +597,7 @@ func (g *Generator) isNonFinisherExprStmt(pos token.Pos) bool {
 	// Check if the method is a finisher
 	// Finishers are methods that typically end a chain: Find, Count, First, etc.
 	methodName := sel.Sel.Name
-	return !isFinisher(methodName)
-}
-
-// isFinisher checks if a method name is a GORM finisher method.
-func isFinisher(methodName string) bool {
-	finishers := map[string]bool{
-		"Find":          true,
-		"First":         true,
-		"Last":          true,
-		"Take":          true,
-		"Count":         true,
-		"Pluck":         true,
-		"Scan":          true,
-		"Row":           true,
-		"Rows":          true,
-		"ScanRows":      true,
-		"Create":        true,
-		"Save":          true,
-		"Update":        true,
-		"Updates":       true,
-		"Delete":        true,
-		"Exec":          true,
-		"Transaction":   true,
-		"FirstOrCreate": true, // terminal (executes); #71 secondary
-		"FirstOrInit":   true, // terminal (executes); #71 secondary
-	}
-	return finishers[methodName]
+	return !typeutil.IsFinisherMethod(methodName)
 }
 
 // virtualRootKey represents a virtual root (either original or created by reassignment).
@@ -782,7 +1049,11 @@ func (g *Generator) getVariableNameAtPos(pos token.Pos) string {
 // - Slice expressions: x[i:j]
 // - Type assertions: x.(T)
 // - Literals, composite literals
+// - Types, such as the (*gorm.DB) of a method expression (*gorm.DB).Find(q, nil)
 func (g *Generator) extractAssignableLHS(expr ast.Expr) string {
+	if g.pass != nil && g.pass.TypesInfo != nil && g.pass.TypesInfo.Types[expr].IsType() {
+		return ""
+	}
 	// Use a buffer to reconstruct the expression as we traverse
 	return g.extractAssignableLHSImpl(expr)
 }
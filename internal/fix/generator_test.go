@@ -83,19 +83,3 @@ func TestExtractAssignableLHS(t *testing.T) {
 		})
 	}
 }
-
-func TestIsFinisher(t *testing.T) {
-	t.Parallel()
-	finishers := []string{"Find", "First", "Count", "Create", "Save", "Delete", "Exec", "Transaction", "Scan", "Rows"}
-	for _, m := range finishers {
-		if !isFinisher(m) {
-			t.Errorf("%q should be a finisher", m)
-		}
-	}
-	nonFinishers := []string{"Where", "Order", "Limit", "Session", "WithContext", "Preload", "Scopes", ""}
-	for _, m := range nonFinishers {
-		if isFinisher(m) {
-			t.Errorf("%q should not be a finisher", m)
-		}
-	}
-}
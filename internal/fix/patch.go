@@ -0,0 +1,284 @@
+package fix
+
+import (
+	"bytes"
+	"cmp"
+	"fmt"
+	"go/token"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+
+	"golang.org/x/tools/go/analysis"
+)
+
+// =============================================================================
+// Patch Output (-fix-diff)
+// =============================================================================
+
+// contextLines is the number of unchanged lines around each hunk of a patch,
+// as in diff -u.
+const contextLines = 3
+
+// Edit is a suggested-fix text edit resolved to byte offsets in its file.
+type Edit struct {
+	Start, End int
+	NewText    string
+}
+
+// ApplyEdits returns a copy of src with edits applied. Identical edits, such as
+// the same Session insertion suggested for two uses of one root, are applied
+// once; overlapping edits are an error.
+func ApplyEdits(src []byte, edits []Edit) ([]byte, error) {
+	edits, err := sortEdits(edits)
+	if err != nil {
+		return nil, err
+	}
+	var out bytes.Buffer
+	last := 0
+	for _, e := range edits {
+		out.Write(src[last:e.Start])
+		out.WriteString(e.NewText)
+		last = e.End
+	}
+	out.Write(src[last:])
+	return out.Bytes(), nil
+}
+
+// sortEdits returns edits in offset order without duplicates. Two edits
+// conflict when their ranges overlap, or when both insert at the same offset:
+// neither order of the insertions is more right than the other.
+func sortEdits(edits []Edit) ([]Edit, error) {
+	edits = slices.Clone(edits)
+	slices.SortFunc(edits, func(a, b Edit) int {
+		return cmp.Or(cmp.Compare(a.Start, b.Start), cmp.Compare(a.End, b.End), cmp.Compare(a.NewText, b.NewText))
+	})
+	edits = slices.Compact(edits)
+	for i := 1; i < len(edits); i++ {
+		prev, e := edits[i-1], edits[i]
+		if e.Start < prev.End || prev.Start == prev.End && e.Start == prev.Start && e.End == e.Start {
+			return nil, fmt.Errorf("conflicting edits at offsets %d-%d and %d-%d", prev.Start, prev.End, e.Start, e.End)
+		}
+	}
+	return edits, nil
+}
+
+// ComputePatch returns a unified diff, like gofmt -d, of the files edited by
+// the first suggested fix of each diagnostic: the fix drivers such as go vet
+// -fix apply, the others being alternatives to it. Files are read from disk
+// and named relative to the working directory when possible, so the patch
+// applies with patch -p0 from there:
+//
+//	diff -u internal/repo.go.orig internal/repo.go
+//	--- internal/repo.go.orig
+//	+++ internal/repo.go
+//	@@ -12,3 +12,3 @@
+//	 func find(db *gorm.DB) {
+//	-	q := db.Where("x")
+//	+	q := db.Where("x").Session(&gorm.Session{})
+//	 	q.Find(nil)
+//
+// Files come in name order. Overlapping edits to a file are an error rather
+// than guessed at; identical ones are applied once (see ApplyEdits).
+func ComputePatch(fset *token.FileSet, diagnostics []analysis.Diagnostic) ([]byte, error) {
+	byFile := make(map[string][]Edit)
+	for _, d := range diagnostics {
+		if len(d.SuggestedFixes) == 0 {
+			continue
+		}
+		for _, e := range d.SuggestedFixes[0].TextEdits {
+			end := e.End
+			if !end.IsValid() {
+				end = e.Pos
+			}
+			start := fset.Position(e.Pos)
+			byFile[start.Filename] = append(byFile[start.Filename], Edit{
+				Start:   start.Offset,
+				End:     fset.Position(end).Offset,
+				NewText: string(e.NewText),
+			})
+		}
+	}
+
+	var out bytes.Buffer
+	for _, filename := range slices.Sorted(maps.Keys(byFile)) {
+		src, err := os.ReadFile(filename)
+		if err != nil {
+			return nil, err
+		}
+		edits, err := sortEdits(byFile[filename])
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", filename, err)
+		}
+		hunks := diffHunks(src, edits)
+		if len(hunks) == 0 {
+			continue
+		}
+		name := patchName(filename)
+		fmt.Fprintf(&out, "diff -u %s.orig %s\n--- %s.orig\n+++ %s\n", name, name, name, name)
+		for _, h := range hunks {
+			h.write(&out)
+		}
+	}
+	return out.Bytes(), nil
+}
+
+// patchName returns filename relative to the working directory, or filename
+// itself when it is not below it.
+func patchName(filename string) string {
+	wd, err := os.Getwd()
+	if err != nil {
+		return filename
+	}
+	rel, err := filepath.Rel(wd, filename)
+	if err != nil || !filepath.IsLocal(rel) {
+		return filename
+	}
+	return filepath.ToSlash(rel)
+}
+
+// change replaces the lines [oldStart, oldEnd) of a file, 0-based, with
+// newLines.
+type change struct {
+	oldStart, oldEnd int
+	newLines         [][]byte
+}
+
+// hunk is one @@ section of a unified diff: changes close enough to share
+// their context lines.
+type hunk struct {
+	oldLines         [][]byte // all lines of the file
+	changes          []change
+	oldStart, oldEnd int // context included
+	newStart         int // 0-based line of the hunk in the edited file
+}
+
+// diffHunks returns the hunks of the diff between src and src with the sorted
+// edits applied. Edits touching the same lines are one change; lines a change
+// leaves as they were are trimmed from it, so an insertion shows as added
+// lines only.
+func diffHunks(src []byte, edits []Edit) []hunk {
+	lines := splitLines(src)
+	// starts[i] is the offset of line i; starts[len(lines)] is len(src).
+	starts := make([]int, 0, len(lines)+1)
+	offset := 0
+	for _, l := range lines {
+		starts = append(starts, offset)
+		offset += len(l)
+	}
+	starts = append(starts, len(src))
+	lineOf := func(off int) int {
+		i, found := slices.BinarySearch(starts, off)
+		if !found {
+			i--
+		}
+		return min(i, max(len(lines)-1, 0))
+	}
+
+	var changes []change
+	for i := 0; i < len(edits); {
+		first, last := lineOf(edits[i].Start), lineOf(max(edits[i].End-1, edits[i].Start))
+		j := i + 1
+		for ; j < len(edits) && lineOf(edits[j].Start) <= last; j++ {
+			last = max(last, lineOf(max(edits[j].End-1, edits[j].Start)))
+		}
+		var text bytes.Buffer
+		pos := starts[first]
+		for _, e := range edits[i:j] {
+			text.Write(src[pos:e.Start])
+			text.WriteString(e.NewText)
+			pos = e.End
+		}
+		text.Write(src[pos:starts[last+1]])
+		i = j
+
+		c := change{oldStart: first, oldEnd: last + 1, newLines: splitLines(text.Bytes())}
+		for c.oldStart < c.oldEnd && len(c.newLines) > 0 && bytes.Equal(lines[c.oldStart], c.newLines[0]) {
+			c.oldStart++
+			c.newLines = c.newLines[1:]
+		}
+		for c.oldStart < c.oldEnd && len(c.newLines) > 0 && bytes.Equal(lines[c.oldEnd-1], c.newLines[len(c.newLines)-1]) {
+			c.oldEnd--
+			c.newLines = c.newLines[:len(c.newLines)-1]
+		}
+		if c.oldStart < c.oldEnd || len(c.newLines) > 0 {
+			changes = append(changes, c)
+		}
+	}
+
+	var hunks []hunk
+	delta := 0 // lines added so far, minus lines removed
+	for _, c := range changes {
+		start, end := max(c.oldStart-contextLines, 0), min(c.oldEnd+contextLines, len(lines))
+		if n := len(hunks); n > 0 && start <= hunks[n-1].oldEnd {
+			hunks[n-1].changes = append(hunks[n-1].changes, c)
+			hunks[n-1].oldEnd = end
+		} else {
+			hunks = append(hunks, hunk{oldLines: lines, changes: []change{c}, oldStart: start, oldEnd: end, newStart: start + delta})
+		}
+		delta += len(c.newLines) - (c.oldEnd - c.oldStart)
+	}
+	return hunks
+}
+
+// write renders h with its @@ header.
+func (h hunk) write(out *bytes.Buffer) {
+	var body bytes.Buffer
+	oldCount, newCount := 0, 0
+	line := func(prefix byte, l []byte) {
+		body.WriteByte(prefix)
+		body.Write(l)
+		if !bytes.HasSuffix(l, []byte("\n")) {
+			body.WriteString("\n\\ No newline at end of file\n")
+		}
+	}
+	pos := h.oldStart
+	context := func(end int) {
+		for ; pos < end; pos++ {
+			line(' ', h.oldLines[pos])
+			oldCount++
+			newCount++
+		}
+	}
+	for _, c := range h.changes {
+		context(c.oldStart)
+		for ; pos < c.oldEnd; pos++ {
+			line('-', h.oldLines[pos])
+			oldCount++
+		}
+		for _, l := range c.newLines {
+			line('+', l)
+			newCount++
+		}
+	}
+	context(h.oldEnd)
+	fmt.Fprintf(out, "@@ -%s +%s @@\n", hunkRange(h.oldStart, oldCount), hunkRange(h.newStart, newCount))
+	out.Write(body.Bytes())
+}
+
+// hunkRange renders the 0-based start and the length of a hunk's side as
+// diff -u does: 1-based, and naming the line before an empty range.
+func hunkRange(start, count int) string {
+	if count == 0 {
+		return fmt.Sprintf("%d,0", start)
+	}
+	if count == 1 {
+		return fmt.Sprintf("%d", start+1)
+	}
+	return fmt.Sprintf("%d,%d", start+1, count)
+}
+
+// splitLines splits src after each newline; a last line without one is kept.
+func splitLines(src []byte) [][]byte {
+	var lines [][]byte
+	for len(src) > 0 {
+		i := bytes.IndexByte(src, '\n') + 1
+		if i == 0 {
+			i = len(src)
+		}
+		lines = append(lines, src[:i])
+		src = src[i:]
+	}
+	return lines
+}
@@ -0,0 +1,46 @@
+package fix
+
+import (
+	"testing"
+)
+
+// TestApplyEdits covers the deduplication and conflict rules shared by the
+// golden fixtures and -fix-diff.
+func TestApplyEdits(t *testing.T) {
+	t.Parallel()
+	const src = "q := db.Where(x)\nq.Find(nil)\n"
+	session := Edit{Start: 16, End: 16, NewText: ".Session(&gorm.Session{})"}
+	tests := []struct {
+		name    string
+		edits   []Edit
+		want    string
+		wantErr bool
+	}{
+		{"none", nil, src, false},
+		{"insertion", []Edit{session}, "q := db.Where(x).Session(&gorm.Session{})\nq.Find(nil)\n", false},
+		{"duplicate", []Edit{session, session}, "q := db.Where(x).Session(&gorm.Session{})\nq.Find(nil)\n", false},
+		{"unordered", []Edit{{Start: 17, End: 17, NewText: "q = "}, session}, "q := db.Where(x).Session(&gorm.Session{})\nq = q.Find(nil)\n", false},
+		{"insertion before replacement", []Edit{{Start: 0, End: 1, NewText: "r"}, {Start: 0, End: 0, NewText: "// x\n"}}, "// x\nr := db.Where(x)\nq.Find(nil)\n", false},
+		{"adjacent replacements", []Edit{{Start: 0, End: 1, NewText: "r"}, {Start: 1, End: 2, NewText: "_"}}, "r_:= db.Where(x)\nq.Find(nil)\n", false},
+		{"overlap", []Edit{{Start: 5, End: 10, NewText: "tx"}, {Start: 8, End: 16, NewText: "Or"}}, "", true},
+		{"insertions at one offset", []Edit{session, {Start: 16, End: 16, NewText: ".Debug()"}}, "", true},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			got, err := ApplyEdits([]byte(src), tc.edits)
+			if tc.wantErr {
+				if err == nil {
+					t.Errorf("ApplyEdits() = %q, want conflict error", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ApplyEdits() error: %v", err)
+			}
+			if string(got) != tc.want {
+				t.Errorf("ApplyEdits() = %q, want %q", got, tc.want)
+			}
+		})
+	}
+}
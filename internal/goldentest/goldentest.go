@@ -1,5 +1,6 @@
 // Package goldentest holds the shared machinery for generating and checking the
-// suggested-fix golden (.golden) and diff (.diff) fixtures. Before this package
+// suggested-fix golden (.golden) and diff (.diff) fixtures, and the
+// .diagnostics.golden snapshots of the full diagnostic output. Before this package
 // existed, the "run the analyzer, apply its suggested fixes in reverse offset
 // order, render a stable unified diff" logic was copied three times (the
 // gengolden command, TestGenerateDiffFiles, and TestDiffFilesUpToDate) and the
@@ -9,15 +10,25 @@ package goldentest
 
 import (
 	"bytes"
+	"cmp"
+	"fmt"
+	"go/token"
+	"maps"
 	"os"
 	"os/exec"
 	"path/filepath"
 	"regexp"
+	"slices"
 	"sort"
 	"strings"
+	"sync"
 
 	"golang.org/x/tools/go/analysis"
 	"golang.org/x/tools/go/analysis/analysistest"
+	"golang.org/x/tools/txtar"
+
+	"github.com/mpyw/gormreuse/internal"
+	"github.com/mpyw/gormreuse/internal/fix"
 )
 
 // NoopT implements the analysistest.Testing interface, swallowing every failure
@@ -53,49 +64,128 @@ func Fixtures(srcDir string) ([]string, error) {
 	return out, nil
 }
 
+// runs caches the results of analysistest.Run by testdata, package and
+// analyzer: every fixture of a package shares one run over the package, rather
+// than re-analyzing it once per fixture. Callers must not change the analyzer's
+// flags between runs.
+var runs sync.Map // runKey -> *cachedRun
+
+type runKey struct {
+	testdata, pkg string
+	a             *analysis.Analyzer
+}
+
+type cachedRun struct {
+	once    sync.Once
+	results []*analysistest.Result
+}
+
+func run(testdata, pkg string, a *analysis.Analyzer) []*analysistest.Result {
+	v, _ := runs.LoadOrStore(runKey{testdata, pkg, a}, new(cachedRun))
+	r := v.(*cachedRun)
+	r.once.Do(func() { r.results = analysistest.Run(NoopT{}, testdata, a, pkg) })
+	return r.results
+}
+
 // ApplyFixes runs analyzer a over pkg (loaded from testdata) and returns
-// srcPath's original bytes together with the content produced by applying every
-// suggested-fix text edit targeting srcPath. Edits are matched to the file by
-// their own position and applied from the highest offset down so earlier offsets
-// stay valid.
+// srcPath's original bytes together with the content produced by applying the
+// first suggested fix of every diagnostic to srcPath, as drivers such as
+// `go vet -fix` do; alternative fixes are covered by Golden instead.
 func ApplyFixes(testdata, pkg, srcPath string, a *analysis.Analyzer) (original, fixed []byte, err error) {
 	original, err = os.ReadFile(srcPath)
 	if err != nil {
 		return nil, nil, err
 	}
+	results := run(testdata, pkg, a)
+	var edits []fix.Edit
+	for _, fe := range fileEdits(results, srcPath) {
+		if fe.index == 0 {
+			edits = append(edits, fe.edits...)
+		}
+	}
+	fixed, err = fix.ApplyEdits(original, edits)
+	if err != nil {
+		return nil, nil, fmt.Errorf("%s: %w", srcPath, err)
+	}
+	return original, fixed, nil
+}
 
-	results := analysistest.Run(NoopT{}, testdata, a, pkg)
+// Golden returns the committed .golden content for srcPath. It is the result
+// of ApplyFixes unless a diagnostic offers alternative fixes, in which case it
+// is a txtar archive with one section per fix message holding the result of
+// applying every fix of that message — the form RunWithSuggestedFixes expects
+// when alternatives would otherwise conflict.
+func Golden(testdata, pkg, srcPath string, a *analysis.Analyzer) ([]byte, error) {
+	original, err := os.ReadFile(srcPath)
+	if err != nil {
+		return nil, err
+	}
+	results := run(testdata, pkg, a)
+	all := fileEdits(results, srcPath)
 
-	type offsetEdit struct {
-		start, end int
-		newText    string
+	alternatives := false
+	byMessage := make(map[string][]fix.Edit)
+	var primary []fix.Edit
+	for _, fe := range all {
+		if fe.index == 0 {
+			primary = append(primary, fe.edits...)
+		} else {
+			alternatives = true
+		}
+		byMessage[fe.message] = append(byMessage[fe.message], fe.edits...)
+	}
+	if !alternatives {
+		fixed, err := fix.ApplyEdits(original, primary)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", srcPath, err)
+		}
+		return fixed, nil
+	}
+
+	var ar txtar.Archive
+	for _, message := range slices.Sorted(maps.Keys(byMessage)) {
+		fixed, err := fix.ApplyEdits(original, byMessage[message])
+		if err != nil {
+			return nil, fmt.Errorf("%s: fix %q: %w", srcPath, message, err)
+		}
+		ar.Files = append(ar.Files, txtar.File{Name: message, Data: fixed})
 	}
-	var edits []offsetEdit
+	return txtar.Format(&ar), nil
+}
+
+// fixEdits holds the edits one suggested fix makes to a file, with the fix's
+// index among its diagnostic's alternatives.
+type fixEdits struct {
+	index   int
+	message string
+	edits   []fix.Edit
+}
+
+// fileEdits returns, for every suggested fix in results that edits srcPath, the
+// edits it makes there. Edits are matched to the file by their own position.
+func fileEdits(results []*analysistest.Result, srcPath string) []fixEdits {
+	var out []fixEdits
 	for _, result := range results {
 		for _, diag := range result.Diagnostics {
-			for _, fix := range diag.SuggestedFixes {
-				for _, edit := range fix.TextEdits {
+			for i, sf := range diag.SuggestedFixes {
+				fe := fixEdits{index: i, message: sf.Message}
+				for _, edit := range sf.TextEdits {
 					if result.Pass.Fset.File(edit.Pos).Name() != srcPath {
 						continue
 					}
-					edits = append(edits, offsetEdit{
-						start:   result.Pass.Fset.Position(edit.Pos).Offset,
-						end:     result.Pass.Fset.Position(edit.End).Offset,
-						newText: string(edit.NewText),
+					fe.edits = append(fe.edits, fix.Edit{
+						Start:   result.Pass.Fset.Position(edit.Pos).Offset,
+						End:     result.Pass.Fset.Position(edit.End).Offset,
+						NewText: string(edit.NewText),
 					})
 				}
+				if len(fe.edits) > 0 {
+					out = append(out, fe)
+				}
 			}
 		}
 	}
-
-	fixed = append([]byte(nil), original...)
-	if len(edits) > 0 {
-		sort.Slice(edits, func(i, j int) bool { return edits[i].start > edits[j].start })
-		for _, e := range edits {
-			fixed = append(fixed[:e.start], append([]byte(e.newText), fixed[e.end:]...)...)
-		}
-	}
-	return original, fixed, nil
+	return out
 }
 
 var timestampRegex = regexp.MustCompile(`\d{4}-\d{2}-\d{2} \d{2}:\d{2}:\d{2}(\.\d+)?( [+-]\d{4})?`)
@@ -135,3 +225,68 @@ func GenerateDiff(filename string, original, fixed []byte) ([]byte, error) {
 	b = timestampRegex.ReplaceAll(b, []byte("1970-01-01 00:00:00"))
 	return b, nil
 }
+
+// FormatDiagnostics renders every diagnostic of results as the committed
+// .diagnostics.golden snapshot: one block per diagnostic with its position,
+// category and message, followed by its related information and the text
+// edits of each suggested fix. Unlike a // want regexp, the snapshot pins the
+// whole structured output, so a changed message suffix, category, related
+// location or fix is a visible diff.
+//
+//	basic.go:14:9 [BRANCH] *gorm.DB reused: second branch from mutable root (...)
+//	  related basic.go:12:15: root defined here
+//	  fix "Add reassignment and Session to fix reuse"
+//	    edit basic.go:12:26-12:26 ".Session(&gorm.Session{})"
+//
+// Positions use file base names so the snapshot does not depend on where the
+// module is checked out. Blocks are sorted by internal.DiagnosticKey, then by
+// their related information and fixes; identical blocks, such
+// as those reported for both a package and its test variant, appear once.
+func FormatDiagnostics(results []*analysistest.Result) []byte {
+	type block struct {
+		key  internal.DiagnosticKey
+		text string
+	}
+	var blocks []block
+	seen := make(map[string]bool)
+	for _, r := range results {
+		fset := r.Pass.Fset
+		pos := func(p token.Pos) string {
+			position := fset.Position(p)
+			return fmt.Sprintf("%s:%d:%d", filepath.Base(position.Filename), position.Line, position.Column)
+		}
+		for _, d := range r.Diagnostics {
+			var b strings.Builder
+			fmt.Fprintf(&b, "%s [%s] %s\n", pos(d.Pos), d.Category, d.Message)
+			for _, rel := range d.Related {
+				fmt.Fprintf(&b, "  related %s: %s\n", pos(rel.Pos), rel.Message)
+			}
+			for _, fix := range d.SuggestedFixes {
+				fmt.Fprintf(&b, "  fix %q\n", fix.Message)
+				for _, e := range fix.TextEdits {
+					end := e.End
+					if !end.IsValid() {
+						end = e.Pos
+					}
+					endPos := fset.Position(end)
+					fmt.Fprintf(&b, "    edit %s-%d:%d %q\n", pos(e.Pos), endPos.Line, endPos.Column, e.NewText)
+				}
+			}
+			if seen[b.String()] {
+				continue
+			}
+			seen[b.String()] = true
+			key := internal.NewDiagnosticKey(fset, d)
+			key.File = filepath.Base(key.File)
+			blocks = append(blocks, block{key, b.String()})
+		}
+	}
+	slices.SortFunc(blocks, func(a, b block) int {
+		return cmp.Or(a.key.Compare(b.key), cmp.Compare(a.text, b.text))
+	})
+	var out bytes.Buffer
+	for _, b := range blocks {
+		out.WriteString(b.text)
+	}
+	return out.Bytes()
+}
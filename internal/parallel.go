@@ -0,0 +1,74 @@
+package internal
+
+import (
+	"runtime"
+	"sync"
+
+	"golang.org/x/tools/go/ssa"
+
+	ssautil "github.com/mpyw/gormreuse/internal/ssa"
+	"github.com/mpyw/gormreuse/internal/ssa/pollution"
+)
+
+// =============================================================================
+// Parallel Analysis (-parallel)
+// =============================================================================
+
+// funcAnalysis is the outcome of the SSA analysis of one function, handed to
+// its checker for reporting.
+type funcAnalysis struct {
+	violations []pollution.Violation
+	roots      []pollution.GraphRoot // nil unless collected for -report-root-graph or -list-roots-json
+}
+
+// analyzeFunctions runs the SSA analysis of funcs on up to parallel workers
+// (GOMAXPROCS when parallel is 0) and returns the outcomes in the order of
+// funcs. The analysis of a function reads the shared directive sets and
+// callback maps but writes nothing outside its own Analyzer, so functions are
+// independent of each other. Reporting is left to the caller, which walks the
+// outcomes in order: deduplicating a violation found in both a function and
+// its closure then keeps the same diagnostic whichever worker finished first.
+//
+// A function whose analysis panics has no violations, as when analyzed alone
+// (see recoverPerFunction).
+func analyzeFunctions(funcs []*ssa.Function, opts ssautil.Options, parallel int, withRoots bool) []funcAnalysis {
+	results := make([]funcAnalysis, len(funcs))
+	analyze := func(i int) {
+		recoverPerFunction(funcs[i], func() {
+			analyzer := opts.Analyzer(funcs[i])
+			results[i].violations = analyzer.Analyze()
+			if withRoots {
+				results[i].roots = analyzer.RootGraph()
+			}
+		})
+	}
+
+	if parallel <= 0 {
+		parallel = runtime.GOMAXPROCS(0)
+	}
+	parallel = min(parallel, len(funcs))
+	if parallel <= 1 {
+		for i := range funcs {
+			analyze(i)
+		}
+		return results
+	}
+
+	next := make(chan int)
+	var wg sync.WaitGroup
+	for range parallel {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range next {
+				analyze(i)
+			}
+		}()
+	}
+	for i := range funcs {
+		next <- i
+	}
+	close(next)
+	wg.Wait()
+	return results
+}
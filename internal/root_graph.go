@@ -0,0 +1,137 @@
+package internal
+
+import (
+	"fmt"
+	"go/token"
+	"io"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"golang.org/x/tools/go/ssa"
+
+	"github.com/mpyw/gormreuse/internal/ssa/pollution"
+)
+
+// =============================================================================
+// Root Graph (-report-root-graph)
+// =============================================================================
+
+// rootGraph collects the roots and uses recorded while analyzing each function
+// and renders them as a Graphviz DOT digraph. It is a debugging aid: it
+// visualizes tracker state and has no effect on diagnostics.
+//
+//	digraph "gormreuse: example.com/pkg" {
+//	  subgraph "cluster_0" {
+//	    label="example.com/pkg.f";
+//	    "f0_r0" [shape=box, label="Where\nfile.go:6"];
+//	    "f0_u0" [label="branch\nfile.go:7"];
+//	    "f0_r0" -> "f0_u0" [label="branch"];
+//	  }
+//	}
+//
+// Each root points at its uses; a use that derives another root (q2 :=
+// q.Where(...)) points at that root with a dashed "derives" edge. Uses where a
+// violation was reported are drawn in red.
+type rootGraph struct {
+	fset  *token.FileSet
+	funcs []rootGraphFunc
+}
+
+type rootGraphFunc struct {
+	fn    *ssa.Function
+	roots []pollution.GraphRoot
+}
+
+func newRootGraph(fset *token.FileSet) *rootGraph {
+	return &rootGraph{fset: fset}
+}
+
+// add records the roots of fn. Functions without roots are omitted.
+func (g *rootGraph) add(fn *ssa.Function, roots []pollution.GraphRoot) {
+	if len(roots) == 0 {
+		return
+	}
+	g.funcs = append(g.funcs, rootGraphFunc{fn: fn, roots: roots})
+}
+
+// writeDOT renders the collected functions as a single digraph named after pkgPath.
+func (g *rootGraph) writeDOT(w io.Writer, pkgPath string) {
+	var b strings.Builder
+	fmt.Fprintf(&b, "digraph %s {\n", dotQuote("gormreuse: "+pkgPath))
+	b.WriteString("  node [fontname=\"monospace\"];\n")
+	for i, f := range g.funcs {
+		g.writeFunc(&b, i, f)
+	}
+	b.WriteString("}\n")
+	_, _ = io.WriteString(w, b.String())
+}
+
+func (g *rootGraph) writeFunc(b *strings.Builder, idx int, f rootGraphFunc) {
+	fmt.Fprintf(b, "  subgraph %s {\n", dotQuote("cluster_"+strconv.Itoa(idx)))
+	fmt.Fprintf(b, "    label=%s;\n", dotQuote(f.fn.String()))
+
+	rootIDs := make(map[token.Pos]string)
+	for i, r := range f.roots {
+		id := fmt.Sprintf("f%d_r%d", idx, i)
+		if r.Root.Pos().IsValid() {
+			rootIDs[r.Root.Pos()] = id
+		}
+		fmt.Fprintf(b, "    %s [shape=box, label=%s];\n", dotQuote(id), dotQuote(g.label(rootLabel(r.Root), r.Root.Pos())))
+	}
+
+	n := 0
+	for i, r := range f.roots {
+		rootID := fmt.Sprintf("f%d_r%d", idx, i)
+		for _, u := range r.Uses {
+			useID := fmt.Sprintf("f%d_u%d", idx, n)
+			n++
+			attrs := ""
+			if u.Violation {
+				attrs = ", color=red, fontcolor=red"
+			}
+			fmt.Fprintf(b, "    %s [label=%s%s];\n", dotQuote(useID), dotQuote(g.label(u.Kind.String(), u.Pos)), attrs)
+			fmt.Fprintf(b, "    %s -> %s [label=%s%s];\n", dotQuote(rootID), dotQuote(useID), dotQuote(u.Kind.String()), attrs)
+			if derived, ok := rootIDs[u.Pos]; ok && derived != rootID {
+				fmt.Fprintf(b, "    %s -> %s [style=dashed, label=\"derives\"];\n", dotQuote(useID), dotQuote(derived))
+			}
+		}
+	}
+	b.WriteString("  }\n")
+}
+
+// label joins a description and a "file.go:line" location, when known.
+func (g *rootGraph) label(desc string, pos token.Pos) string {
+	if g.fset == nil || !pos.IsValid() {
+		return desc
+	}
+	p := g.fset.Position(pos)
+	return desc + "\n" + filepath.Base(p.Filename) + ":" + strconv.Itoa(p.Line)
+}
+
+// rootLabel describes a root: the called method or function for a call,
+// otherwise the kind of value and its SSA name.
+func rootLabel(v ssa.Value) string {
+	switch val := v.(type) {
+	case *ssa.Call:
+		if callee := val.Call.StaticCallee(); callee != nil {
+			return callee.Name()
+		}
+		if val.Call.Method != nil {
+			return val.Call.Method.Name()
+		}
+		return "call " + val.Name()
+	case *ssa.Parameter:
+		return "param " + val.Name()
+	case *ssa.Phi:
+		return "phi " + val.Name()
+	default:
+		return val.Name()
+	}
+}
+
+// dotQuote renders s as a DOT quoted string. DOT understands the escapes Go
+// produces for quotes, backslashes and newlines.
+func dotQuote(s string) string {
+	return strconv.Quote(s)
+}
@@ -0,0 +1,99 @@
+package internal
+
+import (
+	"encoding/json"
+	"go/token"
+	"io"
+
+	"golang.org/x/tools/go/ssa"
+
+	"github.com/mpyw/gormreuse/internal/ssa/pollution"
+)
+
+// =============================================================================
+// Root List (-list-roots-json)
+// =============================================================================
+
+// rootList collects the roots recorded while analyzing each function and
+// renders them as one JSON document per package, for IDE overlays and custom
+// reports on chain lifetimes:
+//
+//	{"package":"example.com/pkg","functions":[{"function":"example.com/pkg.f","roots":[
+//	  {"rootPos":"/src/file.go:6:15","createdBy":"Where","polluted":true,
+//	   "firstUsePos":"/src/file.go:7:8","reuseSites":["/src/file.go:8:9"]}]}]}
+//
+// Like rootGraph it only reports tracker state and has no effect on diagnostics.
+type rootList struct {
+	fset  *token.FileSet
+	funcs []rootListFunc
+}
+
+type rootListPackage struct {
+	Package   string         `json:"package"`
+	Functions []rootListFunc `json:"functions"`
+}
+
+type rootListFunc struct {
+	Function string          `json:"function"`
+	Roots    []rootListEntry `json:"roots"`
+}
+
+// rootListEntry describes one mutable root. Polluted is set once the root has a
+// branch or a deferred/goroutine use; FirstUsePos is the first of those and
+// ReuseSites are the uses reported as violations.
+type rootListEntry struct {
+	RootPos     string   `json:"rootPos"`
+	CreatedBy   string   `json:"createdBy"`
+	Polluted    bool     `json:"polluted"`
+	FirstUsePos string   `json:"firstUsePos,omitempty"`
+	ReuseSites  []string `json:"reuseSites"`
+}
+
+func newRootList(fset *token.FileSet) *rootList {
+	return &rootList{fset: fset}
+}
+
+// add records the roots of fn. Functions without roots are omitted.
+func (l *rootList) add(fn *ssa.Function, roots []pollution.GraphRoot) {
+	if len(roots) == 0 {
+		return
+	}
+	f := rootListFunc{Function: fn.String()}
+	for _, r := range roots {
+		entry := rootListEntry{
+			RootPos:    l.position(r.Root.Pos()),
+			CreatedBy:  rootLabel(r.Root),
+			ReuseSites: []string{},
+		}
+		for _, u := range r.Uses {
+			if u.Kind == pollution.UsePolluting || u.Kind == pollution.UseDeferred {
+				if !entry.Polluted {
+					entry.Polluted = true
+					entry.FirstUsePos = l.position(u.Pos)
+				}
+			}
+			if u.Violation {
+				entry.ReuseSites = append(entry.ReuseSites, l.position(u.Pos))
+			}
+		}
+		f.Roots = append(f.Roots, entry)
+	}
+	l.funcs = append(l.funcs, f)
+}
+
+// writeJSON renders the collected functions as a single line of JSON.
+func (l *rootList) writeJSON(w io.Writer, pkgPath string) {
+	funcs := l.funcs
+	if funcs == nil {
+		funcs = []rootListFunc{}
+	}
+	_ = json.NewEncoder(w).Encode(rootListPackage{Package: pkgPath, Functions: funcs})
+}
+
+// position renders pos as "file.go:line:col", or "" when unknown.
+func (l *rootList) position(pos token.Pos) string {
+	if l.fset == nil || !pos.IsValid() {
+		return ""
+	}
+	return l.fset.Position(pos).String()
+}
@@ -38,12 +38,12 @@ type scopesWarning struct {
 // validateScopesCallback checks whether fn is a Scopes callback and warns
 // about Session()/WithContext()/Debug() calls inside it. These three are
 // the methods that touch the broken InstanceSet/InstanceGet path.
-func validateScopesCallback(fn *ssa.Function) []scopesWarning {
+func validateScopesCallback(fn *ssa.Function, gormTypes *typeutil.Matcher) []scopesWarning {
 	parent := fn.Parent()
 	if parent == nil {
 		return nil
 	}
-	if !isScopesCallback(fn, parent) {
+	if !isScopesCallback(fn, parent, gormTypes) {
 		return nil
 	}
 
@@ -57,7 +57,7 @@ func validateScopesCallback(fn *ssa.Function) []scopesWarning {
 			// Only flag the GORM bug when these names are actually
 			// methods on *gorm.DB; an unrelated package's Session() or
 			// Debug() shouldn't trigger the warning.
-			if !isGormDBMethodCall(call) {
+			if !isGormDBMethodCall(call, gormTypes) {
 				continue
 			}
 			switch getMethodName(call) {
@@ -99,10 +99,10 @@ func storeRefersToFunction(store *ssa.Store, fn *ssa.Function) bool {
 // isScopesCallback reports whether fn is a callback passed to the Scopes method,
 // via either the variadic-packing path (Store→IndexAddr→Alloc→Slice→Scopes) or a
 // direct function/closure argument.
-func isScopesCallback(fn *ssa.Function, parent *ssa.Function) bool {
+func isScopesCallback(fn *ssa.Function, parent *ssa.Function, gormTypes *typeutil.Matcher) bool {
 	for _, block := range parent.Blocks {
 		for _, instr := range block.Instrs {
-			if storePacksFuncIntoScopes(instr, fn) || callPassesFuncToScopes(instr, fn) {
+			if storePacksFuncIntoScopes(instr, fn, gormTypes) || callPassesFuncToScopes(instr, fn, gormTypes) {
 				return true
 			}
 		}
@@ -112,7 +112,7 @@ func isScopesCallback(fn *ssa.Function, parent *ssa.Function) bool {
 
 // storePacksFuncIntoScopes reports whether instr stores fn into a variadic array
 // that is then sliced and handed to Scopes.
-func storePacksFuncIntoScopes(instr ssa.Instruction, fn *ssa.Function) bool {
+func storePacksFuncIntoScopes(instr ssa.Instruction, fn *ssa.Function, gormTypes *typeutil.Matcher) bool {
 	store, ok := instr.(*ssa.Store)
 	if !ok || !storeRefersToFunction(store, fn) {
 		return false
@@ -125,17 +125,17 @@ func storePacksFuncIntoScopes(instr ssa.Instruction, fn *ssa.Function) bool {
 	if !ok {
 		return false
 	}
-	return allocFlowsToScopes(alloc)
+	return allocFlowsToScopes(alloc, gormTypes)
 }
 
 // allocFlowsToScopes reports whether a slice of alloc is passed to Scopes.
-func allocFlowsToScopes(alloc *ssa.Alloc) bool {
+func allocFlowsToScopes(alloc *ssa.Alloc, gormTypes *typeutil.Matcher) bool {
 	refs := alloc.Referrers()
 	if refs == nil {
 		return false
 	}
 	for _, ref := range *refs {
-		if slice, ok := ref.(*ssa.Slice); ok && sliceFlowsToScopes(slice) {
+		if slice, ok := ref.(*ssa.Slice); ok && sliceFlowsToScopes(slice, gormTypes) {
 			return true
 		}
 	}
@@ -143,13 +143,13 @@ func allocFlowsToScopes(alloc *ssa.Alloc) bool {
 }
 
 // sliceFlowsToScopes reports whether slice is an argument to a Scopes call.
-func sliceFlowsToScopes(slice *ssa.Slice) bool {
+func sliceFlowsToScopes(slice *ssa.Slice, gormTypes *typeutil.Matcher) bool {
 	refs := slice.Referrers()
 	if refs == nil {
 		return false
 	}
 	for _, ref := range *refs {
-		if call, ok := ref.(*ssa.Call); ok && getMethodName(call) == "Scopes" && isGormDBMethodCall(call) {
+		if call, ok := ref.(*ssa.Call); ok && getMethodName(call) == "Scopes" && isGormDBMethodCall(call, gormTypes) {
 			return true
 		}
 	}
@@ -158,9 +158,9 @@ func sliceFlowsToScopes(slice *ssa.Slice) bool {
 
 // callPassesFuncToScopes reports whether instr is a Scopes call that receives fn
 // directly as an argument (a bare function or a closure).
-func callPassesFuncToScopes(instr ssa.Instruction, fn *ssa.Function) bool {
+func callPassesFuncToScopes(instr ssa.Instruction, fn *ssa.Function, gormTypes *typeutil.Matcher) bool {
 	call, ok := instr.(*ssa.Call)
-	if !ok || getMethodName(call) != "Scopes" || !isGormDBMethodCall(call) {
+	if !ok || getMethodName(call) != "Scopes" || !isGormDBMethodCall(call, gormTypes) {
 		return false
 	}
 	for _, arg := range call.Call.Args {
@@ -191,13 +191,13 @@ func getMethodName(call *ssa.Call) string {
 // *gorm.DB. We need this whenever we key off a method *name*
 // (Session/WithContext/Debug/Scopes) so that an unrelated package's
 // identically-named method doesn't trigger GORM-specific diagnostics.
-func isGormDBMethodCall(call *ssa.Call) bool {
+func isGormDBMethodCall(call *ssa.Call, gormTypes *typeutil.Matcher) bool {
 	if call.Call.IsInvoke() {
-		return typeutil.IsGormDB(call.Call.Value.Type())
+		return gormTypes.IsGormDB(call.Call.Value.Type())
 	}
 	callee := call.Call.StaticCallee()
 	if callee == nil || callee.Signature == nil || callee.Signature.Recv() == nil {
 		return false
 	}
-	return typeutil.IsGormDB(callee.Signature.Recv().Type())
+	return gormTypes.IsGormDB(callee.Signature.Recv().Type())
 }
@@ -25,6 +25,12 @@
 //   - cfg/      : Control flow graph analysis (loops, reachability)
 //   - handler/  : SSA instruction handlers (Call, Defer, Send, etc.)
 //
+// # Programmatic Use
+//
+// AnalyzeFunction and AnalyzePackage run the detection on SSA the caller has
+// built, without an analysis.Pass; Options carries the directive sets and DB
+// types the analyzer flags would otherwise configure.
+//
 // # Key Design Principles
 //
 //   - All gorm chain method calls are processed uniformly (no special "terminal" handling)
@@ -34,8 +40,11 @@
 package ssa
 
 import (
+	"cmp"
 	"go/ast"
 	"go/token"
+	"go/types"
+	"slices"
 
 	"golang.org/x/tools/go/ssa"
 
@@ -44,6 +53,7 @@ import (
 	"github.com/mpyw/gormreuse/internal/ssa/handler"
 	"github.com/mpyw/gormreuse/internal/ssa/pollution"
 	"github.com/mpyw/gormreuse/internal/ssa/tracer"
+	"github.com/mpyw/gormreuse/internal/typeutil"
 )
 
 // Violation represents a detected reuse violation.
@@ -65,10 +75,16 @@ type Violation = pollution.Violation
 //	    report(v.Pos, v.Message)
 //	}
 type Analyzer struct {
-	fn                  *ssa.Function          // Function being analyzed
-	rootTracer          *tracer.RootTracer     // Traces values to mutable roots
-	cfgAnalyzer         *cfg.Analyzer          // Control flow analysis
-	needsImmutableParam map[*ssa.Function]bool // immutable-param fns that branch a param (2b caller check)
+	fn                  *ssa.Function               // Function being analyzed
+	rootTracer          *tracer.RootTracer          // Traces values to mutable roots
+	cfgAnalyzer         *cfg.Analyzer               // Control flow analysis
+	needsImmutableParam map[*ssa.Function]bool      // immutable-param fns that branch a param (2b caller check)
+	tracker             *pollution.Tracker          // Tracker of the last Analyze run (for RootGraph)
+	strictInterface     bool                        // Report interface conversions of mutable roots (-strict-interface)
+	assumePureHelpers   bool                        // Trust unannotated helpers as pure (-assume-pure-helpers)
+	impureFuncs         *directive.DirectiveFuncSet // Functions marked //gormreuse:impure (exempt from assumePureHelpers)
+	unhandledSSA        *handler.UnhandledLog       // Logs unknown instructions involving *gorm.DB (-warn-unhandled-ssa)
+	deferredClosures    []*ssa.Function             // Deferred closures processed by the last Analyze run
 }
 
 // NewAnalyzer creates a new Analyzer for the given function.
@@ -80,13 +96,16 @@ type Analyzer struct {
 //   - failedPure: Pure functions that failed contract validation (not trusted as pure)
 //   - scopesCallbacks: Scopes/Preload callbacks whose *gorm.DB param is a mutable root
 //   - immutableParamFuncs: Functions marked //gormreuse:immutable-param (params opt out of Phase 1b)
+//   - finisherFuncs: Functions marked //gormreuse:finisher (terminal uses of their *gorm.DB receiver)
+//   - sinkFuncs: Functions marked //gormreuse:sink (never trusted as pure)
 //   - immutableCallbacks: Transaction callbacks whose tx param is forkable (immutable)
 //   - needsImmutableParam: immutable-param functions that actually branch a param, so a caller
 //     passing a mutable value to them violates the contract (Phase 1b stage 2b)
-func NewAnalyzer(fn *ssa.Function, pureFuncs, immutableReturnFuncs, immutableParamFuncs *directive.DirectiveFuncSet, failedPure, scopesCallbacks, immutableCallbacks, needsImmutableParam map[*ssa.Function]bool) *Analyzer {
+//   - gormTypes: Configured DB types recognized as *gorm.DB (nil for gorm.io/gorm.DB only)
+func NewAnalyzer(fn *ssa.Function, pureFuncs, immutableReturnFuncs, immutableParamFuncs, finisherFuncs, sinkFuncs *directive.DirectiveFuncSet, failedPure, scopesCallbacks, immutableCallbacks, needsImmutableParam map[*ssa.Function]bool, gormTypes *typeutil.Matcher) *Analyzer {
 	return &Analyzer{
 		fn:                  fn,
-		rootTracer:          tracer.New(pureFuncs, immutableReturnFuncs, immutableParamFuncs, failedPure, scopesCallbacks, immutableCallbacks),
+		rootTracer:          tracer.New(pureFuncs, immutableReturnFuncs, immutableParamFuncs, finisherFuncs, sinkFuncs, failedPure, scopesCallbacks, immutableCallbacks, gormTypes),
 		cfgAnalyzer:         cfg.New(),
 		needsImmutableParam: needsImmutableParam,
 	}
@@ -103,7 +122,8 @@ func NewAnalyzer(fn *ssa.Function, pureFuncs, immutableReturnFuncs, immutablePar
 //     earlier use can reach a later use via CFG analysis. If reachable,
 //     the later use is a violation.
 //
-//  3. COLLECTION: Return all detected violations for reporting.
+//  3. COLLECTION: Return all detected violations for reporting, each
+//     annotated with its estimated fix complexity.
 //
 // Closures that capture *gorm.DB are processed recursively to detect
 // violations across closure boundaries.
@@ -113,17 +133,166 @@ func (a *Analyzer) Analyze() []Violation {
 		fset = a.fn.Prog.Fset
 	}
 	tracker := pollution.New(a.cfgAnalyzer, fset)
+	a.tracker = tracker
 
 	// PHASE 1: TRACKING
 	// Process all instructions and record usages
-	a.processFunction(a.fn, tracker, make(map[*ssa.Function]bool), token.NoPos)
+	a.deferredClosures = nil
+	a.processFunction(a.fn, tracker, make(map[*ssa.Function]bool), token.NoPos, nil)
+
+	// Deferred closures run at exit, after every use of the functions
+	// enclosing them, which are all recorded now.
+	for _, fn := range a.deferredClosures {
+		a.checkDeferredClosure(fn, tracker)
+	}
 
 	// PHASE 2: DETECTION
 	// Detect violations using CFG reachability
 	tracker.DetectViolations()
 
 	// PHASE 3: COLLECTION
-	return tracker.CollectViolations()
+	violations := tracker.CollectViolations()
+	a.classifyFixComplexity(violations)
+	return violations
+}
+
+// RootGraph returns the roots and uses recorded by the last Analyze run, for
+// visualization. It returns nil if Analyze has not been called.
+func (a *Analyzer) RootGraph() []pollution.GraphRoot {
+	if a.tracker == nil {
+		return nil
+	}
+	return a.tracker.Graph()
+}
+
+// =============================================================================
+// Programmatic API
+// =============================================================================
+
+// Options configures AnalyzeFunction and AnalyzePackage. Its fields are the
+// NewAnalyzer parameters of the same names; the zero value analyzes without any
+// directive and recognizes gorm.io/gorm.DB only.
+type Options struct {
+	// PureFuncs, ImmutableReturnFuncs, ImmutableParamFuncs, FinisherFuncs and
+	// SinkFuncs are the functions marked with the corresponding //gormreuse:
+	// directives.
+	PureFuncs            *directive.DirectiveFuncSet
+	ImmutableReturnFuncs *directive.DirectiveFuncSet
+	ImmutableParamFuncs  *directive.DirectiveFuncSet
+	FinisherFuncs        *directive.DirectiveFuncSet
+	SinkFuncs            *directive.DirectiveFuncSet
+
+	// FailedPure lists //gormreuse:pure functions that leaked their argument,
+	// which are not trusted as pure.
+	FailedPure map[*ssa.Function]bool
+
+	// ScopesCallbacks and ImmutableCallbacks are the callbacks whose *gorm.DB
+	// parameter is a mutable root (Scopes/Preload) or immutable (Transaction),
+	// as collected by tracer.CollectScopesCallbacks and
+	// tracer.CollectImmutableCallbacks.
+	ScopesCallbacks    map[*ssa.Function]bool
+	ImmutableCallbacks map[*ssa.Function]bool
+
+	// NeedsImmutableParam lists the //gormreuse:immutable-param functions that
+	// branch a parameter, to which passing a mutable *gorm.DB is a violation.
+	NeedsImmutableParam map[*ssa.Function]bool
+
+	// GormTypes recognizes configured DB and builder types as *gorm.DB. nil
+	// matches gorm.io/gorm.DB only.
+	GormTypes *typeutil.Matcher
+
+	// StrictInterface reports each conversion of a mutable *gorm.DB to an
+	// interface as a KindEscape violation (-strict-interface).
+	StrictInterface bool
+
+	// AssumePureHelpers trusts named non-gorm functions as pure unless they
+	// are in ImpureFuncs or SinkFuncs (-assume-pure-helpers). ImpureFuncs are
+	// the functions marked //gormreuse:impure.
+	AssumePureHelpers bool
+	ImpureFuncs       *directive.DirectiveFuncSet
+
+	// UnhandledSSA, when non-nil, logs the instruction kinds involving a
+	// *gorm.DB that the analysis does not account for (-warn-unhandled-ssa).
+	UnhandledSSA *handler.UnhandledLog
+}
+
+// Analyzer returns the Analyzer that AnalyzeFunction runs on fn, for callers
+// that also need its RootGraph.
+func (o Options) Analyzer(fn *ssa.Function) *Analyzer {
+	a := NewAnalyzer(fn, o.PureFuncs, o.ImmutableReturnFuncs, o.ImmutableParamFuncs, o.FinisherFuncs, o.SinkFuncs, o.FailedPure, o.ScopesCallbacks, o.ImmutableCallbacks, o.NeedsImmutableParam, o.GormTypes)
+	a.strictInterface = o.StrictInterface
+	a.assumePureHelpers = o.AssumePureHelpers
+	a.impureFuncs = o.ImpureFuncs
+	a.unhandledSSA = o.UnhandledSSA
+	return a
+}
+
+// RootTracer returns a RootTracer configured like the one of the Analyzer
+// returned by Analyzer, for tracing values outside an analysis.
+func (o Options) RootTracer() *tracer.RootTracer {
+	return tracer.New(o.PureFuncs, o.ImmutableReturnFuncs, o.ImmutableParamFuncs, o.FinisherFuncs, o.SinkFuncs, o.FailedPure, o.ScopesCallbacks, o.ImmutableCallbacks, o.GormTypes)
+}
+
+// AnalyzeFunction detects the *gorm.DB reuse violations of fn and of the
+// closures it creates. It is the engine behind the gormreuse analyzer,
+// independent of analysis.Pass: the caller owns building SSA for fn (with
+// bodies, e.g. through buildssa or ssautil.BuildPackage) and reporting the
+// returned violations. A position may carry several violations, one per
+// polluted root or detection path, so callers report each position once.
+func AnalyzeFunction(fn *ssa.Function, opts Options) []Violation {
+	return opts.Analyzer(fn).Analyze()
+}
+
+// AnalyzePackage runs AnalyzeFunction on every source function of pkg, in
+// position order, and returns their violations. A violation found both in a
+// function and in a closure it creates is returned once. As for
+// AnalyzeFunction, the caller owns building pkg.
+func AnalyzePackage(pkg *ssa.Package, opts Options) []Violation {
+	var violations []Violation
+	seen := make(map[token.Pos]bool)
+	for _, fn := range sourceFunctions(pkg) {
+		for _, v := range AnalyzeFunction(fn, opts) {
+			if seen[v.Pos] {
+				continue
+			}
+			seen[v.Pos] = true
+			violations = append(violations, v)
+		}
+	}
+	return violations
+}
+
+// sourceFunctions returns the functions declared in pkg — package-level
+// functions, methods and, recursively, their anonymous functions — sorted by
+// position, like buildssa's SrcFuncs.
+func sourceFunctions(pkg *ssa.Package) []*ssa.Function {
+	var funcs []*ssa.Function
+	var add func(fn *ssa.Function)
+	add = func(fn *ssa.Function) {
+		if fn == nil || fn.Synthetic != "" || fn.Pkg != pkg {
+			return
+		}
+		funcs = append(funcs, fn)
+		for _, anon := range fn.AnonFuncs {
+			add(anon)
+		}
+	}
+	for _, mem := range pkg.Members {
+		switch mem := mem.(type) {
+		case *ssa.Function:
+			add(mem)
+		case *ssa.Type:
+			named, ok := mem.Type().(*types.Named)
+			if !ok {
+				continue
+			}
+			for i := 0; i < named.NumMethods(); i++ {
+				add(pkg.Prog.FuncValue(named.Method(i)))
+			}
+		}
+	}
+	slices.SortFunc(funcs, func(a, b *ssa.Function) int { return cmp.Compare(a.Pos(), b.Pos()) })
+	return funcs
 }
 
 // processFunction processes all instructions in a function and its closures.
@@ -142,7 +311,10 @@ func (a *Analyzer) Analyze() []Violation {
 // invoked; uses recorded while analyzing fn adopt it instead of their body
 // position, so define-early/call-late reuse orders by execution, not source,
 // position (#68).
-func (a *Analyzer) processFunction(fn *ssa.Function, tracker *pollution.Tracker, visited map[*ssa.Function]bool, posOverride token.Pos) {
+//
+// deferLoop, when non-nil, is the loop info of the parent in whose loop body
+// fn is deferred (see handler.Context.DeferLoop).
+func (a *Analyzer) processFunction(fn *ssa.Function, tracker *pollution.Tracker, visited map[*ssa.Function]bool, posOverride token.Pos, deferLoop *cfg.LoopInfo) {
 	if fn == nil || fn.Blocks == nil {
 		return
 	}
@@ -163,6 +335,11 @@ func (a *Analyzer) processFunction(fn *ssa.Function, tracker *pollution.Tracker,
 		CurrentFn:           fn,
 		PosOverride:         posOverride,
 		NeedsImmutableParam: a.needsImmutableParam,
+		DeferLoop:           deferLoop,
+		StrictInterface:     a.strictInterface,
+		AssumePureHelpers:   a.assumePureHelpers,
+		ImpureFuncs:         a.impureFuncs,
+		UnhandledSSA:        a.unhandledSSA,
 	}
 
 	// Collect defers and go statements for second pass
@@ -175,13 +352,18 @@ func (a *Analyzer) processFunction(fn *ssa.Function, tracker *pollution.Tracker,
 		for _, instr := range block.Instrs {
 			// Recursively process closures that capture *gorm.DB
 			if mc, ok := instr.(*ssa.MakeClosure); ok {
+				// A deferred closure returning *gorm.DB runs at function
+				// exit; its body is checked with the defers below.
+				if handler.DeferredReturnClosure(mc, a.rootTracer.GormTypes()) != nil {
+					continue
+				}
 				if closureFn, ok := mc.Fn.(*ssa.Function); ok {
 					// Recurse into closures that capture *gorm.DB, and into
 					// Scopes/Preload callbacks (which operate on their mutable
 					// parameter rather than a captured variable — see #60).
 					// Skip provably-dead closures: their uses never execute, so
 					// analyzing them yields false positives (#68).
-					if (tracer.ClosureCapturesGormDB(mc) || a.rootTracer.IsScopesCallbackFunc(closureFn)) && !isDeadClosure(mc) {
+					if (tracer.ClosureCapturesGormDB(mc, a.rootTracer.GormTypes()) || a.rootTracer.IsScopesCallbackFunc(closureFn)) && !isDeadClosure(mc) {
 						// If the closure is invoked at a single call site, order
 						// its captured uses by that call-site position (#68). When
 						// there is no single such site (IIFE, defer/go, multiple
@@ -190,7 +372,19 @@ func (a *Analyzer) processFunction(fn *ssa.Function, tracker *pollution.Tracker,
 						if p := closureInvocationPos(mc, a.fset()); p.IsValid() {
 							childOverride = p
 						}
-						a.processFunction(closureFn, tracker, visited, childOverride)
+						// A closure deferred in a loop runs once per iteration,
+						// and so does one called in a loop, stored or not.
+						var childDeferLoop *cfg.LoopInfo
+						if isDeferredIn(mc, loopInfo) || isCalledInLoop(mc, fn, loopInfo) {
+							childDeferLoop = loopInfo
+						}
+						// A closure only deferred runs at exit, after the
+						// uses of fn outside it.
+						if isOnlyDeferred(mc) {
+							tracker.RecordDeferredClosure(closureFn)
+							a.deferredClosures = append(a.deferredClosures, closureFn)
+						}
+						a.processFunction(closureFn, tracker, visited, childOverride, childDeferLoop)
 					}
 				}
 				continue
@@ -206,6 +400,11 @@ func (a *Analyzer) processFunction(fn *ssa.Function, tracker *pollution.Tracker,
 			// (SSA block order may differ from source order, so we need
 			// all pollution recorded before checking go statements)
 			if g, ok := instr.(*ssa.Go); ok {
+				// A spawned literal's parameters are bound to the go
+				// arguments, so its body branches them like captures.
+				if lit := a.spawnedDBLiteral(g); lit != nil {
+					a.processFunction(lit, tracker, visited, posOverride, nil)
+				}
 				goStmts = append(goStmts, g)
 				continue
 			}
@@ -224,10 +423,31 @@ func (a *Analyzer) processFunction(fn *ssa.Function, tracker *pollution.Tracker,
 	// Third pass: process defer statements
 	// Defers use IsPollutedAnywhere since they execute at function exit
 	for _, d := range defers {
+		if mc, ok := d.Call.Value.(*ssa.MakeClosure); ok {
+			if closureFn := handler.DeferredReturnClosure(mc, a.rootTracer.GormTypes()); closureFn != nil {
+				(&handler.DeferHandler{}).HandleClosure(d, closureFn, ctx)
+				continue
+			}
+		}
 		handler.DispatchDefer(d, ctx)
 	}
 }
 
+// spawnedDBLiteral returns the function literal spawned by g when g passes it
+// a *gorm.DB argument (see tracer.SpawnedLiteral), or nil.
+func (a *Analyzer) spawnedDBLiteral(g *ssa.Go) *ssa.Function {
+	lit := tracer.SpawnedLiteral(g)
+	if lit == nil {
+		return nil
+	}
+	for _, arg := range g.Call.Args {
+		if a.rootTracer.IsGormDB(arg.Type()) {
+			return lit
+		}
+	}
+	return nil
+}
+
 // isDeadClosure reports whether mc's closure value is provably never used: it
 // has no referrers at all, so nothing can invoke it (e.g. `h := func(){...}; _ =
 // h`). Such a closure cannot run, so recording pollution from its body would
@@ -240,6 +460,76 @@ func isDeadClosure(mc *ssa.MakeClosure) bool {
 	return refs == nil || len(*refs) == 0
 }
 
+// isDeferredIn reports whether the closure value mc is deferred inside a loop
+// of loopInfo's function that may iterate more than once, so its body runs
+// once per iteration at exit.
+func isDeferredIn(mc *ssa.MakeClosure, loopInfo *cfg.LoopInfo) bool {
+	refs := mc.Referrers()
+	if refs == nil {
+		return false
+	}
+	for _, r := range *refs {
+		if d, ok := r.(*ssa.Defer); ok && d.Call.Value == ssa.Value(mc) && loopInfo.MayIterateMultiple(d.Block()) {
+			return true
+		}
+	}
+	return false
+}
+
+// isCalledInLoop reports whether the closure value mc is called inside a loop
+// of fn that may iterate more than once, directly or after being stored in a
+// variable, slice, array or map (see tracer.CalleeClosures), so its body runs
+// once per iteration.
+func isCalledInLoop(mc *ssa.MakeClosure, fn *ssa.Function, loopInfo *cfg.LoopInfo) bool {
+	for _, block := range fn.Blocks {
+		if !loopInfo.MayIterateMultiple(block) {
+			continue
+		}
+		for _, instr := range block.Instrs {
+			call, ok := instr.(*ssa.Call)
+			if !ok || call.Call.IsInvoke() {
+				continue
+			}
+			if slices.Contains(tracer.CalleeClosures(call.Call.Value), mc) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// isOnlyDeferred reports whether mc's closure is only ever invoked by defer
+// statements, as in defer func() { ... }(), so that its body runs at exit.
+func isOnlyDeferred(mc *ssa.MakeClosure) bool {
+	refs := mc.Referrers()
+	if refs == nil || len(*refs) == 0 {
+		return false
+	}
+	for _, r := range *refs {
+		if d, ok := r.(*ssa.Defer); !ok || d.Call.Value != ssa.Value(mc) {
+			return false
+		}
+	}
+	return true
+}
+
+// checkDeferredClosure reports the uses in fn, a closure only ever deferred,
+// of roots used outside it (see handler.DeferHandler.CheckClosure).
+func (a *Analyzer) checkDeferredClosure(fn *ssa.Function, tracker *pollution.Tracker) {
+	ctx := &handler.Context{
+		Tracker:             tracker,
+		RootTracer:          a.rootTracer,
+		CFG:                 a.cfgAnalyzer,
+		LoopInfo:            a.cfgAnalyzer.DetectLoops(fn),
+		CurrentFn:           fn,
+		NeedsImmutableParam: a.needsImmutableParam,
+		StrictInterface:     a.strictInterface,
+		AssumePureHelpers:   a.assumePureHelpers,
+		ImpureFuncs:         a.impureFuncs,
+	}
+	(&handler.DeferHandler{}).CheckClosure(fn, ctx)
+}
+
 // closureInvocationPos returns the source position at which the closure value
 // mc is invoked, but ONLY for the define-early/call-late case that #68 targets:
 // a closure invoked by exactly one plain call whose call site is on a LATER line
@@ -255,6 +545,11 @@ func closureInvocationPos(mc *ssa.MakeClosure, fset *token.FileSet) token.Pos {
 	if refs == nil {
 		return token.NoPos
 	}
+	// A closure passed to reflect (reflect.ValueOf(f).Call(nil)) is taken to be
+	// invoked at that call, where its captured roots are marked polluted.
+	if call := handler.ReflectInvocation(mc); call != nil {
+		return call.Pos()
+	}
 	found := token.NoPos
 	for _, r := range *refs {
 		call, ok := r.(*ssa.Call)
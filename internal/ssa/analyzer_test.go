@@ -0,0 +1,197 @@
+package ssa_test
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"slices"
+	"testing"
+
+	"golang.org/x/tools/go/ssa"
+	"golang.org/x/tools/go/ssa/ssautil"
+
+	"github.com/mpyw/gormreuse/internal/directive"
+	ssaanalysis "github.com/mpyw/gormreuse/internal/ssa"
+)
+
+// gormSrc is a minimal gorm.io/gorm: a chain method, a finisher and Session.
+const gormSrc = `package gorm
+
+type DB struct{}
+
+type Session struct{}
+
+func (db *DB) Where(query interface{}) *DB    { return db }
+func (db *DB) Find(dest interface{}) *DB      { return db }
+func (db *DB) Session(config *Session) *DB    { return db }
+`
+
+const userSrc = `package p
+
+import "gorm.io/gorm"
+
+func reuse(db *gorm.DB) {
+	q := db.Where("x")
+	q.Find(nil)
+	q.Find(nil)
+}
+
+func closure(db *gorm.DB) {
+	q := db.Where("x")
+	q.Find(nil)
+	func() { q.Find(nil) }()
+}
+
+func immutable(db *gorm.DB) {
+	q := db.Where("x").Session(&gorm.Session{})
+	q.Find(nil)
+	q.Find(nil)
+}
+
+type Repo struct{ db *gorm.DB }
+
+func (r Repo) reuse() {
+	q := r.db.Where("x")
+	q.Find(nil)
+	q.Find(nil)
+}
+
+//gormreuse:pure
+func helper(db *gorm.DB) {}
+
+func pure(db *gorm.DB) {
+	q := db.Where("x")
+	helper(q)
+	q.Find(nil)
+}
+
+func sameLine(db *gorm.DB) {
+	q := db.Where("x")
+	q.Find(nil)
+	func() { q.Find(nil) }(); q.Find(nil)
+}
+`
+
+// importerFunc resolves imports with a function.
+type importerFunc func(path string) (*types.Package, error)
+
+func (f importerFunc) Import(path string) (*types.Package, error) { return f(path) }
+
+// buildPackage type-checks the gorm stub, then builds SSA for userSrc against
+// it, returning the user package and its parsed file.
+func buildPackage(t *testing.T) (*ssa.Package, *ast.File, *types.Info) {
+	t.Helper()
+	fset := token.NewFileSet()
+
+	gormFile, err := parser.ParseFile(fset, "gorm.go", gormSrc, 0)
+	if err != nil {
+		t.Fatalf("parse gorm: %v", err)
+	}
+	gormPkg, err := new(types.Config).Check("gorm.io/gorm", fset, []*ast.File{gormFile}, nil)
+	if err != nil {
+		t.Fatalf("check gorm: %v", err)
+	}
+
+	file, err := parser.ParseFile(fset, "p.go", userSrc, parser.ParseComments)
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	conf := &types.Config{Importer: importerFunc(func(path string) (*types.Package, error) {
+		return gormPkg, nil
+	})}
+	pkg, info, err := ssautil.BuildPackage(conf, fset, types.NewPackage("p", "p"), []*ast.File{file}, ssa.SanityCheckFunctions)
+	if err != nil {
+		t.Fatalf("build: %v", err)
+	}
+	return pkg, file, info
+}
+
+// TestAnalyzeFunction verifies that AnalyzeFunction reports a reuse with its
+// position and root, independent of analysis.Pass.
+func TestAnalyzeFunction(t *testing.T) {
+	t.Parallel()
+
+	pkg, _, _ := buildPackage(t)
+	fset := pkg.Prog.Fset
+
+	violations := ssaanalysis.AnalyzeFunction(pkg.Func("reuse"), ssaanalysis.Options{})
+	if len(violations) == 0 {
+		t.Fatal("got no violations, want the reuse at line 8")
+	}
+	for _, v := range violations {
+		if got := fset.Position(v.Pos).Line; got != 8 {
+			t.Errorf("violation line = %d, want 8", got)
+		}
+		if got := fset.Position(v.RootPos).Line; got != 6 {
+			t.Errorf("root line = %d, want 6", got)
+		}
+	}
+
+	if violations := ssaanalysis.AnalyzeFunction(pkg.Func("immutable"), ssaanalysis.Options{}); len(violations) != 0 {
+		t.Errorf("immutable: got %d violations, want 0", len(violations))
+	}
+}
+
+// TestAnalyzePackage verifies that AnalyzePackage covers functions, methods
+// and closures in position order, reports a closure violation once, and
+// honors the directive sets passed in Options.
+func TestAnalyzePackage(t *testing.T) {
+	t.Parallel()
+
+	pkg, file, info := buildPackage(t)
+	fset := pkg.Prog.Fset
+
+	lines := func(violations []ssaanalysis.Violation) []int {
+		var lines []int
+		for _, v := range violations {
+			lines = append(lines, fset.Position(v.Pos).Line)
+		}
+		return lines
+	}
+
+	// Without the pure set, passing q to helper pollutes it.
+	got := lines(ssaanalysis.AnalyzePackage(pkg, ssaanalysis.Options{}))
+	want := []int{8, 14, 28, 37, 43, 43}
+	if !slices.Equal(got, want) {
+		t.Errorf("lines = %v, want %v", got, want)
+	}
+
+	pureFuncs := directive.NewPureFuncSet(fset, info, nil)
+	pureFuncs.AddFile(file)
+	for key := range directive.BuildPureFunctionSet(file, "p") {
+		pureFuncs.Add(key)
+	}
+	got = lines(ssaanalysis.AnalyzePackage(pkg, ssaanalysis.Options{PureFuncs: pureFuncs}))
+	want = []int{8, 14, 28, 43, 43}
+	if !slices.Equal(got, want) {
+		t.Errorf("with pure: lines = %v, want %v", got, want)
+	}
+}
+
+// TestAnalyzeFunctionDedup verifies that a use reached both directly and
+// through a capturing closure, or from several earlier uses, is reported once
+// per position and root.
+func TestAnalyzeFunctionDedup(t *testing.T) {
+	t.Parallel()
+
+	pkg, _, _ := buildPackage(t)
+	fset := pkg.Prog.Fset
+
+	type key struct {
+		pos  token.Pos
+		root ssa.Value
+	}
+	seen := make(map[key]bool)
+	violations := ssaanalysis.AnalyzeFunction(pkg.Func("sameLine"), ssaanalysis.Options{})
+	for _, v := range violations {
+		k := key{v.Pos, v.Root}
+		if seen[k] {
+			t.Errorf("duplicate violation at %v", fset.Position(v.Pos))
+		}
+		seen[k] = true
+	}
+	if len(seen) != 2 {
+		t.Errorf("got %d violations, want 2 (the closure use and the direct use)", len(seen))
+	}
+}
@@ -36,8 +36,8 @@
 //
 // # Loop Detection
 //
-// A loop exists when there's a back-edge in the CFG (edge to a block with
-// lower index that can reach the source):
+// A loop exists when there's a back-edge in the CFG (edge to a block that
+// dominates its source, the loop header):
 //
 //	┌─────────────────────────────────────────────────────────────────────────┐
 //	│                    Example: for loop CFG                                │
@@ -61,6 +61,11 @@
 package cfg
 
 import (
+	"go/constant"
+	"go/token"
+	"go/types"
+	"slices"
+
 	"golang.org/x/tools/go/ssa"
 )
 
@@ -82,6 +87,16 @@ func New() *Analyzer {
 type LoopInfo struct {
 	loopBlocks  map[*ssa.BasicBlock]bool // Blocks that are part of any loop
 	loopHeaders map[*ssa.BasicBlock]bool // Blocks that are loop headers
+	multiBlocks map[*ssa.BasicBlock]bool // Blocks of loops that may iterate more than once
+	loops       []*loop                  // Natural loops, one per header, in block order
+	rangeFunc   *ssa.Function            // fn when it is a range-over-func body
+}
+
+// loop is the natural loop of a header: the union of the loops its back-edges
+// close, so that nested loops, labeled or not, are told apart.
+type loop struct {
+	blocks map[*ssa.BasicBlock]bool // header and body
+	multi  bool                     // may iterate more than once (see MayIterateMultiple)
 }
 
 // IsInLoop returns true if the block is inside a loop.
@@ -89,6 +104,23 @@ func (l *LoopInfo) IsInLoop(block *ssa.BasicBlock) bool {
 	return l.loopBlocks[block]
 }
 
+// MayIterateMultiple reports whether block may run in more than one iteration
+// of a loop. It is conservatively true for every for and range loop, except
+// one whose trip count is provably at most one (see runsAtMostOnce):
+//
+//	for i := 0; i < 1; i++ {
+//	    defer q.Count(nil) // registered once: not a reuse on its own
+//	}
+//	for _, id := range []int{1} {
+//	    q.Where("id = ?", id).Find(nil) // runs once: not a reuse on its own
+//	}
+//
+// A loop ending in an unconditional break (`for { ...; break }`) has no
+// back-edge at all, so its body is not even IsInLoop.
+func (l *LoopInfo) MayIterateMultiple(block *ssa.BasicBlock) bool {
+	return l.multiBlocks[block]
+}
+
 // IsLoopHeader returns true if the block is a loop header.
 // Loop headers are entry points to loops where Phi nodes merge
 // values from outside the loop and from loop back-edges.
@@ -105,6 +137,24 @@ func (l *LoopInfo) IsLoopHeader(block *ssa.BasicBlock) bool {
 //
 // Time complexity: O(V + E) where V = blocks, E = edges
 func (a *Analyzer) CanReach(src, dst *ssa.BasicBlock) bool {
+	return a.CanReachWithout(src, dst, nil)
+}
+
+// CanReachWithout is CanReach along the paths that do not enter the block
+// without. A path through the block defining a mutable root runs the
+// definition again, so the uses after it see a fresh value:
+//
+//	for i := 0; i < n; i++ {
+//	    q := db.Where("x")  // without: the loop body
+//	    if c {
+//	        q.Find(nil)
+//	        continue        // reaches q.Count only through a new q
+//	    }
+//	    q.Count(nil)
+//	}
+//
+// A nil without is CanReach.
+func (a *Analyzer) CanReachWithout(src, dst, without *ssa.BasicBlock) bool {
 	if src == nil || dst == nil {
 		return false
 	}
@@ -122,6 +172,9 @@ func (a *Analyzer) CanReach(src, dst *ssa.BasicBlock) bool {
 		queue = queue[1:]
 
 		for _, succ := range block.Succs {
+			if succ == without {
+				continue
+			}
 			if succ == dst {
 				return true
 			}
@@ -137,47 +190,381 @@ func (a *Analyzer) CanReach(src, dst *ssa.BasicBlock) bool {
 // DetectLoops analyzes the function and returns loop information.
 //
 // Algorithm:
-//  1. Assign index to each block based on order in fn.Blocks
-//  2. Find back-edges: edges where succ.index <= src.index
-//  3. For each back-edge, verify it's a real loop (succ can reach src)
-//  4. Mark all blocks between loop header and tail as loop blocks
-//  5. Mark loop headers for special handling of Phi nodes
+//  1. Find back-edges: edges whose target dominates their source
+//  2. For each back-edge, mark the natural loop between header and tail
+//  3. Mark loop headers for special handling of Phi nodes
+//
+// This handles for, for-range, and while-style loops. Every range source kind
+// (string, []byte, slice, array, pointer to array, map, channel, integer)
+// lowers to a header block testing the next element and a body jumping back
+// to it, so they all share this one back-edge shape.
 //
-// This handles for, for-range, and while-style loops.
+// Back-edges are found by dominance rather than block order: in
+// `for cond { body }` the SSA builder places the condition block after the
+// body, so the body → condition edge points to a higher index even though it
+// closes the loop. Treating the condition → body edge as the back-edge instead
+// made the body the header, and the natural-loop walk from the condition
+// escaped into the entry block, which then counted as in-loop.
 func (a *Analyzer) DetectLoops(fn *ssa.Function) *LoopInfo {
 	loopBlocks := make(map[*ssa.BasicBlock]bool)
 	loopHeaders := make(map[*ssa.BasicBlock]bool)
+	multiBlocks := make(map[*ssa.BasicBlock]bool)
 	if fn.Blocks == nil {
 		return &LoopInfo{
 			loopBlocks:  loopBlocks,
 			loopHeaders: loopHeaders,
+			multiBlocks: multiBlocks,
 		}
 	}
 
-	// Build block index map (for back-edge detection)
-	blockIndex := make(map[*ssa.BasicBlock]int)
-	for i, b := range fn.Blocks {
-		blockIndex[b] = i
+	// A back-edge goes to a block that dominates its source: the loop header.
+	var loops []*loop
+	byHeader := make(map[*ssa.BasicBlock]*loop)
+	for _, block := range fn.Blocks {
+		for _, succ := range block.Succs {
+			if succ.Dominates(block) {
+				a.markLoopBlocks(fn, succ, block, loopBlocks)
+				loopHeaders[succ] = true // succ is the loop header
+				l := byHeader[succ]
+				if l == nil {
+					l = &loop{blocks: make(map[*ssa.BasicBlock]bool)}
+					byHeader[succ] = l
+					loops = append(loops, l)
+				}
+				a.markLoopBlocks(fn, succ, block, l.blocks)
+			}
+		}
 	}
 
-	// Detect back-edges that create cycles
-	// A back-edge goes from a higher-indexed block to a lower-indexed block
+	// Mark the loops again, skipping the provably single-iteration ones. A
+	// block nested in such a loop still iterates if an enclosing loop does.
 	for _, block := range fn.Blocks {
 		for _, succ := range block.Succs {
-			if blockIndex[succ] <= blockIndex[block] {
-				// Potential back-edge: verify it creates a cycle
-				if a.CanReach(succ, block) {
-					a.markLoopBlocks(fn, succ, block, loopBlocks)
-					loopHeaders[succ] = true // succ is the loop header
-				}
+			if succ.Dominates(block) && !runsAtMostOnce(succ, block, loopBlocks) {
+				a.markLoopBlocks(fn, succ, block, multiBlocks)
+				byHeader[succ].multi = true
 			}
 		}
 	}
 
+	markIrreducibleCycles(fn, loopBlocks, multiBlocks)
+
+	var rangeFunc *ssa.Function
+	if IsRangeFuncBody(fn) {
+		rangeFunc = fn
+		markRangeFuncBlocks(fn, loopBlocks)
+		markRangeFuncBlocks(fn, multiBlocks)
+	}
+
 	return &LoopInfo{
 		loopBlocks:  loopBlocks,
 		loopHeaders: loopHeaders,
+		multiBlocks: multiBlocks,
+		loops:       loops,
+		rangeFunc:   rangeFunc,
+	}
+}
+
+// markIrreducibleCycles marks the blocks of cycles that no back-edge closes.
+// goto can enter a cycle at two places, so that neither dominates the other
+// and there is no header:
+//
+//	    if cond {
+//	        goto b
+//	    }
+//	a:  n++          // entered from above, or from the goto below
+//	b:  q.Find(nil)  // entered from a, or from the goto above
+//	    if n < 3 {
+//	        goto a
+//	    }
+//
+// Every block of a cyclic strongly connected component that is not already in
+// a natural loop is such a block; it may run more than once.
+func markIrreducibleCycles(fn *ssa.Function, loopBlocks, multiBlocks map[*ssa.BasicBlock]bool) {
+	for _, scc := range stronglyConnected(fn) {
+		if len(scc) == 1 && !slices.Contains(scc[0].Succs, scc[0]) {
+			continue
+		}
+		for _, b := range scc {
+			if !loopBlocks[b] {
+				loopBlocks[b] = true
+				multiBlocks[b] = true
+			}
+		}
+	}
+}
+
+// stronglyConnected returns the strongly connected components of the CFG of
+// fn (Tarjan's algorithm).
+func stronglyConnected(fn *ssa.Function) [][]*ssa.BasicBlock {
+	index := make(map[*ssa.BasicBlock]int, len(fn.Blocks))
+	lowlink := make(map[*ssa.BasicBlock]int, len(fn.Blocks))
+	onStack := make(map[*ssa.BasicBlock]bool, len(fn.Blocks))
+	var stack []*ssa.BasicBlock
+	var sccs [][]*ssa.BasicBlock
+
+	var visit func(b *ssa.BasicBlock)
+	visit = func(b *ssa.BasicBlock) {
+		index[b] = len(index)
+		lowlink[b] = index[b]
+		stack = append(stack, b)
+		onStack[b] = true
+		for _, succ := range b.Succs {
+			if _, seen := index[succ]; !seen {
+				visit(succ)
+				lowlink[b] = min(lowlink[b], lowlink[succ])
+			} else if onStack[succ] {
+				lowlink[b] = min(lowlink[b], index[succ])
+			}
+		}
+		if lowlink[b] != index[b] {
+			return
+		}
+		var scc []*ssa.BasicBlock
+		for {
+			top := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			onStack[top] = false
+			scc = append(scc, top)
+			if top == b {
+				break
+			}
+		}
+		sccs = append(sccs, scc)
+	}
+	for _, b := range fn.Blocks {
+		if _, seen := index[b]; !seen {
+			visit(b)
+		}
+	}
+	return sccs
+}
+
+// runsAtMostOnce reports whether the loop closed by the back-edge tail →
+// header provably enters its body at most once. It recognizes a counter with
+// constant bounds, tested either in the header or, for a range over an
+// integer, at the end of the body:
+//
+//	for i := 0; i < 1; i++ { ... } // header: i = phi(0, i+1); if i < 1
+//	for range 1 { ... }            // tail: i' = i+1; if i' < 1 goto header
+//
+// A range over an array, or over a slice of one such as a slice literal,
+// tests the next index in the header against the constant length:
+//
+//	for _, id := range []int{1} { ... } // header: i' = phi(-1, i')+1; if i' < len(s)
+//
+// by evaluating the condition for the first two counter values. Any other
+// shape may iterate more than once.
+func runsAtMostOnce(header, tail *ssa.BasicBlock, loopBlocks map[*ssa.BasicBlock]bool) bool {
+	// Header test: the true edge enters the body, the false edge leaves. A
+	// header jumping to itself is the body, testing after each iteration.
+	if cond, ok := lastIf(header); ok && header.Succs[0] != header && loopBlocks[header.Succs[0]] && !loopBlocks[header.Succs[1]] {
+		if cmp, ok := cond.(*ssa.BinOp); ok {
+			if first, second, ok := testedValues(header, cmp.X); ok {
+				return !compareConst(first, cmp.Op, cmp.Y) || !compareConst(second, cmp.Op, cmp.Y)
+			}
+		}
+	}
+	// Tail test: the true edge starts the next iteration, the false edge leaves.
+	if cond, ok := lastIf(tail); ok && tail.Succs[0] == header && !loopBlocks[tail.Succs[1]] {
+		if cmp, ok := cond.(*ssa.BinOp); ok {
+			if next, ok := cmp.X.(*ssa.BinOp); ok {
+				if _, second, ok := counterValues(header, next.X); ok && isStep(next, next.X) {
+					return !compareConst(second, cmp.Op, cmp.Y)
+				}
+			}
+		}
+	}
+	return false
+}
+
+// testedValues returns the first two values of v, tested by the header of a
+// loop: a counter Phi of the header (see counterValues), or the counter plus
+// a constant, as a range over an array or a slice tests the next index.
+func testedValues(header *ssa.BasicBlock, v ssa.Value) (first, second constant.Value, ok bool) {
+	next, ok := v.(*ssa.BinOp)
+	if !ok {
+		return counterValues(header, v)
+	}
+	if !isStep(next, next.X) || next.Block() != header {
+		return nil, nil, false
+	}
+	first, second, ok = counterValues(header, next.X)
+	if !ok {
+		return nil, nil, false
+	}
+	step := next.Y.(*ssa.Const).Value
+	first, second = constant.BinaryOp(first, token.ADD, step), constant.BinaryOp(second, token.ADD, step)
+	if !fitsInt(first, v.Type()) || !fitsInt(second, v.Type()) {
+		return nil, nil, false
+	}
+	return first, second, true
+}
+
+// lastIf returns the condition of the If ending block.
+func lastIf(block *ssa.BasicBlock) (ssa.Value, bool) {
+	if len(block.Instrs) == 0 || len(block.Succs) != 2 {
+		return nil, false
+	}
+	cond, ok := block.Instrs[len(block.Instrs)-1].(*ssa.If)
+	if !ok {
+		return nil, false
+	}
+	return cond.Cond, true
+}
+
+// counterValues returns the first two values of v, a counter Phi of header
+// starting at a constant and stepped by a constant on the back-edge.
+func counterValues(header *ssa.BasicBlock, v ssa.Value) (first, second constant.Value, ok bool) {
+	phi, ok := v.(*ssa.Phi)
+	if !ok || phi.Block() != header || len(phi.Edges) != 2 {
+		return nil, nil, false
+	}
+	var step constant.Value
+	for i, edge := range phi.Edges {
+		if header.Dominates(header.Preds[i]) {
+			next, ok := edge.(*ssa.BinOp)
+			if !ok || !isStep(next, phi) {
+				return nil, nil, false
+			}
+			step = next.Y.(*ssa.Const).Value
+		} else if c, ok := edge.(*ssa.Const); ok && c.Value != nil && c.Value.Kind() == constant.Int {
+			first = c.Value
+		}
+	}
+	if first == nil || step == nil {
+		return nil, nil, false
+	}
+	second = constant.BinaryOp(first, token.ADD, step)
+	if !fitsInt(second, phi.Type()) {
+		return nil, nil, false // the counter wraps around
+	}
+	return first, second, true
+}
+
+// isStep reports whether next is counter plus an integer constant.
+func isStep(next *ssa.BinOp, counter ssa.Value) bool {
+	c, ok := next.Y.(*ssa.Const)
+	return next.Op == token.ADD && next.X == counter && ok && c.Value != nil && c.Value.Kind() == constant.Int
+}
+
+// compareConst evaluates `x op y` for a constant integer y, reporting true
+// when it cannot be decided so that the loop is taken to continue.
+func compareConst(x constant.Value, op token.Token, y ssa.Value) bool {
+	bound, ok := constInt(y)
+	if !ok {
+		return true
+	}
+	switch op {
+	case token.LSS, token.LEQ, token.GTR, token.GEQ, token.NEQ:
+		return constant.Compare(x, op, bound)
 	}
+	return true
+}
+
+// constInt returns the value of v when it is an integer constant or the
+// length of a slice of a whole array, such as a slice literal:
+//
+//	t2 = new [2]int (slicelit)
+//	t4 = slice t2[:]
+//	t5 = len(t4)  // 2
+func constInt(v ssa.Value) (constant.Value, bool) {
+	switch v := v.(type) {
+	case *ssa.Const:
+		if v.Value != nil && v.Value.Kind() == constant.Int {
+			return v.Value, true
+		}
+	case *ssa.Call:
+		b, ok := v.Call.Value.(*ssa.Builtin)
+		if !ok || b.Name() != "len" || len(v.Call.Args) != 1 {
+			return nil, false
+		}
+		sl, ok := v.Call.Args[0].(*ssa.Slice)
+		if !ok || sl.Low != nil || sl.High != nil {
+			return nil, false
+		}
+		ptr, ok := sl.X.Type().Underlying().(*types.Pointer)
+		if !ok {
+			return nil, false
+		}
+		if arr, ok := ptr.Elem().Underlying().(*types.Array); ok {
+			return constant.MakeInt64(arr.Len()), true
+		}
+	}
+	return nil, false
+}
+
+// fitsInt reports whether v is representable in the integer type t.
+func fitsInt(v constant.Value, t types.Type) bool {
+	basic, ok := t.Underlying().(*types.Basic)
+	if !ok {
+		return false
+	}
+	bits := map[types.BasicKind]uint{
+		types.Int8: 8, types.Int16: 16, types.Int32: 32, types.Int: 64, types.Int64: 64,
+		types.Uint8: 8, types.Uint16: 16, types.Uint32: 32, types.Uint: 64, types.Uint64: 64, types.Uintptr: 64,
+	}[basic.Kind()]
+	if bits == 0 {
+		return false
+	}
+	lo, hi := constant.MakeInt64(0), constant.Shift(constant.MakeInt64(1), token.SHL, bits)
+	if basic.Info()&types.IsUnsigned == 0 {
+		hi = constant.Shift(constant.MakeInt64(1), token.SHL, bits-1)
+		lo = constant.UnaryOp(token.SUB, hi, 0)
+	}
+	return constant.Compare(v, token.GEQ, lo) && constant.Compare(v, token.LSS, hi)
+}
+
+// IsRangeFuncBody reports whether fn is the yield function the SSA builder
+// synthesizes for the body of a range-over-func loop (Go 1.23 iterators):
+//
+//	for q := range queries { // queries: func(yield func(*gorm.DB) bool)
+//	    q.Find(nil)          // body lowered to func(q *gorm.DB) bool { ... }
+//	}
+//
+// The iterator calls it once per element, so its body is a loop body even
+// though it has no back-edge of its own.
+func IsRangeFuncBody(fn *ssa.Function) bool {
+	return fn != nil && fn.Synthetic == "range-over-func yield"
+}
+
+// markRangeFuncBlocks marks the blocks of the range-over-func body fn that
+// lead to the next iteration. Falling off the end of the body or continuing
+// returns true to the iterator, which calls fn again; break, return and goto
+// out of the loop return false instead, so a block reaching only those exits
+// runs at most once, like a loop body ending in break.
+func markRangeFuncBlocks(fn *ssa.Function, loopBlocks map[*ssa.BasicBlock]bool) {
+	var stack []*ssa.BasicBlock
+	for _, block := range fn.Blocks {
+		if returnsTrue(block) && !loopBlocks[block] {
+			loopBlocks[block] = true
+			stack = append(stack, block)
+		}
+	}
+	for len(stack) > 0 {
+		b := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		for _, p := range b.Preds {
+			if !loopBlocks[p] {
+				loopBlocks[p] = true
+				stack = append(stack, p)
+			}
+		}
+	}
+}
+
+// returnsTrue reports whether block ends in `return true`, the continue exit
+// of a range-over-func body.
+func returnsTrue(block *ssa.BasicBlock) bool {
+	if len(block.Instrs) == 0 {
+		return false
+	}
+	ret, ok := block.Instrs[len(block.Instrs)-1].(*ssa.Return)
+	if !ok || len(ret.Results) != 1 {
+		return false
+	}
+	c, ok := ret.Results[0].(*ssa.Const)
+	return ok && c.Value != nil && c.Value.Kind() == constant.Bool && constant.BoolVal(c.Value)
 }
 
 // markLoopBlocks marks blocks that are part of the loop whose header is loopHead
@@ -219,7 +606,8 @@ func (a *Analyzer) markLoopBlocks(_ *ssa.Function, loopHead, loopTail *ssa.Basic
 	}
 }
 
-// IsDefinedOutsideLoop checks if a value is defined outside the loop.
+// IsDefinedOutsideLoop checks if a value is defined outside the loop of its
+// use in block use.
 //
 // Used to detect the pattern:
 //
@@ -230,7 +618,27 @@ func (a *Analyzer) markLoopBlocks(_ *ssa.Function, loopHead, loopTail *ssa.Basic
 //
 // When a mutable root is defined outside a loop but used inside, each
 // iteration reuses the same root, which is always a violation.
-func (a *Analyzer) IsDefinedOutsideLoop(v ssa.Value, loopInfo *LoopInfo) bool {
+//
+// A value defined in a loop is still outside a loop nested in it, labeled or
+// not, when the use is in the nested loop:
+//
+//	for _, g := range groups {
+//	    q := db.Where("x")        // Defined in the OUTER loop
+//	    for _, id := range g {
+//	        q.Where(id).Find(nil) // Used in the INNER loop - VIOLATION
+//	    }
+//	}
+//
+// Such a value counts as outside when some loop containing use but not the
+// definition may iterate more than once. A nil use only tells values defined
+// outside every loop.
+func (a *Analyzer) IsDefinedOutsideLoop(v ssa.Value, use *ssa.BasicBlock, loopInfo *LoopInfo) bool {
+	// The range variables of a range-over-func body are its parameters, bound
+	// afresh by every call of the body.
+	if p, ok := v.(*ssa.Parameter); ok && loopInfo.rangeFunc != nil && p.Parent() == loopInfo.rangeFunc {
+		return false
+	}
+
 	instr, ok := v.(ssa.Instruction)
 	if !ok {
 		// Non-instructions (parameters, constants) are "outside"
@@ -238,9 +646,90 @@ func (a *Analyzer) IsDefinedOutsideLoop(v ssa.Value, loopInfo *LoopInfo) bool {
 	}
 
 	block := instr.Block()
-	if block == nil {
+	if block == nil || !loopInfo.IsInLoop(block) {
 		return true
 	}
+	if use == nil {
+		return false
+	}
+	for _, l := range loopInfo.loops {
+		if l.multi && l.blocks[use] && !l.blocks[block] {
+			return true
+		}
+	}
+	return false
+}
+
+// IsCarriedAcrossIterations reports whether v, used in block, is a loop-header
+// Phi that the loop carries back to itself unchanged along a path through
+// block. The next iteration then uses the same value again:
+//
+//	q := db.Where("x")
+//	for _, id := range ids {   // q = phi(q, q')
+//	    if id > 0 {
+//	        q = q.Where(id)    // reassigned on this path...
+//	    } else {
+//	        q.Find(nil)        // ...but not on this one: VIOLATION
+//	    }
+//	}
+//
+// The path matters: when every path from block back to the header reassigns
+// v, each iteration uses a fresh value.
+func (a *Analyzer) IsCarriedAcrossIterations(v ssa.Value, block *ssa.BasicBlock, loopInfo *LoopInfo) bool {
+	phi, ok := v.(*ssa.Phi)
+	if !ok || block == nil || !loopInfo.IsLoopHeader(phi.Block()) {
+		return false
+	}
+	header := phi.Block()
 
-	return !loopInfo.IsInLoop(block)
+	// carries reports whether val holds phi on the edge from pred, and block
+	// reaches pred without starting another iteration. Non-header Phis on the
+	// way to the back-edge are followed edge by edge.
+	visited := make(map[*ssa.Phi]bool)
+	var carries func(val ssa.Value, pred *ssa.BasicBlock) bool
+	carries = func(val ssa.Value, pred *ssa.BasicBlock) bool {
+		if val == phi {
+			return reachesWithin(block, pred, header)
+		}
+		inner, ok := val.(*ssa.Phi)
+		if !ok || inner.Block() == header || visited[inner] {
+			return false
+		}
+		visited[inner] = true
+		for i, edge := range inner.Edges {
+			if carries(edge, inner.Block().Preds[i]) {
+				return true
+			}
+		}
+		return false
+	}
+
+	for i, edge := range phi.Edges {
+		pred := header.Preds[i]
+		if header.Dominates(pred) && carries(edge, pred) {
+			return true
+		}
+	}
+	return false
+}
+
+// reachesWithin reports whether src reaches dst without passing through
+// header, i.e. within one iteration of header's loop.
+func reachesWithin(src, dst, header *ssa.BasicBlock) bool {
+	visited := map[*ssa.BasicBlock]bool{src: true}
+	queue := []*ssa.BasicBlock{src}
+	for len(queue) > 0 {
+		block := queue[0]
+		queue = queue[1:]
+		if block == dst {
+			return true
+		}
+		for _, succ := range block.Succs {
+			if succ != header && !visited[succ] {
+				visited[succ] = true
+				queue = append(queue, succ)
+			}
+		}
+	}
+	return false
 }
@@ -75,6 +75,42 @@ func TestDetectLoops(t *testing.T) {
 			fn:       "f",
 			wantLoop: true,
 		},
+		{
+			name:     "range over string",
+			src:      "package p\nfunc f(s string) int { n := 0; for _, c := range s { n += int(c) }; return n }",
+			fn:       "f",
+			wantLoop: true,
+		},
+		{
+			name:     "range over bytes",
+			src:      "package p\nfunc f(b []byte) int { n := 0; for _, c := range b { n += int(c) }; return n }",
+			fn:       "f",
+			wantLoop: true,
+		},
+		{
+			name:     "range over array pointer",
+			src:      "package p\nfunc f(a *[3]int) int { n := 0; for i := range a { n += i }; return n }",
+			fn:       "f",
+			wantLoop: true,
+		},
+		{
+			name:     "range over map",
+			src:      "package p\nfunc f(m map[string]int) int { n := 0; for _, v := range m { n += v }; return n }",
+			fn:       "f",
+			wantLoop: true,
+		},
+		{
+			name:     "range over channel",
+			src:      "package p\nfunc f(ch chan int) int { n := 0; for v := range ch { n += v }; return n }",
+			fn:       "f",
+			wantLoop: true,
+		},
+		{
+			name:     "range over int",
+			src:      "package p\nfunc f(k int) int { n := 0; for i := range k { n += i }; return n }",
+			fn:       "f",
+			wantLoop: true,
+		},
 		{
 			// Single-block self-loop: the back-edge source is the header itself
 			// (loopTail == loopHead), exercising markLoopBlocks' early return.
@@ -164,6 +200,84 @@ func TestCanReach(t *testing.T) {
 	}
 }
 
+// TestCanReachWithout guards the paths that run a block again: the loop body
+// reaches itself through the header, but not without entering the body again.
+func TestCanReachWithout(t *testing.T) {
+	t.Parallel()
+	fn := buildFunc(t, "package p\nfunc f(n int) int { s := 0; for i := 0; i < n; i++ { s += i }; return s }", "f")
+	a := New()
+	info := a.DetectLoops(fn)
+
+	var header, body *ssa.BasicBlock
+	for _, b := range fn.Blocks {
+		switch {
+		case info.IsLoopHeader(b):
+			header = b
+		case info.IsInLoop(b):
+			body = b
+		}
+	}
+	if header == nil || body == nil {
+		t.Fatal("expected a loop header and body")
+	}
+	if !a.CanReachWithout(body, header, nil) {
+		t.Error("the body should reach the header")
+	}
+	if a.CanReachWithout(body, header, header) {
+		t.Error("no path reaches a block without entering it")
+	}
+	if !a.CanReachWithout(body, body, header) {
+		t.Error("a block must reach itself")
+	}
+	if !a.CanReachWithout(header, header, body) {
+		t.Error("a block must reach itself whatever the block avoided")
+	}
+}
+
+// TestDetectLoopsIrreducible guards cycles a goto enters at two places: no
+// block dominates the other, so there is no back-edge and no header, yet both
+// labels are in a loop that may iterate.
+func TestDetectLoopsIrreducible(t *testing.T) {
+	t.Parallel()
+	const src = `package p
+func f(skip bool) int {
+	n := 0
+	if skip {
+		goto b
+	}
+a:
+	n++
+b:
+	n *= 2
+	if n < 100 {
+		goto a
+	}
+	return n
+}`
+	fn := buildFunc(t, src, "f")
+	info := New().DetectLoops(fn)
+
+	entry, exit := fn.Blocks[0], fn.Blocks[len(fn.Blocks)-1]
+	if info.IsInLoop(entry) || info.IsInLoop(exit) {
+		t.Error("the entry and exit blocks must stay outside the cycle")
+	}
+	var inLoop int
+	for _, b := range fn.Blocks {
+		if info.IsLoopHeader(b) {
+			t.Errorf("block #%d: an irreducible cycle has no header", b.Index)
+		}
+		if info.IsInLoop(b) {
+			inLoop++
+			if !info.MayIterateMultiple(b) {
+				t.Errorf("block #%d: an irreducible cycle may iterate", b.Index)
+			}
+		}
+	}
+	if inLoop < 2 {
+		t.Errorf("in-loop blocks = %d, want both labels", inLoop)
+	}
+}
+
 func TestIsDefinedOutsideLoop(t *testing.T) {
 	t.Parallel()
 	fn := buildFunc(t, "package p\nfunc f(n int) int { s := 0; for i := 0; i < n; i++ { s += i }; return s }", "f")
@@ -171,7 +285,7 @@ func TestIsDefinedOutsideLoop(t *testing.T) {
 	info := a.DetectLoops(fn)
 
 	// A parameter is defined outside any loop.
-	if !a.IsDefinedOutsideLoop(fn.Params[0], info) {
+	if !a.IsDefinedOutsideLoop(fn.Params[0], nil, info) {
 		t.Error("parameter should be defined outside the loop")
 	}
 
@@ -183,7 +297,7 @@ func TestIsDefinedOutsideLoop(t *testing.T) {
 		}
 		for _, instr := range b.Instrs {
 			if v, ok := instr.(ssa.Value); ok {
-				if a.IsDefinedOutsideLoop(v, info) {
+				if a.IsDefinedOutsideLoop(v, b, info) {
 					t.Errorf("value %v in a loop block should be inside the loop", v)
 				}
 				insideFound = true
@@ -195,6 +309,62 @@ func TestIsDefinedOutsideLoop(t *testing.T) {
 	}
 }
 
+// TestIsDefinedOutsideLoopNested checks that a value defined in an outer loop,
+// labeled or not, counts as outside the inner loop of its use, but not outside
+// the outer loop itself.
+func TestIsDefinedOutsideLoopNested(t *testing.T) {
+	t.Parallel()
+	const src = `package p
+func use(int) {}
+func f(gs [][]int) {
+outer:
+	for _, g := range gs {
+		q := len(g) * 2
+		use(q)
+	inner:
+		for _, id := range g {
+			use(q + id)
+			if id < 0 {
+				continue outer
+			}
+			if id == 0 {
+				break inner
+			}
+		}
+	}
+}`
+	fn := buildFunc(t, src, "f")
+	a := New()
+	info := a.DetectLoops(fn)
+
+	var def ssa.Value
+	var uses []*ssa.Call
+	for _, b := range fn.Blocks {
+		for _, instr := range b.Instrs {
+			switch instr := instr.(type) {
+			case *ssa.BinOp:
+				if instr.Op == token.MUL {
+					def = instr
+				}
+			case *ssa.Call:
+				if callee := instr.Call.StaticCallee(); callee != nil && callee.Name() == "use" {
+					uses = append(uses, instr)
+				}
+			}
+		}
+	}
+	if def == nil || len(uses) != 2 {
+		t.Fatalf("def = %v, uses = %d; want the product and two calls", def, len(uses))
+	}
+	outerUse, innerUse := uses[0].Block(), uses[1].Block()
+	if a.IsDefinedOutsideLoop(def, outerUse, info) {
+		t.Error("a use in the defining loop should be inside the loop")
+	}
+	if !a.IsDefinedOutsideLoop(def, innerUse, info) {
+		t.Error("a use in the nested loop should be outside the defining loop")
+	}
+}
+
 // TestMarkLoopBlocksExcludesExit guards #113: a loop nested inside a switch arm,
 // followed by post-loop code in that arm, must not have its loop-exit block
 // marked in-loop. The invariant is structural — every in-loop block must be able
@@ -256,3 +426,255 @@ func f(s int, xs []int) int {
 		t.Fatal("expected in-loop blocks")
 	}
 }
+
+// TestDetectLoopsConditionAfterBody guards `for cond { body }`, whose condition
+// block the SSA builder places after the body. The loop header must be the
+// condition block, and the entry block, which only jumps into the loop, must
+// stay outside it.
+func TestDetectLoopsConditionAfterBody(t *testing.T) {
+	t.Parallel()
+	fn := buildFunc(t, "package p\nfunc f(n int) int { s := 0; for n > s { s++ }; return s }", "f")
+	info := New().DetectLoops(fn)
+
+	entry := fn.Blocks[0]
+	if info.IsInLoop(entry) {
+		t.Error("entry block should not be in the loop")
+	}
+	if len(entry.Succs) != 1 {
+		t.Fatalf("expected entry to jump to the loop condition, got %d successors", len(entry.Succs))
+	}
+	cond := entry.Succs[0]
+	if !info.IsLoopHeader(cond) {
+		t.Errorf("condition block #%d should be the loop header", cond.Index)
+	}
+	for _, b := range fn.Blocks {
+		if info.IsLoopHeader(b) && b != cond {
+			t.Errorf("block #%d should not be a loop header", b.Index)
+		}
+	}
+}
+
+func TestIsCarriedAcrossIterations(t *testing.T) {
+	t.Parallel()
+	const prelude = "package p\nfunc use(int) {}\n"
+	tests := []struct {
+		name string
+		body string
+		want bool
+	}{
+		{
+			name: "reassigned on the other branch",
+			body: "func f(xs []int, x int) { for _, v := range xs { if v > 0 { x = x + v } else { use(x) } } }",
+			want: true,
+		},
+		{
+			name: "reassigned before continue",
+			body: "func f(xs []int, x int) { for _, v := range xs { if v > 0 { x = x + v; continue }; use(x) } }",
+			want: true,
+		},
+		{
+			name: "replaced on the same path",
+			body: "func f(xs []int, x int) { for _, v := range xs { if v > 0 { use(x); x = v } } }",
+			want: false,
+		},
+		{
+			name: "path leaves the loop",
+			body: "func f(xs []int, x int) { for _, v := range xs { if v > 0 { x = x + v } else { use(x); break } } }",
+			want: false,
+		},
+		{
+			name: "not a loop-header phi",
+			body: "func f(c bool, x int) { if c { x = 2 }; use(x) }",
+			want: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			fn := buildFunc(t, prelude+tt.body, "f")
+			a := New()
+			info := a.DetectLoops(fn)
+
+			var got, found bool
+			for _, b := range fn.Blocks {
+				for _, instr := range b.Instrs {
+					call, ok := instr.(*ssa.Call)
+					if !ok || call.Call.StaticCallee() == nil || call.Call.StaticCallee().Name() != "use" {
+						continue
+					}
+					got, found = a.IsCarriedAcrossIterations(call.Call.Args[0], b, info), true
+				}
+			}
+			if !found {
+				t.Fatal("call to use not found")
+			}
+			if got != tt.want {
+				t.Errorf("IsCarriedAcrossIterations = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDetectLoopsRangeFunc(t *testing.T) {
+	t.Parallel()
+	const prelude = "package p\nfunc use(int) {}\n"
+	tests := []struct {
+		name string
+		body string
+		want bool
+	}{
+		{
+			name: "body continues",
+			body: "func f(seq func(func(int) bool), x int) { for v := range seq { use(x); _ = v } }",
+			want: true,
+		},
+		{
+			name: "path breaks",
+			body: "func f(seq func(func(int) bool), x int) { for v := range seq { if v > 0 { use(x); break } } }",
+			want: false,
+		},
+		{
+			name: "path returns",
+			body: "func f(seq func(func(int) bool), x int) { for v := range seq { if v > 0 { use(x); return } } }",
+			want: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			fn := buildFunc(t, prelude+tt.body, "f")
+			if len(fn.AnonFuncs) != 1 || !IsRangeFuncBody(fn.AnonFuncs[0]) {
+				t.Fatalf("range-over-func body not found in %v", fn.AnonFuncs)
+			}
+			body := fn.AnonFuncs[0]
+			a := New()
+			info := a.DetectLoops(body)
+
+			var got, found bool
+			for _, b := range body.Blocks {
+				for _, instr := range b.Instrs {
+					call, ok := instr.(*ssa.Call)
+					if !ok || call.Call.StaticCallee() == nil || call.Call.StaticCallee().Name() != "use" {
+						continue
+					}
+					got, found = info.IsInLoop(b), true
+				}
+			}
+			if !found {
+				t.Fatal("call to use not found")
+			}
+			if got != tt.want {
+				t.Errorf("IsInLoop = %v, want %v", got, tt.want)
+			}
+			if a.IsDefinedOutsideLoop(body.Params[0], nil, info) {
+				t.Error("range variable is defined outside the loop")
+			}
+		})
+	}
+}
+
+func TestMayIterateMultiple(t *testing.T) {
+	t.Parallel()
+	const prelude = "package p\nfunc use(int) {}\n"
+	tests := []struct {
+		name string
+		body string
+		want bool
+	}{
+		{
+			name: "range over slice",
+			body: "func f(xs []int) { for _, v := range xs { use(v) } }",
+			want: true,
+		},
+		{
+			name: "counter with variable bound",
+			body: "func f(n int) { for i := 0; i < n; i++ { use(i) } }",
+			want: true,
+		},
+		{
+			name: "counter running twice",
+			body: "func f() { for i := 0; i < 2; i++ { use(i) } }",
+			want: true,
+		},
+		{
+			name: "counter running once",
+			body: "func f() { for i := 0; i < 1; i++ { use(i) } }",
+			want: false,
+		},
+		{
+			name: "counter running never",
+			body: "func f() { for i := 5; i < 1; i++ { use(i) } }",
+			want: false,
+		},
+		{
+			name: "range over constant one",
+			body: "func f() { for i := range 1 { use(i) } }",
+			want: false,
+		},
+		{
+			name: "range over constant two",
+			body: "func f() { for i := range 2 { use(i) } }",
+			want: true,
+		},
+		{
+			name: "range over one-element slice literal",
+			body: "func f() { for _, v := range []int{1} { use(v) } }",
+			want: false,
+		},
+		{
+			name: "range over two-element slice literal",
+			body: "func f() { for _, v := range []int{1, 2} { use(v) } }",
+			want: true,
+		},
+		{
+			name: "range over one-element array",
+			body: "func f() { for _, v := range [...]int{1} { use(v) } }",
+			want: false,
+		},
+		{
+			name: "range over resliced literal",
+			body: "func f() { for _, v := range []int{1, 2}[1:] { use(v) } }",
+			want: true,
+		},
+		{
+			name: "counter wrapping around",
+			body: "func f() { for i := int8(127); i != 0; i++ { use(int(i)) } }",
+			want: true,
+		},
+		{
+			// foreverLoopWithBreak: the unconditional break leaves no back-edge.
+			name: "forever loop with break",
+			body: "func f(ch chan bool) { for { select { case <-ch: default: use(1) }; break } }",
+			want: false,
+		},
+		{
+			name: "single iteration inside a loop",
+			body: "func f(xs []int) { for range xs { for i := 0; i < 1; i++ { use(i) } } }",
+			want: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			fn := buildFunc(t, prelude+tt.body, "f")
+			info := New().DetectLoops(fn)
+
+			var got, found bool
+			for _, b := range fn.Blocks {
+				for _, instr := range b.Instrs {
+					call, ok := instr.(*ssa.Call)
+					if !ok || call.Call.StaticCallee() == nil || call.Call.StaticCallee().Name() != "use" {
+						continue
+					}
+					got, found = info.MayIterateMultiple(b), true
+				}
+			}
+			if !found {
+				t.Fatal("call to use not found")
+			}
+			if got != tt.want {
+				t.Errorf("MayIterateMultiple = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
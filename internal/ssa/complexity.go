@@ -0,0 +1,89 @@
+package ssa
+
+import (
+	"go/token"
+
+	"golang.org/x/tools/go/ssa"
+
+	"github.com/mpyw/gormreuse/internal/ssa/cfg"
+	"github.com/mpyw/gormreuse/internal/ssa/pollution"
+)
+
+// classifyFixComplexity fills in Violation.Complexity from the control-flow
+// context the analysis already has: the function owning the root, the block of
+// the offending use, and the loops of that function.
+//
+//	manual   - no root, or the root is used across a function boundary
+//	           (closure, goroutine, defer)
+//	moderate - the offending use is in a loop the root is defined outside of
+//	trivial  - everything else: Session() or a reassignment fixes it in place
+func (a *Analyzer) classifyFixComplexity(violations []Violation) {
+	loops := make(map[*ssa.Function]*cfg.LoopInfo)
+	for i := range violations {
+		violations[i].Complexity = a.fixComplexity(violations[i], loops)
+	}
+}
+
+func (a *Analyzer) fixComplexity(v Violation, loops map[*ssa.Function]*cfg.LoopInfo) pollution.FixComplexity {
+	rootFn := valueParent(v.Root)
+	if rootFn == nil {
+		return pollution.FixManual
+	}
+	if _, ok := v.Root.(*ssa.FreeVar); ok {
+		return pollution.FixManual // captured by a closure
+	}
+
+	// Uses recorded in another function are closure bodies sharing the root.
+	var useBlock *ssa.BasicBlock
+	for _, u := range v.AllUses {
+		if u.Block == nil || u.Block.Parent() != rootFn {
+			return pollution.FixManual
+		}
+		if u.Pos == v.Pos {
+			useBlock = u.Block
+		}
+	}
+	// A violation that is not one of the direct uses comes from a deferred or
+	// spawned call (or a closure invoked later), which is not fixable in place.
+	if useBlock == nil || isDeferOrGoAt(useBlock, v.Pos) {
+		return pollution.FixManual
+	}
+
+	info, ok := loops[rootFn]
+	if !ok {
+		info = a.cfgAnalyzer.DetectLoops(rootFn)
+		loops[rootFn] = info
+	}
+	if info.IsInLoop(useBlock) && a.cfgAnalyzer.IsDefinedOutsideLoop(v.Root, useBlock, info) {
+		return pollution.FixModerate
+	}
+	return pollution.FixTrivial
+}
+
+// valueParent returns the function defining v, or nil when v is not a local
+// value (globals, constants, missing roots).
+func valueParent(v ssa.Value) *ssa.Function {
+	switch val := v.(type) {
+	case ssa.Instruction:
+		return val.Parent()
+	case *ssa.Parameter:
+		return val.Parent()
+	case *ssa.FreeVar:
+		return val.Parent()
+	default:
+		return nil
+	}
+}
+
+// isDeferOrGoAt reports whether the use at pos is a defer or go statement.
+func isDeferOrGoAt(block *ssa.BasicBlock, pos token.Pos) bool {
+	for _, instr := range block.Instrs {
+		switch instr.(type) {
+		case *ssa.Defer, *ssa.Go:
+			if instr.Pos() == pos {
+				return true
+			}
+		}
+	}
+	return false
+}
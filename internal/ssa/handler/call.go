@@ -36,10 +36,12 @@ package handler
 
 import (
 	"go/token"
+	"slices"
 	"strings"
 
 	"golang.org/x/tools/go/ssa"
 
+	"github.com/mpyw/gormreuse/internal/directive"
 	"github.com/mpyw/gormreuse/internal/ssa/cfg"
 	"github.com/mpyw/gormreuse/internal/ssa/pollution"
 	"github.com/mpyw/gormreuse/internal/ssa/pollutionsource"
@@ -71,6 +73,68 @@ type Context struct {
 	// ordered by the call-site (execution) position rather than the closure's
 	// (earlier) body position — the define-early/call-late case of #68.
 	PosOverride token.Pos
+
+	// DeferLoop, when non-nil, is the loop info of the enclosing function in
+	// whose loop body this closure is deferred or called, possibly after being
+	// stored. The closure then runs once per iteration, so a branch from a
+	// captured root defined outside that loop is a violation on its own, like a
+	// loop use.
+	DeferLoop *cfg.LoopInfo
+
+	// StrictInterface reports each conversion of a mutable *gorm.DB to an
+	// interface as an escape (-strict-interface).
+	StrictInterface bool
+
+	// AssumePureHelpers trusts named non-gorm functions receiving a *gorm.DB
+	// as pure unless they are in ImpureFuncs, the functions marked
+	// //gormreuse:impure, or marked //gormreuse:sink (-assume-pure-helpers).
+	AssumePureHelpers bool
+	ImpureFuncs       *directive.DirectiveFuncSet
+
+	// UnhandledSSA, when non-nil, logs the instruction kinds involving a
+	// *gorm.DB that Dispatch does not route and the tracer does not follow
+	// (-warn-unhandled-ssa).
+	UnhandledSSA *UnhandledLog
+}
+
+// assumedPure reports whether callee is trusted as pure under
+// -assume-pure-helpers. Closures and function values are not: only a named
+// helper can carry //gormreuse:impure to opt back out. Neither are gorm's own
+// functions and the methods of the *gorm.DB types, which Find and friends are.
+func (c *Context) assumedPure(callee *ssa.Function) bool {
+	if !c.AssumePureHelpers || callee == nil || callee.Parent() != nil {
+		return false
+	}
+	if recv := callee.Signature.Recv(); recv != nil && c.RootTracer.IsGormDB(recv.Type()) {
+		return false
+	}
+	if obj := callee.Object(); obj == nil || c.RootTracer.GormTypes().IsGormPackage(obj.Pkg()) {
+		return false
+	}
+	if c.ImpureFuncs.Contains(callee) {
+		return false
+	}
+	return !c.RootTracer.IsSinkFunction(callee)
+}
+
+// isDeferredLoopReuse reports whether root is captured by a closure deferred
+// or called in a loop (see DeferLoop) and defined outside that loop.
+func (c *Context) isDeferredLoopReuse(root ssa.Value) bool {
+	if c.DeferLoop == nil || definedIn(root, c.CurrentFn) {
+		return false
+	}
+	return c.CFG.IsDefinedOutsideLoop(root, nil, c.DeferLoop)
+}
+
+// definedIn reports whether v is an instruction or parameter of fn.
+func definedIn(v ssa.Value, fn *ssa.Function) bool {
+	switch v := v.(type) {
+	case ssa.Instruction:
+		return v.Parent() == fn
+	case *ssa.Parameter:
+		return v.Parent() == fn
+	}
+	return false
 }
 
 // pos returns the effective source position to record for a use: the
@@ -111,18 +175,25 @@ type CallHandler struct{}
 // Non-assignment patterns (pollute):
 //   - q.Find(nil) → direct use (finisher)
 //   - q.Where("x").Find(nil) → chained use where final result is NOT assigned
+//   - res = q.Find(nil) → a finisher is a use even when its result is assigned
 func isAssignment(call *ssa.Call, ctx *Context) bool {
-	return isAssignmentRecursive(call, make(map[*ssa.Call]bool))
+	return isAssignmentRecursive(call, ctx.RootTracer.GormTypes(), make(map[*ssa.Call]bool))
 }
 
 // isAssignmentRecursive checks if a call result eventually flows into an assignment.
 // Uses visited map to avoid infinite recursion in case of cycles.
-func isAssignmentRecursive(call *ssa.Call, visited map[*ssa.Call]bool) bool {
+func isAssignmentRecursive(call *ssa.Call, gormTypes *typeutil.Matcher, visited map[*ssa.Call]bool) bool {
 	if visited[call] {
 		return false
 	}
 	visited[call] = true
 
+	// A finisher executes the statement: its result is the finished *gorm.DB,
+	// not a chain to continue, so res = q.Find(nil) still uses q.
+	if name, _, ok := tracer.GormMethod(&call.Call, gormTypes); ok && typeutil.IsFinisherMethod(name) {
+		return false
+	}
+
 	if call.Referrers() == nil {
 		return false
 	}
@@ -143,9 +214,9 @@ func isAssignmentRecursive(call *ssa.Call, visited map[*ssa.Call]bool) bool {
 		// Chain intermediate: check if the next call in chain eventually becomes assignment
 		// Example: q.Where("x").Where("y") - Where("x") is assignment only if Where("y") is
 		if nextCall, ok := user.(*ssa.Call); ok {
-			if isChainedGormMethodCall(call, nextCall) {
+			if isChainedGormMethodCall(call, nextCall, gormTypes) {
 				// Recursively check if the next call is assignment
-				if isAssignmentRecursive(nextCall, visited) {
+				if isAssignmentRecursive(nextCall, gormTypes, visited) {
 					return true
 				}
 			}
@@ -157,28 +228,10 @@ func isAssignmentRecursive(call *ssa.Call, visited map[*ssa.Call]bool) bool {
 
 // isChainedGormMethodCall checks if nextCall is a gorm method call that uses
 // call's result as receiver (i.e., they form a method chain).
-func isChainedGormMethodCall(call *ssa.Call, nextCall *ssa.Call) bool {
-	// Check if nextCall is a gorm method call
-	callee := nextCall.Call.StaticCallee()
-	if callee == nil {
-		return false
-	}
-
-	sig := callee.Signature
-	if sig == nil || sig.Recv() == nil {
-		return false
-	}
-
-	if !typeutil.IsGormDB(sig.Recv().Type()) {
-		return false
-	}
-
-	// Check if call's result is nextCall's receiver (first argument)
-	if len(nextCall.Call.Args) == 0 {
-		return false
-	}
-
-	return nextCall.Call.Args[0] == call
+func isChainedGormMethodCall(call *ssa.Call, nextCall *ssa.Call, gormTypes *typeutil.Matcher) bool {
+	// Check if nextCall is a gorm method call whose receiver is call's result
+	_, recv, ok := tracer.GormMethod(&nextCall.Call, gormTypes)
+	return ok && recv == call
 }
 
 // Handle processes a Call instruction and tracks *gorm.DB pollution.
@@ -188,36 +241,44 @@ func isChainedGormMethodCall(call *ssa.Call, nextCall *ssa.Call) bool {
 //  2. Handle bound method calls (find := q.Find; find(nil))
 //  3. Process gorm method calls: pure/assignment/actual use
 //  4. Check all Phi roots for conditional merges
+//
+// A use in a loop of a root defined outside it is a reuse on its own, as the
+// next iteration uses the root again, unless the loop provably runs at most
+// once (see cfg.LoopInfo.MayIterateMultiple):
+//
+//	for _, id := range []int{1} {
+//	    q.Where("id = ?", id).Find(nil) // OK: a single iteration
+//	}
 func (h *CallHandler) Handle(call *ssa.Call, ctx *Context) {
-	isInLoop := ctx.LoopInfo.IsInLoop(call.Block())
+	isInLoop := ctx.LoopInfo.MayIterateMultiple(call.Block())
 
 	// Check function call pollution (non-gorm-method calls with *gorm.DB args)
 	h.checkFunctionCallPollution(call, ctx)
 
+	// Closures handed to reflect may be invoked there
+	h.processReflectCall(call, ctx)
+
 	// Check bound method calls (method values)
 	if mc, ok := call.Call.Value.(*ssa.MakeClosure); ok {
-		h.processBoundMethodCall(call, mc, isInLoop, ctx)
+		h.processBoundMethodCall(call, []*ssa.MakeClosure{mc}, isInLoop, ctx)
 		return
 	}
-
-	// Check gorm method calls
-	if !h.isGormDBMethodCall(call) {
+	if origins := ctx.RootTracer.BoundMethodOrigins(call.Call.Value); len(origins) > 0 {
+		h.processBoundMethodCall(call, origins, isInLoop, ctx)
 		return
 	}
 
-	callee := call.Call.StaticCallee()
-	if callee == nil {
+	// Check gorm method calls
+	if !h.isGormDBMethodCall(call, ctx) {
+		h.processFinisherCall(call, isInLoop, ctx)
 		return
 	}
 
-	methodName := callee.Name()
-	isImmutableReturning := typeutil.IsImmutableReturningBuiltin(methodName)
-
-	// Get receiver
-	if len(call.Call.Args) == 0 {
+	methodName, recv, ok := tracer.GormMethod(&call.Call, ctx.RootTracer.GormTypes())
+	if !ok {
 		return
 	}
-	recv := call.Call.Args[0]
+	isImmutableReturning := ctx.RootTracer.GormTypes().IsImmutableReturningMethod(methodName, recv.Type())
 
 	// Find mutable root
 	root := ctx.RootTracer.FindMutableRoot(recv, ctx.LoopInfo)
@@ -232,16 +293,21 @@ func (h *CallHandler) Handle(call *ssa.Call, ctx *Context) {
 	pos := ctx.pos(call.Pos())
 	if isImmutableReturning {
 		// Pure methods check for pollution but don't pollute
-		ctx.Tracker.RecordPureUse(root, call.Block(), pos)
+		ctx.Tracker.RecordPureUse(root, call.Block(), pos, methodName)
 	} else if isAssignment(call, ctx) {
 		// Assignment creates new root - record but doesn't pollute
 		ctx.Tracker.RecordAssignment(root, call.Block(), pos)
 	} else {
-		// Actual use - pollutes the root
+		// Actual use - pollutes the root. So is a method leaving the *gorm.DB
+		// type, such as q.Association("Roles"): the *gorm.Association it
+		// returns runs its statements on q.
 		ctx.Tracker.ProcessBranch(root, call.Block(), pos)
 
-		// Loop with external root - immediate violation (only for non-pure methods)
-		if isInLoop && ctx.CFG.IsDefinedOutsideLoop(root, ctx.LoopInfo) {
+		// Loop with external root - immediate violation (only for non-pure methods).
+		// So is a receiver the loop carries unchanged into the next iteration
+		// on some path, even when another path reassigns it.
+		if isInLoop && ctx.CFG.IsDefinedOutsideLoop(root, call.Block(), ctx.LoopInfo) || ctx.isDeferredLoopReuse(root) ||
+			isInLoop && ctx.CFG.IsCarriedAcrossIterations(recv, call.Block(), ctx.LoopInfo) {
 			ctx.Tracker.AddViolationWithRoot(pos, root)
 		}
 	}
@@ -270,27 +336,41 @@ func (h *CallHandler) Handle(call *ssa.Call, ctx *Context) {
 //	find := q.Find  // MakeClosure(Find$bound, [q])
 //	find(nil)       // first use - OK
 //	q.Count(nil)    // VIOLATION (q already polluted by find(nil))
-func (h *CallHandler) processBoundMethodCall(call *ssa.Call, mc *ssa.MakeClosure, isInLoop bool, ctx *Context) {
-	if len(mc.Bindings) == 0 {
-		return
-	}
-
-	recv := mc.Bindings[0]
-	if !typeutil.IsGormDB(recv.Type()) {
-		return
-	}
-
-	methodName := strings.TrimSuffix(mc.Fn.Name(), "$bound")
-	isImmutableReturning := typeutil.IsImmutableReturningBuiltin(methodName)
-
-	root := ctx.RootTracer.FindMutableRoot(recv, ctx.LoopInfo)
-	if root == nil {
-		return
+//
+// origins holds every method value the called function may come from (see
+// RootTracer.BoundMethodOrigins). Like the Phi handling of receivers, the
+// first origin's root records the use and the roots of all origins are checked
+// for earlier pollution.
+func (h *CallHandler) processBoundMethodCall(call *ssa.Call, origins []*ssa.MakeClosure, isInLoop bool, ctx *Context) {
+	var (
+		root                 ssa.Value
+		allRoots             []ssa.Value
+		methodName           string
+		isImmutableReturning bool
+	)
+	seen := make(map[ssa.Value]bool)
+	for _, mc := range origins {
+		if len(mc.Bindings) == 0 {
+			continue
+		}
+		recv := mc.Bindings[0]
+		if !ctx.RootTracer.IsGormDB(recv.Type()) {
+			continue
+		}
+		if root == nil {
+			methodName = strings.TrimSuffix(mc.Fn.Name(), "$bound")
+			isImmutableReturning = ctx.RootTracer.GormTypes().IsImmutableReturningMethod(methodName, recv.Type())
+			root = ctx.RootTracer.FindMutableRoot(recv, ctx.LoopInfo)
+		}
+		// Get ALL possible roots BEFORE recording usage (needed for pollution check)
+		for _, r := range ctx.RootTracer.FindAllMutableRoots(recv, ctx.LoopInfo) {
+			if !seen[r] {
+				seen[r] = true
+				allRoots = append(allRoots, r)
+			}
+		}
 	}
 
-	// Get ALL possible roots BEFORE recording usage (needed for pollution check)
-	allRoots := ctx.RootTracer.FindAllMutableRoots(recv, ctx.LoopInfo)
-
 	pos := ctx.pos(call.Pos())
 
 	// Check if ANY root was already polluted BEFORE this call
@@ -300,25 +380,79 @@ func (h *CallHandler) processBoundMethodCall(call *ssa.Call, mc *ssa.MakeClosure
 		}
 	}
 
+	if root == nil {
+		return
+	}
+
 	// Record usage (violations detected later)
 	if isImmutableReturning {
 		// Pure methods check for pollution but don't pollute
-		ctx.Tracker.RecordPureUse(root, call.Block(), pos)
+		ctx.Tracker.RecordPureUse(root, call.Block(), pos, methodName)
 	} else {
 		// Non-pure methods pollute the root
 		ctx.Tracker.ProcessBranch(root, call.Block(), pos)
 
 		// Loop with external root - immediate violation (only for non-pure methods)
-		if isInLoop && ctx.CFG.IsDefinedOutsideLoop(root, ctx.LoopInfo) {
+		if isInLoop && ctx.CFG.IsDefinedOutsideLoop(root, call.Block(), ctx.LoopInfo) {
 			ctx.Tracker.AddViolationWithRoot(pos, root)
 		}
 	}
 }
 
+// processFinisherCall handles calls to user functions marked //gormreuse:finisher.
+//
+// A finisher is a terminal use of its *gorm.DB receiver (see
+// directive.FinisherReceiverIndex), so it pollutes the root exactly like Find —
+// even when its result is assigned, which would otherwise make a *gorm.DB
+// returning helper a reassignment:
+//
+//	//gormreuse:finisher
+//	func MustFind(db *gorm.DB, dest any) *gorm.DB { ... }
+//
+//	q := db.Where("x")
+//	res := MustFind(q, &users)  // first use - OK
+//	q.Count(nil)                // VIOLATION (q already finished)
+func (h *CallHandler) processFinisherCall(call *ssa.Call, isInLoop bool, ctx *Context) {
+	callee := call.Call.StaticCallee()
+	if !ctx.RootTracer.IsFinisherFunction(callee) {
+		return
+	}
+	idx, ok := directive.FinisherReceiverIndex(callee.Signature, ctx.RootTracer.GormTypes())
+	if !ok || idx >= len(call.Call.Args) {
+		return
+	}
+	recv := call.Call.Args[idx]
+
+	root := ctx.RootTracer.FindMutableRoot(recv, ctx.LoopInfo)
+	if root == nil {
+		return // Immutable source
+	}
+
+	pos := ctx.pos(call.Pos())
+	ctx.Tracker.ProcessBranch(root, call.Block(), pos)
+
+	// Loop with external root - immediate violation
+	if isInLoop && ctx.CFG.IsDefinedOutsideLoop(root, call.Block(), ctx.LoopInfo) {
+		ctx.Tracker.AddViolationWithRoot(pos, root)
+	}
+
+	// Check ALL possible roots for phi nodes
+	for _, r := range ctx.RootTracer.FindAllMutableRoots(recv, ctx.LoopInfo) {
+		if r == root {
+			continue
+		}
+		if ctx.Tracker.IsPollutedAt(r, call.Block()) {
+			ctx.Tracker.AddViolationWithRoot(pos, r)
+		}
+	}
+}
+
 // checkFunctionCallPollution marks *gorm.DB args passed to non-gorm functions as polluted.
 //
 // We conservatively assume non-pure functions may use *gorm.DB arguments.
-// Functions marked with //gormreuse:pure are exempt.
+// Functions marked with //gormreuse:pure are exempt. Functions marked with
+// //gormreuse:sink are not: they pollute exactly like unannotated functions,
+// even when also marked pure (IsPureFunction reports false for them).
 //
 // Example:
 //
@@ -332,11 +466,35 @@ func (h *CallHandler) processBoundMethodCall(call *ssa.Call, mc *ssa.MakeClosure
 //	q := db.Where("x")
 //	pureHelper(q)  // does NOT pollute
 //	q.Count(nil)   // OK (first use)
+//
+//	//gormreuse:sink
+//	func logQuery(db *gorm.DB) { log.Println(db.Statement.SQL.String()) }
+//	q := db.Where("x")
+//	logQuery(q)    // marks q as polluted (intended)
+//	q.Count(nil)   // VIOLATION (q already polluted)
+//
+// An interface method or a function-typed struct field marked
+// //gormreuse:pure is exempt as well: the directive is the user's assertion
+// for every implementation, since the dynamic callee is unknown.
+//
+// With -assume-pure-helpers the default is inverted for named non-gorm
+// functions: only those marked //gormreuse:impure or //gormreuse:sink pollute.
 func (h *CallHandler) checkFunctionCallPollution(call *ssa.Call, ctx *Context) {
 	callee := call.Call.StaticCallee()
 
 	// Check if this is a pure function - pure functions don't pollute args
-	if callee != nil && ctx.RootTracer.IsPureFunction(callee) {
+	if callee != nil && (ctx.RootTracer.IsPureFunction(callee) || ctx.assumedPure(callee)) {
+		return
+	}
+	// So are interface methods and function-typed fields marked pure, which
+	// have no static callee
+	if callee == nil && ctx.RootTracer.IsPureDynamicCall(&call.Call) {
+		return
+	}
+
+	// pool.Put(q) read back by pool.Get().(*gorm.DB) in this function hands q
+	// over: the extracted value is traced to q's root instead.
+	if tracer.IsPoolTransfer(call, ctx.RootTracer.GormTypes()) {
 		return
 	}
 
@@ -348,15 +506,27 @@ func (h *CallHandler) checkFunctionCallPollution(call *ssa.Call, ctx *Context) {
 	// The assignment creates a new mutable root, so we shouldn't ADD pollution to args.
 	// However, we still need to CHECK if args are already polluted (to detect reuse).
 	// This enables patterns like: q = buildQuery(q, "filter")
-	isReassignment := typeutil.IsGormDB(call.Type()) && isAssignment(call, ctx)
+	isReassignment := ctx.RootTracer.IsGormDB(call.Type()) && isAssignment(call, ctx)
 
 	// A method call carries its receiver as Args[0]; //gormreuse:immutable-param
 	// governs parameters, not the receiver, so the contract check below skips it.
 	recvArg := callee != nil && callee.Signature != nil && callee.Signature.Recv() != nil
 
+	// The *gorm.DB a //gormreuse:finisher consumes is recorded by
+	// processFinisherCall, so it is skipped here.
+	finisherRecv := -1
+	if callee != nil && ctx.RootTracer.IsFinisherFunction(callee) {
+		if idx, ok := directive.FinisherReceiverIndex(callee.Signature, ctx.RootTracer.GormTypes()); ok {
+			finisherRecv = idx
+		}
+	}
+
 	for i, arg := range call.Call.Args {
+		if i == finisherRecv {
+			continue
+		}
 		// Check if arg is *gorm.DB (directly or wrapped in MakeInterface)
-		gormArg, ok := pollutionsource.UnwrapGormDB(arg)
+		gormArg, ok := pollutionsource.UnwrapGormDB(arg, ctx.RootTracer.GormTypes())
 		if !ok {
 			continue
 		}
@@ -396,29 +566,71 @@ func immutableParamContractMessage(callee *ssa.Function) string {
 		"; isolate it with .Session(&gorm.Session{}) before passing"
 }
 
-func (h *CallHandler) isGormDBMethodCall(call *ssa.Call) bool {
-	callee := call.Call.StaticCallee()
-	if callee == nil {
-		return false
-	}
-
-	sig := callee.Signature
-	if sig == nil || sig.Recv() == nil {
-		return false
-	}
-
-	return typeutil.IsGormDB(sig.Recv().Type())
+// isGormDBMethodCall reports whether call is a method call on a *gorm.DB (or
+// configured DB/builder type) receiver. Every such method that is not an
+// immutable-returning builtin is handled alike as a branch of the receiver's
+// root, so statement-building methods with variadic arguments (Clauses,
+// Preload, Joins, Having, Distinct, Omit) need no special casing: a *gorm.DB
+// packed into their arguments is a separate use, recorded by the slice store
+// that packs it (see pollutionsource.Leak).
+func (h *CallHandler) isGormDBMethodCall(call *ssa.Call, ctx *Context) bool {
+	_, _, ok := tracer.GormMethod(&call.Call, ctx.RootTracer.GormTypes())
+	return ok
 }
 
 // GoHandler handles *ssa.Go instructions.
 type GoHandler struct{}
 
 // Handle processes a Go instruction.
+//
+// A struct argument carrying a *gorm.DB in a field hands the DB to the
+// goroutine, which runs concurrently with the rest of the function. Like a
+// channel send, the DB escapes at the go statement, so a use after it is a
+// reuse:
+//
+//	go handle(reqCtx{ctx: ctx, db: q})
+//	q.Find(nil)  // VIOLATION: concurrent with handle's use of q
+//
+// A go statement in a loop body spawns once per iteration, so a root defined
+// outside the loop is reused on its own. The back-edge already shows this for
+// ordinary loops; a range-over-func body has none, as the iterator calls it.
+//
+// A *gorm.DB argument of a spawned function literal is not a use on its own:
+// the literal's parameter is bound to it (see tracer.SpawnedLiteral), so the
+// body's branches of the parameter are the uses, recorded when the analyzer
+// processes the body. Only the per-iteration spawn is checked here.
 func (h *GoHandler) Handle(g *ssa.Go, ctx *Context) {
 	block := g.Block()
-	processGormDBCallCommonWith(&g.Call, g.Pos(), block, ctx, func(root ssa.Value) bool {
-		return ctx.Tracker.IsPollutedAt(root, block)
-	})
+	isInLoop := ctx.LoopInfo.MayIterateMultiple(block)
+	if tracer.SpawnedLiteral(g) != nil {
+		for _, arg := range g.Call.Args {
+			if !ctx.RootTracer.IsGormDB(arg.Type()) {
+				continue
+			}
+			root := ctx.RootTracer.FindMutableRoot(arg, ctx.LoopInfo)
+			if root != nil && isInLoop && ctx.CFG.IsDefinedOutsideLoop(root, block, ctx.LoopInfo) {
+				ctx.Tracker.AddViolationWithRoot(g.Pos(), root)
+			}
+		}
+	} else {
+		processGormDBCallCommonWith(&g.Call, g.Pos(), block, ctx, ctx.Tracker.RecordBranchUse, func(root ssa.Value) bool {
+			if isInLoop && ctx.CFG.IsDefinedOutsideLoop(root, block, ctx.LoopInfo) {
+				return true
+			}
+			return ctx.Tracker.IsPollutedAt(root, block)
+		})
+	}
+
+	for _, arg := range g.Call.Args {
+		if ctx.RootTracer.IsGormDB(arg.Type()) {
+			continue
+		}
+		for _, db := range ctx.RootTracer.StructFieldDBs(arg) {
+			if root := ctx.RootTracer.FindMutableRoot(db, ctx.LoopInfo); root != nil {
+				ctx.Tracker.MarkPolluted(root, block, ctx.pos(g.Pos()))
+			}
+		}
+	}
 }
 
 // DeferHandler handles *ssa.Defer instructions.
@@ -426,20 +638,135 @@ type DeferHandler struct{}
 
 // Handle processes a Defer instruction.
 // Defer uses IsPollutedAnywhere because it executes at function exit.
+//
+// A defer registered in a loop body runs once per iteration at exit, so like
+// a loop use in CallHandler it is a violation on its own when its root is
+// defined outside the loop:
+//
+//	for range items {
+//	    defer q.Count(nil) // VIOLATION: runs len(items) times on q
+//	}
+//
+// A loop provably bounded to one iteration registers the defer only once
+// (see cfg.LoopInfo.MayIterateMultiple).
 func (h *DeferHandler) Handle(d *ssa.Defer, ctx *Context) {
-	processGormDBCallCommonWith(&d.Call, d.Pos(), d.Block(), ctx, func(root ssa.Value) bool {
-		return ctx.Tracker.IsPollutedAnywhere(root)
+	isInLoop := ctx.LoopInfo.MayIterateMultiple(d.Block())
+	processGormDBCallCommonWith(&d.Call, d.Pos(), d.Block(), ctx, ctx.Tracker.RecordDeferredUse, func(root ssa.Value) bool {
+		if isInLoop && ctx.CFG.IsDefinedOutsideLoop(root, d.Block(), ctx.LoopInfo) {
+			return true
+		}
+		return ctx.Tracker.IsPollutedBeforeDefer(root, d.Block())
 	})
 }
 
+// HandleClosure processes the body of fn, a deferred closure returning
+// *gorm.DB (see DeferredReturnClosure). The body runs at function exit, so its
+// gorm calls are checked with IsPollutedAnywhere like a direct defer:
+//
+//	q := db.Where("x")
+//	defer func() *gorm.DB {
+//	    return q.Where("y") // VIOLATION: q is finished below before exit
+//	}()
+//	q.Find(nil)
+func (h *DeferHandler) HandleClosure(d *ssa.Defer, fn *ssa.Function, ctx *Context) {
+	isInLoop := ctx.LoopInfo.MayIterateMultiple(d.Block())
+	for _, block := range fn.Blocks {
+		for _, instr := range block.Instrs {
+			switch i := instr.(type) {
+			case *ssa.Call:
+				processGormDBCallCommonWith(&i.Call, i.Pos(), i.Block(), ctx, ctx.Tracker.RecordBranchUse, func(root ssa.Value) bool {
+					if isInLoop && !definedIn(root, fn) && ctx.CFG.IsDefinedOutsideLoop(root, d.Block(), ctx.LoopInfo) {
+						return true
+					}
+					return ctx.Tracker.IsPollutedAnywhere(root)
+				})
+			case *ssa.MakeClosure, *ssa.Go, *ssa.Defer:
+				// Nested closures and deferred or spawned calls are not followed.
+			default:
+				Dispatch(instr, ctx)
+			}
+		}
+	}
+}
+
+// CheckClosure reports the gorm calls of fn, a closure only ever deferred,
+// whose root has a use in the function deferring fn, outside it. The body
+// runs at that function's exit, after every such use, however it is guarded:
+//
+//	q := db.Where("x")
+//	defer func() {
+//	    if flag {
+//	        q.Count(nil) // VIOLATION: q is finished below before exit
+//	    }
+//	}()
+//	q.Find(nil)
+//
+// The uses of fn are recorded when fn is processed as a closure, as running at
+// exit (see pollution.Tracker.RecordDeferredClosure); this adds the violations
+// against the defer and go statements of the enclosing function too, so it
+// runs once they are all recorded.
+func (h *DeferHandler) CheckClosure(fn *ssa.Function, ctx *Context) {
+	noRecord := func(ssa.Value, *ssa.BasicBlock, token.Pos) {}
+	for _, block := range fn.Blocks {
+		for _, instr := range block.Instrs {
+			if call, ok := instr.(*ssa.Call); ok {
+				processGormDBCallCommonWith(&call.Call, call.Pos(), block, ctx, noRecord, func(root ssa.Value) bool {
+					return ctx.Tracker.IsPollutedOutside(root, fn)
+				})
+			}
+		}
+	}
+}
+
+// DeferredReturnClosure returns the function of mc when mc is only deferred
+// and returns *gorm.DB, as in defer func() *gorm.DB { ... }(); otherwise nil.
+// The result is discarded, so the closure's returned chain is a branch taken
+// at function exit rather than where the closure is written.
+func DeferredReturnClosure(mc *ssa.MakeClosure, gormTypes *typeutil.Matcher) *ssa.Function {
+	fn, ok := mc.Fn.(*ssa.Function)
+	if !ok || fn.Signature == nil {
+		return nil
+	}
+	refs := mc.Referrers()
+	if refs == nil || len(*refs) != 1 {
+		return nil
+	}
+	d, ok := (*refs)[0].(*ssa.Defer)
+	if !ok || d.Call.Value != ssa.Value(mc) {
+		return nil
+	}
+	results := fn.Signature.Results()
+	for i := 0; i < results.Len(); i++ {
+		if gormTypes.IsGormDB(results.At(i).Type()) {
+			return fn
+		}
+	}
+	return nil
+}
+
 // SendHandler handles *ssa.Send instructions.
 type SendHandler struct{}
 
 // Handle marks *gorm.DB sent to channels as polluted.
 // Handles both direct sends and sends through MakeInterface (chan interface{}).
+//
+// A struct sent by value or by pointer hands the DBs in its fields to the
+// receiver, like a struct argument of a go statement (see GoHandler):
+//
+//	ch <- holder{db: q}
+//	q.Count(nil)  // VIOLATION: the receiver of ch owns q
 func (h *SendHandler) Handle(send *ssa.Send, ctx *Context) {
-	gormVal, kind := pollutionsource.Leak(send)
+	gormVal, kind := pollutionsource.Leak(send, ctx.RootTracer.GormTypes())
 	if kind == pollutionsource.KindNone {
+		x := send.X
+		if mi, ok := x.(*ssa.MakeInterface); ok {
+			x = mi.X
+		}
+		for _, db := range ctx.RootTracer.StructFieldDBs(x) {
+			if root := ctx.RootTracer.FindMutableRoot(db, ctx.LoopInfo); root != nil {
+				ctx.Tracker.MarkPolluted(root, send.Block(), ctx.pos(send.Pos()))
+			}
+		}
 		return
 	}
 
@@ -456,12 +783,25 @@ type StoreHandler struct{}
 
 // Handle marks *gorm.DB stored to slice elements as polluted.
 // Handles both direct stores and stores through MakeInterface ([]interface{}).
+// This also covers append(s, q): the builtin receives its elements through a
+// varargs array, whose element stores are handled here rather than by
+// checkFunctionCallPollution.
 //
 // The read-only variadic stdlib exemption (fmt.Println(q), log.Printf, t.Logf)
 // lives in pollutionsource.Leak so the purity validator honors it too.
+//
+// A store into a field of a local struct pollutes the root only where that
+// struct escapes (see handleFieldStore); a struct that is merely discarded or
+// read back field by field stays safe.
 func (h *StoreHandler) Handle(store *ssa.Store, ctx *Context) {
-	gormVal, kind := pollutionsource.Leak(store)
+	gormVal, kind := pollutionsource.Leak(store, ctx.RootTracer.GormTypes())
 	if kind == pollutionsource.KindNone {
+		h.handleFieldStore(store, ctx)
+		return
+	}
+	// A local slice read back by constant-index element stores is traced like
+	// a variable; the store is an assignment, not a use.
+	if tracer.IsLocalSliceStore(store) {
 		return
 	}
 
@@ -473,16 +813,177 @@ func (h *StoreHandler) Handle(store *ssa.Store, ctx *Context) {
 	ctx.Tracker.MarkPolluted(root, store.Block(), ctx.pos(store.Pos()))
 }
 
+// handleFieldStore marks the root of a *gorm.DB stored into a field of a
+// struct allocated in this function polluted at each point where the struct
+// escapes after the store:
+//
+//	h := &holder{db: q}
+//	use(h)        // h escapes: use may branch h.db
+//	q.Count(nil)  // VIOLATION
+//
+// A go statement escapes the struct when the goroutine may use its fields in
+// another method, even when the struct is not an argument GoHandler follows:
+//
+//	w := &worker{db: q}
+//	go w.Run()           // or run := w.Run; go run(), or go func() { w.Run() }()
+//	q.Count(nil)         // VIOLATION: Run may branch w.db concurrently
+//
+// A struct pointer held in a variable a closure captures is stored through a
+// load of the variable (w.db = q is *(*w).db = q); the escapes of the variable
+// are those of the struct then.
+//
+// A field promoted from an embedded struct (r.db, stored through &r.Base) is
+// a field of the outer struct, whose escapes are followed.
+//
+// Sends are not walked here; SendHandler already follows the fields of the
+// struct it hands over.
+func (h *StoreHandler) handleFieldStore(store *ssa.Store, ctx *Context) {
+	fa, ok := store.Addr.(*ssa.FieldAddr)
+	if !ok {
+		return
+	}
+	alloc, ok := fieldBaseAlloc(fa)
+	if !ok {
+		return
+	}
+	gormVal, ok := pollutionsource.UnwrapGormDB(store.Val, ctx.RootTracer.GormTypes())
+	if !ok {
+		return
+	}
+	root := ctx.RootTracer.FindMutableRoot(gormVal, ctx.LoopInfo)
+	if root == nil {
+		return
+	}
+	for _, esc := range structEscapes(alloc, ctx, make(map[ssa.Value]bool)) {
+		if !runsAfter(store, esc, ctx.CFG) {
+			continue
+		}
+		ctx.Tracker.MarkPolluted(root, esc.Block(), ctx.pos(esc.Pos()))
+	}
+}
+
+// structEscapes returns the instructions through which the struct v (an
+// Alloc, a load of it, or a value boxed from either) leaves the function's
+// control: a call argument, a store into memory other than a local variable,
+// a map entry, or a go statement running it (see goEscapes). Field reads (h.db) and copies into local variables are
+// followed rather than counted, and a //gormreuse:pure callee, which promises
+// not to branch what it is given, is no escape. A return is not an escape point either:
+// nothing in the function runs after it, and handing a used root to the
+// caller is no reuse, just as for `return q`.
+func structEscapes(v ssa.Value, ctx *Context, visited map[ssa.Value]bool) []ssa.Instruction {
+	if visited[v] {
+		return nil
+	}
+	visited[v] = true
+	refs := v.Referrers()
+	if refs == nil {
+		return nil
+	}
+	var escapes []ssa.Instruction
+	for _, r := range *refs {
+		switch r := r.(type) {
+		case *ssa.Call:
+			callee := r.Call.StaticCallee()
+			if slices.Contains(r.Call.Args, v) && (callee == nil || !ctx.RootTracer.IsPureFunction(callee)) {
+				escapes = append(escapes, r)
+			}
+		case *ssa.Go:
+			if r.Call.Value == v || slices.Contains(r.Call.Args, v) {
+				escapes = append(escapes, r)
+			}
+		case *ssa.MakeClosure:
+			escapes = append(escapes, goEscapes(r)...)
+		case *ssa.MapUpdate:
+			escapes = append(escapes, r)
+		case *ssa.Store:
+			if r.Val != v {
+				continue
+			}
+			if local, ok := r.Addr.(*ssa.Alloc); ok {
+				escapes = append(escapes, structEscapes(local, ctx, visited)...)
+				continue
+			}
+			// A field of a local struct, such as its embedded *Base, escapes
+			// with the struct.
+			if fa, ok := r.Addr.(*ssa.FieldAddr); ok {
+				if outer, ok := fieldBaseAlloc(fa); ok {
+					escapes = append(escapes, structEscapes(outer, ctx, visited)...)
+					continue
+				}
+			}
+			escapes = append(escapes, r)
+		case *ssa.UnOp:
+			if r.Op == token.MUL {
+				escapes = append(escapes, structEscapes(r, ctx, visited)...)
+			}
+		case *ssa.MakeInterface, *ssa.ChangeInterface, *ssa.ChangeType:
+			escapes = append(escapes, structEscapes(r.(ssa.Value), ctx, visited)...)
+		}
+	}
+	return escapes
+}
+
+// fieldBaseAlloc returns the local struct whose field fa addresses, directly
+// or through the fields of nested structs (&r.Base.db), possibly through a
+// variable holding a pointer to it.
+func fieldBaseAlloc(fa *ssa.FieldAddr) (*ssa.Alloc, bool) {
+	base := fa.X
+	for {
+		inner, ok := base.(*ssa.FieldAddr)
+		if !ok {
+			break
+		}
+		base = inner.X
+	}
+	if load, ok := base.(*ssa.UnOp); ok && load.Op == token.MUL {
+		base = load.X
+	}
+	alloc, ok := base.(*ssa.Alloc)
+	return alloc, ok
+}
+
+// goEscapes returns the go statements spawning mc, a closure or bound method
+// value binding a struct: the goroutine may read the struct's fields however
+// late it runs.
+func goEscapes(mc *ssa.MakeClosure) []ssa.Instruction {
+	refs := mc.Referrers()
+	if refs == nil {
+		return nil
+	}
+	var escapes []ssa.Instruction
+	for _, r := range *refs {
+		if g, ok := r.(*ssa.Go); ok && g.Call.Value == mc {
+			escapes = append(escapes, g)
+		}
+	}
+	return escapes
+}
+
+// runsAfter reports whether esc may execute after store: later in the same
+// block, or in a block store's block reaches.
+func runsAfter(store, esc ssa.Instruction, a *cfg.Analyzer) bool {
+	if store.Block() != esc.Block() {
+		return a.CanReach(store.Block(), esc.Block())
+	}
+	instrs := store.Block().Instrs
+	return slices.Index(instrs, store) < slices.Index(instrs, esc)
+}
+
 // MapUpdateHandler handles *ssa.MapUpdate instructions.
 type MapUpdateHandler struct{}
 
 // Handle marks *gorm.DB stored in maps as polluted.
 // Handles both direct stores and stores through MakeInterface (map[K]interface{}).
 func (h *MapUpdateHandler) Handle(mapUpdate *ssa.MapUpdate, ctx *Context) {
-	gormVal, kind := pollutionsource.Leak(mapUpdate)
+	gormVal, kind := pollutionsource.Leak(mapUpdate, ctx.RootTracer.GormTypes())
 	if kind == pollutionsource.KindNone {
 		return
 	}
+	// A local constant-key map read back by key is traced like a variable;
+	// the store is an assignment, not a use.
+	if tracer.IsConstKeyMapStore(mapUpdate) {
+		return
+	}
 
 	root := ctx.RootTracer.FindMutableRoot(gormVal, ctx.LoopInfo)
 	if root == nil {
@@ -502,37 +1003,68 @@ type MakeInterfaceHandler struct{}
 // - Type assertion extraction followed by gorm method calls
 // - Function calls that receive the interface{} value
 // Those are handled by their respective handlers.
+//
+// With -strict-interface (Context.StrictInterface), converting a mutable
+// *gorm.DB is reported as an escape at the conversion, so the reader learns
+// where the root was handed out rather than only seeing its next use flagged.
 func (h *MakeInterfaceHandler) Handle(mi *ssa.MakeInterface, ctx *Context) {
-	// No-op: interface conversion doesn't pollute the source
-	// The value is just wrapped in interface{}, not used.
+	if !ctx.StrictInterface || !ctx.RootTracer.IsGormDB(mi.X.Type()) {
+		return
+	}
+	pos := conversionPos(mi, ctx)
+	if !pos.IsValid() {
+		return
+	}
+	root := ctx.RootTracer.FindMutableRoot(mi.X, ctx.LoopInfo)
+	if root == nil {
+		return
+	}
+	ctx.Tracker.AddEscapeViolation(ctx.pos(pos), root)
+}
+
+// conversionPos returns where mi is reported: its own position for an
+// explicit interface{}(q) conversion, otherwise that of the instruction
+// consuming it, such as the call receiving q as an interface{} argument. A
+// conversion handed to sync.Pool.Put is a transfer, not an escape, and has no
+// position.
+func conversionPos(mi *ssa.MakeInterface, ctx *Context) token.Pos {
+	if mi.Pos().IsValid() {
+		return mi.Pos()
+	}
+	refs := mi.Referrers()
+	if refs == nil {
+		return token.NoPos
+	}
+	for _, ref := range *refs {
+		if call, ok := ref.(*ssa.Call); ok && tracer.IsPoolTransfer(call, ctx.RootTracer.GormTypes()) {
+			return token.NoPos
+		}
+		if ref.Pos().IsValid() {
+			return ref.Pos()
+		}
+	}
+	return token.NoPos
 }
 
 // pollutionChecker is a function that checks if a root is polluted.
 type pollutionChecker func(root ssa.Value) bool
 
+// useRecorder records a deferred or spawned use of a root, such as
+// pollution.Tracker.RecordBranchUse.
+type useRecorder func(root ssa.Value, block *ssa.BasicBlock, pos token.Pos)
+
 // processGormDBCallCommonWith processes gorm calls with a custom pollution checker.
 //
 // Used by the defer and goroutine handlers. In addition to CHECKING whether the
 // receiver/argument root is already polluted (and reporting a violation if so),
-// it RECORDS each use as a branch use. Recording lets a later defer/goroutine
-// observe an earlier one, so patterns whose ONLY uses are deferred/spawned —
-// e.g. `defer q.Find(nil); defer q.Count(nil)` — are detected. Branch uses are
-// excluded from position-ordered detection (see pollution.Tracker.branchUses).
-func processGormDBCallCommonWith(callCommon *ssa.CallCommon, pos token.Pos, block *ssa.BasicBlock, ctx *Context, isPolluted pollutionChecker) {
-	callee := callCommon.StaticCallee()
-	if callee == nil {
-		return
-	}
-
-	sig := callee.Signature
-
+// it RECORDS each use with record, as a branch or deferred use. Recording lets
+// a later defer/goroutine observe an earlier one, so patterns whose ONLY uses
+// are deferred/spawned — e.g. `defer q.Find(nil); defer q.Count(nil)` — are
+// detected. Branch uses are excluded from position-ordered detection (see
+// pollution.Tracker.branchUses).
+func processGormDBCallCommonWith(callCommon *ssa.CallCommon, pos token.Pos, block *ssa.BasicBlock, ctx *Context, record useRecorder, isPolluted pollutionChecker) {
 	// Method call on *gorm.DB
-	if sig != nil && sig.Recv() != nil && typeutil.IsGormDB(sig.Recv().Type()) {
-		if len(callCommon.Args) == 0 {
-			return
-		}
-		recv := callCommon.Args[0]
-
+	if _, recv, ok := tracer.GormMethod(callCommon, ctx.RootTracer.GormTypes()); ok {
 		root := ctx.RootTracer.FindMutableRoot(recv, ctx.LoopInfo)
 		if root == nil {
 			return
@@ -554,13 +1086,16 @@ func processGormDBCallCommonWith(callCommon *ssa.CallCommon, pos token.Pos, bloc
 		}
 
 		// Record this deferred/spawned use so a later defer/go sees it.
-		ctx.Tracker.RecordBranchUse(root, block, pos)
+		record(root, block, pos)
+		return
+	}
+	if callCommon.StaticCallee() == nil {
 		return
 	}
 
 	// Function call with *gorm.DB arguments
 	for _, arg := range callCommon.Args {
-		if !typeutil.IsGormDB(arg.Type()) {
+		if !ctx.RootTracer.IsGormDB(arg.Type()) {
 			continue
 		}
 
@@ -585,7 +1120,7 @@ func processGormDBCallCommonWith(callCommon *ssa.CallCommon, pos token.Pos, bloc
 		}
 
 		// Record this deferred/spawned use so a later defer/go sees it.
-		ctx.Tracker.RecordBranchUse(root, block, pos)
+		record(root, block, pos)
 	}
 }
 
@@ -599,6 +1134,9 @@ func processGormDBCallCommonWith(callCommon *ssa.CallCommon, pos token.Pos, bloc
 //   - *ssa.MapUpdate    → MapUpdateHandler (map store: m[k] = db)
 //   - *ssa.MakeInterface → MakeInterfaceHandler (interface conversion)
 //
+// Other instructions are ignored; with ctx.UnhandledSSA, unknown kinds
+// involving a *gorm.DB are logged.
+//
 // Note: *ssa.Defer uses DispatchDefer (different pollution semantics).
 func Dispatch(instr ssa.Instruction, ctx *Context) {
 	switch i := instr.(type) {
@@ -614,6 +1152,10 @@ func Dispatch(instr ssa.Instruction, ctx *Context) {
 		(&MapUpdateHandler{}).Handle(i, ctx)
 	case *ssa.MakeInterface:
 		(&MakeInterfaceHandler{}).Handle(i, ctx)
+	default:
+		if ctx.UnhandledSSA != nil {
+			ctx.UnhandledSSA.check(instr, ctx)
+		}
 	}
 }
 
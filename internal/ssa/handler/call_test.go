@@ -1,18 +1,29 @@
 package handler
 
 import (
+	"bytes"
+	"go/constant"
+	"go/token"
+	"go/types"
 	"os"
 	"path/filepath"
 	"runtime"
+	"strings"
 	"testing"
 
 	"golang.org/x/tools/go/packages"
 	"golang.org/x/tools/go/ssa"
 	"golang.org/x/tools/go/ssa/ssautil"
 
+	"github.com/mpyw/gormreuse/internal/ssa/tracer"
 	"github.com/mpyw/gormreuse/internal/typeutil"
 )
 
+// newTestContext returns a handler Context matching gorm.io/gorm.DB only.
+func newTestContext() *Context {
+	return &Context{RootTracer: tracer.New(nil, nil, nil, nil, nil, nil, nil, nil, nil)}
+}
+
 // loadFixtureCalls builds SSA for the testdata/gormreuse fixture package and
 // returns every gorm-method *ssa.Call across all its functions. handler's
 // helpers key on real *gorm.DB types, so it loads the GOPATH fixture package.
@@ -79,7 +90,7 @@ func TestIsChainedGormMethodCall(t *testing.T) {
 		if !ok {
 			continue
 		}
-		if !isChainedGormMethodCall(recv, b) {
+		if !isChainedGormMethodCall(recv, b, nil) {
 			t.Errorf("expected chain recognized: %v -> %v", recv, b)
 		}
 		chains++
@@ -91,7 +102,7 @@ func TestIsChainedGormMethodCall(t *testing.T) {
 	// A call is never chained onto an unrelated call that is not its receiver.
 	if len(calls) >= 2 {
 		a, b := calls[0], calls[1]
-		if len(b.Call.Args) > 0 && b.Call.Args[0] != a && isChainedGormMethodCall(a, b) {
+		if len(b.Call.Args) > 0 && b.Call.Args[0] != a && isChainedGormMethodCall(a, b, nil) {
 			t.Error("unrelated calls must not be reported as a chain")
 		}
 	}
@@ -100,13 +111,14 @@ func TestIsChainedGormMethodCall(t *testing.T) {
 func TestIsAssignment(t *testing.T) {
 	t.Parallel()
 	calls := loadFixtureCalls(t)
+	ctx := newTestContext()
 
 	// The fixtures contain both reassignments (q = q.Where(...); result flows to
 	// a Phi or Store-to-Alloc → assignment) and terminal uses (q.Find(nil);
 	// result discarded → not an assignment), so both branches must be exercised.
 	var sawTrue, sawFalse bool
 	for _, c := range calls {
-		if isAssignment(c, nil) { // ctx is unused by isAssignment
+		if isAssignment(c, ctx) {
 			sawTrue = true
 		} else {
 			sawFalse = true
@@ -123,11 +135,74 @@ func TestIsAssignment(t *testing.T) {
 func TestIsGormDBMethodCall(t *testing.T) {
 	t.Parallel()
 	h := &CallHandler{}
+	ctx := newTestContext()
 	// Every call collected by loadFixtureCalls is, by construction, a gorm
 	// method call; isGormDBMethodCall must agree.
 	for _, c := range loadFixtureCalls(t) {
-		if !h.isGormDBMethodCall(c) {
+		if !h.isGormDBMethodCall(c, ctx) {
 			t.Errorf("collected call should be a gorm method call: %v", c)
 		}
 	}
 }
+
+// unknownInstr stands for an SSA instruction kind the analysis does not know,
+// such as one added by a newer x/tools.
+type unknownInstr struct {
+	ssa.Instruction // nil; only the methods below are called
+	ops             []ssa.Value
+}
+
+func (i *unknownInstr) Operands(rands []*ssa.Value) []*ssa.Value {
+	for k := range i.ops {
+		rands = append(rands, &i.ops[k])
+	}
+	return rands
+}
+func (*unknownInstr) Parent() *ssa.Function { return nil }
+func (*unknownInstr) Pos() token.Pos        { return token.NoPos }
+func (*unknownInstr) String() string        { return "unknown" }
+
+func TestDispatchUnhandledSSA(t *testing.T) {
+	t.Parallel()
+	calls := loadFixtureCalls(t)
+	db := calls[0].Call.Args[0] // a *gorm.DB receiver
+
+	var buf bytes.Buffer
+	ctx := newTestContext()
+	ctx.UnhandledSSA = NewUnhandledLog(&buf)
+
+	// Without -warn-unhandled-ssa an unknown kind is ignored silently.
+	Dispatch(&unknownInstr{ops: []ssa.Value{db}}, newTestContext())
+
+	// An unknown kind without a *gorm.DB operand is not logged.
+	Dispatch(&unknownInstr{ops: []ssa.Value{ssa.NewConst(constant.MakeInt64(1), types.Typ[types.Int])}}, ctx)
+	if buf.Len() != 0 {
+		t.Fatalf("logged an instruction without *gorm.DB: %q", buf.String())
+	}
+
+	// An unknown kind involving a *gorm.DB is logged once.
+	Dispatch(&unknownInstr{ops: []ssa.Value{db}}, ctx)
+	Dispatch(&unknownInstr{ops: []ssa.Value{nil, db}}, ctx)
+	if got := buf.String(); strings.Count(got, "\n") != 1 || !strings.Contains(got, "*handler.unknownInstr") {
+		t.Errorf("log = %q, want one line naming *handler.unknownInstr", got)
+	}
+
+	// Every instruction of the fixtures is of a known kind.
+	buf.Reset()
+	seen := make(map[*ssa.Function]bool)
+	for _, c := range calls {
+		fn := c.Parent()
+		if seen[fn] {
+			continue
+		}
+		seen[fn] = true
+		for _, b := range fn.Blocks {
+			for _, instr := range b.Instrs {
+				ctx.UnhandledSSA.check(instr, ctx)
+			}
+		}
+	}
+	if buf.Len() != 0 {
+		t.Errorf("logged fixture instructions: %q", buf.String())
+	}
+}
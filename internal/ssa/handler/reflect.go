@@ -0,0 +1,96 @@
+package handler
+
+import (
+	"go/types"
+
+	"golang.org/x/tools/go/ssa"
+
+	"github.com/mpyw/gormreuse/internal/ssa/tracer"
+)
+
+// isReflectCall reports whether call statically calls a function or method of
+// package reflect, such as reflect.ValueOf or reflect.Value.Call.
+func isReflectCall(call *ssa.Call) bool {
+	callee := call.Call.StaticCallee()
+	if callee == nil {
+		return false
+	}
+	if callee.Pkg != nil {
+		return callee.Pkg.Pkg.Path() == "reflect"
+	}
+	obj := callee.Object()
+	return obj != nil && obj.Pkg() != nil && obj.Pkg().Path() == "reflect"
+}
+
+// ReflectInvocation returns the call to a reflect function that the closure
+// value mc is passed to, as in reflect.ValueOf(f).Call(nil), or nil when mc has
+// any other referrer. reflect can invoke the closure at that call, so it is
+// taken as the closure's invocation site.
+func ReflectInvocation(mc *ssa.MakeClosure) *ssa.Call {
+	refs := mc.Referrers()
+	if refs == nil || len(*refs) != 1 {
+		return nil
+	}
+	mi, ok := (*refs)[0].(*ssa.MakeInterface)
+	if !ok {
+		return nil
+	}
+	if refs = mi.Referrers(); refs == nil || len(*refs) != 1 {
+		return nil
+	}
+	call, ok := (*refs)[0].(*ssa.Call)
+	if !ok || !isReflectCall(call) {
+		return nil
+	}
+	return call
+}
+
+// processReflectCall marks the *gorm.DB roots captured by a closure passed to
+// a reflect function as polluted. Reflection is not traced, so the closure is
+// conservatively assumed to be invoked there, using or handing out the value
+// it captures:
+//
+//	q := db.Where("x")
+//	f := func() *gorm.DB { return q }
+//	reflect.ValueOf(f).Call(nil)  // q may be branched by the caller of Call
+//	q.Find(nil)                   // VIOLATION
+func (h *CallHandler) processReflectCall(call *ssa.Call, ctx *Context) {
+	if !isReflectCall(call) {
+		return
+	}
+	for _, arg := range call.Call.Args {
+		mi, ok := arg.(*ssa.MakeInterface)
+		if !ok {
+			continue
+		}
+		mc, ok := mi.X.(*ssa.MakeClosure)
+		if !ok || !tracer.ClosureCapturesGormDB(mc, ctx.RootTracer.GormTypes()) {
+			continue
+		}
+		for _, binding := range mc.Bindings {
+			if !isGormDBOrPointer(binding.Type(), ctx) {
+				continue
+			}
+			root := ctx.RootTracer.FindMutableRoot(binding, ctx.LoopInfo)
+			if root == nil {
+				continue
+			}
+			ctx.Tracker.MarkPolluted(root, call.Block(), ctx.pos(call.Pos()))
+		}
+	}
+}
+
+// isGormDBOrPointer reports whether typ is *gorm.DB or a pointer chain to it,
+// the type of a captured *gorm.DB variable.
+func isGormDBOrPointer(typ types.Type, ctx *Context) bool {
+	for {
+		if ctx.RootTracer.IsGormDB(typ) {
+			return true
+		}
+		ptr, ok := typ.(*types.Pointer)
+		if !ok {
+			return false
+		}
+		typ = ptr.Elem()
+	}
+}
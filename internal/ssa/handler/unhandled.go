@@ -0,0 +1,85 @@
+package handler
+
+import (
+	"fmt"
+	"io"
+	"reflect"
+	"sync"
+
+	"golang.org/x/tools/go/ssa"
+)
+
+// knownInstrs are the SSA instruction kinds the analysis accounts for: those
+// Dispatch routes to a handler, and those the tracer follows from the values
+// they define or that carry no *gorm.DB use of their own (Phi, FieldAddr,
+// Return, ...). Defer is dispatched separately by DispatchDefer.
+var knownInstrs = func() map[reflect.Type]bool {
+	known := make(map[reflect.Type]bool)
+	for _, instr := range []ssa.Instruction{
+		(*ssa.Alloc)(nil), (*ssa.BinOp)(nil), (*ssa.Call)(nil),
+		(*ssa.ChangeInterface)(nil), (*ssa.ChangeType)(nil), (*ssa.Convert)(nil),
+		(*ssa.DebugRef)(nil), (*ssa.Defer)(nil), (*ssa.Extract)(nil),
+		(*ssa.Field)(nil), (*ssa.FieldAddr)(nil), (*ssa.Go)(nil), (*ssa.If)(nil),
+		(*ssa.Index)(nil), (*ssa.IndexAddr)(nil), (*ssa.Jump)(nil),
+		(*ssa.Lookup)(nil), (*ssa.MakeChan)(nil), (*ssa.MakeClosure)(nil),
+		(*ssa.MakeInterface)(nil), (*ssa.MakeMap)(nil), (*ssa.MakeSlice)(nil),
+		(*ssa.MapUpdate)(nil), (*ssa.MultiConvert)(nil), (*ssa.Next)(nil),
+		(*ssa.Panic)(nil), (*ssa.Phi)(nil), (*ssa.Range)(nil), (*ssa.Return)(nil),
+		(*ssa.RunDefers)(nil), (*ssa.Select)(nil), (*ssa.Send)(nil),
+		(*ssa.Slice)(nil), (*ssa.SliceToArrayPointer)(nil), (*ssa.Store)(nil),
+		(*ssa.TypeAssert)(nil), (*ssa.UnOp)(nil),
+	} {
+		known[reflect.TypeOf(instr)] = true
+	}
+	return known
+}()
+
+// UnhandledLog logs the SSA instruction kinds involving a *gorm.DB that the
+// analysis does not account for (-warn-unhandled-ssa), such as kinds added by
+// newer versions of x/tools for new language features. Dispatch would drop
+// them silently, hiding possible false negatives. Each kind is logged once, at
+// its first instruction. It is safe for concurrent use.
+type UnhandledLog struct {
+	mu   sync.Mutex
+	out  io.Writer
+	seen map[reflect.Type]bool
+}
+
+// NewUnhandledLog returns an UnhandledLog writing to out. Write errors are not
+// reported.
+func NewUnhandledLog(out io.Writer) *UnhandledLog {
+	return &UnhandledLog{out: out, seen: make(map[reflect.Type]bool)}
+}
+
+// check logs the kind of instr if it is unknown and one of its operands is a
+// *gorm.DB, or a pointer to one, and its kind was not logged yet.
+func (l *UnhandledLog) check(instr ssa.Instruction, ctx *Context) {
+	kind := reflect.TypeOf(instr)
+	if knownInstrs[kind] || !involvesGormDB(instr, ctx) {
+		return
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.seen[kind] {
+		return
+	}
+	l.seen[kind] = true
+
+	pos := "-"
+	if fn := instr.Parent(); fn != nil && instr.Pos().IsValid() {
+		pos = fn.Prog.Fset.Position(instr.Pos()).String()
+	}
+	_, _ = fmt.Fprintf(l.out, "gormreuse: %s: unhandled SSA instruction %s involving *gorm.DB: %s\n", pos, kind, instr)
+}
+
+// involvesGormDB reports whether an operand of instr is a *gorm.DB, or a
+// pointer to one.
+func involvesGormDB(instr ssa.Instruction, ctx *Context) bool {
+	for _, op := range instr.Operands(nil) {
+		if op != nil && *op != nil && isGormDBOrPointer((*op).Type(), ctx) {
+			return true
+		}
+	}
+	return false
+}
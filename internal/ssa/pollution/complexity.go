@@ -0,0 +1,29 @@
+package pollution
+
+// FixComplexity estimates how much work fixing a violation takes, for triage.
+type FixComplexity int
+
+const (
+	// FixTrivial is fixed locally by adding Session() or reassigning the root.
+	FixTrivial FixComplexity = iota
+	// FixModerate needs the surrounding loop restructured (a root defined
+	// outside a loop is branched on every iteration).
+	FixModerate
+	// FixManual crosses a function boundary (closure, goroutine, defer) or has
+	// no root to fix, so the ownership of the *gorm.DB must be redesigned.
+	FixManual
+)
+
+// String returns the label used in diagnostics.
+func (c FixComplexity) String() string {
+	switch c {
+	case FixTrivial:
+		return "trivial"
+	case FixModerate:
+		return "moderate"
+	case FixManual:
+		return "manual"
+	default:
+		return "unknown"
+	}
+}
@@ -0,0 +1,115 @@
+package pollution
+
+import (
+	"go/token"
+	"sort"
+
+	"golang.org/x/tools/go/ssa"
+)
+
+// UseKind classifies a recorded use of a mutable root.
+type UseKind int
+
+const (
+	// UsePolluting is a branch that consumes the root (ProcessBranch, MarkPolluted).
+	UsePolluting UseKind = iota
+	// UsePure checks the root for pollution without consuming it (RecordPureUse).
+	UsePure
+	// UseAssignment derives a new root from the root (RecordAssignment).
+	UseAssignment
+	// UseDeferred is a deferred or goroutine branch (RecordBranchUse).
+	UseDeferred
+)
+
+// String returns the label used for the kind in root graphs.
+func (k UseKind) String() string {
+	switch k {
+	case UsePolluting:
+		return "branch"
+	case UsePure:
+		return "pure"
+	case UseAssignment:
+		return "assign"
+	case UseDeferred:
+		return "defer/go"
+	default:
+		return "unknown"
+	}
+}
+
+// GraphUse is a single recorded use of a root.
+type GraphUse struct {
+	UsageInfo
+	Kind      UseKind
+	Violation bool // a violation was reported at this use
+}
+
+// GraphRoot is a mutable root together with all of its recorded uses.
+type GraphRoot struct {
+	Root ssa.Value
+	Uses []GraphUse // sorted by position
+}
+
+// Graph returns a snapshot of every root the tracker has seen and its uses,
+// sorted by root position. It is meant for visualization (-report-root-graph)
+// and must be called after DetectViolations so violations are marked.
+func (t *Tracker) Graph() []GraphRoot {
+	violated := make(map[ssa.Value]map[token.Pos]bool)
+	for _, v := range t.violations {
+		if v.Root == nil {
+			continue
+		}
+		if violated[v.Root] == nil {
+			violated[v.Root] = make(map[token.Pos]bool)
+		}
+		violated[v.Root][v.Pos] = true
+	}
+
+	// The same use may be recorded more than once (e.g. through both a direct
+	// call and a bound method), so identical uses are collapsed.
+	type useKey struct {
+		root ssa.Value
+		info UsageInfo
+		kind UseKind
+	}
+	seen := make(map[useKey]bool)
+	uses := make(map[ssa.Value][]GraphUse)
+	add := func(m map[ssa.Value][]UsageInfo, kind UseKind) {
+		for root, infos := range m {
+			for _, info := range infos {
+				key := useKey{root: root, info: info, kind: kind}
+				if seen[key] {
+					continue
+				}
+				seen[key] = true
+				uses[root] = append(uses[root], GraphUse{
+					UsageInfo: info,
+					Kind:      kind,
+					Violation: violated[root][info.Pos],
+				})
+			}
+		}
+	}
+	add(t.pollutingUses, UsePolluting)
+	add(t.pureUses, UsePure)
+	add(t.assignmentUses, UseAssignment)
+	add(t.branchUses, UseDeferred)
+
+	roots := make([]GraphRoot, 0, len(uses))
+	for root, us := range uses {
+		sort.SliceStable(us, func(i, j int) bool {
+			if us[i].Pos != us[j].Pos {
+				return us[i].Pos < us[j].Pos
+			}
+			return us[i].Kind < us[j].Kind
+		})
+		roots = append(roots, GraphRoot{Root: root, Uses: us})
+	}
+	sort.SliceStable(roots, func(i, j int) bool {
+		if roots[i].Root.Pos() != roots[j].Root.Pos() {
+			return roots[i].Root.Pos() < roots[j].Root.Pos()
+		}
+		return roots[i].Root.Name() < roots[j].Root.Name()
+	})
+	return roots
+}
@@ -0,0 +1,96 @@
+package pollution
+
+// ViolationKind classifies a diagnostic. Its String form is reported as the
+// analysis.Diagnostic Category, so machine-readable output (-json) and
+// category-based configuration can tell reuse apart from directive problems.
+type ViolationKind int
+
+const (
+	// KindBranch is a second branch from a mutable root (the reuse itself).
+	KindBranch ViolationKind = iota
+	// KindPure is a //gormreuse:pure function polluting its *gorm.DB argument.
+	KindPure
+	// KindContract is a broken immutable-return, immutable-param or
+	// immutable-input contract.
+	KindContract
+	// KindUnusedIgnore is a //gormreuse:ignore directive suppressing nothing.
+	KindUnusedIgnore
+	// KindUnusedAllowReuse is a //gormreuse:allow-reuse directive allowing no
+	// reuse. It is kept apart from KindUnusedIgnore so audits can tell
+	// confirmed-safe reuse markers from noise suppression.
+	KindUnusedAllowReuse
+	// KindUnusedDirective is a pure/immutable-*/finisher directive that matches
+	// no function or has no effect.
+	KindUnusedDirective
+	// KindScopesSession is the Session/WithContext/Debug inside a Scopes
+	// callback warning (go-gorm/gorm#7592).
+	KindScopesSession
+	// KindSuggestPure is an unannotated helper that never pollutes its
+	// *gorm.DB argument and could be marked //gormreuse:pure (-suggest-pure).
+	KindSuggestPure
+	// KindLateSession is a Session called on a root that an earlier branch
+	// already polluted. It is reuse like KindBranch, reported apart because
+	// the Session reads as a fix while it does not undo the earlier branch.
+	KindLateSession
+	// KindEscape is a mutable *gorm.DB converted to an interface, after which
+	// any use of its root is unsafe (-strict-interface).
+	KindEscape
+	// KindMissingReason is a //gormreuse:ignore directive giving no reason
+	// (-require-ignore-reason).
+	KindMissingReason
+	// KindUnvalidatedPure is a call of a //gormreuse:pure function of another
+	// package whose source cannot be read, so its contract is trusted without
+	// validating its body (-warn-unvalidated-pure).
+	KindUnvalidatedPure
+
+	numKinds // number of kinds; keep last
+)
+
+// ParseViolationKind returns the kind whose category name is name, such as
+// "BRANCH". It reports false for an unknown name.
+func ParseViolationKind(name string) (ViolationKind, bool) {
+	for k := ViolationKind(0); k < numKinds; k++ {
+		if k.String() == name {
+			return k, true
+		}
+	}
+	return 0, false
+}
+
+// IsReuse reports whether k is a reuse of a mutable root: KindBranch or
+// KindLateSession. Only reuse honors //gormreuse:allow-reuse.
+func (k ViolationKind) IsReuse() bool {
+	return k == KindBranch || k == KindLateSession
+}
+
+// String returns the category name of the kind, e.g. "BRANCH".
+func (k ViolationKind) String() string {
+	switch k {
+	case KindBranch:
+		return "BRANCH"
+	case KindPure:
+		return "PURE"
+	case KindContract:
+		return "CONTRACT"
+	case KindUnusedIgnore:
+		return "UNUSED-IGNORE"
+	case KindUnusedAllowReuse:
+		return "UNUSED-ALLOW-REUSE"
+	case KindUnusedDirective:
+		return "UNUSED-DIRECTIVE"
+	case KindScopesSession:
+		return "SCOPES-SESSION"
+	case KindSuggestPure:
+		return "SUGGEST-PURE"
+	case KindLateSession:
+		return "LATE-SESSION"
+	case KindEscape:
+		return "ESCAPE"
+	case KindMissingReason:
+		return "MISSING-REASON"
+	case KindUnvalidatedPure:
+		return "UNVALIDATED-PURE"
+	default:
+		return "UNKNOWN"
+	}
+}
@@ -34,12 +34,16 @@
 package pollution
 
 import (
+	"go/ast"
 	"go/token"
 	"path/filepath"
+	"slices"
 	"strconv"
 	"strings"
 
 	"golang.org/x/tools/go/ssa"
+
+	"github.com/mpyw/gormreuse/internal/ssa/cfg"
 )
 
 // Violation represents a detected reuse violation.
@@ -48,12 +52,30 @@ type Violation struct {
 	Message string
 	Root    ssa.Value   // mutable root that caused the violation (for fix generation)
 	AllUses []UsageInfo // all uses of this root (for fix generation)
+
+	// RootPos is where Root is defined, reported as the "root defined here"
+	// related location. It is token.NoPos when the root is unknown or
+	// synthesized (a Phi, for example), and the location is then omitted.
+	RootPos token.Pos
+
+	// Kind is the diagnostic category: KindBranch for reuse, KindLateSession
+	// for reuse through a Session call, KindContract for AddMessageViolation
+	// contract violations.
+	Kind ViolationKind
+
+	// Complexity estimates the effort of fixing the violation. It is filled in
+	// by ssa.Analyzer.Analyze from the control-flow context of the root.
+	Complexity FixComplexity
 }
 
 // UsageInfo tracks a single usage of a root (exported for fix generation).
 type UsageInfo struct {
 	Block *ssa.BasicBlock
 	Pos   token.Pos
+
+	// Method is the method name of a pure use (RecordPureUse), e.g. "Session".
+	// It is empty for other uses.
+	Method string
 }
 
 // Tracker tracks pollution state of mutable *gorm.DB roots.
@@ -86,9 +108,33 @@ type Tracker struct {
 	// IsPolluted/IsPollutedAt, which do consult this map.
 	branchUses map[ssa.Value][]UsageInfo
 
+	// deferredUses maps roots to the uses of defer statements. They are
+	// branch uses kept apart because two defers conflict only when one
+	// execution registers both (see IsPollutedBeforeDefer):
+	//
+	//	defer q.Find(nil)
+	//	defer q.Count(nil) // both run at exit: reuse
+	deferredUses map[ssa.Value][]UsageInfo
+
+	// deferredClosures holds the closures only ever deferred, as in
+	// defer func() { ... }(). Their bodies run at the exit of the function
+	// deferring them, after its other uses (see runsAtExitAfter).
+	deferredClosures map[*ssa.Function]bool
+
 	// violations tracks detected violations.
 	violations []Violation
 
+	// escapes holds the interface conversions added by AddEscapeViolation.
+	// They become KindEscape violations in DetectViolations, at positions with
+	// no reuse violation: a conversion that is itself a reuse is reported as
+	// such.
+	escapes []Violation
+
+	// reported holds the (position, root) pairs already in violations. A use
+	// reached both directly and through a closure, or from several earlier
+	// uses, is found more than once but reported once per root.
+	reported map[violationKey]bool
+
 	// cfgAnalyzer for reachability checks.
 	cfgAnalyzer CFGAnalyzer
 
@@ -97,21 +143,30 @@ type Tracker struct {
 	fset *token.FileSet
 }
 
+// violationKey identifies a reuse violation: its position and mutable root.
+type violationKey struct {
+	pos  token.Pos
+	root ssa.Value
+}
+
 // CFGAnalyzer interface for control flow analysis.
 type CFGAnalyzer interface {
-	CanReach(src, dst *ssa.BasicBlock) bool
+	CanReachWithout(src, dst, without *ssa.BasicBlock) bool
 }
 
 // New creates a new Tracker. fset is used to render source positions in reuse
 // diagnostics and may be nil (positions are then omitted).
 func New(cfgAnalyzer CFGAnalyzer, fset *token.FileSet) *Tracker {
 	return &Tracker{
-		pollutingUses:  make(map[ssa.Value][]UsageInfo),
-		pureUses:       make(map[ssa.Value][]UsageInfo),
-		assignmentUses: make(map[ssa.Value][]UsageInfo),
-		branchUses:     make(map[ssa.Value][]UsageInfo),
-		cfgAnalyzer:    cfgAnalyzer,
-		fset:           fset,
+		pollutingUses:    make(map[ssa.Value][]UsageInfo),
+		pureUses:         make(map[ssa.Value][]UsageInfo),
+		assignmentUses:   make(map[ssa.Value][]UsageInfo),
+		branchUses:       make(map[ssa.Value][]UsageInfo),
+		deferredUses:     make(map[ssa.Value][]UsageInfo),
+		deferredClosures: make(map[*ssa.Function]bool),
+		reported:         make(map[violationKey]bool),
+		cfgAnalyzer:      cfgAnalyzer,
+		fset:             fset,
 	}
 }
 
@@ -123,10 +178,11 @@ func (t *Tracker) ProcessBranch(root ssa.Value, block *ssa.BasicBlock, pos token
 }
 
 // RecordPureUse records a PURE usage (Session, Debug, etc).
-// These uses check for pollution but don't pollute.
-// Caller must ensure root is not nil.
-func (t *Tracker) RecordPureUse(root ssa.Value, block *ssa.BasicBlock, pos token.Pos) {
-	t.pureUses[root] = append(t.pureUses[root], UsageInfo{Block: block, Pos: pos})
+// These uses check for pollution but don't pollute. method is the called
+// method's name, so a Session on a polluted root is reported as
+// KindLateSession. Caller must ensure root is not nil.
+func (t *Tracker) RecordPureUse(root ssa.Value, block *ssa.BasicBlock, pos token.Pos, method string) {
+	t.pureUses[root] = append(t.pureUses[root], UsageInfo{Block: block, Pos: pos, Method: method})
 }
 
 // RecordAssignment records an ASSIGNMENT usage where a root is used to create a new root.
@@ -145,8 +201,56 @@ func (t *Tracker) RecordBranchUse(root ssa.Value, block *ssa.BasicBlock, pos tok
 	t.branchUses[root] = append(t.branchUses[root], UsageInfo{Block: block, Pos: pos})
 }
 
-// isReachable checks if pollution can reach the target block.
-func (t *Tracker) isReachable(pollutedBlock, targetBlock *ssa.BasicBlock) bool {
+// RecordDeferredUse records the use of a root by a defer statement, a branch
+// use checked by a later defer with IsPollutedBeforeDefer. Caller must ensure
+// root is not nil.
+func (t *Tracker) RecordDeferredUse(root ssa.Value, block *ssa.BasicBlock, pos token.Pos) {
+	t.deferredUses[root] = append(t.deferredUses[root], UsageInfo{Block: block, Pos: pos})
+}
+
+// RecordDeferredClosure records that fn is a closure only ever deferred, whose
+// uses run at the exit of the function deferring it rather than at their
+// position.
+func (t *Tracker) RecordDeferredClosure(fn *ssa.Function) {
+	t.deferredClosures[fn] = true
+}
+
+// runsAtExitAfter reports whether the uses in target run at the exit of a
+// function deferring a closure that holds them, after the uses in src, which
+// that function runs outside the closure:
+//
+//	defer func() {
+//	    q.Count(nil) // runs at exit, after q.Find
+//	}()
+//	q.Find(nil)
+func (t *Tracker) runsAtExitAfter(target, src *ssa.Function) bool {
+	for f := target; f != nil; f = f.Parent() {
+		if t.deferredClosures[f] && nestedIn(src, f.Parent()) && !nestedIn(src, f) {
+			return true
+		}
+	}
+	return false
+}
+
+// runsBeforeAcross reports whether the use src runs before the use target of
+// another function: when target is deferred after src (see runsAtExitAfter),
+// or else when src comes first in the source.
+func (t *Tracker) runsBeforeAcross(src, target UsageInfo) bool {
+	switch {
+	case src.Block == nil || target.Block == nil:
+		return src.Pos < target.Pos
+	case t.runsAtExitAfter(target.Block.Parent(), src.Block.Parent()):
+		return true
+	case t.runsAtExitAfter(src.Block.Parent(), target.Block.Parent()):
+		return false
+	}
+	return src.Pos < target.Pos
+}
+
+// isReachable checks if pollution of root can reach the target block. A path
+// running the definition of root again does not: the target then sees a fresh
+// value.
+func (t *Tracker) isReachable(root ssa.Value, pollutedBlock, targetBlock *ssa.BasicBlock) bool {
 	if pollutedBlock == nil || targetBlock == nil {
 		return false
 	}
@@ -157,16 +261,37 @@ func (t *Tracker) isReachable(pollutedBlock, targetBlock *ssa.BasicBlock) bool {
 	}
 
 	// Same function: use CFG reachability
-	return t.cfgAnalyzer.CanReach(pollutedBlock, targetBlock)
+	var def *ssa.BasicBlock
+	if instr, ok := root.(ssa.Instruction); ok && instr.Parent() == pollutedBlock.Parent() {
+		def = instr.Block()
+	}
+	return t.cfgAnalyzer.CanReachWithout(pollutedBlock, targetBlock, def)
 }
 
 // addViolationWithContext adds a violation with root and uses information for fix generation.
 func (t *Tracker) addViolationWithContext(pos token.Pos, root ssa.Value, allUses []UsageInfo) {
+	t.addViolationOfKind(pos, root, allUses, KindBranch)
+}
+
+// addViolationOfKind adds a reuse violation of the given kind, KindBranch or
+// KindLateSession, with root and uses information for fix generation. A
+// violation already added at pos for root is not added again.
+func (t *Tracker) addViolationOfKind(pos token.Pos, root ssa.Value, allUses []UsageInfo, kind ViolationKind) {
+	key := violationKey{pos: pos, root: root}
+	if t.reported[key] {
+		return
+	}
+	t.reported[key] = true
+	rootPos := valuePos(root)
+	// The message is rendered by DetectViolations, once every use is
+	// recorded: the first branch may be recorded after this violation, as a
+	// use of the enclosing function is after one of a deferred closure.
 	t.violations = append(t.violations, Violation{
 		Pos:     pos,
-		Message: t.reuseMessage(root),
 		Root:    root,
 		AllUses: allUses,
+		RootPos: rootPos,
+		Kind:    kind,
 	})
 }
 
@@ -175,20 +300,34 @@ func (t *Tracker) addViolationWithContext(pos token.Pos, root ssa.Value, allUses
 // at all three sites — root, first branch, and the offending second branch (the
 // diagnostic's own position) — not just the last one (#76).
 func (t *Tracker) reuseMessage(root ssa.Value) string {
-	msg := "*gorm.DB reused: second branch from mutable root"
+	return "*gorm.DB reused: second branch from mutable root" + t.rootLocs(root) +
+		"; make the root immutable with .Session(&gorm.Session{})"
+}
 
+// lateSessionMessage builds the KindLateSession diagnostic. The Session looks
+// like the usual fix, so the message says why it comes too late:
+//
+//	q.Count(nil)                       // first branch pollutes q
+//	q.Session(&gorm.Session{}).Find(x) // the Session copies the polluted q
+func (t *Tracker) lateSessionMessage(root ssa.Value) string {
+	return "*gorm.DB reused: Session on an already-polluted value does not undo the earlier branch" + t.rootLocs(root) +
+		"; move .Session(&gorm.Session{}) to the root"
+}
+
+// rootLocs renders the positions of root and its first branch as
+// " (root at a.go:3, first branch at a.go:4)", or "" when neither is known.
+func (t *Tracker) rootLocs(root ssa.Value) string {
 	var locs []string
-	if root != nil && root.Pos().IsValid() {
-		locs = append(locs, "root at "+t.loc(root.Pos()))
+	if pos := valuePos(root); pos.IsValid() {
+		locs = append(locs, "root at "+t.loc(pos))
 	}
 	if fb := t.firstBranchPos(root); fb.IsValid() {
 		locs = append(locs, "first branch at "+t.loc(fb))
 	}
-	if len(locs) > 0 {
-		msg += " (" + strings.Join(locs, ", ") + ")"
+	if len(locs) == 0 {
+		return ""
 	}
-
-	return msg + "; make the root immutable with .Session(&gorm.Session{})"
+	return " (" + strings.Join(locs, ", ") + ")"
 }
 
 // loc renders pos as "file.go:line" (base name only — the file is almost always
@@ -201,12 +340,53 @@ func (t *Tracker) loc(pos token.Pos) string {
 	return filepath.Base(p.Filename) + ":" + strconv.Itoa(p.Line)
 }
 
+// valuePos returns where root is defined. A range variable of a
+// range-over-func loop is a parameter of the synthesized body, positioned in
+// the iterator's signature, so it is placed at the range statement instead. An
+// element of a multi-value result is placed at its call.
+func valuePos(root ssa.Value) token.Pos {
+	if root == nil {
+		return token.NoPos
+	}
+	if x, ok := root.(*ssa.Extract); ok {
+		return x.Tuple.Pos()
+	}
+	p, ok := root.(*ssa.Parameter)
+	if !ok || !cfg.IsRangeFuncBody(p.Parent()) {
+		return root.Pos()
+	}
+	fn := p.Parent()
+	rng, ok := fn.Syntax().(*ast.RangeStmt)
+	if !ok {
+		return fn.Pos()
+	}
+	switch slices.Index(fn.Params, p) {
+	case 0:
+		if rng.Key != nil {
+			return rng.Key.Pos()
+		}
+	case 1:
+		if rng.Value != nil {
+			return rng.Value.Pos()
+		}
+	}
+	return rng.Range
+}
+
 // firstBranchPos returns the earliest polluting use of root (its first branch),
-// which precedes the second branch that triggered the violation.
+// which precedes the second branch that triggered the violation. A use in a
+// deferred closure runs after the uses of the function deferring it, so it is
+// not the first branch of theirs whatever its position.
 func (t *Tracker) firstBranchPos(root ssa.Value) token.Pos {
+	uses := t.pollutingUses[root]
 	best := token.NoPos
-	for _, u := range t.pollutingUses[root] {
-		if u.Pos.IsValid() && (!best.IsValid() || u.Pos < best) {
+	for _, u := range uses {
+		if !u.Pos.IsValid() || best.IsValid() && u.Pos >= best {
+			continue
+		}
+		if !slices.ContainsFunc(uses, func(v UsageInfo) bool {
+			return u.Block != nil && v.Block != nil && t.runsAtExitAfter(u.Block.Parent(), v.Block.Parent())
+		}) {
 			best = u.Pos
 		}
 	}
@@ -217,19 +397,24 @@ func (t *Tracker) firstBranchPos(root ssa.Value) token.Pos {
 // Includes deferred/goroutine branch uses so multiple defers/goroutines that
 // reuse the same root (with no direct use) are detected.
 func (t *Tracker) IsPolluted(root ssa.Value) bool {
-	return len(t.pollutingUses[root]) > 0 || len(t.branchUses[root]) > 0
+	return len(t.pollutingUses[root]) > 0 || len(t.branchUses[root]) > 0 || len(t.deferredUses[root]) > 0
 }
 
 // IsPollutedAt checks if a root has polluting usage that can reach the target block.
 // Includes deferred/goroutine branch uses (see IsPolluted).
 func (t *Tracker) IsPollutedAt(root ssa.Value, targetBlock *ssa.BasicBlock) bool {
 	for _, use := range t.pollutingUses[root] {
-		if t.isReachable(use.Block, targetBlock) {
+		if t.isReachable(root, use.Block, targetBlock) {
 			return true
 		}
 	}
 	for _, use := range t.branchUses[root] {
-		if t.isReachable(use.Block, targetBlock) {
+		if t.isReachable(root, use.Block, targetBlock) {
+			return true
+		}
+	}
+	for _, use := range t.deferredUses[root] {
+		if t.isReachable(root, use.Block, targetBlock) {
 			return true
 		}
 	}
@@ -248,7 +433,30 @@ func (t *Tracker) MarkPolluted(root ssa.Value, block *ssa.BasicBlock, pos token.
 // parameter (Phase 1b stage 2b). It still flows through the normal reporting path,
 // so //gormreuse:ignore and position dedup apply.
 func (t *Tracker) AddMessageViolation(pos token.Pos, message string) {
-	t.violations = append(t.violations, Violation{Pos: pos, Message: message})
+	t.violations = append(t.violations, Violation{Pos: pos, Message: message, Kind: KindContract})
+}
+
+// AddEscapeViolation records that the mutable root escapes into an interface
+// at pos, so any later use of it is unsafe (-strict-interface). The violation
+// is added by DetectViolations. Caller must ensure root is not nil.
+func (t *Tracker) AddEscapeViolation(pos token.Pos, root ssa.Value) {
+	t.escapes = append(t.escapes, Violation{
+		Pos:     pos,
+		Message: t.escapeMessage(root),
+		Root:    root,
+		RootPos: valuePos(root),
+		Kind:    KindEscape,
+	})
+}
+
+// escapeMessage builds the KindEscape diagnostic.
+func (t *Tracker) escapeMessage(root ssa.Value) string {
+	loc := ""
+	if pos := valuePos(root); pos.IsValid() {
+		loc = " (root at " + t.loc(pos) + ")"
+	}
+	return "*gorm.DB escapes into interface{}" + loc +
+		"; any later use of the root is unsafe, make it immutable with .Session(&gorm.Session{}) first"
 }
 
 // AddViolationWithRoot adds a violation with root information for fix generation.
@@ -266,8 +474,31 @@ func (t *Tracker) getAllUses(root ssa.Value) []UsageInfo {
 	return allUses
 }
 
+// kind returns the kind of a violation reported at u: KindLateSession for a
+// Session use, KindBranch otherwise.
+func (u UsageInfo) kind() ViolationKind {
+	if u.Method == "Session" {
+		return KindLateSession
+	}
+	return KindBranch
+}
+
 // checkViolationsBetween checks if any source use can reach any target use.
 // Returns positions of target uses that are reachable from a source use.
+//
+// Across functions (closure), a source runs before the target when its
+// position is earlier, unless one of them is in a deferred closure and runs
+// at exit after the other (see runsAtExitAfter). Within a function, control flow decides rather than
+// positions, which a goto may run out of order:
+//
+//	    goto b
+//	a:  q.Find(nil)  // VIOLATION: runs after q.Count
+//	    return
+//	b:  q.Count(nil)
+//	    goto a
+//
+// Blocks joined by a goto are fused into one, where the instruction order
+// (see runsBefore) is the execution order; across blocks, reachability.
 func (t *Tracker) checkViolationsBetween(targets, sources []UsageInfo, root ssa.Value, allUses []UsageInfo) {
 	for _, target := range targets {
 		for _, src := range sources {
@@ -276,29 +507,84 @@ func (t *Tracker) checkViolationsBetween(targets, sources []UsageInfo, root ssa.
 				continue
 			}
 
-			// Only report if src is BEFORE target (earlier position)
-			if src.Pos >= target.Pos {
+			sameFunc := src.Block != nil && target.Block != nil &&
+				src.Block.Parent() == target.Block.Parent()
+
+			// Only report if src is BEFORE target
+			if sameFunc && src.Block == target.Block {
+				if !runsBefore(src.Block, src.Pos, target.Pos) {
+					continue
+				}
+			} else if !sameFunc && !t.runsBeforeAcross(src, target) {
 				continue
 			}
 
-			// Different functions (closure): position order is sufficient
-			if src.Block != nil && target.Block != nil &&
-				src.Block.Parent() != target.Block.Parent() {
-				t.addViolationWithContext(target.Pos, root, allUses)
+			// Different functions (closure): execution order is sufficient
+			if src.Block != nil && target.Block != nil && !sameFunc {
+				t.addViolationOfKind(target.Pos, root, allUses, target.kind())
 				break
 			}
 
 			// Same function: check CFG reachability
-			if t.isReachable(src.Block, target.Block) {
-				t.addViolationWithContext(target.Pos, root, allUses)
+			if t.isReachable(root, src.Block, target.Block) {
+				t.addViolationOfKind(target.Pos, root, allUses, target.kind())
 				break
 			}
 		}
 	}
 }
 
+// runsBefore reports whether the use at pos a runs before the use at pos b in
+// block: the instruction at a comes first. A use nested in the arguments of
+// the other, as q.Where in q.Or(q.Where("a")), still counts as after it, and
+// uses without an instruction of their own in block are ordered by position.
+func runsBefore(block *ssa.BasicBlock, a, b token.Pos) bool {
+	ia, ib := -1, -1
+	for i, instr := range block.Instrs {
+		switch instr.Pos() {
+		case a:
+			if ia < 0 {
+				ia = i
+			}
+		case b:
+			if ib < 0 {
+				ib = i
+			}
+		}
+	}
+	if ia < 0 || ib < 0 || (ia < ib) == (a < b) {
+		return a < b
+	}
+	if nestedCall(block.Parent(), a, b) || nestedCall(block.Parent(), b, a) {
+		return a < b
+	}
+	return ia < ib
+}
+
+// nestedCall reports whether the call at pos inner is written within the
+// call whose opening parenthesis is at outer, in the syntax of fn.
+func nestedCall(fn *ssa.Function, outer, inner token.Pos) bool {
+	if fn == nil || fn.Syntax() == nil || inner <= outer {
+		return false
+	}
+	nested := false
+	ast.Inspect(fn.Syntax(), func(n ast.Node) bool {
+		if nested || n == nil || n.Pos() > outer || n.End() <= outer {
+			return false
+		}
+		if call, ok := n.(*ast.CallExpr); ok && call.Lparen == outer {
+			nested = inner < call.End()
+			return false
+		}
+		return true
+	})
+	return nested
+}
+
 // DetectViolations performs violation detection after all uses are recorded.
 // For each root with multiple uses, check if an earlier use can reach a later one.
+// Each (position, root) pair is reported once, including pairs already added
+// by AddViolationWithRoot.
 func (t *Tracker) DetectViolations() {
 	// Check violations between polluting uses (non-pure methods)
 	for root, uses := range t.pollutingUses {
@@ -330,6 +616,40 @@ func (t *Tracker) DetectViolations() {
 		allUses := t.getAllUses(root)
 		t.checkViolationsBetween(assignmentUses, pollutingUses, root, allUses)
 	}
+
+	t.renderMessages()
+	t.addEscapes()
+}
+
+// renderMessages renders the messages of the reuse violations, naming the
+// first branch among every recorded use.
+func (t *Tracker) renderMessages() {
+	for i, v := range t.violations {
+		switch {
+		case v.Message != "":
+		case v.Kind == KindLateSession:
+			t.violations[i].Message = t.lateSessionMessage(v.Root)
+		default:
+			t.violations[i].Message = t.reuseMessage(v.Root)
+		}
+	}
+}
+
+// addEscapes adds the violations recorded by AddEscapeViolation, once per
+// position, except where a reuse is already reported.
+func (t *Tracker) addEscapes() {
+	taken := make(map[token.Pos]bool, len(t.violations))
+	for _, v := range t.violations {
+		taken[v.Pos] = true
+	}
+	for _, v := range t.escapes {
+		if taken[v.Pos] {
+			continue
+		}
+		taken[v.Pos] = true
+		v.AllUses = t.getAllUses(v.Root)
+		t.violations = append(t.violations, v)
+	}
 }
 
 // CollectViolations returns all detected violations.
@@ -341,3 +661,51 @@ func (t *Tracker) CollectViolations() []Violation {
 func (t *Tracker) IsPollutedAnywhere(root ssa.Value) bool {
 	return t.IsPolluted(root)
 }
+
+// IsPollutedOutside reports whether root has a polluting, branch or deferred
+// use in the function deferring fn, outside fn and the closures nested in it.
+// fn is a closure only ever deferred: its body runs at exit, after every such
+// use.
+func (t *Tracker) IsPollutedOutside(root ssa.Value, fn *ssa.Function) bool {
+	for _, uses := range [][]UsageInfo{t.pollutingUses[root], t.branchUses[root], t.deferredUses[root]} {
+		for _, use := range uses {
+			if use.Block != nil && nestedIn(use.Block.Parent(), fn.Parent()) && !nestedIn(use.Block.Parent(), fn) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// nestedIn reports whether f is fn or a closure nested in it.
+func nestedIn(f, fn *ssa.Function) bool {
+	for ; f != nil; f = f.Parent() {
+		if f == fn {
+			return true
+		}
+	}
+	return false
+}
+
+// IsPollutedBeforeDefer checks if root is used before a defer statement in
+// block runs at function exit. Any use other than a defer is (a defer runs
+// last), and so is every other defer registered along with it: in the same
+// block, or in a block on the same path. Defers in mutually exclusive
+// branches are never both registered:
+//
+//	if cond {
+//	    defer q.Find(nil)
+//	} else {
+//	    defer q.Count(nil) // OK: only one of them runs
+//	}
+func (t *Tracker) IsPollutedBeforeDefer(root ssa.Value, block *ssa.BasicBlock) bool {
+	if len(t.pollutingUses[root]) > 0 || len(t.branchUses[root]) > 0 {
+		return true
+	}
+	for _, use := range t.deferredUses[root] {
+		if use.Block == block || t.isReachable(root, use.Block, block) || t.isReachable(root, block, use.Block) {
+			return true
+		}
+	}
+	return false
+}
@@ -21,6 +21,16 @@
 // Values may be interface-boxed before storage (a []interface{} / map /
 // chan of interface{}); Leak unwraps a single MakeInterface box.
 //
+// The builtin append(s, db1, db2) needs no case of its own: SSA packs its
+// variadic elements into a fresh array before the call, so each *gorm.DB
+// element is a KindSliceStore into that array:
+//
+//	t1 = new [2]*gorm.DB (varargs)
+//	t2 = &t1[0]              // *t2 = db1 → KindSliceStore
+//	t3 = &t1[1]              // *t3 = db2 → KindSliceStore
+//	t4 = slice t1[:]
+//	t5 = append(s, t4...)
+//
 // # What is deliberately NOT a leak
 //
 //   - Packing into the varargs array of a known read-only stdlib function
@@ -59,12 +69,13 @@ const (
 //
 // This is needed because storing *gorm.DB into interface{} containers (slice,
 // map, channel) makes SSA box the value first, e.g. []interface{}{q} generates
-// MakeInterface(q) -> Store.
-func UnwrapGormDB(v ssa.Value) (ssa.Value, bool) {
-	if typeutil.IsGormDB(v.Type()) {
+// MakeInterface(q) -> Store. gormTypes is the configured DB type matcher (nil
+// for gorm.io/gorm.DB only).
+func UnwrapGormDB(v ssa.Value, gormTypes *typeutil.Matcher) (ssa.Value, bool) {
+	if gormTypes.IsGormDB(v.Type()) {
 		return v, true
 	}
-	if mi, ok := v.(*ssa.MakeInterface); ok && typeutil.IsGormDB(mi.X.Type()) {
+	if mi, ok := v.(*ssa.MakeInterface); ok && gormTypes.IsGormDB(mi.X.Type()) {
 		return mi.X, true
 	}
 	return nil, false
@@ -76,10 +87,10 @@ func UnwrapGormDB(v ssa.Value) (ssa.Value, bool) {
 // doc). Callers still decide what a leak means for them (the main handler
 // marks the value polluted; the purity validator reports a contract
 // violation).
-func Leak(instr ssa.Instruction) (ssa.Value, Kind) {
+func Leak(instr ssa.Instruction, gormTypes *typeutil.Matcher) (ssa.Value, Kind) {
 	switch i := instr.(type) {
 	case *ssa.Send:
-		if v, ok := UnwrapGormDB(i.X); ok {
+		if v, ok := UnwrapGormDB(i.X, gormTypes); ok {
 			return v, KindChannelSend
 		}
 	case *ssa.Store:
@@ -89,7 +100,7 @@ func Leak(instr ssa.Instruction) (ssa.Value, Kind) {
 		if !ok {
 			return nil, KindNone
 		}
-		v, ok := UnwrapGormDB(i.Val)
+		v, ok := UnwrapGormDB(i.Val, gormTypes)
 		if !ok {
 			return nil, KindNone
 		}
@@ -98,7 +109,7 @@ func Leak(instr ssa.Instruction) (ssa.Value, Kind) {
 		}
 		return v, KindSliceStore
 	case *ssa.MapUpdate:
-		if v, ok := UnwrapGormDB(i.Value); ok {
+		if v, ok := UnwrapGormDB(i.Value, gormTypes); ok {
 			return v, KindMapStore
 		}
 	}
@@ -70,7 +70,7 @@ func leakKindsIn(fn *ssa.Function) map[pollutionsource.Kind]bool {
 	kinds := make(map[pollutionsource.Kind]bool)
 	for _, b := range fn.Blocks {
 		for _, instr := range b.Instrs {
-			if v, k := pollutionsource.Leak(instr); k != pollutionsource.KindNone && v != nil {
+			if v, k := pollutionsource.Leak(instr, nil); k != pollutionsource.KindNone && v != nil {
 				kinds[k] = true
 			}
 		}
@@ -91,6 +91,8 @@ func TestLeakEnumeratesSources(t *testing.T) {
 	}{
 		{"pureLeaksViaChanSend", pollutionsource.KindChannelSend},
 		{"pureLeaksViaSliceStore", pollutionsource.KindSliceStore},
+		// append packs its elements into the varargs array: a slice store.
+		{"pureLeaksViaAppend", pollutionsource.KindSliceStore},
 		{"pureLeaksViaMapStore", pollutionsource.KindMapStore},
 		// Read-only variadic stdlib packing (fmt.Println) must NOT be a leak.
 		{"pureLogsArgReadOnly", pollutionsource.KindNone},
@@ -139,7 +141,7 @@ func TestUnwrapGormDB(t *testing.T) {
 			if !ok {
 				continue
 			}
-			if _, isGorm := pollutionsource.UnwrapGormDB(mi); isGorm {
+			if _, isGorm := pollutionsource.UnwrapGormDB(mi, nil); isGorm {
 				sawBoxedGormDB = true
 			}
 		}
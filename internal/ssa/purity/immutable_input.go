@@ -7,7 +7,6 @@ import (
 
 	"github.com/mpyw/gormreuse/internal/directive"
 	"github.com/mpyw/gormreuse/internal/ssa/tracer"
-	"github.com/mpyw/gormreuse/internal/typeutil"
 )
 
 // ValidateImmutableInputs enforces the body-side contract of
@@ -66,7 +65,7 @@ func ValidateImmutableInputs(fn *ssa.Function, set *directive.ImmutableInputSet,
 				continue
 			}
 			for _, arg := range call.Call.Args {
-				if !typeutil.IsGormDB(arg.Type()) {
+				if !rt.IsGormDB(arg.Type()) {
 					continue
 				}
 				if rt.FindMutableRoot(arg, nil) == nil {
@@ -47,11 +47,11 @@ func ValidateImmutableReturn(fn *ssa.Function, set *directive.DirectiveFuncSet,
 				// Only *gorm.DB results carry the contract. A vacuous directive
 				// (no *gorm.DB in the return, e.g. interface{}) governs nothing
 				// and is handled by the unused-directive path, not here.
-				if !typeutil.IsGormDB(res.Type()) {
+				if !rt.IsGormDB(res.Type()) {
 					continue
 				}
 				for _, root := range rt.FindAllMutableRoots(res, nil) {
-					if !isGormChainCall(root) {
+					if !isGormChainCall(root, rt.GormTypes()) {
 						continue // not a provably-mutable root
 					}
 					// One diagnostic per function: the directive, not each
@@ -72,7 +72,7 @@ func ValidateImmutableReturn(fn *ssa.Function, set *directive.DirectiveFuncSet,
 // result is a definitively mutable clone==0 handle; every other mutable root the
 // tracer produces (parameters, user-function/closure calls) is only a
 // conservative guess and must not drive an immutable-return contract violation.
-func isGormChainCall(v ssa.Value) bool {
+func isGormChainCall(v ssa.Value, gormTypes *typeutil.Matcher) bool {
 	call, ok := v.(*ssa.Call)
 	if !ok {
 		return false
@@ -81,8 +81,8 @@ func isGormChainCall(v ssa.Value) bool {
 	if callee == nil || callee.Signature == nil || callee.Signature.Recv() == nil {
 		return false
 	}
-	if !typeutil.IsGormDB(callee.Signature.Recv().Type()) {
+	if !gormTypes.IsGormDB(callee.Signature.Recv().Type()) {
 		return false
 	}
-	return !typeutil.IsImmutableReturningBuiltin(callee.Name())
+	return !gormTypes.IsImmutableReturningMethod(callee.Name(), callee.Signature.Recv().Type())
 }
@@ -57,6 +57,7 @@ type Violation struct {
 type Validator struct {
 	fn           *ssa.Function
 	pureFuncs    *directive.DirectiveFuncSet
+	gormTypes    *typeutil.Matcher
 	paramDerived map[ssa.Value]bool
 }
 
@@ -66,7 +67,9 @@ type Validator struct {
 // Note: Pure functions MAY return mutable *gorm.DB values. The "pure" contract only
 // guarantees that the function doesn't pollute its arguments - callers must treat
 // the return value as potentially mutable.
-func ValidateFunction(fn *ssa.Function, pureFuncs *directive.DirectiveFuncSet) []Violation {
+//
+// gormTypes is the configured DB type matcher (nil for gorm.io/gorm.DB only).
+func ValidateFunction(fn *ssa.Function, pureFuncs *directive.DirectiveFuncSet, gormTypes *typeutil.Matcher) []Violation {
 	if fn == nil || fn.Blocks == nil {
 		return nil
 	}
@@ -74,12 +77,13 @@ func ValidateFunction(fn *ssa.Function, pureFuncs *directive.DirectiveFuncSet) [
 	v := &Validator{
 		fn:           fn,
 		pureFuncs:    pureFuncs,
+		gormTypes:    gormTypes,
 		paramDerived: make(map[ssa.Value]bool),
 	}
 
 	// Initialize with *gorm.DB parameters
 	for _, p := range fn.Params {
-		if typeutil.IsGormDB(p.Type()) {
+		if v.gormTypes.IsGormDB(p.Type()) {
 			v.paramDerived[p] = true
 		}
 	}
@@ -133,8 +137,8 @@ func (v *Validator) trackCallDerivation(call *ssa.Call) {
 	// Interface method call
 	if call.Call.Method != nil {
 		recv := call.Call.Value
-		if typeutil.IsGormDB(recv.Type()) && v.paramDerived[recv] {
-			if !typeutil.IsImmutableReturningBuiltin(call.Call.Method.Name()) {
+		if v.gormTypes.IsGormDB(recv.Type()) && v.paramDerived[recv] {
+			if !v.gormTypes.IsImmutableReturningMethod(call.Call.Method.Name(), recv.Type()) {
 				if result := call.Value(); result != nil {
 					v.paramDerived[result] = true
 				}
@@ -150,10 +154,10 @@ func (v *Validator) trackCallDerivation(call *ssa.Call) {
 	}
 
 	sig := callee.Signature
-	if sig != nil && sig.Recv() != nil && typeutil.IsGormDB(sig.Recv().Type()) {
+	if sig != nil && sig.Recv() != nil && v.gormTypes.IsGormDB(sig.Recv().Type()) {
 		if len(call.Call.Args) > 0 {
 			recv := call.Call.Args[0]
-			if v.paramDerived[recv] && !typeutil.IsImmutableReturningBuiltin(callee.Name()) {
+			if v.paramDerived[recv] && !v.gormTypes.IsImmutableReturningMethod(callee.Name(), sig.Recv().Type()) {
 				if result := call.Value(); result != nil {
 					v.paramDerived[result] = true
 				}
@@ -164,8 +168,8 @@ func (v *Validator) trackCallDerivation(call *ssa.Call) {
 
 	// Regular function call
 	for _, arg := range call.Call.Args {
-		if typeutil.IsGormDB(arg.Type()) && v.paramDerived[arg] {
-			if result := call.Value(); result != nil && typeutil.IsGormDB(result.Type()) {
+		if v.gormTypes.IsGormDB(arg.Type()) && v.paramDerived[arg] {
+			if result := call.Value(); result != nil && v.gormTypes.IsGormDB(result.Type()) {
 				if !v.pureFuncs.Contains(callee) {
 					v.paramDerived[result] = true
 				}
@@ -189,7 +193,7 @@ func (v *Validator) checkCallPollution(call *ssa.Call) []Violation {
 	// gorm chain method on a param-derived receiver pollutes the argument.
 	if callee != nil {
 		sig := callee.Signature
-		if sig != nil && sig.Recv() != nil && typeutil.IsGormDB(sig.Recv().Type()) {
+		if sig != nil && sig.Recv() != nil && v.gormTypes.IsGormDB(sig.Recv().Type()) {
 			return v.checkStaticMethodPollution(call, callee)
 		}
 	}
@@ -205,7 +209,7 @@ func (v *Validator) checkCallPollution(call *ssa.Call) []Violation {
 // checkLeak reports a contract violation when a param-derived *gorm.DB escapes
 // via a non-call pollution source (channel send, slice/array store, map store).
 func (v *Validator) checkLeak(instr ssa.Instruction) []Violation {
-	val, kind := pollutionsource.Leak(instr)
+	val, kind := pollutionsource.Leak(instr, v.gormTypes)
 	if kind == pollutionsource.KindNone || !v.paramDerived[val] {
 		return nil
 	}
@@ -233,7 +237,7 @@ func (v *Validator) checkStaticMethodPollution(call *ssa.Call, callee *ssa.Funct
 
 	recv := call.Call.Args[0]
 	methodName := callee.Name()
-	if v.paramDerived[recv] && !typeutil.IsImmutableReturningBuiltin(methodName) {
+	if v.paramDerived[recv] && !v.gormTypes.IsImmutableReturningMethod(methodName, recv.Type()) {
 		return []Violation{{
 			Pos:     call.Pos(),
 			Message: "pure function pollutes *gorm.DB argument by calling " + methodName,
@@ -252,7 +256,7 @@ func (v *Validator) checkFunctionCallPollution(call *ssa.Call, callee *ssa.Funct
 	for _, arg := range call.Call.Args {
 		// Unwrap interface-boxed args so a *gorm.DB passed as interface{}
 		// (e.g. to a variadic ...any function) is still caught.
-		gormArg, ok := pollutionsource.UnwrapGormDB(arg)
+		gormArg, ok := pollutionsource.UnwrapGormDB(arg, v.gormTypes)
 		if !ok || !v.paramDerived[gormArg] {
 			continue
 		}
@@ -66,7 +66,7 @@ func TestValidateFunction(t *testing.T) {
 	funcs := loadFixtureFuncs(t)
 	// A syntax-backed pure set: Contains resolves //gormreuse:pure via each
 	// function's AST, which is enough for the fixtures.
-	pureFuncs := directive.NewPureFuncSet(nil, nil)
+	pureFuncs := directive.NewPureFuncSet(nil, nil, nil)
 
 	tests := []struct {
 		fn       string
@@ -97,7 +97,7 @@ func TestValidateFunction(t *testing.T) {
 			if !ok {
 				t.Fatalf("fixture function %q not found", tc.fn)
 			}
-			violations := purity.ValidateFunction(fn, pureFuncs)
+			violations := purity.ValidateFunction(fn, pureFuncs, nil)
 
 			if tc.clean {
 				if len(violations) != 0 {
@@ -135,10 +135,10 @@ func TestValidateFunction(t *testing.T) {
 // TestValidateFunctionNil covers the nil/blockless guards.
 func TestValidateFunctionNil(t *testing.T) {
 	t.Parallel()
-	if v := purity.ValidateFunction(nil, directive.NewPureFuncSet(nil, nil)); v != nil {
+	if v := purity.ValidateFunction(nil, directive.NewPureFuncSet(nil, nil, nil), nil); v != nil {
 		t.Errorf("nil function: expected nil, got %+v", v)
 	}
-	if v := purity.ValidateFunction(&ssa.Function{}, directive.NewPureFuncSet(nil, nil)); v != nil {
+	if v := purity.ValidateFunction(&ssa.Function{}, directive.NewPureFuncSet(nil, nil, nil), nil); v != nil {
 		t.Errorf("blockless function: expected nil, got %+v", v)
 	}
 }
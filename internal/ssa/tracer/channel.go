@@ -0,0 +1,31 @@
+package tracer
+
+import (
+	"go/token"
+
+	"golang.org/x/tools/go/ssa"
+)
+
+// isReceived reports whether v is a value received from a channel:
+//
+//	d := <-ch          // t0 = <-ch
+//	d, ok := <-ch      // t0 = <-ch,ok; t1 = extract t0 #0
+//	case d := <-ch:    // t0 = select blocking [<-ch]; t1 = extract t0 #2
+//
+// The sender's chain state is unknown here, so like a parameter the received
+// value is an immutable source: branching it twice is not reported, while a
+// chain started from it (d.Where(...)) is a mutable root as usual.
+func isReceived(v ssa.Value) bool {
+	switch val := v.(type) {
+	case *ssa.UnOp:
+		return val.Op == token.ARROW
+	case *ssa.Extract:
+		switch tuple := val.Tuple.(type) {
+		case *ssa.UnOp:
+			return tuple.Op == token.ARROW
+		case *ssa.Select:
+			return true
+		}
+	}
+	return false
+}
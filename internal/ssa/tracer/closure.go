@@ -0,0 +1,153 @@
+package tracer
+
+import (
+	"go/token"
+
+	"golang.org/x/tools/go/ssa"
+)
+
+// CalleeClosures returns the closures that the callee value v of a dynamic
+// call may be, following it back through local variables and the elements of
+// local slices, arrays and maps it was stored in:
+//
+//	var funcs []func()
+//	for _, item := range items {
+//	    funcs = append(funcs, func() { q.Where("item = ?", item).Find(nil) })
+//	}
+//	for _, f := range funcs {
+//	    f() // t9 = &funcs[i]; t10 = *t9; t10() — the closure above
+//	}
+//
+// A container is followed through its aliases (Phi, slicing, append), so the
+// closures appended in one loop are found when called in another. Values the
+// function cannot see into (parameters, fields, call results) contribute
+// nothing, so the result may be incomplete but never names a closure that is
+// not stored where v reads.
+func CalleeClosures(v ssa.Value) []*ssa.MakeClosure {
+	c := &closureCollector{seen: make(map[ssa.Value]bool)}
+	c.value(v)
+	return c.found
+}
+
+// closureCollector accumulates the closures reached by CalleeClosures.
+type closureCollector struct {
+	seen  map[ssa.Value]bool
+	found []*ssa.MakeClosure
+}
+
+// value collects the closures v itself may be.
+func (c *closureCollector) value(v ssa.Value) {
+	if c.seen[v] {
+		return
+	}
+	c.seen[v] = true
+	switch v := v.(type) {
+	case *ssa.MakeClosure:
+		c.found = append(c.found, v)
+	case *ssa.Phi:
+		for _, edge := range v.Edges {
+			c.value(edge)
+		}
+	case *ssa.UnOp:
+		if v.Op != token.MUL {
+			return
+		}
+		switch addr := v.X.(type) {
+		case *ssa.Alloc:
+			// A local variable: whatever was stored into it.
+			c.stores(addr)
+		case *ssa.IndexAddr:
+			c.elems(addr.X)
+		}
+	case *ssa.Index:
+		c.elems(v.X)
+	case *ssa.Lookup:
+		c.elems(v.X)
+	case *ssa.Extract:
+		// The value of a range over a map (t1 = next t0; t2 = extract t1 #2).
+		if next, ok := v.Tuple.(*ssa.Next); ok && v.Index == 2 {
+			if rng, ok := next.Iter.(*ssa.Range); ok {
+				c.elems(rng.X)
+			}
+		}
+	}
+}
+
+// stores collects the closures stored directly into the variable addr.
+func (c *closureCollector) stores(addr ssa.Value) {
+	if addr.Referrers() == nil {
+		return
+	}
+	for _, ref := range *addr.Referrers() {
+		if s, ok := ref.(*ssa.Store); ok && s.Addr == addr {
+			c.value(s.Val)
+		}
+	}
+}
+
+// elems collects the closures stored into the elements of the slice, array
+// or map x, or of any container x is an alias of.
+func (c *closureCollector) elems(x ssa.Value) {
+	if c.seen[x] {
+		return
+	}
+	c.seen[x] = true
+	switch x := x.(type) {
+	case *ssa.Phi:
+		for _, edge := range x.Edges {
+			c.elems(edge)
+		}
+	case *ssa.Slice:
+		c.elems(x.X)
+	case *ssa.UnOp:
+		// A load of a slice variable or of a whole array.
+		if x.Op != token.MUL {
+			return
+		}
+		if alloc, ok := x.X.(*ssa.Alloc); ok {
+			c.elems(alloc)
+		}
+	case *ssa.Call:
+		// append(s, elems...) holds the elements of both.
+		if b, ok := x.Call.Value.(*ssa.Builtin); ok && b.Name() == "append" {
+			for _, arg := range x.Call.Args {
+				c.elems(arg)
+			}
+		}
+	case *ssa.Alloc, *ssa.MakeSlice, *ssa.MakeMap:
+		c.containerStores(x)
+	}
+}
+
+// containerStores collects the closures stored into the elements of the
+// local container x: through its element addresses and slicings for an array
+// or slice, by MapUpdate for a map, and, for a variable holding a slice, from
+// the slices stored into it.
+func (c *closureCollector) containerStores(x ssa.Value) {
+	if x.Referrers() == nil {
+		return
+	}
+	for _, ref := range *x.Referrers() {
+		switch r := ref.(type) {
+		case *ssa.IndexAddr:
+			c.stores(r)
+		case *ssa.Slice:
+			if r.Referrers() == nil {
+				continue
+			}
+			for _, sref := range *r.Referrers() {
+				if ia, ok := sref.(*ssa.IndexAddr); ok {
+					c.stores(ia)
+				}
+			}
+		case *ssa.MapUpdate:
+			if r.Map == x {
+				c.value(r.Value)
+			}
+		case *ssa.Store:
+			if r.Addr == x {
+				c.elems(r.Val)
+			}
+		}
+	}
+}
@@ -0,0 +1,68 @@
+package tracer
+
+import (
+	"golang.org/x/tools/go/ssa"
+)
+
+// SpawnedLiteral returns the function literal that g spawns when g is its only
+// use, as in
+//
+//	go func(d *gorm.DB) { d.Find(nil) }(q)
+//
+// The literal's parameters are then bound to g's arguments like captured
+// variables to their MakeClosure bindings, so the goroutine body's d is the
+// caller's q (see goArgBinding). Returns nil for any other go statement, or
+// when the literal is also called, stored or passed elsewhere.
+func SpawnedLiteral(g *ssa.Go) *ssa.Function {
+	var fn *ssa.Function
+	switch v := g.Call.Value.(type) {
+	case *ssa.Function:
+		fn = v
+	case *ssa.MakeClosure:
+		fn, _ = v.Fn.(*ssa.Function)
+	}
+	if fn == nil || fn.Parent() == nil || spawningGo(fn) != g {
+		return nil
+	}
+	return fn
+}
+
+// spawningGo returns the go statement that is the only use of the function
+// literal fn, directly or through its MakeClosure, or nil.
+func spawningGo(fn *ssa.Function) *ssa.Go {
+	value := ssa.Value(fn)
+	refs := fn.Referrers()
+	if refs != nil && len(*refs) == 1 {
+		if mc, ok := (*refs)[0].(*ssa.MakeClosure); ok && mc.Fn == ssa.Value(fn) {
+			value, refs = mc, mc.Referrers()
+		}
+	}
+	if refs == nil || len(*refs) != 1 {
+		return nil
+	}
+	g, ok := (*refs)[0].(*ssa.Go)
+	if !ok || g.Call.Value != value {
+		return nil
+	}
+	return g
+}
+
+// goArgBinding resolves the argument bound to p when p's function is a literal
+// spawned by a go statement (see SpawnedLiteral). Returns nil otherwise, so p
+// stays a root of its own.
+func goArgBinding(p *ssa.Parameter) ssa.Value {
+	fn := p.Parent()
+	if fn == nil || fn.Parent() == nil {
+		return nil
+	}
+	g := spawningGo(fn)
+	if g == nil {
+		return nil
+	}
+	for i, param := range fn.Params {
+		if param == p && i < len(g.Call.Args) {
+			return g.Call.Args[i]
+		}
+	}
+	return nil
+}
@@ -0,0 +1,114 @@
+package tracer
+
+import (
+	"go/constant"
+	"go/token"
+
+	"golang.org/x/tools/go/ssa"
+)
+
+// constKeyMapStore returns the single MapUpdate of a local constant-key map.
+//
+// A map qualifies when it is created in the function (MakeMap) and is only
+// written once and read back, both with constant keys:
+//
+//	m := map[string]*gorm.DB{}   // t0 = make map[string]*gorm.DB
+//	m["k"] = q                   // t0["k"] = q        (the only MapUpdate)
+//	m["k"].Find(nil)             // t1 = t0["k"]       (Lookup)
+//	m["k"].Count(nil)            // t2 = t0["k"]       (Lookup)
+//
+// Such a map is just another name for q, like a local variable. Any other use
+// of the map (passing it on, ranging over it, a second store, a variable key,
+// no read at all) keeps the conservative "storing in a map pollutes" rule.
+func constKeyMapStore(m ssa.Value) *ssa.MapUpdate {
+	mm, ok := m.(*ssa.MakeMap)
+	if !ok || mm.Referrers() == nil {
+		return nil
+	}
+
+	var store *ssa.MapUpdate
+	lookups := 0
+	for _, ref := range *mm.Referrers() {
+		switch r := ref.(type) {
+		case *ssa.MapUpdate:
+			if store != nil || r.Map != mm || constKey(r.Key) == nil {
+				return nil
+			}
+			store = r
+		case *ssa.Lookup:
+			if r.X != mm || constKey(r.Index) == nil {
+				return nil
+			}
+			lookups++
+		default:
+			return nil
+		}
+	}
+	if lookups == 0 {
+		return nil
+	}
+	return store
+}
+
+// constKey returns the constant value of a map key, or nil when the key is
+// not a non-nil constant.
+func constKey(v ssa.Value) constant.Value {
+	if c, ok := v.(*ssa.Const); ok {
+		return c.Value
+	}
+	return nil
+}
+
+// mapLookupValue returns the value a Lookup of a local constant-key map reads:
+// the stored value when the keys match, nil otherwise (a missing key reads the
+// zero value, which is not part of any chain).
+func mapLookupValue(lookup *ssa.Lookup) ssa.Value {
+	store := constKeyMapStore(lookup.X)
+	if store == nil {
+		return nil
+	}
+	if !constant.Compare(constKey(store.Key), token.EQL, constKey(lookup.Index)) {
+		return nil
+	}
+	return store.Value
+}
+
+// IsConstKeyMapStore reports whether mu writes into a local constant-key map
+// (see constKeyMapStore). Lookups of such a map are traced back to the stored
+// value, so the store is an assignment rather than a pollution source.
+func IsConstKeyMapStore(mu *ssa.MapUpdate) bool {
+	return constKeyMapStore(mu.Map) == mu
+}
+
+// mapPointerValues returns the addresses a Lookup of a local map of pointers,
+// such as a map[string]**gorm.DB, may read:
+//
+//	m := map[string]**gorm.DB{}  // t1 = make map[string]**gorm.DB
+//	m["q"] = &q                  // t1["q"] = t0   (t0 = new *gorm.DB (q))
+//	m["r"] = &r
+//	(*m["q"]).Find(nil)          // t3 = t1["q"]; t4 = *t3
+//
+// A constant key reads the stores with an equal key and those with a variable
+// key, which may be equal too; a variable key reads every store. Unlike
+// constKeyMapStore, the map may be written any number of times: the result is
+// the candidates, in instruction order, that a dereference of the read address
+// is traced through. ok is false when lookup does not read a map made in the
+// function, or reads it with the comma-ok form.
+func mapPointerValues(lookup *ssa.Lookup) (vals []ssa.Value, ok bool) {
+	mm, isMake := lookup.X.(*ssa.MakeMap)
+	if !isMake || lookup.CommaOk || mm.Referrers() == nil {
+		return nil, false
+	}
+	key := constKey(lookup.Index)
+	for _, ref := range *mm.Referrers() {
+		mu, isUpdate := ref.(*ssa.MapUpdate)
+		if !isUpdate || mu.Map != mm {
+			continue
+		}
+		if k := constKey(mu.Key); key != nil && k != nil && !constant.Compare(k, token.EQL, key) {
+			continue
+		}
+		vals = append(vals, mu.Value)
+	}
+	return vals, true
+}
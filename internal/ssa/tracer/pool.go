@@ -0,0 +1,82 @@
+package tracer
+
+import (
+	"golang.org/x/tools/go/ssa"
+
+	"github.com/mpyw/gormreuse/internal/typeutil"
+)
+
+// isPoolMethod reports whether c is a static call of (*sync.Pool).name.
+func isPoolMethod(c *ssa.CallCommon, name string) bool {
+	callee := c.StaticCallee()
+	if callee == nil || callee.Name() != name || callee.Signature.Recv() == nil {
+		return false
+	}
+	return callee.Pkg != nil && callee.Pkg.Pkg.Path() == "sync" &&
+		callee.Signature.Recv().Type().String() == "*sync.Pool"
+}
+
+// poolPuts returns the (*sync.Pool).Put calls on pool in fn, in instruction
+// order. Pools are matched by SSA value, so only a pool referred to by the same
+// local variable or parameter within one function is paired.
+func poolPuts(fn *ssa.Function, pool ssa.Value) []*ssa.Call {
+	var puts []*ssa.Call
+	for _, b := range fn.Blocks {
+		for _, instr := range b.Instrs {
+			if call, ok := instr.(*ssa.Call); ok && isPoolMethod(&call.Call, "Put") && call.Call.Args[0] == pool {
+				puts = append(puts, call)
+			}
+		}
+	}
+	return puts
+}
+
+// poolGetPutValues returns the *gorm.DB values Put into the pool that the Get
+// call x reads from, within the same function:
+//
+//	pool.Put(q)                  // Put(t0, make any <- *gorm.DB (q))
+//	q2 := pool.Get().(*gorm.DB)  // typeassert Get(t0).(*gorm.DB)
+//
+// q2 is the q that was pooled, so it is traced back to q like the plain
+// interface{} round trip handled by the MakeInterface/TypeAssert cases. A Get
+// from a pool that is not Put into here (e.g. a package-level pool filled
+// elsewhere) returns nil and stays untracked.
+func poolGetPutValues(x ssa.Value, gormTypes *typeutil.Matcher) []ssa.Value {
+	get, ok := x.(*ssa.Call)
+	if !ok || !isPoolMethod(&get.Call, "Get") || get.Parent() == nil {
+		return nil
+	}
+	var vals []ssa.Value
+	for _, put := range poolPuts(get.Parent(), get.Call.Args[0]) {
+		if mi, ok := put.Call.Args[1].(*ssa.MakeInterface); ok && gormTypes.IsGormDB(mi.X.Type()) {
+			vals = append(vals, mi.X)
+		}
+	}
+	return vals
+}
+
+// IsPoolTransfer reports whether put is a (*sync.Pool).Put whose value is read
+// back in the same function by a Get asserted to *gorm.DB. Such a Put hands the
+// value over rather than using it: uses of the extracted value are traced to
+// the pooled root (see poolGetPutValues), so the Put itself does not pollute.
+func IsPoolTransfer(put *ssa.Call, gormTypes *typeutil.Matcher) bool {
+	if !isPoolMethod(&put.Call, "Put") || put.Parent() == nil {
+		return false
+	}
+	pool := put.Call.Args[0]
+	if pool.Referrers() == nil {
+		return false
+	}
+	for _, ref := range *pool.Referrers() {
+		get, ok := ref.(*ssa.Call)
+		if !ok || !isPoolMethod(&get.Call, "Get") || get.Call.Args[0] != pool || get.Referrers() == nil {
+			continue
+		}
+		for _, use := range *get.Referrers() {
+			if ta, ok := use.(*ssa.TypeAssert); ok && gormTypes.IsGormDB(ta.AssertedType) {
+				return true
+			}
+		}
+	}
+	return false
+}
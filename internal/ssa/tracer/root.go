@@ -46,6 +46,7 @@
 //	│  *ssa.UnOp (deref)      │  Trace the pointer being dereferenced      │
 //	│  *ssa.Alloc             │  Find Store instructions to this alloc     │
 //	│  *ssa.FreeVar           │  Find binding in parent's MakeClosure      │
+//	│  *ssa.Parameter (go)    │  Find argument of the spawning go stmt     │
 //	│  *ssa.FieldAddr         │  Find Store to this field                  │
 //	│  *ssa.Parameter         │  ROOT - a *gorm.DB parameter is mutable by │
 //	│                         │  default (caller may pass clone==0, #61)   │
@@ -62,6 +63,9 @@ package tracer
 import (
 	"go/token"
 	"go/types"
+	"slices"
+	"strings"
+	"sync"
 
 	"golang.org/x/tools/go/ssa"
 
@@ -96,13 +100,24 @@ import (
 //
 // Note: User-defined pure functions (//gormreuse:pure) are NOT immutable sources.
 // They may return mutable values - only builtin pure methods guarantee immutable returns.
+//
+// A RootTracer is safe for concurrent use: its only mutable state, the store
+// index cache, is guarded by a mutex. A tracer given a TraceSink is the
+// exception (see SetTraceSink).
 type RootTracer struct {
-	pureFuncs            *directive.DirectiveFuncSet // User-defined pure functions
-	immutableReturnFuncs *directive.DirectiveFuncSet // Functions returning immutable *gorm.DB
-	immutableParamFuncs  *directive.DirectiveFuncSet // Functions whose *gorm.DB params are immutable (opt out of Phase 1b)
-	failedPure           map[*ssa.Function]bool      // Pure functions that FAILED contract validation
-	scopesCallbacks      map[*ssa.Function]bool      // Scopes/Preload callbacks (params are mutable roots)
-	immutableCallbacks   map[*ssa.Function]bool      // Transaction/Connection/FindInBatches callbacks (fresh tx)
+	pureFuncs            *directive.DirectiveFuncSet   // User-defined pure functions
+	immutableReturnFuncs *directive.DirectiveFuncSet   // Functions returning immutable *gorm.DB
+	immutableParamFuncs  *directive.DirectiveFuncSet   // Functions whose *gorm.DB params are immutable (opt out of Phase 1b)
+	finisherFuncs        *directive.DirectiveFuncSet   // Custom finishers: terminal uses of their *gorm.DB receiver
+	sinkFuncs            *directive.DirectiveFuncSet   // Sinks: intentionally consume their *gorm.DB argument
+	failedPure           map[*ssa.Function]bool        // Pure functions that FAILED contract validation
+	scopesCallbacks      map[*ssa.Function]bool        // Scopes/Preload callbacks (params are mutable roots)
+	immutableCallbacks   map[*ssa.Function]bool        // Transaction/Connection/FindInBatches callbacks (fresh tx)
+	storeIndexesMu       sync.Mutex                    // Guards storeIndexes
+	storeIndexes         map[*ssa.Function]*storeIndex // Lazily built Store lookups per function
+	gormTypes            *typeutil.Matcher             // Configured DB types (nil: gorm.io/gorm.DB only)
+	sink                 TraceSink                     // Records the steps of each trace (-trace-value); nil by default
+	step                 string                        // Label of the next value traced, for sink
 }
 
 // New creates a new RootTracer.
@@ -122,15 +137,67 @@ type RootTracer struct {
 // (issue #61). immutableCallbacks lists function literals passed to gorm's
 // Transaction/Connection/FindInBatches, whose tx parameter is a fresh forkable
 // (clone>0) handle and is therefore immutable. Both may be nil.
-func New(pureFuncs, immutableReturnFuncs, immutableParamFuncs *directive.DirectiveFuncSet, failedPure, scopesCallbacks, immutableCallbacks map[*ssa.Function]bool) *RootTracer {
+//
+// finisherFuncs lists functions annotated //gormreuse:finisher, which consume
+// their *gorm.DB receiver like Find. sinkFuncs lists functions annotated
+// //gormreuse:sink, which are never trusted as pure. Both may be nil.
+//
+// gormTypes decides which types are traced as *gorm.DB (-gorm-type). It may be
+// nil, matching gorm.io/gorm.DB only.
+func New(pureFuncs, immutableReturnFuncs, immutableParamFuncs, finisherFuncs, sinkFuncs *directive.DirectiveFuncSet, failedPure, scopesCallbacks, immutableCallbacks map[*ssa.Function]bool, gormTypes *typeutil.Matcher) *RootTracer {
 	return &RootTracer{
 		pureFuncs:            pureFuncs,
 		immutableReturnFuncs: immutableReturnFuncs,
 		immutableParamFuncs:  immutableParamFuncs,
+		finisherFuncs:        finisherFuncs,
+		sinkFuncs:            sinkFuncs,
 		failedPure:           failedPure,
 		scopesCallbacks:      scopesCallbacks,
 		immutableCallbacks:   immutableCallbacks,
+		gormTypes:            gormTypes,
+	}
+}
+
+// GormTypes returns the configured DB type matcher, shared with the handlers
+// so they recognize the same *gorm.DB types as the tracer.
+func (t *RootTracer) GormTypes() *typeutil.Matcher {
+	return t.gormTypes
+}
+
+// IsGormDB checks if typ is *gorm.DB or a configured DB type.
+func (t *RootTracer) IsGormDB(typ types.Type) bool {
+	return t.gormTypes.IsGormDB(typ)
+}
+
+// GormMethod returns the method name and receiver of a call to a method of a
+// *gorm.DB-compatible type. Besides static calls, this covers interface method
+// calls (invoke mode) on a builder interface configured with -builder-type:
+//
+//	type Querier interface{ Where(string) Querier; Find(any) Querier }
+//	x.Find(nil)  // invoke x.Find: name "Find", receiver x
+//
+// and calls of a method expression, whose receiver is the first argument:
+//
+//	(*gorm.DB).Find(q, nil)  // name "Find", receiver q
+func GormMethod(c *ssa.CallCommon, gormTypes *typeutil.Matcher) (name string, recv ssa.Value, ok bool) {
+	if c.IsInvoke() {
+		if gormTypes.IsGormDB(c.Value.Type()) {
+			return c.Method.Name(), c.Value, true
+		}
+		return "", nil, false
+	}
+	callee := c.StaticCallee()
+	if method := ThunkMethod(callee); method != nil {
+		callee = method
 	}
+	if callee == nil || len(c.Args) == 0 {
+		return "", nil, false
+	}
+	sig := callee.Signature
+	if sig == nil || sig.Recv() == nil || !gormTypes.IsGormDB(sig.Recv().Type()) {
+		return "", nil, false
+	}
+	return callee.Name(), c.Args[0], true
 }
 
 // FindMutableRoot finds the mutable root for a receiver value.
@@ -172,6 +239,96 @@ func (t *RootTracer) FindAllMutableRoots(v ssa.Value, loopInfo *cfg.LoopInfo) []
 	return t.traceAll(v, make(map[ssa.Value]bool), loopInfo)
 }
 
+// BoundMethodOrigins returns the bound method values (MakeClosure of a
+// "$bound" method) a called function value may come from. It resolves through
+// Phi edges, skipping nil, and through loads of local variables:
+//
+//	var find func(dest interface{}, conds ...interface{}) *gorm.DB
+//	if cond {
+//	    find = q1.Find        // origin #1
+//	} else {
+//	    find = q2.Find        // origin #2
+//	}
+//	find(nil)                 // Phi node: both receivers are used
+func (t *RootTracer) BoundMethodOrigins(v ssa.Value) []*ssa.MakeClosure {
+	var origins []*ssa.MakeClosure
+	t.collectBoundMethods(v, make(map[ssa.Value]bool), &origins)
+	return origins
+}
+
+func (t *RootTracer) collectBoundMethods(v ssa.Value, visited map[ssa.Value]bool, origins *[]*ssa.MakeClosure) {
+	if v == nil || visited[v] {
+		return
+	}
+	visited[v] = true
+
+	switch val := v.(type) {
+	case *ssa.MakeClosure:
+		if strings.HasSuffix(val.Fn.Name(), "$bound") && len(val.Bindings) > 0 {
+			*origins = append(*origins, val)
+		}
+	case *ssa.Phi:
+		for _, edge := range val.Edges {
+			if !isNilConst(edge) {
+				t.collectBoundMethods(edge, visited, origins)
+			}
+		}
+	case *ssa.UnOp:
+		if alloc, ok := val.X.(*ssa.Alloc); ok && val.Op == token.MUL {
+			for _, stored := range t.allocStoredValues(alloc) {
+				t.collectBoundMethods(stored, visited, origins)
+			}
+		}
+	}
+}
+
+// BoundMethod returns the method that fn, a synthesized "$bound" wrapper of a
+// method value (f := r.query), calls, so the directives of the method apply to
+// calls of the method value. It returns nil for any other function, and for an
+// interface method value, which has no static callee.
+func BoundMethod(fn *ssa.Function) *ssa.Function {
+	if fn == nil || !strings.HasSuffix(fn.Name(), "$bound") {
+		return nil
+	}
+	for _, block := range fn.Blocks {
+		for _, instr := range block.Instrs {
+			if call, ok := instr.(*ssa.Call); ok {
+				return call.Call.StaticCallee()
+			}
+		}
+	}
+	return nil
+}
+
+// ThunkMethod returns the method that fn, a synthesized "$thunk" of a method
+// expression (f := (*gorm.DB).Find), calls. The thunk takes the receiver as
+// its first parameter, so f(q, nil) is the call q.Find(nil). It returns nil
+// for any other function, and for an interface method expression, which has
+// no static callee.
+func ThunkMethod(fn *ssa.Function) *ssa.Function {
+	if fn == nil || fn.Synthetic == "" || !strings.HasSuffix(fn.Name(), "$thunk") {
+		return nil
+	}
+	obj, ok := fn.Object().(*types.Func)
+	if !ok {
+		return nil
+	}
+	return fn.Prog.FuncValue(obj)
+}
+
+// underlying resolves fn to the method it wraps when it is a "$bound" wrapper
+// (see BoundMethod) or a "$thunk" (see ThunkMethod), and returns fn itself
+// otherwise.
+func underlying(fn *ssa.Function) *ssa.Function {
+	if method := BoundMethod(fn); method != nil {
+		return method
+	}
+	if method := ThunkMethod(fn); method != nil {
+		return method
+	}
+	return fn
+}
+
 // IsPureFunction checks if a function is marked as pure (doesn't pollute arguments).
 //
 // A function is pure if:
@@ -185,9 +342,15 @@ func (t *RootTracer) IsPureFunction(fn *ssa.Function) bool {
 	if fn == nil {
 		return false
 	}
+	fn = underlying(fn)
 	if t.IsImmutableReturningBuiltin(fn) {
 		return true
 	}
+	// A //gormreuse:sink consumes its argument by declaration, which overrides
+	// a //gormreuse:pure on the same function.
+	if t.IsSinkFunction(fn) {
+		return false
+	}
 	// A //gormreuse:pure function that failed its own contract validation is
 	// NOT trusted here: its callers must see the leak it hides.
 	if t.failedPure[fn] {
@@ -196,8 +359,85 @@ func (t *RootTracer) IsPureFunction(fn *ssa.Function) bool {
 	return t.pureFuncs.Contains(fn)
 }
 
+// IsPureDynamicCall reports whether a call without a static callee calls a
+// member marked //gormreuse:pure: an interface method, through the interface,
+// or a function-typed struct field, through the field. The directive asserts
+// that every implementation (every function stored in the field) is pure.
+//
+//	type QB interface {
+//	    //gormreuse:pure
+//	    Build(db *gorm.DB) *gorm.DB
+//	}
+//	qb.Build(q)  // does not pollute q
+func (t *RootTracer) IsPureDynamicCall(call *ssa.CallCommon) bool {
+	if t.pureFuncs == nil {
+		return false
+	}
+	if call.IsInvoke() {
+		recv := call.Method.Type().(*types.Signature).Recv()
+		named, _ := types.Unalias(recv.Type()).(*types.Named)
+		return t.pureFuncs.ContainsMember(named, call.Method)
+	}
+	named, field := funcField(call.Value)
+	return field != nil && t.pureFuncs.ContainsMember(named, field)
+}
+
+// funcField returns the struct field v loads, with the named struct type
+// declaring it, when v is a field of a struct value or a load through a
+// field address.
+func funcField(v ssa.Value) (*types.Named, *types.Var) {
+	var typ types.Type
+	var index int
+	switch v := v.(type) {
+	case *ssa.Field:
+		typ, index = v.X.Type(), v.Field
+	case *ssa.UnOp:
+		fa, ok := v.X.(*ssa.FieldAddr)
+		if !ok || v.Op != token.MUL {
+			return nil, nil
+		}
+		ptr, ok := types.Unalias(fa.X.Type()).(*types.Pointer)
+		if !ok {
+			return nil, nil
+		}
+		typ, index = ptr.Elem(), fa.Field
+	default:
+		return nil, nil
+	}
+	named, ok := types.Unalias(typ).(*types.Named)
+	if !ok {
+		return nil, nil
+	}
+	st, ok := named.Underlying().(*types.Struct)
+	if !ok {
+		return nil, nil
+	}
+	return named, st.Field(index)
+}
+
+// IsFinisherFunction checks if a function is marked with //gormreuse:finisher.
+// Calling a finisher is a terminal use of its *gorm.DB receiver: it pollutes the
+// root like Find, even when its result is assigned.
+func (t *RootTracer) IsFinisherFunction(fn *ssa.Function) bool {
+	if fn == nil || t.finisherFuncs == nil {
+		return false
+	}
+	return t.finisherFuncs.Contains(fn)
+}
+
+// IsSinkFunction checks if a function is marked with //gormreuse:sink.
+// Calling a sink pollutes its *gorm.DB arguments exactly like an unannotated
+// function; the directive only records that the consumption is intended.
+func (t *RootTracer) IsSinkFunction(fn *ssa.Function) bool {
+	if fn == nil || t.sinkFuncs == nil {
+		return false
+	}
+	return t.sinkFuncs.Contains(fn)
+}
+
 // IsImmutableReturningBuiltin checks if a function is a builtin method that returns immutable *gorm.DB.
-// Builtin methods (Session, WithContext, Debug, etc.) return immutable *gorm.DB.
+// Builtin methods (Session, WithContext, Debug, etc.) return immutable *gorm.DB,
+// as do the methods configured with -immutable-method (see typeutil.Matcher).
 // This is used for tracing - only builtin methods have immutable return values.
 //
 // The name lookup is gated on the function actually belonging to gorm.io/gorm
@@ -209,22 +449,22 @@ func (t *RootTracer) IsImmutableReturningBuiltin(fn *ssa.Function) bool {
 	if fn == nil {
 		return false
 	}
-	if !typeutil.IsImmutableReturningBuiltin(fn.Name()) {
+	if !t.gormTypes.IsImmutableReturningName(fn.Name()) {
 		return false
 	}
-	return isGormBuiltinFunc(fn)
+	return isGormBuiltinFunc(fn, t.gormTypes)
 }
 
 // isGormBuiltinFunc reports whether fn is genuinely defined by gorm.io/gorm:
 // either a method whose receiver is gorm.DB (Session, WithContext, Debug, Begin,
-// Transaction), or a package-level function in the gorm.io/gorm package
+// Transaction) rather than a builder type wrapping it, or a package-level function in the gorm.io/gorm package
 // (gorm.Open). User-defined functions that merely share a builtin name return false.
-func isGormBuiltinFunc(fn *ssa.Function) bool {
+func isGormBuiltinFunc(fn *ssa.Function, gormTypes *typeutil.Matcher) bool {
 	if sig := fn.Signature; sig != nil && sig.Recv() != nil {
-		return typeutil.IsGormDB(sig.Recv().Type())
+		return gormTypes.IsGormDB(sig.Recv().Type()) && !gormTypes.IsBuilder(sig.Recv().Type())
 	}
 	if obj := fn.Object(); obj != nil {
-		return typeutil.IsGormPackage(obj.Pkg())
+		return gormTypes.IsGormPackage(obj.Pkg())
 	}
 	return false
 }
@@ -260,11 +500,34 @@ func isGormBuiltinFunc(fn *ssa.Function) bool {
 //	│ (Phi/UnOp/etc.)  │
 //	└──────────────────┘
 func (t *RootTracer) trace(v ssa.Value, visited map[ssa.Value]bool, loopInfo *cfg.LoopInfo) ssa.Value {
+	if t.sink == nil || v == nil {
+		return t.traceValue(v, visited, loopInfo)
+	}
+	t.enter(v, visited)
+	root := t.traceValue(v, visited, loopInfo)
+	if root == nil {
+		t.sink.Leave(nil)
+	} else {
+		t.sink.Leave([]ssa.Value{root})
+	}
+	return root
+}
+
+// traceValue is trace without reporting to the sink.
+func (t *RootTracer) traceValue(v ssa.Value, visited map[ssa.Value]bool, loopInfo *cfg.LoopInfo) ssa.Value {
 	if v == nil || visited[v] {
 		return nil
 	}
 	visited[v] = true
 
+	// A parameter of a literal spawned by a go statement is the argument the
+	// go statement binds to it, like a captured variable.
+	if p, ok := v.(*ssa.Parameter); ok {
+		if arg := goArgBinding(p); arg != nil {
+			return t.trace(arg, visited, loopInfo)
+		}
+	}
+
 	// Under Phase 1b a *gorm.DB parameter is a mutable root by default (a caller
 	// may pass a mid-chain clone==0 value that this function then branches). This
 	// must be checked BEFORE isImmutableSource, which still reports the exempt
@@ -301,6 +564,15 @@ func (t *RootTracer) trace(v ssa.Value, visited map[ssa.Value]bool, loopInfo *cf
 //	      │                        └─── Inner call is traced
 //	      └─── Outer IIFE call triggers traceIIFEReturns
 func (t *RootTracer) traceCall(call *ssa.Call, visited map[ssa.Value]bool, loopInfo *cfg.LoopInfo) ssa.Value {
+	// Handle method values (f := r.query; f())
+	if mc, ok := call.Call.Value.(*ssa.MakeClosure); ok && len(mc.Bindings) > 0 {
+		if fn, ok := mc.Fn.(*ssa.Function); ok {
+			if method := BoundMethod(fn); method != nil {
+				return t.traceBoundCall(call, mc, method, visited, loopInfo)
+			}
+		}
+	}
+
 	// Handle closures (IIFE)
 	if mc, ok := call.Call.Value.(*ssa.MakeClosure); ok {
 		if closureFn, ok := mc.Fn.(*ssa.Function); ok {
@@ -308,7 +580,7 @@ func (t *RootTracer) traceCall(call *ssa.Call, visited map[ssa.Value]bool, loopI
 				// If closure result is stored in a variable (Extract instruction),
 				// treat each call as independent root.
 				// Only trace through IIFE when result is directly chained.
-				if isClosureResultStored(call) {
+				if isClosureResultStored(call, t.gormTypes) {
 					return call
 				}
 				return root
@@ -316,19 +588,31 @@ func (t *RootTracer) traceCall(call *ssa.Call, visited map[ssa.Value]bool, loopI
 		}
 	}
 
+	// An interface method call on a builder interface is a gorm method call.
+	if _, _, ok := GormMethod(&call.Call, t.gormTypes); ok && call.Call.IsInvoke() {
+		return call
+	}
+
 	callee := call.Call.StaticCallee()
 	if callee == nil {
 		return nil
 	}
 
 	sig := callee.Signature
-	if sig == nil || sig.Recv() == nil || !typeutil.IsGormDB(sig.Recv().Type()) {
+	if sig == nil || sig.Recv() == nil || !t.gormTypes.IsGormDB(sig.Recv().Type()) {
 		// Not a gorm method - if it returns *gorm.DB, treat as root
-		if typeutil.IsGormDB(call.Type()) {
+		if t.gormTypes.IsGormDB(call.Type()) {
 			// Builtin or //gormreuse:immutable-return function returns immutable.
 			if t.returnsImmutable(callee) {
 				return nil
 			}
+			// A pure function returning its argument unchanged yields an
+			// alias of the argument, so the result shares its root.
+			if t.IsPureFunction(callee) {
+				if idx := identityParam(callee); idx >= 0 && idx < len(call.Call.Args) {
+					return t.trace(call.Call.Args[idx], visited, loopInfo)
+				}
+			}
 			// User-defined pure or non-pure function: treat call as mutable root
 			// (user-defined pure functions may return mutable values)
 			return call
@@ -342,6 +626,53 @@ func (t *RootTracer) traceCall(call *ssa.Call, visited map[ssa.Value]bool, loopI
 	return call
 }
 
+// traceTupleCall handles the *gorm.DB element x of the results of a function
+// returning several values, q, err := buildQuery(db). Like the result of a
+// function returning *gorm.DB alone, it is a mutable root unless the function
+// is //gormreuse:immutable-return; each element is a root of its own. ok is
+// false when call is not such a function, e.g. an IIFE or a gorm method.
+func (t *RootTracer) traceTupleCall(call *ssa.Call, x *ssa.Extract) (root ssa.Value, ok bool) {
+	callee := call.Call.StaticCallee()
+	if callee == nil || callee.Parent() != nil || !t.gormTypes.IsGormDB(x.Type()) {
+		return nil, false
+	}
+	if sig := callee.Signature; sig.Recv() != nil && t.gormTypes.IsGormDB(sig.Recv().Type()) {
+		return nil, false
+	}
+	if t.returnsImmutable(callee) {
+		return nil, true
+	}
+	return x, true
+}
+
+// traceBoundCall handles a call of a method value, whose receiver is bound in
+// mc and whose arguments follow it:
+//
+//	f := r.query  // MakeClosure(query$bound, [r])
+//	q := f()      // the call of the method value
+//
+// The call is classified by the method itself, like a direct r.query() call:
+// an immutable-return method (or builtin, q.Session) yields no root, a pure
+// method returning a parameter yields that argument's root, and any other
+// method returning *gorm.DB makes the call the root. The body of the $bound
+// wrapper is not traced, so the root is the call site rather than the
+// wrapper's synthesized call, shared by every call of the method value.
+func (t *RootTracer) traceBoundCall(call *ssa.Call, mc *ssa.MakeClosure, method *ssa.Function, visited map[ssa.Value]bool, loopInfo *cfg.LoopInfo) ssa.Value {
+	if !t.gormTypes.IsGormDB(call.Type()) || t.returnsImmutable(method) {
+		return nil
+	}
+	if t.IsPureFunction(method) {
+		// The method's receiver is its first parameter, bound in mc.
+		switch idx := identityParam(method); {
+		case idx == 0:
+			return t.trace(mc.Bindings[0], visited, loopInfo)
+		case idx > 0 && idx-1 < len(call.Call.Args):
+			return t.trace(call.Call.Args[idx-1], visited, loopInfo)
+		}
+	}
+	return call
+}
+
 // traceNonCall handles non-call SSA values during tracing.
 // Routes to specialized handlers based on the value type.
 func (t *RootTracer) traceNonCall(v ssa.Value, visited map[ssa.Value]bool, loopInfo *cfg.LoopInfo) ssa.Value {
@@ -358,7 +689,28 @@ func (t *RootTracer) traceNonCall(v ssa.Value, visited map[ssa.Value]bool, loopI
 		// ChangeType: type conversion (same underlying type)
 		return t.trace(val.X, visited, loopInfo)
 
+	case *ssa.ChangeInterface:
+		// ChangeInterface: an interface holding a boxed *gorm.DB assigned to
+		// another interface, var a any = f, keeps the same dynamic value.
+		return t.trace(val.X, visited, loopInfo)
+
+	case *ssa.Convert:
+		// Convert: a pointer round trip through unsafe.Pointer, such as
+		// (*MyDB)(unsafe.Pointer(q)), still refers to the same DB. A
+		// conversion from any other operand type, e.g. uintptr(unsafe.Pointer(q)),
+		// yields a value that is not a DB, so tracing stops there.
+		if t.isDBPointerLike(val.Type()) && t.isDBPointerLike(val.X.Type()) {
+			return t.trace(val.X, visited, loopInfo)
+		}
+		return nil
+
 	case *ssa.MakeInterface:
+		// MakeInterface boxing a wrapper into a builder interface (-builder-type),
+		// var x Querier = impl{db: q}: the wrapper is not tracked itself, so the
+		// interface value starts a new root here.
+		if t.gormTypes.IsBuilder(val.Type()) && !t.gormTypes.IsGormDB(val.X.Type()) {
+			return val
+		}
 		// MakeInterface: interface{}(x) boxing — trace through to the boxed value
 		// so a value stored in an interface{} and later extracted stays tracked.
 		return t.trace(val.X, visited, loopInfo)
@@ -367,18 +719,50 @@ func (t *RootTracer) traceNonCall(v ssa.Value, visited map[ssa.Value]bool, loopI
 		// TypeAssert: i.(*gorm.DB) extraction — trace through to the asserted
 		// operand. Combined with MakeInterface above, this keeps an interface
 		// round-trip (var i interface{} = q; q2 := i.(*gorm.DB)) connected to q.
+		// A sync.Pool round trip (pool.Put(q); pool.Get().(*gorm.DB)) in the
+		// same function is traced to the first pooled value with a root.
+		for _, put := range poolGetPutValues(val.X, t.gormTypes) {
+			if root := t.trace(put, cloneVisited(visited), loopInfo); root != nil {
+				return root
+			}
+		}
 		return t.trace(val.X, visited, loopInfo)
 
 	case *ssa.Extract:
 		// Extract: extract element from tuple (multi-return)
+		if call, ok := val.Tuple.(*ssa.Call); ok {
+			if root, ok := t.traceTupleCall(call, val); ok {
+				return root
+			}
+		}
 		return t.trace(val.Tuple, visited, loopInfo)
 
+	case *ssa.Lookup:
+		// Lookup: m["k"] on a local constant-key map — trace the stored value
+		return t.trace(mapLookupValue(val), visited, loopInfo)
+
+	case *ssa.Field:
+		// Field: repo{db: q}.db, a field read from a struct value rather than
+		// through its address — trace the value stored into the field
+		if vals := t.fieldValues(val); len(vals) > 0 {
+			return t.trace(vals[0], visited, loopInfo)
+		}
+		return nil
+
+	case *ssa.Index:
+		// Index: arr[0] on a loaded local array — trace the stored elements
+		vals, _ := indexValues(val)
+		return t.traceFirst(vals, visited, loopInfo)
+
 	case *ssa.FreeVar:
 		// FreeVar: captured variable in a closure
 		return t.traceFreeVar(val, visited, loopInfo)
 
 	case *ssa.Alloc:
 		// Alloc: local variable allocation
+		if isFreshGormDBAlloc(val, t.gormTypes) {
+			return nil
+		}
 		return t.traceAlloc(val, visited, loopInfo)
 
 	default:
@@ -386,6 +770,26 @@ func (t *RootTracer) traceNonCall(v ssa.Value, visited map[ssa.Value]bool, loopI
 	}
 }
 
+// isDBPointerLike reports whether typ can carry a *gorm.DB across a Convert:
+// unsafe.Pointer, or a pointer laid out like gorm.DB.
+func (t *RootTracer) isDBPointerLike(typ types.Type) bool {
+	if b, ok := typ.Underlying().(*types.Basic); ok {
+		return b.Kind() == types.UnsafePointer
+	}
+	return t.gormTypes.IsGormDBLayout(typ)
+}
+
+// isFreshGormDBAlloc reports whether alloc allocates a gorm.DB value itself
+// (new(gorm.DB), &gorm.DB{}, var d gorm.DB) rather than a *gorm.DB variable.
+//
+// Such a value is a fresh, empty DB — not part of any chain — so it is an
+// unknown root that is never linked to another chain, even when a struct copy
+// (*p = *q) is stored into it. Tracing the copy would tie every use of p to q
+// and report q's reuse through an unrelated value.
+func isFreshGormDBAlloc(alloc *ssa.Alloc, gormTypes *typeutil.Matcher) bool {
+	return gormTypes.IsGormDB(alloc.Type())
+}
+
 // isSwapPhiPair checks if two Phi nodes form a swap pattern.
 //
 // A swap pattern occurs when two Phi nodes in the same block have edges that are
@@ -556,8 +960,79 @@ func findSwapPhiSibling(phi *ssa.Phi) *ssa.Phi {
 	return nil
 }
 
-// isLoopVariableSwap checks if a Phi node is part of a loop variable swap pattern.
-// Returns the loop-header Phis if this is a swap, nil otherwise.
+// findPermutationPhis returns the group of Phi nodes in phi's block, phi
+// included, whose edges are permutations of each other's: on every incoming
+// edge, the group takes the same set of values, each in one of its Phis. A
+// rotation of three variables is such a group without any swap pair:
+//
+//	for _, item := range items {
+//	    if item%3 == 0 {
+//	        q1, q2, q3 = q3, q1, q2
+//	    }
+//	    ...
+//	}
+//
+//	Block 5 (after if, inside loop):
+//	  t20 = phi [no-rotate: t5, rotate: t7] #q1
+//	  t21 = phi [no-rotate: t6, rotate: t5] #q2
+//	  t22 = phi [no-rotate: t7, rotate: t6] #q3
+//
+// A swap is the group of two; its pair is returned as found by
+// findSwapPhiSibling even when other Phis of the block share its values.
+// Returns nil when phi belongs to no such group.
+func findPermutationPhis(phi *ssa.Phi) []*ssa.Phi {
+	if sibling := findSwapPhiSibling(phi); sibling != nil {
+		return []*ssa.Phi{phi, sibling}
+	}
+
+	// Grow the group with the Phis sharing a value with it.
+	group := []*ssa.Phi{phi}
+	values := make(map[ssa.Value]bool)
+	for _, edge := range phi.Edges {
+		values[edge] = true
+	}
+	for grown := true; grown; {
+		grown = false
+		for _, instr := range phi.Block().Instrs {
+			other, ok := instr.(*ssa.Phi)
+			if !ok || slices.Contains(group, other) || len(other.Edges) != len(phi.Edges) {
+				continue
+			}
+			if !slices.ContainsFunc(other.Edges, func(e ssa.Value) bool { return values[e] }) {
+				continue
+			}
+			group = append(group, other)
+			for _, edge := range other.Edges {
+				values[edge] = true
+			}
+			grown = true
+		}
+	}
+	if len(group) < 2 || len(values) != len(group) {
+		return nil
+	}
+
+	// Each edge must permute the values, and at least one must move them.
+	moved := false
+	for i := range phi.Edges {
+		seen := make(map[ssa.Value]bool, len(group))
+		for _, p := range group {
+			seen[p.Edges[i]] = true
+			moved = moved || p.Edges[i] != p.Edges[0]
+		}
+		if len(seen) != len(group) {
+			return nil
+		}
+	}
+	if !moved {
+		return nil
+	}
+	return group
+}
+
+// isLoopVariableSwap checks if a Phi node is part of a loop variable swap, or
+// more generally of a permutation of loop variables (see findPermutationPhis).
+// Returns the loop-header Phis of the whole group if so, nil otherwise.
 // This is a convenience function that combines swap detection checks.
 func isLoopVariableSwap(phi *ssa.Phi, loopInfo *cfg.LoopInfo) []*ssa.Phi {
 	if loopInfo == nil {
@@ -567,28 +1042,34 @@ func isLoopVariableSwap(phi *ssa.Phi, loopInfo *cfg.LoopInfo) []*ssa.Phi {
 	if loopInfo.IsLoopHeader(phi.Block()) {
 		return nil
 	}
-	// Must have a swap sibling
-	if findSwapPhiSibling(phi) == nil {
+	// Must permute its value with siblings
+	group := findPermutationPhis(phi)
+	if group == nil {
 		return nil
 	}
 	// Edges must all be loop-header phis
-	return getLoopHeaderPhiEdges(phi, loopInfo)
+	return getLoopHeaderPhiEdges(group, loopInfo)
 }
 
-// getLoopHeaderPhiEdges checks if all edges of a Phi are loop-header Phis.
-// Returns the list of loop-header Phis if all edges qualify, nil otherwise.
-func getLoopHeaderPhiEdges(phi *ssa.Phi, loopInfo *cfg.LoopInfo) []*ssa.Phi {
+// getLoopHeaderPhiEdges checks if all edges of a group of Phis are loop-header
+// Phis. Returns the distinct loop-header Phis, in edge order, if all edges
+// qualify, nil otherwise.
+func getLoopHeaderPhiEdges(group []*ssa.Phi, loopInfo *cfg.LoopInfo) []*ssa.Phi {
 	if loopInfo == nil {
 		return nil
 	}
 
 	var loopHeaderPhis []*ssa.Phi
-	for _, edge := range phi.Edges {
-		edgePhi, ok := edge.(*ssa.Phi)
-		if !ok || !loopInfo.IsLoopHeader(edgePhi.Block()) {
-			return nil
+	for _, phi := range group {
+		for _, edge := range phi.Edges {
+			edgePhi, ok := edge.(*ssa.Phi)
+			if !ok || !loopInfo.IsLoopHeader(edgePhi.Block()) {
+				return nil
+			}
+			if !slices.Contains(loopHeaderPhis, edgePhi) {
+				loopHeaderPhis = append(loopHeaderPhis, edgePhi)
+			}
 		}
-		loopHeaderPhis = append(loopHeaderPhis, edgePhi)
 	}
 	return loopHeaderPhis
 }
@@ -714,6 +1195,7 @@ func (t *RootTracer) collectInitialEdgeRoots(loopHeaderPhis []*ssa.Phi, swapPhi
 		if len(loopPhi.Edges) > 0 {
 			initialEdge := loopPhi.Edges[0]
 			if !isNilConst(initialEdge) && !visited[initialEdge] {
+				t.via("loop entry edge", -1)
 				roots = append(roots, t.traceAll(initialEdge, visited, loopInfo)...)
 			}
 		}
@@ -849,8 +1331,9 @@ func (t *RootTracer) tracePhi(phi *ssa.Phi, visited map[ssa.Value]bool, loopInfo
 	//
 	// We only apply special swap-phi handling if ALL conditions are met:
 	//   1. loopInfo != nil (we're inside a loop context)
-	//   2. findSwapPhiSibling(phi) != nil (this phi has a swap sibling)
-	//   3. getLoopHeaderPhiEdges(phi) != nil (edges are loop-header phis)
+	//   2. findPermutationPhis(phi) != nil (this phi swaps or rotates its
+	//      value with siblings)
+	//   3. getLoopHeaderPhiEdges(group) != nil (edges are loop-header phis)
 	//
 	// If any condition fails, we fall through to regular tracing.
 	//
@@ -874,10 +1357,11 @@ func (t *RootTracer) tracePhi(phi *ssa.Phi, visited map[ssa.Value]bool, loopInfo
 	//   - Different values merged: if { q = a } else { q = b }
 	//   - Conditional swaps outside loops
 	//
-	for _, edge := range phi.Edges {
+	for i, edge := range phi.Edges {
 		if isNilConst(edge) || visited[edge] {
 			continue
 		}
+		t.via("phi edge", i)
 		if root := t.trace(edge, visited, loopInfo); root != nil {
 			return root
 		}
@@ -901,11 +1385,42 @@ func (t *RootTracer) tracePointerLoad(ptr ssa.Value, visited map[ssa.Value]bool,
 		return t.traceAlloc(p, visited, loopInfo)
 	case *ssa.FieldAddr:
 		return t.traceFieldStore(p, visited, loopInfo)
+	case *ssa.IndexAddr:
+		if vals, ok := sliceElemValues(p.X, p.Index); ok {
+			return t.traceFirst(vals, visited, loopInfo)
+		}
+		return t.trace(ptr, visited, loopInfo)
+	case *ssa.Lookup:
+		if vals, ok := t.dbPointerMapValues(p); ok {
+			return t.traceFirst(vals, visited, loopInfo)
+		}
+		return t.trace(ptr, visited, loopInfo)
 	default:
 		return t.trace(ptr, visited, loopInfo)
 	}
 }
 
+// dbPointerMapValues returns the addresses of *gorm.DB variables a Lookup of
+// a local map of **gorm.DB may read (see mapPointerValues).
+func (t *RootTracer) dbPointerMapValues(lookup *ssa.Lookup) ([]ssa.Value, bool) {
+	ptr, ok := lookup.Type().Underlying().(*types.Pointer)
+	if !ok || !t.gormTypes.IsGormDB(ptr.Elem()) {
+		return nil, false
+	}
+	return mapPointerValues(lookup)
+}
+
+// traceFirst traces the candidate values an element of a local slice may hold
+// (see sliceElemValues) and returns the first root found.
+func (t *RootTracer) traceFirst(vals []ssa.Value, visited map[ssa.Value]bool, loopInfo *cfg.LoopInfo) ssa.Value {
+	for _, v := range vals {
+		if root := t.trace(v, cloneVisited(visited), loopInfo); root != nil {
+			return root
+		}
+	}
+	return nil
+}
+
 // traceFreeVar traces a captured variable in a closure back to its binding.
 //
 // When a closure captures a variable, SSA represents it as:
@@ -919,8 +1434,12 @@ func (t *RootTracer) tracePointerLoad(ptr ssa.Value, visited map[ssa.Value]bool,
 //	    t2 = FreeVar [0]           // References Bindings[0] from parent
 //
 // This function finds the MakeClosure in the parent and traces the binding.
+// The binding is traced like any other value, so a captured helper result
+// (r := helperWhere(db, "x")) binds the helper call itself, which traceCall
+// takes as the mutable root.
 func (t *RootTracer) traceFreeVar(fv *ssa.FreeVar, visited map[ssa.Value]bool, loopInfo *cfg.LoopInfo) ssa.Value {
 	if binding := t.freeVarBinding(fv); binding != nil {
+		t.via("free var binding", -1)
 		return t.trace(binding, visited, loopInfo)
 	}
 	return nil
@@ -983,25 +1502,61 @@ func (t *RootTracer) freeVarBinding(fv *ssa.FreeVar) ssa.Value {
 // This function finds the Store instruction that writes to the Alloc.
 func (t *RootTracer) traceAlloc(alloc *ssa.Alloc, visited map[ssa.Value]bool, loopInfo *cfg.LoopInfo) ssa.Value {
 	// Single-root: trace the first value stored into the Alloc.
-	if vals := allocStoredValues(alloc); len(vals) > 0 {
+	if vals := t.allocStoredValues(alloc); len(vals) > 0 {
+		t.via("alloc store", 0)
 		return t.trace(vals[0], visited, loopInfo)
 	}
 	return nil
 }
 
-// allocStoredValues returns, in program order, the values stored into alloc.
-// Shared by traceAlloc (first) and traceAllAllocStores (all).
-func allocStoredValues(alloc *ssa.Alloc) []ssa.Value {
+// allocStoredValues returns, in program order, the values stored into alloc,
+// followed by those stored through it by closures capturing it (see
+// capturedStoredValues). Shared by traceAlloc (first) and traceAllAllocStores
+// (all).
+func (t *RootTracer) allocStoredValues(alloc *ssa.Alloc) []ssa.Value {
 	fn := alloc.Parent()
 	if fn == nil {
 		return nil
 	}
+	vals := t.storeIndexFor(fn).allocs[alloc]
+	if captured := t.capturedStoredValues(alloc); len(captured) > 0 {
+		vals = append(append([]ssa.Value(nil), vals...), captured...)
+	}
+	return vals
+}
+
+// capturedStoredValues returns the values stored through v by the closures
+// that capture it, including closures nested in those:
+//
+//	var q *gorm.DB         // t0 = Alloc, bound to the closure below
+//	f := func() {
+//	    q = db.Where("x")  // Store FreeVar(q) ← the value returned here
+//	}
+//
+// It is the reverse of freeVarBinding: v is matched against the bindings of
+// the MakeClosure instructions that refer to it.
+func (t *RootTracer) capturedStoredValues(v ssa.Value) []ssa.Value {
+	refs := v.Referrers()
+	if refs == nil {
+		return nil
+	}
 	var vals []ssa.Value
-	for _, block := range fn.Blocks {
-		for _, instr := range block.Instrs {
-			if store, ok := instr.(*ssa.Store); ok && store.Addr == alloc {
-				vals = append(vals, store.Val)
+	for _, ref := range *refs {
+		mc, ok := ref.(*ssa.MakeClosure)
+		if !ok {
+			continue
+		}
+		closureFn, ok := mc.Fn.(*ssa.Function)
+		if !ok {
+			continue
+		}
+		for i, binding := range mc.Bindings {
+			if binding != v || i >= len(closureFn.FreeVars) {
+				continue
 			}
+			fv := closureFn.FreeVars[i]
+			vals = append(vals, t.storeIndexFor(closureFn).freeVars[fv]...)
+			vals = append(vals, t.capturedStoredValues(fv)...)
 		}
 	}
 	return vals
@@ -1023,7 +1578,7 @@ func allocStoredValues(alloc *ssa.Alloc) []ssa.Value {
 // This function finds the Store that wrote to the same field.
 func (t *RootTracer) traceFieldStore(fa *ssa.FieldAddr, visited map[ssa.Value]bool, loopInfo *cfg.LoopInfo) ssa.Value {
 	// Single-root: trace the first value stored into the same field.
-	if vals := fieldStoredValues(fa); len(vals) > 0 {
+	if vals := t.fieldStoredValues(fa); len(vals) > 0 {
 		return t.trace(vals[0], visited, loopInfo)
 	}
 	return nil
@@ -1032,26 +1587,143 @@ func (t *RootTracer) traceFieldStore(fa *ssa.FieldAddr, visited map[ssa.Value]bo
 // fieldStoredValues returns, in program order, the values stored into the same
 // struct field as fa (matched by base value and field index). Shared by
 // traceFieldStore (first) and traceAllFieldStores (all).
-func fieldStoredValues(fa *ssa.FieldAddr) []ssa.Value {
-	fn := fa.Parent()
-	if fn == nil {
+//
+// The base is matched through its aliases (see fieldBaseAliases), so a field
+// read through a dereferenced or copied struct pointer resolves to the stores
+// made through the original pointer:
+//
+//	h := &holder{db: q}
+//	hp := &h
+//	(*hp).db.Count(nil)   // resolves to q
+func (t *RootTracer) fieldStoredValues(fa *ssa.FieldAddr) []ssa.Value {
+	return t.storedFieldValues(fa.X, fa.Field, fa.Parent())
+}
+
+// storedFieldValues returns the values stored into field of the struct x
+// points to, through any alias of x (see fieldBaseAliases). parent is the
+// function searched for a base without a parent of its own.
+//
+// x may itself address a struct nested in another, as the field promoted
+// from an embedded struct is: the stores are matched by the path from the
+// outermost struct (see fieldKey), whose aliases are followed.
+func (t *RootTracer) storedFieldValues(x ssa.Value, field int, parent *ssa.Function) []ssa.Value {
+	return t.storedFieldValuesVisited(x, field, parent, make(map[ssa.Value]bool))
+}
+
+func (t *RootTracer) storedFieldValuesVisited(x ssa.Value, field int, parent *ssa.Function, visited map[ssa.Value]bool) []ssa.Value {
+	x, path := fieldPath(x)
+	var vals []ssa.Value
+	for _, base := range t.fieldBaseAliases(x, visited) {
+		fn := base.Parent()
+		if fn == nil {
+			fn = parent
+		}
+		if fn == nil {
+			continue
+		}
+		vals = append(vals, t.storeIndexFor(fn).fields[fieldKey{base: base, path: path, field: field}]...)
+	}
+	return vals
+}
+
+// fieldValues returns the values stored into the field a Field instruction
+// reads from a struct value. The SSA builder reads a field by value when the
+// struct is not addressable, such as a composite literal used in place, which
+// it loads from the local it builds the literal in. The field stores into that
+// local are found like those of a FieldAddr:
+//
+//	d := repo{db: q}.db  // t4 = *t2; t5 = t4.db → q
+//
+// A struct of unknown origin, such as a parameter or a call result, yields
+// nothing, like a field nothing is stored into. So do a map element and a
+// struct extracted from an interface: storing the struct there is already a
+// use of its *gorm.DB fields.
+func (t *RootTracer) fieldValues(f *ssa.Field) []ssa.Value {
+	load, ok := f.X.(*ssa.UnOp)
+	if !ok || load.Op != token.MUL {
 		return nil
 	}
-	var vals []ssa.Value
-	for _, block := range fn.Blocks {
-		for _, instr := range block.Instrs {
-			store, ok := instr.(*ssa.Store)
-			if !ok {
-				continue
-			}
-			storeFA, ok := store.Addr.(*ssa.FieldAddr)
-			if !ok || storeFA.X != fa.X || storeFA.Field != fa.Field {
-				continue
+	return t.storedFieldValues(load.X, f.Field, f.Parent())
+}
+
+// StructFieldDBs returns the *gorm.DB values stored into the fields of the
+// struct v holds or points to, so a struct handed to another goroutine or
+// deferred call can be traced to the DBs it carries:
+//
+//	go handle(reqCtx{ctx: ctx, db: q})  // returns [q]
+//	go handle(&reqCtx{db: q})           // returns [q]
+//
+// Only direct fields of a struct built in the enclosing function are found.
+func (t *RootTracer) StructFieldDBs(v ssa.Value) []ssa.Value {
+	base := v
+	if load, ok := v.(*ssa.UnOp); ok && load.Op == token.MUL {
+		base = load.X
+	}
+	ptr, ok := base.Type().Underlying().(*types.Pointer)
+	if !ok {
+		return nil
+	}
+	st, ok := ptr.Elem().Underlying().(*types.Struct)
+	if !ok {
+		return nil
+	}
+	var dbs []ssa.Value
+	for i := 0; i < st.NumFields(); i++ {
+		if !t.gormTypes.IsGormDB(st.Field(i).Type()) {
+			continue
+		}
+		dbs = append(dbs, t.storedFieldValues(base, i, nil)...)
+	}
+	return dbs
+}
+
+// fieldBaseAliases returns x followed by the struct pointers x aliases:
+//
+//	t5 = *t2            // load of a pointer variable → values stored into t2
+//	t0 = *h             // load of a captured variable → the parent's binding
+//	t3 = *t1            // load of an embedded *Base, t1 = &r.Base → values stored into r.Base
+//	v := *h             // Alloc initialized with a struct copy → h
+//
+// The resolution is flow-insensitive, like the rest of field tracing.
+func (t *RootTracer) fieldBaseAliases(x ssa.Value, visited map[ssa.Value]bool) []ssa.Value {
+	if x == nil || visited[x] {
+		return nil
+	}
+	visited[x] = true
+
+	aliases := []ssa.Value{x}
+	switch v := x.(type) {
+	case *ssa.UnOp:
+		if v.Op != token.MUL {
+			break
+		}
+		ptrs := t.pointerStoredValues(v.X)
+		if fa, ok := v.X.(*ssa.FieldAddr); ok {
+			ptrs = t.storedFieldValuesVisited(fa.X, fa.Field, fa.Parent(), visited)
+		}
+		for _, ptr := range ptrs {
+			aliases = append(aliases, t.fieldBaseAliases(ptr, visited)...)
+		}
+	case *ssa.Alloc:
+		for _, val := range t.allocStoredValues(v) {
+			if load, ok := val.(*ssa.UnOp); ok && load.Op == token.MUL {
+				aliases = append(aliases, t.fieldBaseAliases(load.X, visited)...)
 			}
-			vals = append(vals, store.Val)
 		}
 	}
-	return vals
+	return aliases
+}
+
+// pointerStoredValues returns the values stored into the variable ptr points
+// to, following a captured variable back to its binding in the parent.
+func (t *RootTracer) pointerStoredValues(ptr ssa.Value) []ssa.Value {
+	if fv, ok := ptr.(*ssa.FreeVar); ok {
+		ptr = t.freeVarBinding(fv)
+	}
+	if alloc, ok := ptr.(*ssa.Alloc); ok {
+		return t.allocStoredValues(alloc)
+	}
+	return nil
 }
 
 // traceIIFEReturns traces through an immediately invoked function expression.
@@ -1071,7 +1743,7 @@ func (t *RootTracer) traceIIFEReturns(fn *ssa.Function, visited map[ssa.Value]bo
 		return nil
 	}
 	results := fn.Signature.Results()
-	if results == nil || results.Len() == 0 || !typeutil.IsGormDB(results.At(0).Type()) {
+	if results == nil || results.Len() == 0 || !t.gormTypes.IsGormDB(results.At(0).Type()) {
 		return nil
 	}
 
@@ -1122,7 +1794,7 @@ func (t *RootTracer) traceAllIIFEReturns(fn *ssa.Function, visited map[ssa.Value
 		return nil
 	}
 	results := fn.Signature.Results()
-	if results == nil || results.Len() == 0 || !typeutil.IsGormDB(results.At(0).Type()) {
+	if results == nil || results.Len() == 0 || !t.gormTypes.IsGormDB(results.At(0).Type()) {
 		return nil
 	}
 
@@ -1155,6 +1827,17 @@ func (t *RootTracer) traceAllIIFEReturns(fn *ssa.Function, visited map[ssa.Value
 //	q.Find(nil)            // Phi has edges from both branches
 //	                       // Need to check pollution of BOTH roots
 func (t *RootTracer) traceAll(v ssa.Value, visited map[ssa.Value]bool, loopInfo *cfg.LoopInfo) []ssa.Value {
+	if t.sink == nil || v == nil {
+		return t.traceAllValue(v, visited, loopInfo)
+	}
+	t.enter(v, visited)
+	roots := t.traceAllValue(v, visited, loopInfo)
+	t.sink.Leave(roots)
+	return roots
+}
+
+// traceAllValue is traceAll without reporting to the sink.
+func (t *RootTracer) traceAllValue(v ssa.Value, visited map[ssa.Value]bool, loopInfo *cfg.LoopInfo) []ssa.Value {
 	if v == nil || visited[v] {
 		return nil
 	}
@@ -1163,8 +1846,13 @@ func (t *RootTracer) traceAll(v ssa.Value, visited map[ssa.Value]bool, loopInfo
 	// A *gorm.DB parameter is a mutable root by default (Phase 1b, #61), except
 	// the exempt cases; keep this consistent with trace()/FindMutableRoot so
 	// Phi-based pollution checks see it too.
-	if p, ok := v.(*ssa.Parameter); ok && t.isMutableParam(p) {
-		return []ssa.Value{p}
+	if p, ok := v.(*ssa.Parameter); ok {
+		if arg := goArgBinding(p); arg != nil {
+			return t.traceAll(arg, visited, loopInfo)
+		}
+		if t.isMutableParam(p) {
+			return []ssa.Value{p}
+		}
 	}
 
 	switch val := v.(type) {
@@ -1249,35 +1937,54 @@ func (t *RootTracer) traceAll(v ssa.Value, visited map[ssa.Value]bool, loopInfo
 		if val.Op == token.MUL {
 			return t.traceAllPointerLoads(val.X, visited, loopInfo)
 		}
+		if isReceived(val) {
+			return nil
+		}
 		return t.traceAll(val.X, visited, loopInfo)
 
 	case *ssa.Alloc:
+		if isFreshGormDBAlloc(val, t.gormTypes) {
+			return nil
+		}
 		return t.traceAllAllocStores(val, visited, loopInfo)
 
 	case *ssa.FreeVar:
 		return t.traceAllFreeVar(val, visited, loopInfo)
 
+	case *ssa.Index:
+		vals, _ := indexValues(val)
+		return t.traceAllValues(vals, visited, loopInfo)
+
+	case *ssa.Field:
+		return t.traceAllValues(t.fieldValues(val), visited, loopInfo)
+
 	case *ssa.Call:
 		// Handle closure calls (IIFE) - collect ALL roots from all returns
 		if mc, ok := val.Call.Value.(*ssa.MakeClosure); ok {
 			if closureFn, ok := mc.Fn.(*ssa.Function); ok {
 				if roots := t.traceAllIIFEReturns(closureFn, visited, loopInfo); len(roots) > 0 {
 					// If closure result is stored, treat call itself as root
-					if isClosureResultStored(val) {
+					if isClosureResultStored(val, t.gormTypes) {
 						return []ssa.Value{val}
 					}
 					return roots
 				}
 			}
 		}
+		// A builtin (WithContext, Session, ...) or //gormreuse:immutable-return
+		// call is an immutable source, as in trace(), even in mid-chain.
+		if t.returnsImmutable(val.Call.StaticCallee()) {
+			return nil
+		}
 		// Non-closure call - treat as potential root
-		if typeutil.IsGormDB(val.Type()) {
+		if t.gormTypes.IsGormDB(val.Type()) {
 			return []ssa.Value{val}
 		}
 		return nil
 
 	default:
-		// For non-special cases (ChangeType, Extract, etc.), delegate to single-root trace.
+		// For non-special cases (ChangeType, ChangeInterface, Convert, Extract,
+		// etc.), delegate to single-root trace.
 		// Clone visited and remove v to allow trace() to process it.
 		// This is safe because trace() doesn't call back to traceAll(), so no cycle risk.
 		freshVisited := cloneVisited(visited)
@@ -1295,6 +2002,17 @@ func (t *RootTracer) traceAllPointerLoads(ptr ssa.Value, visited map[ssa.Value]b
 		return t.traceAllAllocStores(p, visited, loopInfo)
 	case *ssa.FieldAddr:
 		return t.traceAllFieldStores(p, visited, loopInfo)
+	case *ssa.IndexAddr:
+		vals, ok := sliceElemValues(p.X, p.Index)
+		if !ok {
+			return t.traceAll(ptr, visited, loopInfo)
+		}
+		return t.traceAllValues(vals, visited, loopInfo)
+	case *ssa.Lookup:
+		if vals, ok := t.dbPointerMapValues(p); ok {
+			return t.traceAllValues(vals, visited, loopInfo)
+		}
+		return t.traceAll(ptr, visited, loopInfo)
 	case *ssa.Phi:
 		// Check for loop variable swap pattern
 		if loopHeaderPhis := isLoopVariableSwap(p, loopInfo); loopHeaderPhis != nil {
@@ -1313,10 +2031,11 @@ func (t *RootTracer) traceAllPointerLoads(ptr ssa.Value, visited map[ssa.Value]b
 // the Phi cases of traceAll and traceAllPointerLoads.
 func (t *RootTracer) traceAllPhiEdges(phi *ssa.Phi, visited map[ssa.Value]bool, loopInfo *cfg.LoopInfo) []ssa.Value {
 	var roots []ssa.Value
-	for _, edge := range phi.Edges {
+	for i, edge := range phi.Edges {
 		if isNilConst(edge) || visited[edge] {
 			continue
 		}
+		t.via("phi edge", i)
 		roots = append(roots, t.traceAll(edge, visited, loopInfo)...)
 	}
 	return roots
@@ -1324,7 +2043,8 @@ func (t *RootTracer) traceAllPhiEdges(phi *ssa.Phi, visited map[ssa.Value]bool,
 
 func (t *RootTracer) traceAllAllocStores(alloc *ssa.Alloc, visited map[ssa.Value]bool, loopInfo *cfg.LoopInfo) []ssa.Value {
 	var roots []ssa.Value
-	for _, v := range allocStoredValues(alloc) {
+	for i, v := range t.allocStoredValues(alloc) {
+		t.via("alloc store", i)
 		roots = append(roots, t.traceAll(v, visited, loopInfo)...)
 	}
 	return roots
@@ -1344,7 +2064,17 @@ func (t *RootTracer) traceAllAllocStores(alloc *ssa.Alloc, visited map[ssa.Value
 //	h.db.Find(nil)  // Need to check BOTH q1 and q2
 func (t *RootTracer) traceAllFieldStores(fa *ssa.FieldAddr, visited map[ssa.Value]bool, loopInfo *cfg.LoopInfo) []ssa.Value {
 	var roots []ssa.Value
-	for _, v := range fieldStoredValues(fa) {
+	for _, v := range t.fieldStoredValues(fa) {
+		roots = append(roots, t.traceAll(v, visited, loopInfo)...)
+	}
+	return roots
+}
+
+// traceAllValues collects the roots of every value an element of a local
+// slice may hold (see sliceElemValues).
+func (t *RootTracer) traceAllValues(vals []ssa.Value, visited map[ssa.Value]bool, loopInfo *cfg.LoopInfo) []ssa.Value {
+	var roots []ssa.Value
+	for _, v := range vals {
 		roots = append(roots, t.traceAll(v, visited, loopInfo)...)
 	}
 	return roots
@@ -1366,6 +2096,7 @@ func (t *RootTracer) traceAllFieldStores(fa *ssa.FieldAddr, visited map[ssa.Valu
 //	fn()  // Need to check BOTH q1 and q2
 func (t *RootTracer) traceAllFreeVar(fv *ssa.FreeVar, visited map[ssa.Value]bool, loopInfo *cfg.LoopInfo) []ssa.Value {
 	if binding := t.freeVarBinding(fv); binding != nil {
+		t.via("free var binding", -1)
 		return t.traceAll(binding, visited, loopInfo)
 	}
 	return nil
@@ -1386,7 +2117,7 @@ func (t *RootTracer) traceAllFreeVar(fv *ssa.FreeVar, visited map[ssa.Value]bool
 // A Scopes/Preload callback parameter receives a clone==0 value and is ALWAYS
 // mutable; it cannot be exempted by //gormreuse:immutable-param.
 func (t *RootTracer) isMutableParam(p *ssa.Parameter) bool {
-	if !typeutil.IsGormDB(p.Type()) {
+	if !t.gormTypes.IsGormDB(p.Type()) {
 		return false
 	}
 	fn := p.Parent()
@@ -1418,6 +2149,10 @@ func (t *RootTracer) IsScopesCallbackFunc(fn *ssa.Function) bool {
 //     this point (see trace/traceAll). Non-gorm parameters and exempt gorm
 //     parameters (immutable-param / Transaction callback) are immutable.
 //   - Const: constant values (especially nil)
+//   - Global load: a package-level *gorm.DB such as var DB *gorm.DB, set up
+//     once by gorm.Open, is immutable like a parameter; chains derived from it
+//     (q := DB.Where("x")) are still mutable roots
+//   - Channel receive: a value received from a channel (see isReceived)
 //   - Builtin pure function call: returns immutable *gorm.DB (e.g., Session())
 //   - User-defined immutable-return function: marked with //gormreuse:immutable-return
 //
@@ -1435,8 +2170,14 @@ func (t *RootTracer) isImmutableSource(v ssa.Value) bool {
 	case *ssa.Call:
 		// Builtin or //gormreuse:immutable-return call yields an immutable value.
 		return t.returnsImmutable(val.Call.StaticCallee())
+	case *ssa.UnOp:
+		// DB.Where("x") loads the global first: t0 = *DB; t1 = (*gorm.DB).Where(t0, ...)
+		if _, ok := val.X.(*ssa.Global); ok && val.Op == token.MUL {
+			return t.gormTypes.IsGormDB(val.Type())
+		}
+		return isReceived(v)
 	default:
-		return false
+		return isReceived(v)
 	}
 }
 
@@ -1445,6 +2186,7 @@ func (t *RootTracer) isImmutableSource(v ssa.Value) bool {
 // Begin, Transaction) or a function marked //gormreuse:immutable-return. Such
 // results are not mutable roots and can be reused freely. callee may be nil.
 func (t *RootTracer) returnsImmutable(callee *ssa.Function) bool {
+	callee = underlying(callee)
 	if t.IsImmutableReturningBuiltin(callee) {
 		return true
 	}
@@ -1455,6 +2197,43 @@ func (t *RootTracer) returnsImmutable(callee *ssa.Function) bool {
 // Helper Functions
 // =============================================================================
 
+// identityParam returns the index of the parameter that every return statement
+// of fn returns unchanged, or -1 if there is none:
+//
+//	//gormreuse:pure
+//	func withLabel(q *gorm.DB, label string) *gorm.DB {
+//		log.Println(label)
+//		return q  // identityParam = 0
+//	}
+//
+// An instance of a generic function has no body of its own, so its generic
+// origin is inspected; the parameters are in the same order. Functions without
+// a body (declared in another package) return -1.
+func identityParam(fn *ssa.Function) int {
+	if origin := fn.Origin(); origin != nil {
+		fn = origin
+	}
+	if fn.Signature.Results().Len() != 1 {
+		return -1
+	}
+	var param *ssa.Parameter
+	for _, block := range fn.Blocks {
+		ret, ok := block.Instrs[len(block.Instrs)-1].(*ssa.Return)
+		if !ok {
+			continue
+		}
+		p, ok := ret.Results[0].(*ssa.Parameter)
+		if !ok || (param != nil && p != param) {
+			return -1
+		}
+		param = p
+	}
+	if param == nil {
+		return -1
+	}
+	return slices.Index(fn.Params, param)
+}
+
 // isNilConst checks if a value is a nil constant.
 func isNilConst(v ssa.Value) bool {
 	c, ok := v.(*ssa.Const)
@@ -1472,11 +2251,11 @@ func isNilConst(v ssa.Value) bool {
 //
 // Returns false (chained IIFE) for patterns like:
 //   - `closureFunc().Find(nil)` (chain ends with terminal, never stored)
-func isClosureResultStored(call *ssa.Call) bool {
-	return isClosureResultStoredRecursive(call, make(map[*ssa.Call]bool))
+func isClosureResultStored(call *ssa.Call, gormTypes *typeutil.Matcher) bool {
+	return isClosureResultStoredRecursive(call, gormTypes, make(map[*ssa.Call]bool))
 }
 
-func isClosureResultStoredRecursive(call *ssa.Call, visited map[*ssa.Call]bool) bool {
+func isClosureResultStoredRecursive(call *ssa.Call, gormTypes *typeutil.Matcher, visited map[*ssa.Call]bool) bool {
 	if visited[call] {
 		return false
 	}
@@ -1514,14 +2293,14 @@ func isClosureResultStoredRecursive(call *ssa.Call, visited map[*ssa.Call]bool)
 				continue
 			}
 			sig := callee.Signature
-			if sig == nil || sig.Recv() == nil || !typeutil.IsGormDB(sig.Recv().Type()) {
+			if sig == nil || sig.Recv() == nil || !gormTypes.IsGormDB(sig.Recv().Type()) {
 				continue
 			}
 
 			// Check if our result is receiver of this gorm method
 			if len(user.Call.Args) > 0 && user.Call.Args[0] == call {
 				// Our result is receiver - check if THAT call is stored
-				if isClosureResultStoredRecursive(user, visited) {
+				if isClosureResultStoredRecursive(user, gormTypes, visited) {
 					return true
 				}
 			} else {
@@ -1551,9 +2330,9 @@ func cloneVisited(visited map[ssa.Value]bool) map[ssa.Value]bool {
 // that doesn't capture *gorm.DB can be skipped for efficiency.
 //
 // Recursively checks pointer chains: *gorm.DB, **gorm.DB, ***gorm.DB, etc.
-func ClosureCapturesGormDB(mc *ssa.MakeClosure) bool {
+func ClosureCapturesGormDB(mc *ssa.MakeClosure, gormTypes *typeutil.Matcher) bool {
 	for _, binding := range mc.Bindings {
-		if containsGormDBThroughPointers(binding.Type()) {
+		if containsGormDBThroughPointers(binding.Type(), gormTypes) {
 			return true
 		}
 	}
@@ -1572,10 +2351,10 @@ func ClosureCapturesGormDB(mc *ssa.MakeClosure) bool {
 // Scopes(funcs ...func(*DB) *DB) and Preload(query, args ...interface{}) are
 // variadic, so the callbacks are packed into a varargs array (the last call
 // argument is a slice of it); Preload additionally boxes them in interface{}.
-func CollectScopesCallbacks(funcs []*ssa.Function) map[*ssa.Function]bool {
+func CollectScopesCallbacks(funcs []*ssa.Function, gormTypes *typeutil.Matcher) map[*ssa.Function]bool {
 	set := make(map[*ssa.Function]bool)
 	walkCalls(funcs, func(call *ssa.Call) {
-		collectScopesCallbacksFromCall(call, set)
+		collectScopesCallbacksFromCall(call, gormTypes, set)
 	})
 	return set
 }
@@ -1590,10 +2369,10 @@ func CollectScopesCallbacks(funcs []*ssa.Function) map[*ssa.Function]bool {
 // reuse inside the callback is safe (verified against gorm's clone semantics; see
 // the epic's pivotal finding). It must be exempted from the mutable-by-default
 // treatment (#60 SC103, #62).
-func CollectImmutableCallbacks(funcs []*ssa.Function) map[*ssa.Function]bool {
+func CollectImmutableCallbacks(funcs []*ssa.Function, gormTypes *typeutil.Matcher) map[*ssa.Function]bool {
 	set := make(map[*ssa.Function]bool)
 	walkCalls(funcs, func(call *ssa.Call) {
-		collectImmutableCallbacksFromCall(call, set)
+		collectImmutableCallbacksFromCall(call, gormTypes, set)
 	})
 	return set
 }
@@ -1663,9 +2442,9 @@ func walkCalls(funcs []*ssa.Function, visit func(*ssa.Call)) {
 
 // collectScopesCallbacksFromCall adds any callback function passed to a
 // Scopes/Preload call to set.
-func collectScopesCallbacksFromCall(call *ssa.Call, set map[*ssa.Function]bool) {
+func collectScopesCallbacksFromCall(call *ssa.Call, gormTypes *typeutil.Matcher, set map[*ssa.Function]bool) {
 	callee := call.Call.StaticCallee()
-	if callee == nil || !isScopesOrPreloadMethod(callee) {
+	if callee == nil || !isScopesOrPreloadMethod(callee, gormTypes) {
 		return
 	}
 
@@ -1702,13 +2481,13 @@ func collectScopesCallbacksFromCall(call *ssa.Call, set map[*ssa.Function]bool)
 
 // isScopesOrPreloadMethod reports whether callee is gorm's Scopes or Preload
 // method (gated on a gorm.DB receiver so a same-named user method is excluded).
-func isScopesOrPreloadMethod(callee *ssa.Function) bool {
+func isScopesOrPreloadMethod(callee *ssa.Function, gormTypes *typeutil.Matcher) bool {
 	name := callee.Name()
 	if name != "Scopes" && name != "Preload" {
 		return false
 	}
 	sig := callee.Signature
-	return sig != nil && sig.Recv() != nil && typeutil.IsGormDB(sig.Recv().Type())
+	return sig != nil && sig.Recv() != nil && gormTypes.IsGormDB(sig.Recv().Type())
 }
 
 // collectImmutableCallbacksFromCall adds the callback function passed to a gorm
@@ -1716,14 +2495,14 @@ func isScopesOrPreloadMethod(callee *ssa.Function) bool {
 // different argument position per method (Transaction/Connection: first
 // argument; FindInBatches: third), so scan for the func-typed argument whose
 // signature takes a *gorm.DB rather than hard-coding an index.
-func collectImmutableCallbacksFromCall(call *ssa.Call, set map[*ssa.Function]bool) {
+func collectImmutableCallbacksFromCall(call *ssa.Call, gormTypes *typeutil.Matcher, set map[*ssa.Function]bool) {
 	callee := call.Call.StaticCallee()
-	if callee == nil || !isImmutableCallbackMethod(callee) {
+	if callee == nil || !isImmutableCallbackMethod(callee, gormTypes) {
 		return
 	}
 	for _, arg := range call.Call.Args {
 		cb := callbackFuncValue(arg)
-		if cb != nil && cb.Signature != nil && directive.HasGormDBParameter(cb.Signature) {
+		if cb != nil && cb.Signature != nil && directive.HasGormDBParameter(cb.Signature, gormTypes) {
 			set[cb] = true
 		}
 	}
@@ -1733,14 +2512,14 @@ func collectImmutableCallbacksFromCall(call *ssa.Call, set map[*ssa.Function]boo
 // fresh (clone>0) *gorm.DB to a callback — Transaction, Connection, or
 // FindInBatches — gated on a gorm.DB receiver so a same-named user method is
 // excluded.
-func isImmutableCallbackMethod(callee *ssa.Function) bool {
+func isImmutableCallbackMethod(callee *ssa.Function, gormTypes *typeutil.Matcher) bool {
 	switch callee.Name() {
 	case "Transaction", "Connection", "FindInBatches":
 	default:
 		return false
 	}
 	sig := callee.Signature
-	return sig != nil && sig.Recv() != nil && typeutil.IsGormDB(sig.Recv().Type())
+	return sig != nil && sig.Recv() != nil && gormTypes.IsGormDB(sig.Recv().Type())
 }
 
 // callbackFuncValue extracts the concrete function from a value stored into a
@@ -1766,19 +2545,19 @@ func callbackFuncValue(v ssa.Value) *ssa.Function {
 // Unlike containsGormDB in directive package, this does NOT check:
 //   - interface{} (would cause false positives in SSA analysis)
 //   - struct fields, slices, maps, channels (SSA handles these differently)
-func containsGormDBThroughPointers(t types.Type) bool {
+func containsGormDBThroughPointers(t types.Type, gormTypes *typeutil.Matcher) bool {
 	if t == nil {
 		return false
 	}
 
 	// Direct *gorm.DB or gorm.DB check
-	if typeutil.IsGormDB(t) {
+	if gormTypes.IsGormDB(t) {
 		return true
 	}
 
 	// Unwrap pointer and check recursively
 	if ptr, ok := t.(*types.Pointer); ok {
-		return containsGormDBThroughPointers(ptr.Elem())
+		return containsGormDBThroughPointers(ptr.Elem(), gormTypes)
 	}
 
 	return false
@@ -80,7 +80,7 @@ func gormMethod(allFuncs map[*ssa.Function]bool, name string) *ssa.Function {
 func TestIsImmutableReturningBuiltin(t *testing.T) {
 	t.Parallel()
 	fixtures, all := loadProgram(t)
-	tr := tracer.New(nil, nil, nil, nil, nil, nil)
+	tr := tracer.New(nil, nil, nil, nil, nil, nil, nil, nil, nil)
 
 	session := gormMethod(all, "Session")
 	if session == nil {
@@ -108,7 +108,7 @@ func TestIsPureFunction(t *testing.T) {
 	t.Parallel()
 	fixtures, all := loadProgram(t)
 	// Syntax-backed pure set resolves //gormreuse:pure via each function's AST.
-	tr := tracer.New(directive.NewPureFuncSet(nil, nil), nil, nil, nil, nil, nil)
+	tr := tracer.New(directive.NewPureFuncSet(nil, nil, nil), nil, nil, nil, nil, nil, nil, nil, nil)
 
 	if session := gormMethod(all, "Session"); session != nil && !tr.IsPureFunction(session) {
 		t.Error("Session (immutable builtin) should count as pure")
@@ -134,7 +134,7 @@ func TestClosureCapturesGormDB(t *testing.T) {
 	for fn := range all {
 		for _, b := range fn.Blocks {
 			for _, instr := range b.Instrs {
-				if mc, ok := instr.(*ssa.MakeClosure); ok && tracer.ClosureCapturesGormDB(mc) {
+				if mc, ok := instr.(*ssa.MakeClosure); ok && tracer.ClosureCapturesGormDB(mc, nil) {
 					sawCapturing = true
 				}
 			}
@@ -153,7 +153,7 @@ func TestCollectScopesCallbacks(t *testing.T) {
 	for _, fn := range fixtures {
 		srcFuncs = append(srcFuncs, fn)
 	}
-	set := tracer.CollectScopesCallbacks(srcFuncs)
+	set := tracer.CollectScopesCallbacks(srcFuncs, nil)
 
 	// The named function passed to Scopes must be collected.
 	named := fixtures["namedScope"]
@@ -195,7 +195,7 @@ func TestFindMutableRootScopesParam(t *testing.T) {
 	// With namedScope registered as a Scopes callback, its *gorm.DB parameter is
 	// a mutable root.
 	scopes := map[*ssa.Function]bool{named: true}
-	tr := tracer.New(nil, nil, nil, nil, scopes, nil)
+	tr := tracer.New(nil, nil, nil, nil, nil, nil, scopes, nil, nil)
 
 	if !tr.IsScopesCallbackFunc(named) {
 		t.Error("namedScope should be recognized as a Scopes callback function")
@@ -218,14 +218,14 @@ func TestFindMutableRootScopesParam(t *testing.T) {
 	if root := tr.FindMutableRoot(ordParam, loops.DetectLoops(ordinary)); root != ordParam {
 		t.Errorf("Phase 1b: ordinary parameter should be a mutable root, got %v", root)
 	}
-	trPlain := tracer.New(nil, nil, nil, nil, nil, nil)
+	trPlain := tracer.New(nil, nil, nil, nil, nil, nil, nil, nil, nil)
 	if root := trPlain.FindMutableRoot(namedParam, loops.DetectLoops(named)); root != namedParam {
 		t.Errorf("Phase 1b: unregistered parameter should be a mutable root, got %v", root)
 	}
 
 	// A Transaction callback's tx parameter is exempt (fresh forkable handle):
 	// registering the helper as a transaction callback makes its param immutable.
-	trTx := tracer.New(nil, nil, nil, nil, nil, map[*ssa.Function]bool{ordinary: true})
+	trTx := tracer.New(nil, nil, nil, nil, nil, nil, nil, map[*ssa.Function]bool{ordinary: true}, nil)
 	if root := trTx.FindMutableRoot(ordParam, loops.DetectLoops(ordinary)); root != nil {
 		t.Errorf("Transaction callback parameter should be immutable (nil root), got %v", root)
 	}
@@ -233,3 +233,107 @@ func TestFindMutableRootScopesParam(t *testing.T) {
 		t.Errorf("Transaction callback parameter should yield no roots, got %v", roots)
 	}
 }
+
+// TestFindMutableRootMidChainWithContext verifies that tracing stops at a
+// WithContext in the middle of a chain, which is an immutable source even
+// though the receiver is not assigned from it directly.
+func TestFindMutableRootMidChainWithContext(t *testing.T) {
+	t.Parallel()
+	fixtures, _ := loadProgram(t)
+	loops := cfg.New()
+	tr := tracer.New(nil, nil, nil, nil, nil, nil, nil, nil, nil)
+
+	// findRecv returns the receiver of the first call of the gorm method name
+	// in fn.
+	findRecv := func(fn *ssa.Function, name string) ssa.Value {
+		for _, block := range fn.Blocks {
+			for _, instr := range block.Instrs {
+				call, ok := instr.(*ssa.Call)
+				if !ok {
+					continue
+				}
+				if callee := call.Call.StaticCallee(); callee != nil && callee.Name() == name && len(call.Call.Args) > 0 {
+					return call.Call.Args[0]
+				}
+			}
+		}
+		return nil
+	}
+
+	direct := fixtures["withContextMidChainDirect"]
+	derived := fixtures["withContextThenWhereReuse"]
+	if direct == nil || derived == nil {
+		t.Fatal("required fixtures missing")
+	}
+
+	recv := findRecv(direct, "Find")
+	if root := tr.FindMutableRoot(recv, loops.DetectLoops(direct)); root != nil {
+		t.Errorf("a mid-chain WithContext result should be immutable, got root %v", root)
+	}
+	if roots := tr.FindAllMutableRoots(recv, loops.DetectLoops(direct)); len(roots) != 0 {
+		t.Errorf("a mid-chain WithContext result should yield no roots, got %v", roots)
+	}
+
+	// The Where after WithContext is the root; tracing does not reach the
+	// Where before it.
+	recv = findRecv(derived, "Find")
+	root := tr.FindMutableRoot(recv, loops.DetectLoops(derived))
+	call, ok := root.(*ssa.Call)
+	if !ok || call.Call.StaticCallee().Name() != "Where" || !strings.Contains(call.Call.Args[0].String(), "WithContext") {
+		t.Errorf("the root should be the Where called on the WithContext result, got %v", root)
+	}
+}
+
+// TestFindAllMutableRootsNamedResultBranches checks that the result of a
+// closure called in place, whose named result is assigned in both branches of
+// an if, resolves to the roots of both assignments, whether the named result
+// is a Phi at the bare return or a variable captured by a deferred closure.
+func TestFindAllMutableRootsNamedResultBranches(t *testing.T) {
+	t.Parallel()
+	fixtures, _ := loadProgram(t)
+	loops := cfg.New()
+	tr := tracer.New(nil, nil, nil, nil, nil, nil, nil, nil, nil)
+
+	for _, name := range []string{"namedResultIIFEChained", "namedResultIIFEChainedDefer"} {
+		fn := fixtures[name]
+		if fn == nil {
+			t.Fatalf("fixture %s missing", name)
+		}
+
+		// The Where calls of fn are the roots assigned in the branches, and
+		// the receiver of Count is the result of the closure.
+		wheres := make(map[ssa.Value]bool)
+		var recv ssa.Value
+		for _, block := range fn.Blocks {
+			for _, instr := range block.Instrs {
+				call, ok := instr.(*ssa.Call)
+				if !ok || call.Call.StaticCallee() == nil {
+					continue
+				}
+				switch call.Call.StaticCallee().Name() {
+				case "Where":
+					wheres[call] = true
+				case "Count":
+					recv = call.Call.Args[0]
+				}
+			}
+		}
+		if len(wheres) != 2 || recv == nil {
+			t.Fatalf("%s: want two Where calls and a Count, got %d and %v", name, len(wheres), recv)
+		}
+
+		roots := tr.FindAllMutableRoots(recv, loops.DetectLoops(fn))
+		got := make(map[ssa.Value]bool)
+		for _, r := range roots {
+			got[r] = true
+		}
+		if len(got) != 2 {
+			t.Errorf("%s: roots = %v, want both Where calls", name, roots)
+		}
+		for w := range wheres {
+			if !got[w] {
+				t.Errorf("%s: root %v of a branch not found in %v", name, w, roots)
+			}
+		}
+	}
+}
@@ -0,0 +1,50 @@
+package tracer
+
+import (
+	"fmt"
+
+	"golang.org/x/tools/go/ssa"
+)
+
+// TraceSink records the steps of FindMutableRoot and FindAllMutableRoots for
+// debugging (-trace-value). Each value the tracer visits is reported by Enter
+// before it is traced and by the matching Leave once its roots are known, so
+// the calls nest like the trace itself.
+type TraceSink interface {
+	// Enter reports that v is traced, reached by step: "phi edge 1", "alloc
+	// store 0", "free var binding" and the like, or "" when v is the operand
+	// of the value traced before it. visited is true when v was already traced
+	// and is skipped, which ends the step.
+	Enter(v ssa.Value, step string, visited bool)
+
+	// Leave reports the roots found for the value of the matching Enter: one
+	// at most for FindMutableRoot, none when it is an immutable source.
+	Leave(roots []ssa.Value)
+}
+
+// SetTraceSink makes t report its steps to sink; nil stops reporting. A
+// tracer with a sink is not safe for concurrent use, so it should be one of
+// its own rather than the tracer of an analysis.
+func (t *RootTracer) SetTraceSink(sink TraceSink) {
+	t.sink = sink
+}
+
+// via labels the next value traced with a step of the given kind, numbered
+// by index unless it is negative. It does nothing without a sink.
+func (t *RootTracer) via(kind string, index int) {
+	if t.sink == nil {
+		return
+	}
+	if index < 0 {
+		t.step = kind
+		return
+	}
+	t.step = fmt.Sprintf("%s %d", kind, index)
+}
+
+// enter reports v to the sink with the pending step, which it consumes.
+func (t *RootTracer) enter(v ssa.Value, visited map[ssa.Value]bool) {
+	step := t.step
+	t.step = ""
+	t.sink.Enter(v, step, visited[v])
+}
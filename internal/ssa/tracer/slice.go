@@ -0,0 +1,167 @@
+package tracer
+
+import (
+	"go/constant"
+	"go/token"
+	"go/types"
+	"maps"
+	"slices"
+
+	"golang.org/x/tools/go/ssa"
+)
+
+// localSliceStores returns, by index, the values stored into the elements of
+// a local slice or array origin (see sliceOrigin).
+//
+// An origin qualifies when it is only written element by element with
+// constant indices, at most once per index, and read back by indexing:
+//
+//	s := []*gorm.DB{q}      // t0 = new [1]*gorm.DB; t1 = &t0[0]; *t1 = q
+//	s[0].Find(nil)          // t2 = slice t0[:]; t3 = &t2[0]; t4 = *t3
+//	s[0].Count(nil)         // t5 = &t2[0]; t6 = *t5
+//
+// Such a slice is just another name for q, like a local constant-key map (see
+// constKeyMapStore). Any other use (passing it on, appending, ranging over it,
+// taking an element's address, a variable-index store, no read at all) keeps
+// the conservative "storing in a slice pollutes" rule, and nil is returned.
+func localSliceStores(origin ssa.Value) map[int64]ssa.Value {
+	if origin == nil || origin.Referrers() == nil {
+		return nil
+	}
+	stores := make(map[int64]ssa.Value)
+	loads := 0
+	addElem := func(ia *ssa.IndexAddr) bool {
+		if ia.Referrers() == nil {
+			return true
+		}
+		for _, ref := range *ia.Referrers() {
+			switch r := ref.(type) {
+			case *ssa.Store:
+				idx, ok := constIndex(ia.Index)
+				if !ok || r.Addr != ia {
+					return false
+				}
+				if _, dup := stores[idx]; dup {
+					return false
+				}
+				stores[idx] = r.Val
+			case *ssa.UnOp:
+				if r.Op != token.MUL {
+					return false
+				}
+				loads++
+			default:
+				return false
+			}
+		}
+		return true
+	}
+
+	for _, ref := range *origin.Referrers() {
+		switch r := ref.(type) {
+		case *ssa.IndexAddr:
+			if !addElem(r) {
+				return nil
+			}
+		case *ssa.Slice:
+			if r.X != origin || r.Referrers() == nil {
+				return nil
+			}
+			for _, sref := range *r.Referrers() {
+				ia, ok := sref.(*ssa.IndexAddr)
+				if !ok || !addElem(ia) {
+					return nil
+				}
+			}
+		case *ssa.UnOp:
+			// A load of a whole array (arr := *t0) read back by Index.
+			if r.Op != token.MUL || r.Referrers() == nil {
+				return nil
+			}
+			for _, lref := range *r.Referrers() {
+				if _, ok := lref.(*ssa.Index); !ok {
+					return nil
+				}
+				loads++
+			}
+		default:
+			return nil
+		}
+	}
+	if loads == 0 {
+		return nil
+	}
+	return stores
+}
+
+// sliceOrigin returns the local array (an Alloc) or slice (a MakeSlice) that
+// x indexes, looking through a slicing of an array (s := arr[:]), or nil.
+func sliceOrigin(x ssa.Value) ssa.Value {
+	if s, ok := x.(*ssa.Slice); ok {
+		x = s.X
+	}
+	switch v := x.(type) {
+	case *ssa.Alloc:
+		if ptr, ok := v.Type().Underlying().(*types.Pointer); ok {
+			if _, ok := ptr.Elem().Underlying().(*types.Array); ok {
+				return v
+			}
+		}
+	case *ssa.MakeSlice:
+		return v
+	}
+	return nil
+}
+
+// constIndex returns the value of a constant index.
+func constIndex(v ssa.Value) (int64, bool) {
+	c, ok := v.(*ssa.Const)
+	if !ok || c.Value == nil {
+		return 0, false
+	}
+	return constant.Int64Val(constant.ToInt(c.Value))
+}
+
+// sliceElemValues returns the values an element read x[index] of a local
+// slice or array may see: the value stored at a constant index, or every
+// stored value, in index order, for a variable index. ok is false when x is
+// not such a slice (see localSliceStores). A constant index with no store
+// reads the zero value, which is not part of any chain.
+func sliceElemValues(x, index ssa.Value) (vals []ssa.Value, ok bool) {
+	stores := localSliceStores(sliceOrigin(x))
+	if stores == nil {
+		return nil, false
+	}
+	if idx, ok := constIndex(index); ok {
+		if v, ok := stores[idx]; ok {
+			return []ssa.Value{v}, true
+		}
+		return nil, true
+	}
+	for _, idx := range slices.Sorted(maps.Keys(stores)) {
+		vals = append(vals, stores[idx])
+	}
+	return vals, true
+}
+
+// IsLocalSliceStore reports whether store writes an element of a local slice
+// or array that is read back by indexing (see localSliceStores). Reads of such
+// a slice are traced back to the stored values, so the store is an assignment
+// rather than a pollution source.
+func IsLocalSliceStore(store *ssa.Store) bool {
+	ia, ok := store.Addr.(*ssa.IndexAddr)
+	if !ok {
+		return false
+	}
+	return localSliceStores(sliceOrigin(ia.X)) != nil
+}
+
+// indexValues returns the values an Index of a loaded local array (see
+// localSliceStores) may read, like sliceElemValues.
+func indexValues(ix *ssa.Index) ([]ssa.Value, bool) {
+	load, ok := ix.X.(*ssa.UnOp)
+	if !ok || load.Op != token.MUL {
+		return nil, false
+	}
+	return sliceElemValues(load.X, ix.Index)
+}
@@ -0,0 +1,95 @@
+package tracer
+
+import (
+	"strconv"
+
+	"golang.org/x/tools/go/ssa"
+)
+
+// storeIndex maps the addresses written by *ssa.Store instructions of one
+// function to the stored values, in program order.
+//
+// Tracing a variable or a struct field back to its stores used to rescan every
+// instruction of the enclosing function on each lookup, which is quadratic for
+// large functions with many *gorm.DB variables. The index is built once per
+// function, on first use, and answers every later lookup from a map.
+type storeIndex struct {
+	allocs   map[*ssa.Alloc][]ssa.Value   // Store t1 v, where t1 = Alloc
+	fields   map[fieldKey][]ssa.Value     // Store t2 v, where t2 = &base.field or &base.outer.field
+	freeVars map[*ssa.FreeVar][]ssa.Value // Store fv v, where fv is a captured variable
+}
+
+// fieldKey identifies a struct field by its base value and field index, the
+// same match traceFieldStore has always used. A field of a nested struct, such
+// as a field promoted from an embedded struct, is addressed through one
+// FieldAddr per struct; its key is the outermost base with the path of the
+// enclosing fields, so every access through the same path matches:
+//
+//	type Repo struct{ Base }  // Base holds db
+//	r.db = q                  // t1 = &r.Base; t2 = &t1.db; *t2 = q   → {r, "0.", 0}
+//	r.db.Find(nil)            // t3 = &r.Base; t4 = &t3.db; *t4       → {r, "0.", 0}
+type fieldKey struct {
+	base  ssa.Value
+	path  string // indices of the enclosing fields from base, e.g. "0." for r.Base
+	field int
+}
+
+// fieldPath returns the outermost struct pointer x addresses a nested struct
+// from, with the path of the enclosing fields (see fieldKey). x that is not a
+// FieldAddr is its own base with an empty path.
+func fieldPath(x ssa.Value) (ssa.Value, string) {
+	var path string
+	for {
+		fa, ok := x.(*ssa.FieldAddr)
+		if !ok {
+			return x, path
+		}
+		path = strconv.Itoa(fa.Field) + "." + path
+		x = fa.X
+	}
+}
+
+// buildStoreIndex scans fn once and records every Store into an Alloc, a
+// FieldAddr or a FreeVar.
+func buildStoreIndex(fn *ssa.Function) *storeIndex {
+	idx := &storeIndex{
+		allocs:   make(map[*ssa.Alloc][]ssa.Value),
+		fields:   make(map[fieldKey][]ssa.Value),
+		freeVars: make(map[*ssa.FreeVar][]ssa.Value),
+	}
+	for _, block := range fn.Blocks {
+		for _, instr := range block.Instrs {
+			store, ok := instr.(*ssa.Store)
+			if !ok {
+				continue
+			}
+			switch addr := store.Addr.(type) {
+			case *ssa.Alloc:
+				idx.allocs[addr] = append(idx.allocs[addr], store.Val)
+			case *ssa.FieldAddr:
+				base, path := fieldPath(addr.X)
+				key := fieldKey{base: base, path: path, field: addr.Field}
+				idx.fields[key] = append(idx.fields[key], store.Val)
+			case *ssa.FreeVar:
+				idx.freeVars[addr] = append(idx.freeVars[addr], store.Val)
+			}
+		}
+	}
+	return idx
+}
+
+// storeIndexFor returns the store index of fn, building it on first use. It
+// is safe for concurrent use, like the rest of RootTracer.
+func (t *RootTracer) storeIndexFor(fn *ssa.Function) *storeIndex {
+	t.storeIndexesMu.Lock()
+	defer t.storeIndexesMu.Unlock()
+	if idx, ok := t.storeIndexes[fn]; ok {
+		return idx
+	}
+	if t.storeIndexes == nil {
+		t.storeIndexes = make(map[*ssa.Function]*storeIndex)
+	}
+	idx := buildStoreIndex(fn)
+	t.storeIndexes[fn] = idx
+	return idx
+}
@@ -0,0 +1,109 @@
+package tracer_test
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"golang.org/x/tools/go/packages"
+	"golang.org/x/tools/go/ssa"
+	"golang.org/x/tools/go/ssa/ssautil"
+
+	"github.com/mpyw/gormreuse/internal/ssa/cfg"
+	"github.com/mpyw/gormreuse/internal/ssa/tracer"
+	"github.com/mpyw/gormreuse/internal/typeutil"
+)
+
+// manyVarsSource generates a function with n address-taken *gorm.DB variables
+// and n struct fields holding them, so that tracing each receiver goes through
+// the Alloc and FieldAddr store lookups.
+func manyVarsSource(n int) string {
+	var b strings.Builder
+	b.WriteString("package many\n\nimport \"gorm.io/gorm\"\n\ntype holder struct{ db *gorm.DB }\n\nfunc many(db *gorm.DB) {\n")
+	for i := 0; i < n; i++ {
+		fmt.Fprintf(&b, "\tq%d := db.Where(%q)\n\t_ = &q%d\n\th%d := &holder{}\n\th%d.db = q%d\n", i, fmt.Sprint(i), i, i, i, i)
+	}
+	for i := 0; i < n; i++ {
+		fmt.Fprintf(&b, "\tq%d.Find(nil)\n\th%d.db.Count(nil)\n", i, i)
+	}
+	b.WriteString("}\n")
+	return b.String()
+}
+
+// loadManyVars builds SSA for manyVarsSource(n) in a temporary GOPATH next to
+// a copy of the gorm stub, and returns the function with the receivers of its
+// gorm method calls.
+func loadManyVars(b *testing.B, n int) (*ssa.Function, []ssa.Value) {
+	b.Helper()
+	_, file, _, ok := runtime.Caller(0)
+	if !ok {
+		b.Fatal("runtime.Caller failed")
+	}
+	stub, err := os.ReadFile(filepath.Join(filepath.Dir(file), "..", "..", "..", "testdata", "src", "gorm.io", "gorm", "gorm.go"))
+	if err != nil {
+		b.Fatalf("reading gorm stub: %v", err)
+	}
+
+	gopath := b.TempDir()
+	for path, src := range map[string]string{
+		filepath.Join("src", "gorm.io", "gorm", "gorm.go"): string(stub),
+		filepath.Join("src", "many", "many.go"):            manyVarsSource(n),
+	} {
+		path = filepath.Join(gopath, path)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			b.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(src), 0o644); err != nil {
+			b.Fatal(err)
+		}
+	}
+
+	pkgs, err := packages.Load(&packages.Config{
+		Mode: packages.LoadAllSyntax,
+		Dir:  gopath,
+		Env:  append(os.Environ(), "GOPATH="+gopath, "GO111MODULE=off", "GOFLAGS="),
+	}, "many")
+	if err != nil {
+		b.Fatalf("packages.Load: %v", err)
+	}
+	if packages.PrintErrors(pkgs) > 0 {
+		b.Fatal("packages had errors")
+	}
+	prog, ssaPkgs := ssautil.Packages(pkgs, ssa.BuilderMode(0))
+	prog.Build()
+
+	fn := ssaPkgs[0].Func("many")
+	var recvs []ssa.Value
+	for _, block := range fn.Blocks {
+		for _, instr := range block.Instrs {
+			call, ok := instr.(*ssa.Call)
+			if !ok || len(call.Call.Args) == 0 {
+				continue
+			}
+			if callee := call.Call.StaticCallee(); callee != nil && callee.Signature.Recv() != nil && typeutil.IsGormDB(callee.Signature.Recv().Type()) {
+				recvs = append(recvs, call.Call.Args[0])
+			}
+		}
+	}
+	return fn, recvs
+}
+
+// BenchmarkFindAllMutableRootsManyVars traces every receiver of a function with
+// 500 variables. Each trace looks up the stores of an Alloc and of a struct
+// field; with a fresh tracer per iteration, the cost of building the store
+// index is included.
+func BenchmarkFindAllMutableRootsManyVars(b *testing.B) {
+	fn, recvs := loadManyVars(b, 500)
+	loopInfo := cfg.New().DetectLoops(fn)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		tr := tracer.New(nil, nil, nil, nil, nil, nil, nil, nil, nil)
+		for _, recv := range recvs {
+			tr.FindAllMutableRoots(recv, loopInfo)
+		}
+	}
+}
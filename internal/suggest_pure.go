@@ -0,0 +1,72 @@
+package internal
+
+import (
+	"go/ast"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/analysis/passes/buildssa"
+	"golang.org/x/tools/go/ssa"
+
+	"github.com/mpyw/gormreuse/internal/directive"
+	"github.com/mpyw/gormreuse/internal/ssa/pollution"
+	"github.com/mpyw/gormreuse/internal/ssa/purity"
+	"github.com/mpyw/gormreuse/internal/typeutil"
+)
+
+// suggestPureFunctions reports the unannotated helpers that already satisfy
+// the //gormreuse:pure contract (-suggest-pure): they take a *gorm.DB and the
+// pure validator finds no way they pollute it. Marking them documents the
+// contract, lets callers keep using the argument afterwards, and makes the
+// validator guard it from then on. Each note carries a fix inserting the
+// directive.
+//
+// The inference is the validator itself, so it is as conservative: a helper
+// passing its argument to another unannotated helper is not suggested, even if
+// that helper would be suggested in turn. Closures are skipped, as directive
+// placement on function literals is left to the user, and so are sinks and
+// finishers, which consume their argument by declaration.
+func suggestPureFunctions(
+	pass *analysis.Pass,
+	ssaInfo *buildssa.SSA,
+	pureFuncs, finisherFuncs, sinkFuncs *directive.DirectiveFuncSet,
+	gormTypes *typeutil.Matcher,
+	skip func(*ssa.Function, bool) bool,
+) {
+	for _, fn := range ssaInfo.SrcFuncs {
+		if skip(fn, false) || pureFuncs.Contains(fn) || finisherFuncs.Contains(fn) || sinkFuncs.Contains(fn) {
+			continue
+		}
+		fd, ok := fn.Syntax().(*ast.FuncDecl)
+		if !ok || !hasGormDBParam(fn, gormTypes) {
+			continue
+		}
+		recoverPerFunction(fn, func() {
+			if len(purity.ValidateFunction(fn, pureFuncs, gormTypes)) > 0 {
+				return
+			}
+			pass.Report(analysis.Diagnostic{
+				Pos:      fd.Name.Pos(),
+				Category: pollution.KindSuggestPure.String(),
+				Message:  fn.Name() + " never pollutes its *gorm.DB argument; consider marking it //gormreuse:pure",
+				SuggestedFixes: []analysis.SuggestedFix{{
+					Message: "Declare //gormreuse:pure",
+					TextEdits: []analysis.TextEdit{{
+						Pos:     fd.Pos(),
+						End:     fd.Pos(),
+						NewText: []byte("//gormreuse:pure\n"),
+					}},
+				}},
+			})
+		})
+	}
+}
+
+// hasGormDBParam reports whether fn takes a *gorm.DB parameter.
+func hasGormDBParam(fn *ssa.Function, gormTypes *typeutil.Matcher) bool {
+	for _, p := range fn.Params {
+		if gormTypes.IsGormDB(p.Type()) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,142 @@
+package internal
+
+import (
+	"cmp"
+	"fmt"
+	"go/ast"
+	"go/token"
+	"io"
+	"slices"
+	"text/tabwriter"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/ssa"
+
+	"github.com/mpyw/gormreuse/internal/ssa/pollution"
+)
+
+// =============================================================================
+// Summary (-summary)
+// =============================================================================
+
+// packageScope is the function column of diagnostics outside any function,
+// such as a directive on a package-level declaration.
+const packageScope = "(package)"
+
+// summary counts the diagnostics of a package by category and by enclosing
+// function, and renders them as a table for dashboards:
+//
+//	package   example.com/pkg
+//	category  BRANCH                   3
+//	category  UNUSED-IGNORE            1
+//	function  example.com/pkg.f        2
+//	function  (*example.com/pkg.T).g   1
+//	function  (package)                1
+//	total                              4
+//
+// The enclosing function of a diagnostic is the top-level function whose
+// declaration, doc comment included, contains it; closures count towards the
+// function declaring them. Categories are listed in ViolationKind order and
+// functions in source order.
+type summary struct {
+	funcs      enclosingFuncs
+	byCategory map[string]int
+	byFunc     map[int]int // by index in funcs, len(funcs) for packageScope
+	total      int
+}
+
+// summarize returns a copy of pass whose Report counts each diagnostic in the
+// returned summary before passing it on; when only is true, it drops the
+// diagnostic instead.
+func summarize(pass *analysis.Pass, srcFuncs []*ssa.Function, only bool) (*analysis.Pass, *summary) {
+	s := &summary{
+		funcs:      newEnclosingFuncs(srcFuncs),
+		byCategory: make(map[string]int),
+		byFunc:     make(map[int]int),
+	}
+
+	counted := *pass
+	counted.Report = func(d analysis.Diagnostic) {
+		s.add(d)
+		if !only {
+			pass.Report(d)
+		}
+	}
+	return &counted, s
+}
+
+// add counts d.
+func (s *summary) add(d analysis.Diagnostic) {
+	s.byCategory[d.Category]++
+	s.byFunc[s.funcs.enclosing(d.Pos)]++
+	s.total++
+}
+
+// enclosingFuncs are the top-level source functions of a package, in source
+// order, to which diagnostics are attributed.
+type enclosingFuncs []*ssa.Function
+
+// newEnclosingFuncs returns the top-level functions among srcFuncs; closures
+// count towards the function declaring them.
+func newEnclosingFuncs(srcFuncs []*ssa.Function) enclosingFuncs {
+	var funcs enclosingFuncs
+	for _, fn := range srcFuncs {
+		if fn.Parent() == nil && fn.Syntax() != nil {
+			funcs = append(funcs, fn)
+		}
+	}
+	slices.SortFunc(funcs, func(a, b *ssa.Function) int { return cmp.Compare(a.Pos(), b.Pos()) })
+	return funcs
+}
+
+// enclosing returns the index of the function whose declaration, doc comment
+// included, contains pos, or len(funcs) when there is none.
+func (funcs enclosingFuncs) enclosing(pos token.Pos) int {
+	for i, fn := range funcs {
+		start, end := fn.Syntax().Pos(), fn.Syntax().End()
+		if fd, ok := fn.Syntax().(*ast.FuncDecl); ok && fd.Doc != nil {
+			start = fd.Doc.Pos()
+		}
+		if start <= pos && pos < end {
+			return i
+		}
+	}
+	return len(funcs)
+}
+
+// name returns the name of the function at index i, or packageScope for
+// len(funcs).
+func (funcs enclosingFuncs) name(i int) string {
+	if i < len(funcs) {
+		return funcs[i].String()
+	}
+	return packageScope
+}
+
+// write renders the table of the package pkgPath.
+func (s *summary) write(w io.Writer, pkgPath string) {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(tw, "package\t%s\t\n", pkgPath)
+	categories := make([]string, 0, len(s.byCategory))
+	for category := range s.byCategory {
+		categories = append(categories, category)
+	}
+	slices.SortFunc(categories, func(a, b string) int {
+		ka, _ := pollution.ParseViolationKind(a)
+		kb, _ := pollution.ParseViolationKind(b)
+		return cmp.Or(cmp.Compare(ka, kb), cmp.Compare(a, b))
+	})
+	for _, category := range categories {
+		fmt.Fprintf(tw, "category\t%s\t%d\n", category, s.byCategory[category])
+	}
+	// Functions in source order, diagnostics outside any function last.
+	for i := 0; i <= len(s.funcs); i++ {
+		n := s.byFunc[i]
+		if n == 0 {
+			continue
+		}
+		fmt.Fprintf(tw, "function\t%s\t%d\n", s.funcs.name(i), n)
+	}
+	fmt.Fprintf(tw, "total\t\t%d\n", s.total)
+	_ = tw.Flush()
+}
@@ -0,0 +1,67 @@
+package internal
+
+import (
+	"go/ast"
+	"go/token"
+
+	"golang.org/x/tools/go/ast/astutil"
+	"golang.org/x/tools/go/types/typeutil"
+)
+
+// =============================================================================
+// Test Helpers (-no-test-helpers)
+// =============================================================================
+
+// testHelperPkgSet returns the set of assertion packages whose calls suppress
+// nested violations, or nil when the option is off.
+func testHelperPkgSet(pkgs []string) map[string]bool {
+	if len(pkgs) == 0 {
+		return nil
+	}
+	set := make(map[string]bool, len(pkgs))
+	for _, pkg := range pkgs {
+		set[pkg] = true
+	}
+	return set
+}
+
+// inTestHelper reports whether the violating use at pos is nested inside a
+// call to a function of a test-helper package:
+//
+//	require.NoError(t, tx.Create(&u).Error)   // suppressed
+//	tx.Create(&u)                             // still reported
+//
+// Only the enclosing expressions of the same function are considered: a
+// closure passed to an assertion is a separate body whose uses still count.
+func (c *checker) inTestHelper(pos token.Pos) bool {
+	if len(c.testHelperPkgs) == 0 {
+		return false
+	}
+	file := c.fileAt(pos)
+	if file == nil {
+		return false
+	}
+	path, _ := astutil.PathEnclosingInterval(file, pos, pos)
+	for _, n := range path {
+		switch n := n.(type) {
+		case *ast.FuncLit, *ast.FuncDecl:
+			return false
+		case *ast.CallExpr:
+			fn := typeutil.StaticCallee(c.pass.TypesInfo, n)
+			if fn != nil && fn.Pkg() != nil && c.testHelperPkgs[fn.Pkg().Path()] {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// fileAt returns the syntax of the analyzed file containing pos.
+func (c *checker) fileAt(pos token.Pos) *ast.File {
+	for _, file := range c.pass.Files {
+		if file.FileStart <= pos && pos < file.FileEnd {
+			return file
+		}
+	}
+	return nil
+}
@@ -0,0 +1,62 @@
+package internal
+
+import (
+	"cmp"
+	"go/token"
+	"io"
+	"slices"
+
+	"golang.org/x/tools/go/ssa"
+
+	"github.com/mpyw/gormreuse/internal/debug"
+	ssautil "github.com/mpyw/gormreuse/internal/ssa"
+	"github.com/mpyw/gormreuse/internal/ssa/cfg"
+	"github.com/mpyw/gormreuse/internal/ssa/tracer"
+)
+
+// =============================================================================
+// Value Trace (-trace-value)
+// =============================================================================
+
+// traceValues writes to w how the receiver of each gorm method call of funcs
+// on the target line resolves to its roots (see debug.Write). Each receiver is
+// traced by a tracer of its own, configured like the analysis, so the trace
+// neither depends on nor affects the analysis of the functions.
+func traceValues(w io.Writer, fset *token.FileSet, funcs []*ssa.Function, opts ssautil.Options, target debug.Target) {
+	var traces []debug.Trace
+	for _, fn := range funcs {
+		var loopInfo *cfg.LoopInfo
+		for _, block := range fn.Blocks {
+			for _, instr := range block.Instrs {
+				call, ok := instr.(ssa.CallInstruction)
+				if !ok || !target.Matches(fset.Position(call.Pos())) {
+					continue
+				}
+				method, recv, ok := tracer.GormMethod(call.Common(), opts.GormTypes)
+				if !ok {
+					continue
+				}
+				if loopInfo == nil {
+					loopInfo = cfg.New().DetectLoops(fn)
+				}
+				traces = append(traces, traceReceiver(fn, call.Pos(), method, recv, opts, loopInfo))
+			}
+		}
+	}
+	slices.SortStableFunc(traces, func(a, b debug.Trace) int { return cmp.Compare(a.Pos, b.Pos) })
+	debug.Write(w, fset, traces)
+}
+
+// traceReceiver traces recv with both FindMutableRoot and FindAllMutableRoots,
+// recording their steps.
+func traceReceiver(fn *ssa.Function, pos token.Pos, method string, recv ssa.Value, opts ssautil.Options, loopInfo *cfg.LoopInfo) debug.Trace {
+	rec := &debug.Recorder{}
+	rt := opts.RootTracer()
+	rt.SetTraceSink(rec)
+	tr := debug.Trace{Pos: pos, Fn: fn, Method: method, Recv: recv}
+	tr.Root = rt.FindMutableRoot(recv, loopInfo)
+	tr.RootSteps = rec.Take()
+	tr.AllRoots = rt.FindAllMutableRoots(recv, loopInfo)
+	tr.AllSteps = rec.Take()
+	return tr
+}
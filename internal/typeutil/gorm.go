@@ -13,6 +13,19 @@
 // It uses exact package path matching to prevent false positives from
 // malicious packages like "evil.com/fake-gorm.io/gorm".
 //
+// Type aliases (type DB = gorm.DB) are resolved before matching.
+//
+// Teams that vendor GORM under another import path, or wrap *gorm.DB in a
+// type of their own, configure a Matcher (-gorm-type) that accepts those type
+// names too. The matcher is threaded through the analysis rather than stored
+// in a package variable, so analyzers configured differently do not interfere.
+// The nil *Matcher, and the package-level IsGormDB, match gorm.io/gorm.DB only.
+//
+// A Matcher also accepts builder types (-builder-type): wrappers holding a
+// *gorm.DB whose methods return the wrapper to chain or finish the query.
+// Their values are tracked like *gorm.DB, but IsBuilder lets callers withhold
+// gorm-specific fixes such as inserting Session().
+//
 // Note: Nested pointers (**gorm.DB) and interfaces are handled separately:
 //   - ClosureCapturesGormDB in tracer package handles **gorm.DB from closure captures
 //   - containsGormDB in directive package handles interfaces conservatively
@@ -37,7 +50,11 @@
 package typeutil
 
 import (
+	"errors"
+	"fmt"
+	"go/token"
 	"go/types"
+	"strings"
 )
 
 const (
@@ -51,17 +68,13 @@ const (
 
 // IsGormDB checks if the given type is *gorm.DB or gorm.DB.
 // Both are dangerous because gorm.DB contains *Statement which is shared on copy.
+// Only gorm.io/gorm.DB is matched; see Matcher for configured DB types.
 //
 // Note: This function does NOT handle nested pointers (**gorm.DB) or interfaces.
 // For nested pointers in closure captures, see ClosureCapturesGormDB in tracer package.
 // For conservative checks including interfaces, see containsGormDB in directive package.
 func IsGormDB(t types.Type) bool {
-	// Check for *gorm.DB (most common case)
-	if ptr, ok := t.(*types.Pointer); ok {
-		return isGormDBNamed(ptr.Elem())
-	}
-	// Check for gorm.DB (non-pointer, still dangerous due to *Statement field)
-	return isGormDBNamed(t)
+	return (*Matcher)(nil).IsGormDB(t)
 }
 
 // IsGormPackage reports whether pkg is exactly the gorm.io/gorm package.
@@ -75,7 +88,7 @@ func IsGormPackage(pkg *types.Package) bool {
 
 // isGormDBNamed checks if the type is gorm.DB (named type).
 func isGormDBNamed(t types.Type) bool {
-	named, ok := t.(*types.Named)
+	named, ok := types.Unalias(t).(*types.Named)
 	if !ok {
 		return false
 	}
@@ -87,6 +100,212 @@ func isGormDBNamed(t types.Type) bool {
 	return obj.Name() == gormDBType && obj.Pkg().Path() == gormPkgPath
 }
 
+// =============================================================================
+// Configured DB Types
+// =============================================================================
+
+// Matcher recognizes gorm.io/gorm.DB plus the DB types configured with
+// -gorm-type, e.g. a vendored copy of GORM:
+//
+//	-gorm-type=github.com/acme/vendor/gorm.DB
+//
+// With underlying set, a defined type over gorm.DB is matched as well:
+//
+//	type Handle gorm.DB     // *Handle matches
+//	type HandlePtr *gorm.DB // HandlePtr matches
+//
+// Builder types configured with -builder-type are matched by value or
+// pointer:
+//
+//	type Builder struct{ db *gorm.DB }
+//	func (b Builder) Where(s string) Builder { b.db = b.db.Where(s); return b }
+//	func (b Builder) Find(dest any)          { b.db.Find(dest) }
+//
+// A builder type may also be a gorm-like interface; calls of its methods
+// (invoke mode) are tracked like calls on the wrapped *gorm.DB:
+//
+//	type Querier interface{ Where(string) Querier; Find(any) Querier }
+//
+// Methods configured with -immutable-method join the builtin immutable-returning
+// methods (Session, WithContext, ...) on the DB types:
+//
+//	-immutable-method=ReadOnly // (*Handle).ReadOnly() returns an immutable copy
+//
+// A nil *Matcher matches gorm.io/gorm.DB and the builtin methods only.
+type Matcher struct {
+	types      map[typeName]bool
+	builders   map[typeName]bool
+	immutable  map[string]bool
+	underlying bool
+}
+
+// typeName is a fully-qualified named type, e.g. {"gorm.io/gorm", "DB"}.
+type typeName struct {
+	pkg  string
+	name string
+}
+
+// NewMatcher returns a Matcher accepting the fully-qualified DB type names (such
+// as "github.com/acme/db.Handle") in addition to gorm.io/gorm.DB, and the
+// builder type names wrapping *gorm.DB, and the names of additional
+// immutable-returning methods. It reports an error for a type name that is not
+// of the form "import/path.Name" or a method name that is not an identifier.
+func NewMatcher(dbTypes, builderTypes, immutableMethods []string, underlying bool) (*Matcher, error) {
+	m := &Matcher{
+		types:      make(map[typeName]bool, len(dbTypes)),
+		builders:   make(map[typeName]bool, len(builderTypes)),
+		immutable:  make(map[string]bool, len(immutableMethods)),
+		underlying: underlying,
+	}
+	for _, name := range dbTypes {
+		tn, err := parseTypeName(name)
+		if err != nil {
+			return nil, fmt.Errorf("invalid gorm type %q: %w", name, err)
+		}
+		m.types[tn] = true
+	}
+	for _, name := range builderTypes {
+		tn, err := parseTypeName(name)
+		if err != nil {
+			return nil, fmt.Errorf("invalid builder type %q: %w", name, err)
+		}
+		m.builders[tn] = true
+	}
+	for _, name := range immutableMethods {
+		if !token.IsIdentifier(name) {
+			return nil, fmt.Errorf("invalid immutable method %q: want a method name", name)
+		}
+		m.immutable[name] = true
+	}
+	return m, nil
+}
+
+// parseTypeName splits "import/path.Name" into its package path and name.
+func parseTypeName(name string) (typeName, error) {
+	dot := strings.LastIndex(name, ".")
+	if dot <= 0 || dot == len(name)-1 || strings.HasSuffix(name[:dot], "/") || strings.Contains(name[dot+1:], "/") {
+		return typeName{}, errors.New("want import/path.TypeName")
+	}
+	return typeName{pkg: name[:dot], name: name[dot+1:]}, nil
+}
+
+// IsGormDB is the configured counterpart of the package-level IsGormDB: it
+// checks if t is a pointer to, or a value of, gorm.DB, a configured DB type or
+// a builder type.
+func (m *Matcher) IsGormDB(t types.Type) bool {
+	if m.IsBuilder(t) {
+		return true
+	}
+	if ptr, ok := types.Unalias(t).(*types.Pointer); ok {
+		return m.isDBNamed(ptr.Elem())
+	}
+	return m.isDBNamed(t) || m.isDBPointerNamed(t)
+}
+
+// IsBuilder reports whether t is a configured builder type or a pointer to
+// one. Builders are not gorm.DB, so Session() cannot be inserted on them.
+func (m *Matcher) IsBuilder(t types.Type) bool {
+	if m == nil || len(m.builders) == 0 || t == nil {
+		return false
+	}
+	if ptr, ok := types.Unalias(t).(*types.Pointer); ok {
+		t = ptr.Elem()
+	}
+	named, ok := types.Unalias(t).(*types.Named)
+	if !ok || named.Obj() == nil || named.Obj().Pkg() == nil {
+		return false
+	}
+	obj := named.Obj()
+	return m.builders[typeName{pkg: obj.Pkg().Path(), name: obj.Name()}]
+}
+
+// IsGormDBLayout reports whether t is a pointer to gorm.DB, a configured DB
+// type or a type defined over gorm.DB's struct (type Handle gorm.DB), whatever
+// -gorm-type-underlying says. Pointers of these types share one layout, so an
+// unsafe.Pointer conversion between them still refers to the same DB.
+func (m *Matcher) IsGormDBLayout(t types.Type) bool {
+	ptr, ok := types.Unalias(t).Underlying().(*types.Pointer)
+	if !ok {
+		return false
+	}
+	if m.isDBNamed(ptr.Elem()) {
+		return true
+	}
+	named, ok := types.Unalias(ptr.Elem()).(*types.Named)
+	return ok && hasGormDBStruct(named)
+}
+
+// IsGormPackage reports whether pkg is gorm.io/gorm or declares a configured
+// DB type, so the package-level builtins of a vendored GORM (Open) are
+// recognized like the original ones.
+func (m *Matcher) IsGormPackage(pkg *types.Package) bool {
+	if IsGormPackage(pkg) {
+		return true
+	}
+	if m == nil || pkg == nil {
+		return false
+	}
+	for tn := range m.types {
+		if tn.pkg == pkg.Path() {
+			return true
+		}
+	}
+	return false
+}
+
+// isDBNamed checks if t is gorm.DB, a configured DB type or, with underlying,
+// a defined type whose underlying struct is gorm.DB's.
+func (m *Matcher) isDBNamed(t types.Type) bool {
+	if isGormDBNamed(t) {
+		return true
+	}
+	if m == nil {
+		return false
+	}
+	named, ok := types.Unalias(t).(*types.Named)
+	if !ok || named.Obj() == nil || named.Obj().Pkg() == nil {
+		return false
+	}
+	obj := named.Obj()
+	if m.types[typeName{pkg: obj.Pkg().Path(), name: obj.Name()}] {
+		return true
+	}
+	return m.underlying && hasGormDBStruct(named)
+}
+
+// isDBPointerNamed checks, with underlying, if t is a defined pointer type
+// over *gorm.DB (type HandlePtr *gorm.DB).
+func (m *Matcher) isDBPointerNamed(t types.Type) bool {
+	if m == nil || !m.underlying {
+		return false
+	}
+	named, ok := types.Unalias(t).(*types.Named)
+	if !ok {
+		return false
+	}
+	ptr, ok := named.Underlying().(*types.Pointer)
+	return ok && m.isDBNamed(ptr.Elem())
+}
+
+// hasGormDBStruct reports whether named is defined over gorm.DB's struct
+// (type Handle gorm.DB). The struct is found through the package of its
+// fields, which is gorm.io/gorm for the genuine gorm.DB.
+func hasGormDBStruct(named *types.Named) bool {
+	st, ok := named.Underlying().(*types.Struct)
+	if !ok {
+		return false
+	}
+	for i := 0; i < st.NumFields(); i++ {
+		pkg := st.Field(i).Pkg()
+		if !IsGormPackage(pkg) {
+			continue
+		}
+		db, ok := pkg.Scope().Lookup(gormDBType).(*types.TypeName)
+		return ok && db.Type() != named && types.Identical(db.Type().Underlying(), st)
+	}
+	return false
+}
+
 // =============================================================================
 // Method Classification
 // =============================================================================
@@ -112,9 +331,66 @@ var immutableReturningMethods = map[string]struct{}{
 // These methods (Session, WithContext, Debug, Open, Begin, Transaction) return a new
 // immutable instance that can be branched freely without pollution.
 //
+// The classification depends on the name alone: gorm clones the statement
+// whatever the arguments, so Session(&gorm.Session{NewDB: true}), Session with a
+// stored *gorm.Session and WithContext with any context all return immutable.
+//
 // Note: This is different from user-defined pure functions (//gormreuse:pure),
 // which only guarantee no argument pollution - they may return mutable values.
 func IsImmutableReturningBuiltin(name string) bool {
 	_, ok := immutableReturningMethods[name]
 	return ok
 }
+
+// finisherMethods are the gorm methods that execute the statement built so
+// far. Their result is the finished *gorm.DB (carrying Error and RowsAffected),
+// so assigning it never starts a new chain from the receiver, and a reuse
+// through one is never fixed by reassignment (q = q.Scan(&x)).
+//
+// This map is unexported to prevent external modification.
+var finisherMethods = map[string]struct{}{
+	"Find":          {},
+	"First":         {},
+	"Last":          {},
+	"Take":          {},
+	"Count":         {},
+	"Pluck":         {},
+	"Scan":          {},
+	"Row":           {},
+	"Rows":          {},
+	"ScanRows":      {},
+	"Create":        {},
+	"Save":          {},
+	"Update":        {},
+	"Updates":       {},
+	"Delete":        {},
+	"Exec":          {},
+	"Transaction":   {},
+	"FirstOrCreate": {}, // terminal (executes); #71 secondary
+	"FirstOrInit":   {}, // terminal (executes); #71 secondary
+}
+
+// IsFinisherMethod returns true if the gorm method executes the statement,
+// like Find, Count or Create. A finisher is always a use of its receiver, even
+// when its result is assigned.
+func IsFinisherMethod(name string) bool {
+	_, ok := finisherMethods[name]
+	return ok
+}
+
+// IsImmutableReturningName reports whether name is an immutable-returning
+// builtin or a method configured with -immutable-method. The receiver is not
+// checked; see IsImmutableReturningMethod.
+func (m *Matcher) IsImmutableReturningName(name string) bool {
+	return IsImmutableReturningBuiltin(name) || m != nil && m.immutable[name]
+}
+
+// IsImmutableReturningMethod reports whether a method name called on a receiver
+// of type recv is an immutable-returning builtin or configured method. Besides
+// the name, the receiver must be gorm.DB or a configured DB type: a builder
+// type passes IsGormDB, but its own Session or WithContext is user code that
+// may return the wrapped *gorm.DB unchanged, and a method of the same name on
+// any other type is not affected at all.
+func (m *Matcher) IsImmutableReturningMethod(name string, recv types.Type) bool {
+	return m.IsImmutableReturningName(name) && m.IsGormDB(recv) && !m.IsBuilder(recv)
+}
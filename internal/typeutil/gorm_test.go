@@ -187,3 +187,224 @@ func TestIsGormDBNamed(t *testing.T) {
 		}
 	})
 }
+
+func TestNewMatcher(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		input   string
+		wantErr bool
+	}{
+		{"qualified name", "github.com/acme/db.Handle", false},
+		{"single-segment path", "db.Handle", false},
+		{"no dot", "Handle", true},
+		{"empty path", ".Handle", true},
+		{"empty name", "github.com/acme/db.", true},
+		{"path only", "github.com/acme/db", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := NewMatcher([]string{tt.input}, nil, nil, false)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("NewMatcher(%q) error = %v, wantErr %v", tt.input, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestMatcherIsGormDB(t *testing.T) {
+	t.Parallel()
+
+	gormPkg := types.NewPackage("gorm.io/gorm", "gorm")
+	dbType := types.NewNamed(types.NewTypeName(0, gormPkg, "DB", nil), types.NewStruct(nil, nil), nil)
+
+	acmePkg := types.NewPackage("github.com/acme/db", "db")
+	handle := types.NewNamed(types.NewTypeName(0, acmePkg, "Handle", nil), types.NewStruct(nil, nil), nil)
+
+	// type DBPtr *gorm.DB
+	dbPtr := types.NewNamed(types.NewTypeName(0, acmePkg, "DBPtr", nil), types.NewPointer(dbType), nil)
+
+	configured, err := NewMatcher([]string{"github.com/acme/db.Handle"}, nil, nil, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	underlying, err := NewMatcher(nil, nil, nil, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		matcher *Matcher
+		typ     types.Type
+		want    bool
+	}{
+		{"nil matcher accepts *gorm.DB", nil, types.NewPointer(dbType), true},
+		{"nil matcher rejects vendored type", nil, types.NewPointer(handle), false},
+		{"configured accepts vendored type", configured, types.NewPointer(handle), true},
+		{"configured still accepts *gorm.DB", configured, types.NewPointer(dbType), true},
+		{"configured rejects named pointer", configured, dbPtr, false},
+		{"underlying accepts named pointer", underlying, dbPtr, true},
+		{"underlying rejects vendored type", underlying, types.NewPointer(handle), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := tt.matcher.IsGormDB(tt.typ); got != tt.want {
+				t.Errorf("IsGormDB(%v) = %v, want %v", tt.typ, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMatcherIsBuilder(t *testing.T) {
+	t.Parallel()
+
+	pkg := types.NewPackage("github.com/acme/repo", "repo")
+	query := types.NewNamed(types.NewTypeName(0, pkg, "Query", nil), types.NewStruct(nil, nil), nil)
+	other := types.NewNamed(types.NewTypeName(0, pkg, "Other", nil), types.NewStruct(nil, nil), nil)
+
+	m, err := NewMatcher(nil, []string{"github.com/acme/repo.Query"}, nil, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		matcher *Matcher
+		typ     types.Type
+		want    bool
+	}{
+		{"builder value", m, query, true},
+		{"builder pointer", m, types.NewPointer(query), true},
+		{"other type", m, other, false},
+		{"nil matcher", nil, query, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := tt.matcher.IsBuilder(tt.typ); got != tt.want {
+				t.Errorf("IsBuilder(%v) = %v, want %v", tt.typ, got, tt.want)
+			}
+			if got := tt.matcher.IsGormDB(tt.typ); got != tt.want {
+				t.Errorf("IsGormDB(%v) = %v, want %v", tt.typ, got, tt.want)
+			}
+		})
+	}
+
+	if _, err := NewMatcher(nil, []string{"Query"}, nil, false); err == nil {
+		t.Error("NewMatcher with an unqualified builder type should fail")
+	}
+}
+
+func TestMatcherIsImmutableReturningMethod(t *testing.T) {
+	t.Parallel()
+
+	gormPkg := types.NewPackage("gorm.io/gorm", "gorm")
+	dbPtr := types.NewPointer(types.NewNamed(types.NewTypeName(0, gormPkg, "DB", nil), types.NewStruct(nil, nil), nil))
+
+	pkg := types.NewPackage("github.com/acme/repo", "repo")
+	query := types.NewNamed(types.NewTypeName(0, pkg, "Query", nil), types.NewStruct(nil, nil), nil)
+
+	m, err := NewMatcher(nil, []string{"github.com/acme/repo.Query"}, []string{"ReadOnly"}, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name   string
+		method string
+		recv   types.Type
+		want   bool
+	}{
+		{"gorm Session", "Session", dbPtr, true},
+		{"gorm WithContext", "WithContext", dbPtr, true},
+		{"gorm Where", "Where", dbPtr, false},
+		{"builder Session", "Session", query, false},
+		{"builder pointer Session", "Session", types.NewPointer(query), false},
+		{"non-gorm Session", "Session", types.Typ[types.Int], false},
+		{"gorm configured ReadOnly", "ReadOnly", dbPtr, true},
+		{"builder configured ReadOnly", "ReadOnly", query, false},
+		{"non-gorm configured ReadOnly", "ReadOnly", types.Typ[types.Int], false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := m.IsImmutableReturningMethod(tt.method, tt.recv); got != tt.want {
+				t.Errorf("IsImmutableReturningMethod(%q, %v) = %v, want %v", tt.method, tt.recv, got, tt.want)
+			}
+		})
+	}
+
+	if _, err := NewMatcher(nil, nil, []string{"db.ReadOnly"}, false); err == nil {
+		t.Error("NewMatcher with a qualified immutable method should fail")
+	}
+}
+
+func TestMatcherIsGormDBLayout(t *testing.T) {
+	t.Parallel()
+
+	// gorm.DB needs a field declared in gorm.io/gorm so that a defined type
+	// over it is recognized by its struct.
+	gormPkg := types.NewPackage("gorm.io/gorm", "gorm")
+	st := types.NewStruct([]*types.Var{types.NewField(0, gormPkg, "Error", types.Universe.Lookup("error").Type(), false)}, nil)
+	dbName := types.NewTypeName(0, gormPkg, "DB", nil)
+	dbType := types.NewNamed(dbName, st, nil)
+	gormPkg.Scope().Insert(dbName)
+
+	userPkg := types.NewPackage("example.com/app", "app")
+	// type Handle gorm.DB
+	handle := types.NewNamed(types.NewTypeName(0, userPkg, "Handle", nil), st, nil)
+	other := types.NewNamed(types.NewTypeName(0, userPkg, "Other", nil), types.NewStruct(nil, nil), nil)
+
+	tests := []struct {
+		name string
+		typ  types.Type
+		want bool
+	}{
+		{"*gorm.DB", types.NewPointer(dbType), true},
+		{"pointer to defined type over gorm.DB", types.NewPointer(handle), true},
+		{"gorm.DB value", dbType, false},
+		{"pointer to unrelated struct", types.NewPointer(other), false},
+		{"unsafe.Pointer", types.Typ[types.UnsafePointer], false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := (*Matcher)(nil).IsGormDBLayout(tt.typ); got != tt.want {
+				t.Errorf("IsGormDBLayout(%v) = %v, want %v", tt.typ, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIsFinisherMethod(t *testing.T) {
+	t.Parallel()
+	finishers := []string{
+		"Scan", "Row", "Rows", "Pluck", "Take", "First", "Last", "Find", "Count",
+		"Create", "Save", "Update", "Updates", "Delete", "Exec", "Transaction",
+	}
+	for _, m := range finishers {
+		if !IsFinisherMethod(m) {
+			t.Errorf("%q should be a finisher", m)
+		}
+	}
+	nonFinishers := []string{"Where", "Order", "Limit", "Session", "WithContext", "Preload", "Scopes", ""}
+	for _, m := range nonFinishers {
+		if IsFinisherMethod(m) {
+			t.Errorf("%q should not be a finisher", m)
+		}
+	}
+}
@@ -0,0 +1,70 @@
+package internal
+
+import (
+	"cmp"
+	"go/token"
+	"slices"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/analysis/passes/buildssa"
+	"golang.org/x/tools/go/ssa"
+
+	"github.com/mpyw/gormreuse/internal/directive"
+	"github.com/mpyw/gormreuse/internal/ssa/pollution"
+)
+
+// warnUnvalidatedPure reports the //gormreuse:pure functions of other
+// packages that the analyzed package calls and whose source cannot be read
+// (-warn-unvalidated-pure). A body with readable source is validated when its
+// own package is analyzed; one without, as in a stripped build, is trusted by
+// its fact alone (see DirectiveFuncSet.IsTrusted) and checked nowhere.
+//
+// Each function is reported once, at its first call in the package, so a
+// helper called all over the package adds a single note.
+func warnUnvalidatedPure(
+	pass *analysis.Pass,
+	ssaInfo *buildssa.SSA,
+	pureFuncs *directive.DirectiveFuncSet,
+	skip func(*ssa.Function, bool) bool,
+) {
+	first := make(map[*ssa.Function]token.Pos)
+	trusted := make(map[*ssa.Function]bool)
+	for _, fn := range ssaInfo.SrcFuncs {
+		if skip(fn, false) {
+			continue
+		}
+		for _, block := range fn.Blocks {
+			for _, instr := range block.Instrs {
+				call, ok := instr.(ssa.CallInstruction)
+				if !ok || !call.Pos().IsValid() {
+					continue
+				}
+				callee := call.Common().StaticCallee()
+				if callee == nil {
+					continue
+				}
+				if origin := callee.Origin(); origin != nil {
+					callee = origin
+				}
+				ok, known := trusted[callee]
+				if !known {
+					ok = pureFuncs.IsTrusted(callee)
+					trusted[callee] = ok
+				}
+				if pos, seen := first[callee]; ok && (!seen || call.Pos() < pos) {
+					first[callee] = call.Pos()
+				}
+			}
+		}
+	}
+
+	callees := make([]*ssa.Function, 0, len(first))
+	for callee := range first {
+		callees = append(callees, callee)
+	}
+	slices.SortFunc(callees, func(a, b *ssa.Function) int { return cmp.Compare(first[a], first[b]) })
+	for _, callee := range callees {
+		report(pass, first[callee], pollution.KindUnvalidatedPure,
+			callee.RelString(pass.Pkg)+" is trusted as //gormreuse:pure without validation: its source cannot be read")
+	}
+}
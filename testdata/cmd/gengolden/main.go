@@ -26,12 +26,12 @@ func main() {
 		fmt.Printf("Generating golden for %s...\n", base)
 
 		srcPath := filepath.Join(srcDir, base)
-		_, fixed, err := goldentest.ApplyFixes(testdata, "gormreuse", srcPath, gormreuse.Analyzer)
+		golden, err := goldentest.Golden(testdata, "gormreuse", srcPath, gormreuse.Analyzer)
 		if err != nil {
 			fmt.Printf("  Error: %v\n", err)
 			continue
 		}
-		if err := os.WriteFile(srcPath+".golden", fixed, 0o644); err != nil {
+		if err := os.WriteFile(srcPath+".golden", golden, 0o644); err != nil {
 			fmt.Printf("  Error: %v\n", err)
 			continue
 		}
@@ -0,0 +1,76 @@
+package assumepure
+
+import "gorm.io/gorm"
+
+func applyFilter(db *gorm.DB) *gorm.DB { return db.Where("active = ?", true) }
+
+func describe(*gorm.DB) string { return "users" }
+
+//gormreuse:impure
+func runCount(db *gorm.DB) {
+	var n int64
+	db.Count(&n)
+}
+
+//gormreuse:sink
+func logQuery(*gorm.DB) {}
+
+//gormreuse:impure // want `unused gormreuse:impure directive: no \*gorm\.DB parameter`
+func noDB(x int) int { return x }
+
+type repo struct{}
+
+func (repo) scope(db *gorm.DB) {}
+
+// =============================================================================
+// SHOULD NOT REPORT - Unannotated helpers are assumed pure
+// =============================================================================
+
+// helperThenUse passes the root to an unannotated helper before using it.
+func helperThenUse(db *gorm.DB) {
+	q := db.Where("x = ?", 1)
+	describe(q)
+	q.Find(nil)
+}
+
+// helperTwice passes the root to unannotated helpers, a method included.
+func helperTwice(db *gorm.DB) {
+	q := db.Where("x = ?", 1)
+	applyFilter(q)
+	repo{}.scope(q)
+	q.Count(nil)
+}
+
+// =============================================================================
+// SHOULD REPORT - Impure and sink helpers, gorm methods and closures pollute
+// =============================================================================
+
+// impureThenUse passes the root to a //gormreuse:impure helper.
+func impureThenUse(db *gorm.DB) {
+	q := db.Where("x = ?", 1)
+	runCount(q)
+	q.Find(nil) // want `\*gorm\.DB reused: second branch from mutable root`
+}
+
+// sinkThenUse passes the root to a //gormreuse:sink helper.
+func sinkThenUse(db *gorm.DB) {
+	q := db.Where("x = ?", 1)
+	logQuery(q)
+	q.Find(nil) // want `\*gorm\.DB reused: second branch from mutable root`
+}
+
+// gormMethodsStillPollute branches the root twice with gorm methods.
+func gormMethodsStillPollute(db *gorm.DB) {
+	q := db.Where("x = ?", 1)
+	q.Count(nil)
+	q.Find(nil) // want `\*gorm\.DB reused: second branch from mutable root`
+}
+
+// closureStillPollutes passes the root to a closure, which cannot carry
+// //gormreuse:impure.
+func closureStillPollutes(db *gorm.DB) {
+	q := db.Where("x = ?", 1)
+	count := func(d *gorm.DB) { d.Count(nil) }
+	count(q)
+	q.Find(nil) // want `\*gorm\.DB reused: second branch from mutable root`
+}
@@ -0,0 +1,16 @@
+package off
+
+import "gorm.io/gorm"
+
+func describe(*gorm.DB) string { return "users" }
+
+// =============================================================================
+// Without -assume-pure-helpers, an unannotated helper pollutes its argument.
+// =============================================================================
+
+// helperThenUse passes the root to an unannotated helper before using it.
+func helperThenUse(db *gorm.DB) {
+	q := db.Where("x = ?", 1)
+	describe(q)
+	q.Find(nil) // want `\*gorm\.DB reused: second branch from mutable root`
+}
@@ -0,0 +1,35 @@
+package baseline
+
+import "gorm.io/gorm"
+
+// legacy reuses a root: the reuse is listed in baseline.txt, which was
+// written before the logging lines were added above it.
+func legacy(db *gorm.DB, logf func(string)) {
+	logf("legacy")
+	q := db.Where("x = ?", 1)
+	logf("find")
+	q.Find(nil)
+	q.Count(nil)
+}
+
+// legacyLoop finishes a root in a loop: listed in baseline.txt too.
+func legacyLoop(db *gorm.DB, items []int) {
+	q := db.Where("x = ?", 1)
+	for range items {
+		q.Find(nil)
+	}
+}
+
+// introduced reuses a root the same way legacy does, but is not listed.
+func introduced(db *gorm.DB) {
+	q := db.Where("x = ?", 1)
+	q.Find(nil)
+	q.Count(nil) // want `\*gorm\.DB reused: second branch from mutable root`
+}
+
+// edited is listed under its former reused line, q.Count(nil).
+func edited(db *gorm.DB) {
+	q := db.Where("x = ?", 1)
+	q.Find(nil)
+	q.First(nil) // want `\*gorm\.DB reused: second branch from mutable root`
+}
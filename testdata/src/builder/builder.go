@@ -0,0 +1,67 @@
+// Package builder tests -builder-type: a value-receiver wrapper around
+// *gorm.DB whose chain methods return the wrapper is tracked like *gorm.DB.
+package builder
+
+import "gorm.io/gorm"
+
+// Query is a value-receiver builder wrapping *gorm.DB.
+type Query struct {
+	db *gorm.DB
+}
+
+// New wraps db in a Query.
+func New(db *gorm.DB) Query {
+	return Query{db: db}
+}
+
+// Where chains a condition, returning the updated copy.
+func (q Query) Where(cond string) Query {
+	q.db = q.db.Where(cond)
+	return q
+}
+
+// Find finishes the query.
+func (q Query) Find(dest any) {
+	q.db.Find(dest)
+}
+
+// Count finishes the query.
+func (q Query) Count(n *int64) {
+	q.db.Count(n)
+}
+
+// =============================================================================
+// SHOULD REPORT - builder reused after finishing
+// =============================================================================
+
+// builderDoubleFind finishes the same builder twice.
+func builderDoubleFind(db *gorm.DB) {
+	x := New(db.Session(&gorm.Session{})).Where("a")
+	x.Find(nil)
+	x.Find(nil) // want `\*gorm\.DB reused: second branch from mutable root`
+}
+
+// builderBranches branches two chains from the same builder.
+func builderBranches(db *gorm.DB) {
+	x := New(db.Session(&gorm.Session{})).Where("a")
+	x.Where("b").Find(nil)
+	x.Where("c").Count(nil) // want `\*gorm\.DB reused: second branch from mutable root`
+}
+
+// =============================================================================
+// SHOULD NOT REPORT - single use per builder
+// =============================================================================
+
+// builderSingleChain finishes one chain.
+func builderSingleChain(db *gorm.DB) {
+	New(db.Session(&gorm.Session{})).Where("a").Where("b").Find(nil)
+}
+
+// builderReassigned reassigns the builder before finishing it.
+func builderReassigned(db *gorm.DB, conds []string) {
+	x := New(db.Session(&gorm.Session{}))
+	for _, c := range conds {
+		x = x.Where(c)
+	}
+	x.Find(nil)
+}
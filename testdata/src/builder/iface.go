@@ -0,0 +1,74 @@
+package builder
+
+import "gorm.io/gorm"
+
+// Querier is a gorm-like interface: with -builder-type=builder.Querier, its
+// method calls are tracked like *gorm.DB methods on the wrapped value.
+type Querier interface {
+	Where(cond string) Querier
+	Find(dest any) Querier
+}
+
+// querier implements Querier by wrapping *gorm.DB.
+type querier struct {
+	db *gorm.DB
+}
+
+func (q querier) Where(cond string) Querier { return querier{db: q.db.Where(cond)} }
+func (q querier) Find(dest any) Querier     { return querier{db: q.db.Find(dest)} }
+
+// wrap returns db behind the Querier interface.
+func wrap(db *gorm.DB) Querier {
+	return querier{db: db}
+}
+
+// =============================================================================
+// SHOULD REPORT - interface finished twice
+// =============================================================================
+
+// querierDoubleFind finishes the wrapped query twice through the interface.
+func querierDoubleFind(db *gorm.DB) {
+	q := db.Session(&gorm.Session{}).Where("a")
+	var x Querier = wrap(q)
+	x.Find(nil)
+	x.Find(nil) // want `\*gorm\.DB reused: second branch from mutable root`
+}
+
+// querierBranches branches two chains from the same interface value.
+func querierBranches(db *gorm.DB) {
+	x := wrap(db.Session(&gorm.Session{})).Where("a")
+	x.Where("b").Find(nil)
+	x.Where("c").Find(nil) // want `\*gorm\.DB reused: second branch from mutable root`
+}
+
+// querierBoxed boxes the wrapper into the interface directly.
+func querierBoxed(db *gorm.DB) {
+	var x Querier = querier{db: db.Session(&gorm.Session{})}
+	x.Find(nil)
+	x.Find(nil) // want `\*gorm\.DB reused: second branch from mutable root`
+}
+
+// querierDeferred finishes the interface value, then again in a defer.
+func querierDeferred(db *gorm.DB) {
+	x := wrap(db.Session(&gorm.Session{}))
+	defer x.Find(nil) // want `\*gorm\.DB reused: second branch from mutable root`
+	x.Find(nil)
+}
+
+// =============================================================================
+// SHOULD NOT REPORT - single use per interface value
+// =============================================================================
+
+// querierSingleChain finishes one chain.
+func querierSingleChain(db *gorm.DB) {
+	wrap(db.Session(&gorm.Session{})).Where("a").Find(nil)
+}
+
+// querierReassigned reassigns the interface value before finishing it.
+func querierReassigned(db *gorm.DB, conds []string) {
+	x := wrap(db.Session(&gorm.Session{}))
+	for _, c := range conds {
+		x = x.Where(c)
+	}
+	x.Find(nil)
+}
@@ -0,0 +1,40 @@
+package builder
+
+import "gorm.io/gorm"
+
+// Session shares its name with gorm's immutable-returning builtin, but it is
+// the builder's own method: it returns the same wrapped *gorm.DB, so the
+// result is still mutable.
+func (q Query) Session(label string) Query {
+	if label != "" {
+		q.db = q.db.Where("label = ?", label)
+	}
+	return q
+}
+
+// =============================================================================
+// SHOULD REPORT - builder Session is not immutable-returning
+// =============================================================================
+
+// builderSessionBranches branches twice from the builder's Session result.
+func builderSessionBranches(db *gorm.DB, label string) {
+	x := New(db.Session(&gorm.Session{})).Where("a").Session(label)
+	x.Where("b").Find(nil)
+	x.Where("c").Count(nil) // want `\*gorm\.DB reused: second branch from mutable root`
+}
+
+// builderSessionReused finishes the builder, then reuses it through Session.
+func builderSessionReused(db *gorm.DB, label string) {
+	x := New(db.Session(&gorm.Session{})).Where("a")
+	x.Find(nil)
+	x.Session(label).Find(nil) // want `\*gorm\.DB reused: second branch from mutable root`
+}
+
+// =============================================================================
+// SHOULD NOT REPORT - single chain through the builder's Session
+// =============================================================================
+
+// builderSessionSingleChain finishes one chain through Session.
+func builderSessionSingleChain(db *gorm.DB, label string) {
+	New(db.Session(&gorm.Session{})).Where("a").Session(label).Find(nil)
+}
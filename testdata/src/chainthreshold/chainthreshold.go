@@ -0,0 +1,39 @@
+package chainthreshold
+
+import (
+	"gorm.io/gorm"
+)
+
+// Run with -chain-threshold=3: a reuse in a chain of more than three method
+// calls is also offered the root Session fix, labeled as recommended. A
+// finisher reuse is offered it whatever the length of its chain.
+
+// shortChain reuses q in a chain of two calls: the reassignment fix only.
+func shortChain(db *gorm.DB) {
+	q := db.Where("x = ?", 1)
+	q.Where("a = ?", 1)
+	q.Where("b = ?", 2).Order("id") // want `\*gorm\.DB reused: second branch from mutable root`
+}
+
+// thresholdChain reuses q in a chain of exactly three calls, which is not
+// above the threshold.
+func thresholdChain(db *gorm.DB) {
+	q := db.Where("x = ?", 1)
+	q.Where("a = ?", 1)
+	q.Where("b = ?", 2).Order("id").Limit(10) // want `\*gorm\.DB reused: second branch from mutable root`
+}
+
+// longChain reuses q in a chain of five calls: both fixes.
+func longChain(db *gorm.DB) {
+	q := db.Where("x = ?", 1)
+	q.Where("a = ?", 1)
+	q.Where("b = ?", 2).Order("id").Limit(10).Offset(20).Group("name") // want `\*gorm\.DB reused: second branch from mutable root`
+}
+
+// longChainAssigned reuses q in a long chain built into a variable.
+func longChainAssigned(db *gorm.DB) *gorm.DB {
+	q := db.Where("x = ?", 1)
+	q.Where("a = ?", 1)
+	r := q.Where("b = ?", 2).Order("id").Limit(10).Offset(20) // want `\*gorm\.DB reused: second branch from mutable root`
+	return r
+}
@@ -0,0 +1,17 @@
+package coalesce
+
+import "gorm.io/gorm"
+
+func merge(db *gorm.DB, cond bool) {
+	q1 := db.Where("a")
+	q2 := db.Where("b")
+	q1.Find(nil)
+	q2.Find(nil)
+	var q *gorm.DB
+	if cond {
+		q = q1
+	} else {
+		q = q2
+	}
+	q.Count(nil) // want `\*gorm\.DB reused`
+}
@@ -0,0 +1,30 @@
+// Package enableonly tests -enable-only=PURE: only PURE diagnostics are
+// reported and the reuse analysis is skipped.
+package enableonly
+
+import "gorm.io/gorm"
+
+// reuse is a BRANCH diagnostic, which is not enabled.
+func reuse(db *gorm.DB) {
+	q := db.Where("x")
+	q.Find(nil)
+	q.Count(nil)
+}
+
+// brokenPure is a PURE diagnostic.
+//
+//gormreuse:pure
+func brokenPure(db *gorm.DB) {
+	db.Find(nil) // want `pure function pollutes \*gorm\.DB argument by calling Find`
+}
+
+// unusedIgnore is an UNUSED-IGNORE diagnostic, which is not enabled.
+func unusedIgnore(db *gorm.DB) {
+	//gormreuse:ignore
+	db.Session(&gorm.Session{}).Find(nil)
+}
+
+// unusedPure is an UNUSED-DIRECTIVE diagnostic, which is not enabled.
+//
+//gormreuse:pure
+func unusedPure() {}
@@ -0,0 +1,12 @@
+// Package exclude tests -exclude: files matching an exclude glob are skipped
+// like generated files, while their siblings are still analyzed.
+package exclude
+
+import "gorm.io/gorm"
+
+// reuse is reported: exclude.go matches no glob.
+func reuse(db *gorm.DB) {
+	q := db.Where("x")
+	q.Find(nil)
+	q.Count(nil) // want `\*gorm\.DB reused: second branch from mutable root`
+}
@@ -0,0 +1,10 @@
+package exclude
+
+import "gorm.io/gorm"
+
+// legacyReuse is not reported: legacy_*.go is excluded.
+func legacyReuse(db *gorm.DB) {
+	q := db.Where("x")
+	q.Find(nil)
+	q.Count(nil)
+}
@@ -0,0 +1,11 @@
+// Package lib is not analyzed: its directory third_party is excluded.
+package lib
+
+import "gorm.io/gorm"
+
+// Reuse is not reported.
+func Reuse(db *gorm.DB) {
+	q := db.Where("x")
+	q.Find(nil)
+	q.Count(nil)
+}
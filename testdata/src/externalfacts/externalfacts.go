@@ -0,0 +1,49 @@
+package externalfacts
+
+import (
+	"gorm.io/gorm"
+
+	"purelib"
+)
+
+// =============================================================================
+// SHOULD REPORT - Unmarked function of an imported package
+// =============================================================================
+
+func loadThenFind(db *gorm.DB) {
+	q := db.Where("x = ?", 1)
+	purelib.Load(q, nil) // First use
+	q.Find(nil)          // want `\*gorm\.DB reused: second branch from mutable root`
+}
+
+func saveThenFind(db *gorm.DB) {
+	q := db.Where("x = ?", 1)
+	purelib.Save(q, nil) // First use (SinkFact)
+	q.Find(nil)          // want `\*gorm\.DB reused: second branch from mutable root`
+}
+
+// =============================================================================
+// SHOULD NOT REPORT - Pure function of an imported package (PureFact)
+// =============================================================================
+
+func countThenFind(db *gorm.DB) {
+	q := db.Where("x = ?", 1)
+	purelib.Count(q)
+	q.Find(nil)
+}
+
+// =============================================================================
+// Interface methods of an imported package, looked up in its source
+// =============================================================================
+
+func buildThenFind(db *gorm.DB, b purelib.Builder) {
+	q := db.Where("x = ?", 1)
+	b.Build(q) // Pure interface method
+	q.Find(nil)
+}
+
+func applyThenFind(db *gorm.DB, b purelib.Builder) {
+	q := db.Where("x = ?", 1)
+	b.Apply(q)  // First use
+	q.Find(nil) // want `\*gorm\.DB reused: second branch from mutable root`
+}
@@ -0,0 +1,29 @@
+package factsconsumer
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+
+	"purelib"
+)
+
+// =============================================================================
+// SHOULD REPORT - Calls classified by the facts of purelib
+// =============================================================================
+
+func callPure(ctx context.Context) {
+	new(purelib.Orm).DB(ctx) // want `pure: DB`
+	new(purelib.Orm).GetDB() // want `pure: GetDB`
+	purelib.PureFactory()    // want `pure: PureFactory`
+	purelib.Scoped()         // want `immutable-return: Scoped`
+}
+
+// =============================================================================
+// SHOULD NOT REPORT - Unmarked functions
+// =============================================================================
+
+func callPlain(db *gorm.DB) {
+	db.Find(nil)
+	_ = context.Background()
+}
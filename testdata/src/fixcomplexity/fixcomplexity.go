@@ -0,0 +1,55 @@
+package fixcomplexity
+
+import "gorm.io/gorm"
+
+// =============================================================================
+// trivial - fixed in place with Session() or a reassignment
+// =============================================================================
+
+// simpleReuse branches the same root twice in straight-line code.
+func simpleReuse(db *gorm.DB) {
+	q := db.Where("x")
+	q.Find(nil)
+	q.Count(nil) // want `\*gorm\.DB reused: second branch from mutable root .* \[fix: trivial\]`
+}
+
+// branchReuse branches the root on both sides of an if and after it.
+func branchReuse(db *gorm.DB, cond bool) {
+	q := db.Where("x")
+	if cond {
+		q.Find(nil)
+	}
+	q.Count(nil) // want `\[fix: trivial\]`
+}
+
+// =============================================================================
+// moderate - the loop must be restructured
+// =============================================================================
+
+// loopReuse branches a root defined outside the loop on every iteration.
+func loopReuse(db *gorm.DB, ids []int) {
+	q := db.Where("x")
+	for _, id := range ids {
+		q.Where("id = ?", id).Find(nil) // want `\[fix: moderate\]`
+	}
+}
+
+// =============================================================================
+// manual - the root crosses a function boundary
+// =============================================================================
+
+// closureReuse branches the root both outside and inside a closure.
+func closureReuse(db *gorm.DB) {
+	q := db.Where("x")
+	q.Find(nil)
+	func() {
+		q.Count(nil) // want `\[fix: manual\]`
+	}()
+}
+
+// goroutineReuse spawns a goroutine that branches an already-used root.
+func goroutineReuse(db *gorm.DB) {
+	q := db.Where("x")
+	q.Find(nil)
+	go q.Count(nil) // want `\[fix: manual\]`
+}
@@ -0,0 +1,43 @@
+package fixdiff
+
+import "gorm.io/gorm"
+
+// twice branches a root twice: the root gets a Session.
+func twice(db *gorm.DB) {
+	q := db.Where("x")
+	q.Find(nil)
+	q.Count(nil) // want `\*gorm\.DB reused: second branch from mutable root`
+}
+
+// thrice branches a root three times: both reports share one Session edit.
+func thrice(db *gorm.DB) {
+	q := db.Where("x")
+	q.Find(nil)
+	q.Count(nil) // want `\*gorm\.DB reused: second branch from mutable root`
+	q.First(nil) // want `\*gorm\.DB reused: second branch from mutable root`
+}
+
+// clean has nothing to fix, and no hunk.
+func clean(db *gorm.DB) {
+	q := db.Where("x").Session(&gorm.Session{})
+	q.Find(nil)
+	q.Count(nil)
+}
+
+// conditions adds a condition that later branches would share.
+func conditions(db *gorm.DB, cond bool) {
+	q := db.Where("x")
+	q.Where("y")
+	if cond {
+		q.Find(nil) // want `\*gorm\.DB reused: second branch from mutable root`
+	}
+	q.Count(nil) // want `\*gorm\.DB reused: second branch from mutable root`
+}
+
+// loop branches a root defined outside the loop on every iteration.
+func loop(db *gorm.DB, ids []int) {
+	q := db.Where("x")
+	for _, id := range ids {
+		q.Where("id = ?", id).Find(nil) // want `\*gorm\.DB reused: second branch from mutable root`
+	}
+}
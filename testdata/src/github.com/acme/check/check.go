@@ -0,0 +1,7 @@
+// Package check is a stub of an in-house assertion package.
+package check
+
+// NotNil reports whether v is not nil.
+func NotNil(v interface{}) bool {
+	return v != nil
+}
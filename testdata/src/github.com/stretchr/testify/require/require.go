@@ -9,3 +9,7 @@ type TestingT interface {
 // NoError asserts that err is nil.
 func NoError(t TestingT, err error, msgAndArgs ...interface{}) {
 }
+
+// NotNil asserts that object is not nil.
+func NotNil(t TestingT, object interface{}, msgAndArgs ...interface{}) {
+}
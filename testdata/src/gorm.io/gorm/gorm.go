@@ -198,3 +198,34 @@ func (db *DB) SavePoint(name string) *DB { return db }
 
 // RollbackTo rollbacks to save point.
 func (db *DB) RollbackTo(name string) *DB { return db }
+
+// =============================================================================
+// Association Mode - Consumes the receiver without returning *DB
+// =============================================================================
+
+// Association is the association mode of a model.
+type Association struct {
+	DB    *DB
+	Error error
+}
+
+// Association starts association mode for column of the current model.
+func (db *DB) Association(column string) *Association { return &Association{DB: db} }
+
+// Find finds the associated records.
+func (association *Association) Find(out interface{}, conds ...interface{}) error { return nil }
+
+// Append appends associations.
+func (association *Association) Append(values ...interface{}) error { return nil }
+
+// Replace replaces associations.
+func (association *Association) Replace(values ...interface{}) error { return nil }
+
+// Delete deletes associations.
+func (association *Association) Delete(values ...interface{}) error { return nil }
+
+// Clear clears associations.
+func (association *Association) Clear() error { return nil }
+
+// Count counts associations.
+func (association *Association) Count() int64 { return 0 }
@@ -122,17 +122,18 @@ func functionReturnWithSession(db *gorm.DB) {
 // =============================================================================
 
 // sessionAfterPolluted demonstrates that Session() after pollution doesn't help.
+// It is reported in the LATE-SESSION category (see late_session.go).
 func sessionAfterPolluted(db *gorm.DB) {
 	q := db.Model(&User{}).Where("active = ?", true)
 	q.Count(new(int64))                        // q is polluted
-	q.Session(&gorm.Session{}).Find(&[]User{}) // want `\*gorm\.DB reused: second branch from mutable root`
+	q.Session(&gorm.Session{}).Find(&[]User{}) // want `\*gorm\.DB reused: Session on an already-polluted value`
 }
 
 // sessionOnPollutedValue demonstrates Session on already-polluted value.
 func sessionOnPollutedValue(db *gorm.DB) {
 	q := db.Where("x = ?", 1)
 	q.Find(nil)
-	q.Session(&gorm.Session{}).Count(nil) // want `\*gorm\.DB reused: second branch from mutable root`
+	q.Session(&gorm.Session{}).Count(nil) // want `\*gorm\.DB reused: Session on an already-polluted value`
 }
 
 // multipleDirectUsesWithoutSession demonstrates multiple uses without Session.
@@ -140,7 +141,7 @@ func multipleDirectUsesWithoutSession(db *gorm.DB) {
 	q := db.Where("x = ?", 1)
 	q.Find(nil)
 	q.Count(nil)                          // want `\*gorm\.DB reused: second branch from mutable root`
-	q.Session(&gorm.Session{}).First(nil) // want `\*gorm\.DB reused: second branch from mutable root`
+	q.Session(&gorm.Session{}).First(nil) // want `\*gorm\.DB reused: Session on an already-polluted value`
 }
 
 // =============================================================================
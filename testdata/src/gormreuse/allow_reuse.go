@@ -0,0 +1,58 @@
+package internal
+
+import "gorm.io/gorm"
+
+// =============================================================================
+// SHOULD NOT REPORT - allow-reuse directives
+// =============================================================================
+
+// allowReuseOnSameLine marks the second branch as an intentional reuse.
+func allowReuseOnSameLine(db *gorm.DB) {
+	q := db.Where("active = ?", true)
+	q.Find(nil)
+	q.Count(nil) //gormreuse:allow-reuse
+}
+
+// allowReuseOnPreviousLine marks the reuse on the next line.
+func allowReuseOnPreviousLine(db *gorm.DB) {
+	q := db.Where("active = ?", true)
+	q.Find(nil)
+	//gormreuse:allow-reuse // the loop below runs at most once
+	q.Count(nil)
+}
+
+// allowReuseOnRootDefinition allows every reuse of the root.
+func allowReuseOnRootDefinition(db *gorm.DB) {
+	q := db.Where("active = ?", true) //gormreuse:allow-reuse
+	q.Find(nil)
+	q.Count(nil)
+	q.First(nil)
+}
+
+// =============================================================================
+// SHOULD REPORT - unused allow-reuse directives
+// =============================================================================
+
+// unusedAllowReuseOnSafeCode marks a use that is not a reuse.
+func unusedAllowReuseOnSafeCode(db *gorm.DB) {
+	q := db.Where("x = ?", 1).Session(&gorm.Session{})
+	q.Find(nil)
+	//gormreuse:allow-reuse // want `unused gormreuse:allow-reuse directive`
+	q.Count(nil)
+}
+
+// unusedAllowReuseOnRootDefinition marks a root that is never reused.
+func unusedAllowReuseOnRootDefinition(db *gorm.DB) {
+	q := db.Where("x = ?", 1) //gormreuse:allow-reuse // want `unused gormreuse:allow-reuse directive`
+	q.Find(nil)
+}
+
+// unusedAllowReuseOnFunction has no function-level form: the directive only
+// covers the declaration line, so the reuse in the body is still reported.
+//
+//gormreuse:allow-reuse // want `unused gormreuse:allow-reuse directive`
+func unusedAllowReuseOnFunction(db *gorm.DB) {
+	q := db.Where("x = ?", 1)
+	q.Find(nil)
+	q.Count(nil) // want `\*gorm\.DB reused: second branch from mutable root`
+}
@@ -0,0 +1,90 @@
+package internal
+
+import (
+	"gorm.io/gorm"
+)
+
+// =============================================================================
+// Append Test Cases
+//
+// append(s, q) lowers to a store of q into the variadic array passed to the
+// builtin, so the *gorm.DB escapes into the slice like slice[i] = q: every
+// appended element's root is polluted at the append.
+// =============================================================================
+
+// =============================================================================
+// SHOULD REPORT - Use after appending to a slice
+// =============================================================================
+
+// appendSingleThenUse appends q, then uses it.
+func appendSingleThenUse(db *gorm.DB) []*gorm.DB {
+	q := db.Where("x = ?", 1)
+	var s []*gorm.DB
+	s = append(s, q) // First use (escapes into s)
+	q.Find(nil)      // want `\*gorm\.DB reused: second branch from mutable root`
+	return s
+}
+
+// appendMultipleThenUse appends two roots at once, then uses both.
+func appendMultipleThenUse(db *gorm.DB) []*gorm.DB {
+	base := db.Session(&gorm.Session{})
+	q := base.Where("x = ?", 1)
+	r := base.Where("y = ?", 2)
+	s := append([]*gorm.DB{}, q, r) // First use of q and r
+	q.Find(nil)                     // want `\*gorm\.DB reused: second branch from mutable root`
+	r.Count(nil)                    // want `\*gorm\.DB reused: second branch from mutable root`
+	return s
+}
+
+// appendSameRootTwice appends q twice in one call.
+func appendSameRootTwice(db *gorm.DB) []*gorm.DB {
+	q := db.Where("x = ?", 1)
+	return append([]*gorm.DB{}, q, q) // want `\*gorm\.DB reused: second branch from mutable root`
+}
+
+// appendInterfaceThenUse appends q boxed into interface{}.
+func appendInterfaceThenUse(db *gorm.DB) []interface{} {
+	q := db.Where("x = ?", 1)
+	s := append([]interface{}{}, q) // First use (escapes into s)
+	q.Find(nil)                     // want `\*gorm\.DB reused: second branch from mutable root`
+	return s
+}
+
+// appendAfterUse appends q after it was already used.
+func appendAfterUse(db *gorm.DB, s []*gorm.DB) []*gorm.DB {
+	q := db.Where("x = ?", 1)
+	q.Find(nil)         // First use
+	return append(s, q) // want `\*gorm\.DB reused: second branch from mutable root`
+}
+
+// pureLeaksViaAppend is marked pure but appends its argument.
+//
+//gormreuse:pure
+func pureLeaksViaAppend(db *gorm.DB, dst []*gorm.DB) []*gorm.DB {
+	return append(dst, db) // want `pure function leaks \*gorm\.DB argument via slice/array store`
+}
+
+// =============================================================================
+// SHOULD NOT REPORT - Single use or immutable element
+// =============================================================================
+
+// appendOnly appends q without using it again.
+func appendOnly(db *gorm.DB) []*gorm.DB {
+	q := db.Where("x = ?", 1)
+	return append([]*gorm.DB{}, q)
+}
+
+// appendSession appends an immutable value, which stays reusable.
+func appendSession(db *gorm.DB) []*gorm.DB {
+	q := db.Where("x = ?", 1).Session(&gorm.Session{})
+	s := append([]*gorm.DB{}, q)
+	q.Find(nil)
+	q.Count(nil)
+	return s
+}
+
+// appendFreshChains appends a fresh chain per element.
+func appendFreshChains(db *gorm.DB) []*gorm.DB {
+	base := db.Session(&gorm.Session{})
+	return append([]*gorm.DB{}, base.Where("a"), base.Where("b"))
+}
@@ -0,0 +1,74 @@
+package internal
+
+import "gorm.io/gorm"
+
+// =============================================================================
+// SHOULD REPORT - Association mode consumes its receiver
+// =============================================================================
+
+// associationTwice opens association mode twice on the same mutable root.
+// Association returns a *gorm.Association rather than a *gorm.DB, but the
+// Model(&u) chain it is called on is consumed all the same.
+func associationTwice(db *gorm.DB, u interface{}) error {
+	q := db.Model(u)
+	if err := q.Association("Languages").Append("en"); err != nil {
+		return err
+	}
+	return q.Association("Roles").Append("admin") // want `\*gorm\.DB reused: second branch from mutable root`
+}
+
+// associationStored keeps both associations in variables before using them.
+func associationStored(db *gorm.DB, u interface{}) {
+	q := db.Model(u)
+	languages := q.Association("Languages")
+	roles := q.Association("Roles") // want `\*gorm\.DB reused: second branch from mutable root`
+	_ = languages.Clear()
+	_ = roles.Clear()
+}
+
+// associationThenFind finds through the root after an association.
+func associationThenFind(db *gorm.DB, u interface{}) {
+	q := db.Model(u)
+	_ = q.Association("Languages").Count()
+	q.Find(nil) // want `\*gorm\.DB reused: second branch from mutable root`
+}
+
+// associationInLoop opens association mode on a root defined outside a loop.
+func associationInLoop(db *gorm.DB, users []interface{}) {
+	q := db.Model(users[0])
+	for range users {
+		_ = q.Association("Languages").Clear() // want `\*gorm\.DB reused: second branch from mutable root`
+	}
+}
+
+// associationConditional assigns an association in one branch only.
+func associationConditional(db *gorm.DB, u interface{}, cond bool) {
+	q := db.Model(u)
+	var a *gorm.Association
+	if cond {
+		a = q.Association("Languages")
+	}
+	q.Find(nil) // want `\*gorm\.DB reused: second branch from mutable root`
+	_ = a
+}
+
+// =============================================================================
+// SHOULD NOT REPORT - Association mode on an immutable root
+// =============================================================================
+
+// associationOnSession opens association mode twice on a Session root.
+func associationOnSession(db *gorm.DB, u interface{}) error {
+	q := db.Model(u).Session(&gorm.Session{})
+	if err := q.Association("Languages").Append("en"); err != nil {
+		return err
+	}
+	return q.Association("Roles").Append("admin") // OK: q is immutable
+}
+
+// associationReused uses one association value twice; only the chain it was
+// opened on is tracked.
+func associationReused(db *gorm.DB, u interface{}) {
+	a := db.Model(u).Association("Languages")
+	_ = a.Append("en")
+	_ = a.Count()
+}
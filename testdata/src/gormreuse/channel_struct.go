@@ -0,0 +1,95 @@
+package internal
+
+import (
+	"gorm.io/gorm"
+)
+
+// sentHolder carries a *gorm.DB through a channel.
+type sentHolder struct {
+	name string
+	db   *gorm.DB
+}
+
+// =============================================================================
+// SHOULD REPORT - *gorm.DB sent on a channel inside a struct
+// The receiver of the channel owns the struct's DB, so the send is a use of
+// it, like sending the DB itself.
+// =============================================================================
+
+// chanStructThenUse sends a struct holding q to a goroutine, then uses q.
+func chanStructThenUse(db *gorm.DB) {
+	q := db.Where("x = ?", 1)
+	ch := make(chan sentHolder, 1)
+	ch <- sentHolder{name: "q", db: q} // First use (sent)
+	go func() {
+		h := <-ch
+		h.db.Find(nil)
+	}()
+	q.Count(nil) // want `\*gorm\.DB reused: second branch from mutable root`
+}
+
+// chanStructPointerThenUse sends a pointer to a struct holding q.
+func chanStructPointerThenUse(db *gorm.DB) {
+	q := db.Where("x = ?", 1)
+	ch := make(chan *sentHolder, 1)
+	ch <- &sentHolder{db: q} // First use (sent)
+	q.Find(nil)              // want `\*gorm\.DB reused: second branch from mutable root`
+}
+
+// chanStructVariableThenUse fills the struct before sending it.
+func chanStructVariableThenUse(db *gorm.DB) {
+	q := db.Where("x = ?", 1)
+	ch := make(chan sentHolder, 1)
+	h := sentHolder{name: "q"}
+	h.db = q
+	ch <- h     // First use (sent)
+	q.Find(nil) // want `\*gorm\.DB reused: second branch from mutable root`
+}
+
+// chanStructInterfaceThenUse sends the struct on a chan interface{}.
+func chanStructInterfaceThenUse(db *gorm.DB) {
+	q := db.Where("x = ?", 1)
+	ch := make(chan interface{}, 1)
+	ch <- sentHolder{db: q} // First use (sent)
+	q.Find(nil)             // want `\*gorm\.DB reused: second branch from mutable root`
+}
+
+// chanStructAfterUse uses q, then sends it inside a struct.
+func chanStructAfterUse(db *gorm.DB) {
+	q := db.Where("x = ?", 1)
+	ch := make(chan sentHolder, 1)
+	q.Find(nil)
+	ch <- sentHolder{db: q} // want `\*gorm\.DB reused: second branch from mutable root`
+}
+
+// =============================================================================
+// SHOULD NOT REPORT - the receiver is the only user
+// =============================================================================
+
+// chanStructOnly sends q without using it afterwards.
+func chanStructOnly(db *gorm.DB) {
+	q := db.Where("x = ?", 1)
+	ch := make(chan sentHolder, 1)
+	ch <- sentHolder{db: q}
+	go func() {
+		h := <-ch
+		h.db.Find(nil)
+	}()
+}
+
+// chanStructImmutable sends an immutable DB.
+func chanStructImmutable(db *gorm.DB) {
+	q := db.Where("x = ?", 1).Session(&gorm.Session{})
+	ch := make(chan sentHolder, 1)
+	ch <- sentHolder{db: q}
+	q.Find(nil) // OK: q is immutable
+}
+
+// chanStructOtherDB sends a different DB.
+func chanStructOtherDB(db *gorm.DB) {
+	base := db.Session(&gorm.Session{})
+	q := base.Where("x = ?", 1)
+	ch := make(chan sentHolder, 1)
+	ch <- sentHolder{db: base.Where("y = ?", 2)}
+	q.Find(nil) // OK: q stays in this goroutine
+}
@@ -0,0 +1,124 @@
+package internal
+
+import "gorm.io/gorm"
+
+// =============================================================================
+// SHOULD REPORT - clause-building methods branch a mutable root
+// Clauses, Preload, Joins, Group, Having, Distinct and Omit add to the
+// statement like Where, so each call is a branch of its receiver's root.
+// =============================================================================
+
+// clausesTwice branches twice with Clauses.
+func clausesTwice(db *gorm.DB) {
+	q := db.Where("x = ?", 1)
+	q.Clauses("FOR UPDATE")
+	q.Clauses("SKIP LOCKED") // want `\*gorm\.DB reused: second branch from mutable root`
+}
+
+// preloadTwice branches twice with Preload.
+func preloadTwice(db *gorm.DB) {
+	q := db.Where("x = ?", 1)
+	q.Preload("A")
+	q.Preload("B") // want `\*gorm\.DB reused: second branch from mutable root`
+}
+
+// preloadArgsTwice branches twice with Preload passing variadic conditions.
+func preloadArgsTwice(db *gorm.DB) {
+	q := db.Where("x = ?", 1)
+	q.Preload("A", "state = ?", "active")
+	q.Preload("B", "state = ?", "active") // want `\*gorm\.DB reused: second branch from mutable root`
+}
+
+// joinsTwice branches twice with Joins.
+func joinsTwice(db *gorm.DB) {
+	q := db.Where("x = ?", 1)
+	q.Joins("Company").Find(nil)
+	q.Joins("Manager").Find(nil) // want `\*gorm\.DB reused: second branch from mutable root`
+}
+
+// groupHavingTwice branches twice with Group and Having.
+func groupHavingTwice(db *gorm.DB) {
+	q := db.Where("x = ?", 1)
+	q.Group("name").Having("count(*) > ?", 1).Find(nil)
+	q.Having("sum(n) > ?", 10).Find(nil) // want `\*gorm\.DB reused: second branch from mutable root`
+}
+
+// distinctThenFind branches with Distinct, then finishes the root.
+func distinctThenFind(db *gorm.DB) {
+	q := db.Where("x = ?", 1)
+	q.Distinct("name")
+	q.Find(nil) // want `\*gorm\.DB reused: second branch from mutable root`
+}
+
+// omitTwice branches twice with Omit.
+func omitTwice(db *gorm.DB) {
+	q := db.Model(nil)
+	q.Omit("CreatedAt").Save(nil)
+	q.Omit("UpdatedAt").Save(nil) // want `\*gorm\.DB reused: second branch from mutable root`
+}
+
+// preloadSubQueryReused passes a mutable sub-query through Preload's variadic
+// arguments, then finishes it: the argument was a branch of the sub-query.
+func preloadSubQueryReused(db *gorm.DB) {
+	base := db.Session(&gorm.Session{})
+	sub := base.Model(nil).Where("y = ?", 2)
+	q := base.Where("x = ?", 1)
+	q.Preload("A", sub).Find(nil)
+	sub.Find(nil) // want `\*gorm\.DB reused: second branch from mutable root`
+}
+
+// preloadSelfSubQuery passes the receiver as its own argument: the receiver
+// and the packed argument are two branches of one root.
+func preloadSelfSubQuery(db *gorm.DB) {
+	q := db.Where("x = ?", 1)
+	q.Preload("A", q).Find(nil) // want `\*gorm\.DB reused: second branch from mutable root`
+}
+
+// =============================================================================
+// SHOULD NOT REPORT - single chains of clause-building methods
+// =============================================================================
+
+// clauseChain chains every clause-building method once.
+func clauseChain(db *gorm.DB) {
+	db.Where("x = ?", 1).
+		Clauses("FOR UPDATE").
+		Preload("A", "state = ?", "active").
+		Joins("Company").
+		Group("name").
+		Having("count(*) > ?", 1).
+		Distinct("name").
+		Omit("CreatedAt").
+		Find(nil)
+}
+
+// preloadReassigned reassigns after each Preload.
+func preloadReassigned(db *gorm.DB) {
+	q := db.Where("x = ?", 1)
+	q = q.Preload("A")
+	q = q.Preload("B")
+	q.Find(nil)
+}
+
+// preloadSubQuery passes an immutable sub-query through Preload's variadic
+// interface arguments: one branch of the receiver, none of the sub-query.
+func preloadSubQuery(db *gorm.DB) {
+	sub := db.Session(&gorm.Session{}).Model(nil)
+	q := db.Where("x = ?", 1)
+	q.Preload("A", sub).Find(nil)
+}
+
+// preloadMutableSubQuery passes a mutable sub-query once: its only branch.
+func preloadMutableSubQuery(db *gorm.DB) {
+	base := db.Session(&gorm.Session{})
+	sub := base.Model(nil).Where("y = ?", 2)
+	q := base.Where("x = ?", 1)
+	q.Preload("A", sub).Find(nil)
+}
+
+// clausesSession branches freely from an immutable value.
+func clausesSession(db *gorm.DB) {
+	q := db.Where("x = ?", 1).Session(&gorm.Session{})
+	q.Clauses("FOR UPDATE").Find(nil)
+	q.Preload("A").Find(nil)
+	q.Omit("CreatedAt").Save(nil)
+}
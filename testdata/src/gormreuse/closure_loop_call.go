@@ -0,0 +1,62 @@
+package internal
+
+import "gorm.io/gorm"
+
+// =============================================================================
+// Closure Called in a Loop Test Cases
+//
+// A closure capturing a root defined outside a loop and called in the loop
+// branches the root once per iteration, whether it is called directly or
+// looked up from a slice or map where it was stored.
+// =============================================================================
+
+// =============================================================================
+// SHOULD REPORT - one captured root branched per iteration
+// =============================================================================
+
+// closureStoredInMapCalledInLoop invokes a closure looked up from a map on
+// every iteration.
+func closureStoredInMapCalledInLoop(db *gorm.DB, items []string) {
+	q := db.Where("x = ?", 1)
+
+	handlers := map[string]func(){
+		"find": func() {
+			q.Find(nil) // want `\*gorm\.DB reused: second branch from mutable root`
+		},
+	}
+
+	for range items {
+		handlers["find"]()
+	}
+}
+
+// =============================================================================
+// SHOULD NOT REPORT - called once, or a fresh root per closure
+// =============================================================================
+
+// closureStoredCalledOnce stores a closure and invokes it once, outside any
+// loop.
+func closureStoredCalledOnce(db *gorm.DB) {
+	q := db.Where("x = ?", 1)
+
+	funcs := []func(){func() {
+		q.Find(nil) // OK: invoked once
+	}}
+	funcs[0]()
+}
+
+// closureCapturingPerIterationRoot stores closures each capturing their own
+// root, then invokes each once.
+func closureCapturingPerIterationRoot(db *gorm.DB, items []string) {
+	var funcs []func()
+	for _, item := range items {
+		q := db.Where("item = ?", item)
+		funcs = append(funcs, func() {
+			q.Find(nil) // OK: a fresh q per closure
+		})
+	}
+
+	for _, f := range funcs {
+		f()
+	}
+}
@@ -0,0 +1,88 @@
+package internal
+
+import "gorm.io/gorm"
+
+// helperRepo is a wrapper type whose method returns a mutable *gorm.DB.
+type helperRepo struct{}
+
+func (helperRepo) scoped(db *gorm.DB) *gorm.DB { return db.Where("deleted_at IS NULL") }
+
+// runCapture invokes f, the way a callback-taking helper would.
+func runCapture(f func()) { f() }
+
+// =============================================================================
+// SHOULD REPORT - helper return captured by a closure
+// A closure captures the value returned by a non-gorm function; the captured
+// binding is the helper call, which is the mutable root.
+// =============================================================================
+
+// capturedReturnIIFE finishes the helper result, then again in an IIFE.
+func capturedReturnIIFE(db *gorm.DB) {
+	r := helperWhere(db, "x")
+	r.Find(nil)
+	func() { r.Count(nil) }() // want `\*gorm\.DB reused: second branch from mutable root`
+}
+
+// capturedReturnPassedClosure hands the closure to another function.
+func capturedReturnPassedClosure(db *gorm.DB) {
+	r := helperWhere(db, "x")
+	r.Find(nil)
+	runCapture(func() { r.Count(nil) }) // want `\*gorm\.DB reused: second branch from mutable root`
+}
+
+// capturedReturnCalledLater defines the closure first and calls it after the
+// outer finish.
+func capturedReturnCalledLater(db *gorm.DB) {
+	r := helperWhere(db, "x")
+	f := func() { r.Count(nil) }
+	r.Find(nil)
+	f() // want `\*gorm\.DB reused: second branch from mutable root`
+}
+
+// capturedReturnNested reaches the helper result through two closures.
+func capturedReturnNested(db *gorm.DB) {
+	r := helperWhere(db, "x")
+	r.Find(nil)
+	func() { func() { r.Count(nil) }() }() // want `\*gorm\.DB reused: second branch from mutable root`
+}
+
+// capturedMethodReturn captures the result of a wrapper method.
+func capturedMethodReturn(db *gorm.DB, repo helperRepo) {
+	r := repo.scoped(db)
+	r.Find(nil)
+	runCapture(func() { r.Count(nil) }) // want `\*gorm\.DB reused: second branch from mutable root`
+}
+
+// capturedReturnByReference captures a variable the closure reassigns.
+func capturedReturnByReference(db *gorm.DB) {
+	r := helperWhere(db, "x")
+	r.Find(nil)
+	runCapture(func() {
+		r.Count(nil) // want `\*gorm\.DB reused: second branch from mutable root`
+		r = helperWhere(db, "y")
+	})
+}
+
+// =============================================================================
+// SHOULD NOT REPORT - captured helper returns used once or made immutable
+// =============================================================================
+
+// capturedReturnSingleUse uses the helper result only in the closure.
+func capturedReturnSingleUse(db *gorm.DB) {
+	r := helperWhere(db, "x")
+	runCapture(func() { r.Count(nil) })
+}
+
+// capturedImmutableReturn captures an immutable-return helper result.
+func capturedImmutableReturn(db *gorm.DB) {
+	r := immutableReturnReturnsDB(db)
+	r.Find(nil)
+	runCapture(func() { r.Count(nil) }) // OK: r is immutable
+}
+
+// capturedReturnSession captures the helper result made immutable.
+func capturedReturnSession(db *gorm.DB) {
+	r := helperWhere(db, "x").Session(&gorm.Session{})
+	r.Find(nil)
+	runCapture(func() { r.Count(nil) }) // OK: r is immutable
+}
@@ -0,0 +1,92 @@
+package internal
+
+import "gorm.io/gorm"
+
+// =============================================================================
+// SHOULD REPORT - Finisher in a condition, reuse in the body
+// A finisher in an if/for/switch condition lowers to a call in the condition
+// block, which dominates the body: it pollutes the root before the body runs.
+// =============================================================================
+
+// conditionIfFinisher: Find in the if condition, Count in the body.
+func conditionIfFinisher(db *gorm.DB) {
+	q := db.Where("x = ?", 1)
+	if q.Find(nil).Error == nil {
+		q.Count(nil) // want `\*gorm\.DB reused: second branch from mutable root`
+	}
+}
+
+// conditionIfInitFinisher: Find in the if init statement, Count in the else.
+func conditionIfInitFinisher(db *gorm.DB) {
+	q := db.Where("x = ?", 1)
+	if err := q.Find(nil).Error; err != nil {
+		return
+	} else {
+		q.Count(nil) // want `\*gorm\.DB reused: second branch from mutable root`
+	}
+}
+
+// conditionElseIfFinisher: Find in an else-if condition, Count in its body.
+func conditionElseIfFinisher(db *gorm.DB, flag bool) {
+	q := db.Where("x = ?", 1)
+	if flag {
+		return
+	} else if q.Find(nil).Error == nil {
+		q.Count(nil) // want `\*gorm\.DB reused: second branch from mutable root`
+	}
+}
+
+// conditionShortCircuitFinisher: Find on the right of &&, which only runs
+// when the left side holds; the body still runs after it.
+func conditionShortCircuitFinisher(db *gorm.DB, flag bool) {
+	q := db.Where("x = ?", 1)
+	if flag && q.Find(nil).Error == nil {
+		q.Count(nil) // want `\*gorm\.DB reused: second branch from mutable root`
+	}
+}
+
+// conditionSwitchFinisher: Find in the switch tag, Count in a case.
+func conditionSwitchFinisher(db *gorm.DB) {
+	q := db.Where("x = ?", 1)
+	switch q.Find(nil).Error {
+	case nil:
+		q.Count(nil) // want `\*gorm\.DB reused: second branch from mutable root`
+	}
+}
+
+// conditionForFinisher: the for condition runs on every iteration, so it
+// reuses q by itself, and the body reuses it after the condition.
+func conditionForFinisher(db *gorm.DB) {
+	q := db.Where("x = ?", 1)
+	for q.Find(nil).Error == nil { // want `\*gorm\.DB reused: second branch from mutable root`
+		q.Count(nil) // want `\*gorm\.DB reused: second branch from mutable root`
+	}
+}
+
+// =============================================================================
+// SHOULD NOT REPORT - Finisher in a condition
+// =============================================================================
+
+// conditionSessionFinisher: the root is immutable, so the body may reuse it.
+func conditionSessionFinisher(db *gorm.DB) {
+	q := db.Where("x = ?", 1).Session(&gorm.Session{})
+	if q.Find(nil).Error == nil {
+		q.Count(nil)
+	}
+}
+
+// conditionOnlyFinisher: the condition is the only use of q.
+func conditionOnlyFinisher(db *gorm.DB) {
+	q := db.Where("x = ?", 1)
+	if q.Find(nil).Error != nil {
+		return
+	}
+}
+
+// conditionFreshChainInBody: the body starts a new chain from db.
+func conditionFreshChainInBody(db *gorm.DB) {
+	base := db.Session(&gorm.Session{})
+	if base.Where("x = ?", 1).Find(nil).Error == nil {
+		base.Where("y = ?", 2).Count(nil)
+	}
+}
@@ -0,0 +1,114 @@
+package internal
+
+import (
+	"unsafe"
+
+	"gorm.io/gorm"
+)
+
+// convAliasDB is an alias of gorm.DB: converting to it changes nothing.
+type convAliasDB = gorm.DB
+
+// convNamedDB is a defined type over gorm.DB, so *convNamedDB and *gorm.DB
+// share one layout.
+type convNamedDB gorm.DB
+
+// convFinder is an interface *gorm.DB implements: assigning a convFinder to
+// an interface{} is a ChangeInterface, which keeps the boxed DB.
+type convFinder interface {
+	Find(dest interface{}, conds ...interface{}) *gorm.DB
+}
+
+// =============================================================================
+// SHOULD REPORT - Conversions between *gorm.DB-compatible types
+// =============================================================================
+
+// conversionAliasRoundTrip converts to the alias and back before reusing.
+func conversionAliasRoundTrip(db *gorm.DB) {
+	q := db.Where("x")
+	a := (*convAliasDB)(q)
+	(*gorm.DB)(a).Find(nil)
+	q.Count(nil) // want `\*gorm\.DB reused: second branch from mutable root`
+}
+
+// conversionNamedRoundTrip converts to the defined type and back (ChangeType).
+func conversionNamedRoundTrip(db *gorm.DB) {
+	q := db.Where("x")
+	n := (*convNamedDB)(q)
+	(*gorm.DB)(n).Find(nil)
+	(*gorm.DB)(n).Count(nil) // want `\*gorm\.DB reused: second branch from mutable root`
+}
+
+// conversionUnsafeRoundTrip converts through unsafe.Pointer (Convert), which
+// still refers to the same DB.
+func conversionUnsafeRoundTrip(db *gorm.DB) {
+	q := db.Where("x")
+	n := (*convNamedDB)(unsafe.Pointer(q))
+	back := (*gorm.DB)(unsafe.Pointer(n))
+	back.Find(nil)
+	q.Count(nil) // want `\*gorm\.DB reused: second branch from mutable root`
+}
+
+// conversionUnsafeBothConverted finishes two separately converted copies.
+func conversionUnsafeBothConverted(db *gorm.DB) {
+	q := db.Where("x")
+	p := unsafe.Pointer(q)
+	(*gorm.DB)(p).Find(nil)
+	(*gorm.DB)(p).Count(nil) // want `\*gorm\.DB reused: second branch from mutable root`
+}
+
+// conversionInterfaceNarrowing narrows the interface holding q to interface{}
+// (ChangeInterface) and finishes the asserted DB before reusing q.
+func conversionInterfaceNarrowing(db *gorm.DB) {
+	q := db.Where("x")
+	var f convFinder = q
+	var a interface{} = f
+	a.(*gorm.DB).Find(nil)
+	q.Count(nil) // want `\*gorm\.DB reused: second branch from mutable root`
+}
+
+// conversionInterfaceNarrowingTwice finishes the DB asserted from the narrowed
+// interface twice.
+func conversionInterfaceNarrowingTwice(db *gorm.DB) {
+	q := db.Where("x")
+	var f convFinder = q
+	var a interface{} = f
+	a.(*gorm.DB).Find(nil)
+	a.(*gorm.DB).Count(nil) // want `\*gorm\.DB reused: second branch from mutable root`
+}
+
+// =============================================================================
+// SHOULD NOT REPORT - Single use after a conversion
+// =============================================================================
+
+// conversionUnsafeSingleUse finishes the converted value once.
+func conversionUnsafeSingleUse(db *gorm.DB) {
+	q := db.Where("x")
+	(*gorm.DB)(unsafe.Pointer(q)).Find(nil)
+}
+
+// conversionUnsafeSession converts an immutable Session result: reusing it is fine.
+func conversionUnsafeSession(db *gorm.DB) {
+	q := db.Where("x").Session(&gorm.Session{})
+	p := (*gorm.DB)(unsafe.Pointer(q))
+	p.Find(nil)
+	p.Count(nil)
+}
+
+// conversionInterfaceNarrowingSingleUse finishes the narrowed DB once.
+func conversionInterfaceNarrowingSingleUse(db *gorm.DB) {
+	q := db.Where("x")
+	var f convFinder = q
+	var a interface{} = f
+	a.(*gorm.DB).Find(nil)
+}
+
+// conversionInterfaceNarrowingSession narrows an immutable Session result:
+// reusing it is fine.
+func conversionInterfaceNarrowingSession(db *gorm.DB) {
+	q := db.Where("x").Session(&gorm.Session{})
+	var f convFinder = q
+	var a interface{} = f
+	a.(*gorm.DB).Find(nil)
+	q.Count(nil)
+}
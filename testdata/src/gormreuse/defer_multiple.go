@@ -0,0 +1,98 @@
+package internal
+
+import (
+	"gorm.io/gorm"
+)
+
+// =============================================================================
+// Multiple Defer Test Cases
+//
+// Defers run one after another at function exit, so two defers registered in
+// the same execution branch the same root twice even when nothing else uses
+// it. Defers in mutually exclusive branches are never both registered.
+// =============================================================================
+
+// =============================================================================
+// SHOULD REPORT - Several defers on one root
+// =============================================================================
+
+// deferTwice defers two finishers on an otherwise unused root.
+func deferTwice(db *gorm.DB) {
+	q := db.Where("x = ?", 1)
+	defer q.Count(nil) // First use
+	defer q.First(nil) // want `\*gorm\.DB reused: second branch from mutable root`
+}
+
+// deferThrice reports every defer after the first.
+func deferThrice(db *gorm.DB) {
+	q := db.Where("x = ?", 1)
+	defer q.Count(nil) // First use
+	defer q.First(nil) // want `\*gorm\.DB reused: second branch from mutable root`
+	defer q.Last(nil)  // want `\*gorm\.DB reused: second branch from mutable root`
+}
+
+// deferConditionalThenAlways registers the second defer on every path.
+func deferConditionalThenAlways(db *gorm.DB, cond bool) {
+	q := db.Where("x = ?", 1)
+	if cond {
+		defer q.Count(nil) // First use
+	}
+	defer q.First(nil) // want `\*gorm\.DB reused: second branch from mutable root`
+}
+
+// deferNestedConditions registers both defers when both conditions hold.
+func deferNestedConditions(db *gorm.DB, a, b bool) {
+	q := db.Where("x = ?", 1)
+	if a {
+		defer q.Count(nil) // First use
+		if b {
+			defer q.First(nil) // want `\*gorm\.DB reused: second branch from mutable root`
+		}
+	}
+}
+
+// =============================================================================
+// SHOULD NOT REPORT - One defer per execution
+// =============================================================================
+
+// deferOnce defers a single finisher.
+func deferOnce(db *gorm.DB) {
+	q := db.Where("x = ?", 1)
+	defer q.Count(nil)
+}
+
+// deferExclusiveBranches registers exactly one of the defers.
+func deferExclusiveBranches(db *gorm.DB, cond bool) {
+	q := db.Where("x = ?", 1)
+	if cond {
+		defer q.Count(nil)
+	} else {
+		defer q.First(nil)
+	}
+}
+
+// deferSwitchCases registers one defer per case.
+func deferSwitchCases(db *gorm.DB, kind int) {
+	q := db.Where("x = ?", 1)
+	switch kind {
+	case 0:
+		defer q.Count(nil)
+	case 1:
+		defer q.First(nil)
+	default:
+		defer q.Last(nil)
+	}
+}
+
+// deferDistinctRoots defers finishers on different roots.
+func deferDistinctRoots(q1, q2 *gorm.DB) {
+	defer q1.Count(nil)
+	defer q2.Count(nil)
+}
+
+// deferImmutable defers finishers on an immutable root.
+func deferImmutable(db *gorm.DB) {
+	q := db.Where("x = ?", 1).Session(&gorm.Session{})
+	defer q.Count(nil)
+	defer q.First(nil)
+}
@@ -0,0 +1,142 @@
+package internal
+
+import (
+	"gorm.io/gorm"
+)
+
+// =============================================================================
+// Fields Promoted from Embedded Structs
+//
+// A field promoted from an embedded struct is addressed through one FieldAddr
+// per struct: r.db is &(&r.embedBase).db. Its stores and reads match by the
+// path from the outer struct, and an embedded *embedBase is followed to the
+// struct it points to, so reuse through the promoted field is detected.
+// =============================================================================
+
+// embedBase holds the *gorm.DB promoted into the repositories below.
+type embedBase struct {
+	db *gorm.DB
+}
+
+// embedRepo embeds embedBase by value.
+type embedRepo struct {
+	embedBase
+	name string
+}
+
+// embedPtrRepo embeds embedBase by pointer.
+type embedPtrRepo struct {
+	*embedBase
+}
+
+// embedOuterRepo embeds embedRepo, promoting db through two structs.
+type embedOuterRepo struct {
+	embedRepo
+}
+
+func consumeEmbedRepo(*embedRepo) {}
+
+// =============================================================================
+// SHOULD REPORT
+// =============================================================================
+
+// embeddedFieldReuse assigns the promoted field and branches it twice.
+func embeddedFieldReuse(db *gorm.DB) {
+	r := &embedRepo{name: "users"}
+	r.db = db.Where("x = ?", 1)
+	r.db.Find(nil)
+	r.db.Count(nil) // want `\*gorm\.DB reused: second branch from mutable root`
+}
+
+// embeddedLiteralReuse initializes the embedded struct in a literal.
+func embeddedLiteralReuse(db *gorm.DB) {
+	r := &embedRepo{embedBase: embedBase{db: db.Where("x = ?", 1)}}
+	r.db.Find(nil)
+	r.db.Count(nil) // want `\*gorm\.DB reused: second branch from mutable root`
+}
+
+// embeddedExplicitPathReuse mixes the promoted and the explicit path.
+func embeddedExplicitPathReuse(db *gorm.DB) {
+	r := &embedRepo{}
+	r.embedBase.db = db.Where("x = ?", 1)
+	r.db.Find(nil)
+	r.embedBase.db.Count(nil) // want `\*gorm\.DB reused: second branch from mutable root`
+}
+
+// embeddedTwoLevelsReuse promotes db through two embedded structs.
+func embeddedTwoLevelsReuse(db *gorm.DB) {
+	r := &embedOuterRepo{}
+	r.db = db.Where("x = ?", 1)
+	r.db.Find(nil)
+	r.db.Count(nil) // want `\*gorm\.DB reused: second branch from mutable root`
+}
+
+// embeddedPointerReuse embeds a pointer to the struct holding db.
+func embeddedPointerReuse(db *gorm.DB) {
+	r := &embedPtrRepo{&embedBase{db: db.Where("x = ?", 1)}}
+	r.db.Find(nil)
+	r.db.Count(nil) // want `\*gorm\.DB reused: second branch from mutable root`
+}
+
+// embeddedPointerVariableReuse embeds a pointer built beforehand.
+func embeddedPointerVariableReuse(db *gorm.DB) {
+	b := &embedBase{db: db.Where("x = ?", 1)}
+	r := &embedPtrRepo{b}
+	r.db.Find(nil)
+	r.db.Count(nil) // want `\*gorm\.DB reused: second branch from mutable root`
+}
+
+// embeddedEscapeReuse hands the outer struct to a helper, which may branch
+// the promoted field.
+func embeddedEscapeReuse(db *gorm.DB) {
+	q := db.Where("x = ?", 1)
+	r := &embedRepo{}
+	r.db = q
+	consumeEmbedRepo(r)
+	q.Count(nil) // want `\*gorm\.DB reused: second branch from mutable root`
+}
+
+// =============================================================================
+// SHOULD NOT REPORT
+// =============================================================================
+
+// embeddedFieldOnce branches the promoted field once.
+func embeddedFieldOnce(db *gorm.DB) {
+	r := &embedRepo{}
+	r.db = db.Where("x = ?", 1)
+	r.db.Find(nil)
+}
+
+// embeddedPointerOnce builds the embedded pointer in place, which is no use
+// of db by itself.
+func embeddedPointerOnce(db *gorm.DB) {
+	r := &embedPtrRepo{&embedBase{db: db.Where("x = ?", 1)}}
+	r.db.Find(nil)
+}
+
+// embeddedSessionField stores an immutable *gorm.DB.
+func embeddedSessionField(db *gorm.DB) {
+	r := &embedRepo{}
+	r.db = db.Where("x = ?", 1).Session(&gorm.Session{})
+	r.db.Find(nil)
+	r.db.Count(nil)
+}
+
+// embeddedDistinctRepos keeps a *gorm.DB per repository.
+func embeddedDistinctRepos(db *gorm.DB) {
+	base := db.Session(&gorm.Session{})
+	r1 := &embedRepo{}
+	r2 := &embedRepo{}
+	r1.db = base.Where("x = ?", 1)
+	r2.db = base.Where("y = ?", 2)
+	r1.db.Find(nil)
+	r2.db.Find(nil)
+}
+
+// embeddedSiblingField reads a field beside the embedded struct, not db.
+func embeddedSiblingField(db *gorm.DB) {
+	r := &embedRepo{name: "users"}
+	r.db = db.Where("x = ?", 1)
+	_ = r.name
+	r.db.Find(nil)
+}
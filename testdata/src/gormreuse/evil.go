@@ -656,11 +656,11 @@ func goroutineInsideDefer(db *gorm.DB) {
 
 	defer func() {
 		go func() {
-			q.Find(nil) // Pollutes q in goroutine inside defer
+			q.Find(nil) // want `\*gorm\.DB reused: second branch from mutable root`
 		}()
 	}()
 
-	q.Count(nil) // want `\*gorm\.DB reused: second branch from mutable root`
+	q.Count(nil) // First use - the deferred closure runs after this at function exit
 }
 
 // nestedDeferGoroutineDefer demonstrates defer->goroutine->defer chain.
@@ -685,7 +685,7 @@ func multipleDefers(db *gorm.DB) {
 	defer q.Count(nil) // want `\*gorm\.DB reused: second branch from mutable root`
 	defer q.First(nil) // want `\*gorm\.DB reused: second branch from mutable root`
 
-	q.Find(nil) // LIMITATION: Not detected (defers execute after, but q.Find is first in code order)
+	q.Find(nil) // First use - both defers execute AFTER this at function exit
 }
 
 // =============================================================================
@@ -793,6 +793,38 @@ func iifeReturnChain(db *gorm.DB) {
 	q.Count(nil) // want `\*gorm\.DB reused: second branch from mutable root`
 }
 
+// deferredIIFEReturn defers an IIFE whose return branches from q. The deferred
+// body runs at exit, after q.Find, so the returned branch is the reuse and is
+// reported inside the closure rather than at q.Find.
+func deferredIIFEReturn(db *gorm.DB) {
+	q := db.Where("x = ?", 1)
+
+	defer func() *gorm.DB {
+		return q.Where("y = ?", 2) // want `\*gorm\.DB reused: second branch from mutable root`
+	}()
+
+	q.Find(nil) // First branch: runs before the deferred closure
+}
+
+// deferredIIFEReturnAfterFinish defers the IIFE after q is already finished.
+func deferredIIFEReturnAfterFinish(db *gorm.DB) {
+	q := db.Where("x = ?", 1)
+	q.Find(nil)
+
+	defer func() *gorm.DB {
+		return q.Where("y = ?", 2).Order("id") // want `\*gorm\.DB reused: second branch from mutable root`
+	}()
+}
+
+// deferredIIFEReturnOnly is the only branch from q, so it is not reused.
+func deferredIIFEReturnOnly(db *gorm.DB) {
+	q := db.Where("x = ?", 1)
+
+	defer func() *gorm.DB {
+		return q.Where("y = ?", 2) // OK: single branch at exit
+	}()
+}
+
 // =============================================================================
 // SHOULD REPORT - Struct Fields
 // =============================================================================
@@ -825,6 +857,49 @@ func multiStructField(db *gorm.DB) {
 	h.q2.Count(nil) // want `\*gorm\.DB reused: second branch from mutable root`
 }
 
+// structPointerDeref mixes field access through the pointer and through an
+// explicit dereference. Both resolve to q.
+func structPointerDeref(db *gorm.DB) {
+	q := db.Where("x = ?", 1)
+	h := &queryHolder{db: q}
+	h.db.Find(nil) // First use - pollutes underlying q
+
+	(*h).db.Count(nil) // want `\*gorm\.DB reused: second branch from mutable root`
+}
+
+// structPointerDoubleDeref reaches the field through a pointer to the struct
+// pointer, so the struct pointer itself is loaded from a local variable.
+func structPointerDoubleDeref(db *gorm.DB) {
+	q := db.Where("x = ?", 1)
+	h := &queryHolder{db: q}
+	hp := &h
+	(*hp).db.Find(nil) // First use - pollutes underlying q
+
+	(**hp).db.Count(nil) // want `\*gorm\.DB reused: second branch from mutable root`
+}
+
+// structPointerDerefCopy copies the struct out of the pointer. The copy's
+// field still holds q.
+func structPointerDerefCopy(db *gorm.DB) {
+	q := db.Where("x = ?", 1)
+	h := &queryHolder{db: q}
+	h.db.Find(nil) // First use - pollutes underlying q
+
+	v := *h
+	v.db.Count(nil) // want `\*gorm\.DB reused: second branch from mutable root`
+}
+
+// structPointerDerefImmutable stores an immutable value, so mixing pointer
+// and dereferenced field access is safe.
+func structPointerDerefImmutable(db *gorm.DB) {
+	q := db.Where("x = ?", 1).Session(&gorm.Session{})
+	h := &queryHolder{db: q}
+	hp := &h
+	h.db.Find(nil)
+	(*h).db.Count(nil)   // OK: q is immutable
+	(**hp).db.First(nil) // OK: q is immutable
+}
+
 // =============================================================================
 // EVIL PATTERNS - Pointer Indirection
 // =============================================================================
@@ -848,6 +923,44 @@ func doublePointer(db *gorm.DB) {
 	q.Count(nil) // want `\*gorm\.DB reused: second branch from mutable root`
 }
 
+// =============================================================================
+// EVIL PATTERNS - Fresh gorm.DB allocations
+// =============================================================================
+
+// newGormDBIndependent allocates an empty DB with new(gorm.DB). It is not part
+// of any chain, so its uses do not mix with q; q's own reuse is still flagged.
+func newGormDBIndependent(db *gorm.DB) {
+	p := new(gorm.DB)
+	p.Where("a").Find(nil)
+	p.Where("b").Find(nil) // OK: fresh empty DB, not a mutable chain
+
+	q := db.Where("x = ?", 1)
+	q.Find(nil)
+	q.Count(nil) // want `\*gorm\.DB reused: second branch from mutable root`
+}
+
+// newGormDBChainIndependent derives a chain from a fresh DB. The chain is a
+// root of its own, independent of any other chain.
+func newGormDBChainIndependent(db *gorm.DB) {
+	p := new(gorm.DB)
+	r := p.Where("a")
+	r.Find(nil)
+	r.Count(nil) // want `\*gorm\.DB reused: second branch from mutable root`
+
+	q := db.Where("x = ?", 1)
+	q.Find(nil) // OK: first use of q, unrelated to r
+}
+
+// newGormDBStructCopy copies q into a fresh DB. The fresh DB is not linked to
+// q's chain, so using both once is not a reuse of q.
+func newGormDBStructCopy(db *gorm.DB) {
+	q := db.Where("x = ?", 1)
+	p := new(gorm.DB)
+	*p = *q
+	p.Find(nil)
+	q.Count(nil) // OK: p is treated as a fresh root
+}
+
 // =============================================================================
 // SHOULD NOT REPORT - Interface Conversion (Ownership Transfer)
 // =============================================================================
@@ -885,21 +998,89 @@ func mapPollution(db *gorm.DB) {
 	q.Count(nil) // want `\*gorm\.DB reused: second branch from mutable root`
 }
 
+// =============================================================================
+// Local constant-key maps
+//
+// A map created locally, written once and read back with constant keys is
+// traced like a variable: m["k"] resolves to the stored value, and the store
+// itself is not a use.
+// =============================================================================
+
+// mapConstKeyDoubleFinish finishes the same map entry twice.
+func mapConstKeyDoubleFinish(db *gorm.DB) {
+	q := db.Where("x = ?", 1)
+	m := map[string]*gorm.DB{}
+	m["k"] = q
+	m["k"].Find(nil)  // First use
+	m["k"].Count(nil) // want `\*gorm\.DB reused: second branch from mutable root`
+}
+
+// mapConstKeyLiteralDoubleFinish stores the entry through a map literal.
+func mapConstKeyLiteralDoubleFinish(db *gorm.DB) {
+	m := map[string]*gorm.DB{"k": db.Where("x = ?", 1)}
+	m["k"].Find(nil)  // First use
+	m["k"].Count(nil) // want `\*gorm\.DB reused: second branch from mutable root`
+}
+
+// mapConstKeyCommaOk reads the entry with the comma-ok form.
+func mapConstKeyCommaOk(db *gorm.DB) {
+	m := make(map[string]*gorm.DB)
+	m["k"] = db.Where("x = ?", 1)
+	if q, ok := m["k"]; ok {
+		q.Find(nil)  // First use
+		q.Count(nil) // want `\*gorm\.DB reused: second branch from mutable root`
+	}
+}
+
+// mapConstKeyThenDirect finishes through the map, then through the variable.
+func mapConstKeyThenDirect(db *gorm.DB) {
+	q := db.Where("x = ?", 1)
+	m := map[string]*gorm.DB{}
+	m["k"] = q
+	m["k"].Find(nil) // First use
+	q.Count(nil)     // want `\*gorm\.DB reused: second branch from mutable root`
+}
+
+// mapConstKeySingleFinish finishes the map entry once.
+func mapConstKeySingleFinish(db *gorm.DB) {
+	q := db.Where("x = ?", 1)
+	m := map[string]*gorm.DB{}
+	m["k"] = q
+	m["k"].Find(nil) // OK: only use
+}
+
+// mapConstKeyOtherKey reads a key that was never stored (a nil *gorm.DB).
+func mapConstKeyOtherKey(db *gorm.DB) {
+	q := db.Where("x = ?", 1)
+	m := map[string]*gorm.DB{}
+	m["k"] = q
+	_ = m["other"]
+	q.Find(nil) // OK: m["other"] is not q
+}
+
+// mapConstKeyImmutable finishes an immutable map entry twice.
+func mapConstKeyImmutable(db *gorm.DB) {
+	m := map[string]*gorm.DB{}
+	m["k"] = db.Where("x = ?", 1).Session(&gorm.Session{})
+	m["k"].Find(nil)
+	m["k"].Count(nil) // OK: the stored value is immutable
+}
+
 // =============================================================================
 // EVIL PATTERNS - Panic/Recover
 // =============================================================================
 
-// panicRecover demonstrates pollution in recover block.
+// panicRecover reuses q in a recover block, which runs at exit after q.Count.
 func panicRecover(db *gorm.DB) {
 	q := db.Where("x = ?", 1)
 
 	defer func() {
 		if r := recover(); r != nil {
-			q.Find(nil) // Pollutes q in recover
+			q.Find(nil) // want `\*gorm\.DB reused: second branch from mutable root`
 		}
 	}()
 
-	q.Count(nil) // want `\*gorm\.DB reused: second branch from mutable root`
+	q.Count(nil) // First use - the deferred closure runs after this at function exit
 }
 
 // =============================================================================
@@ -921,6 +1102,50 @@ func selectStatement(db *gorm.DB, ch chan int) {
 	q.Count(nil) // want `\*gorm\.DB reused: second branch from mutable root`
 }
 
+// selectCaseEmptyDefault demonstrates that a single polluting case is enough:
+// the empty default does not use q, but on the case path q is already polluted
+// when the select completes.
+func selectCaseEmptyDefault(db *gorm.DB, ch chan int) {
+	q := db.Where("x = ?", 1)
+
+	select {
+	case <-ch:
+		q.Find(nil) // First use (only on the case path)
+	default:
+	}
+
+	q.Count(nil) // want `\*gorm\.DB reused: second branch from mutable root`
+}
+
+// selectOneOfManyCases demonstrates that any polluting case taints the code
+// after the select, even when the other cases leave q untouched.
+func selectOneOfManyCases(db *gorm.DB, ch1, ch2 chan int) {
+	q := db.Where("x = ?", 1)
+
+	select {
+	case <-ch1:
+	case v := <-ch2:
+		q.Where("y = ?", v).Find(nil) // First use (only on the ch2 path)
+	default:
+	}
+
+	q.Count(nil) // want `\*gorm\.DB reused: second branch from mutable root`
+}
+
+// selectCaseFinishedImmutable demonstrates that no violation is reported when
+// the root is immutable, whichever case polluted it.
+func selectCaseFinishedImmutable(db *gorm.DB, ch chan int) {
+	q := db.Where("x = ?", 1).Session(&gorm.Session{})
+
+	select {
+	case <-ch:
+		q.Find(nil)
+	default:
+	}
+
+	q.Count(nil) // OK: q is immutable
+}
+
 // =============================================================================
 // Method Value - Now Detected
 // SSA bound methods ($bound suffix) are now tracked properly.
@@ -967,7 +1192,7 @@ func methodValueNonTerminal(db *gorm.DB) {
 }
 
 // methodValueConditional demonstrates method value with conditional.
-// [LIMITATION] Phi node tracing for bound methods not fully supported.
+// The bound method is traced through the Phi merging both assignments.
 func methodValueConditional(db *gorm.DB, flag bool) {
 	q := db.Where("x = ?", 1)
 	var find func(dest interface{}, conds ...interface{}) *gorm.DB
@@ -976,14 +1201,29 @@ func methodValueConditional(db *gorm.DB, flag bool) {
 	} else {
 		find = q.Find
 	}
-	find(nil) // [LIMITATION] Not detected - Phi node tracing for method values
+	find(nil) // Pollutes q through either method value
 
-	q.Count(nil) // Not detected due to limitation above
+	q.Count(nil) // want `\*gorm\.DB reused: second branch from mutable root`
+}
+
+// methodValueConditionalRoots demonstrates a Phi of method values bound to
+// different roots: the call may use either, so both are checked.
+func methodValueConditionalRoots(db *gorm.DB, flag bool) {
+	base := db.Session(&gorm.Session{})
+	q1 := base.Where("a")
+	q2 := base.Where("b")
+	q2.Find(nil)
+	var find func(dest interface{}, conds ...interface{}) *gorm.DB
+	if flag {
+		find = q1.Find
+	} else {
+		find = q2.Find
+	}
+	find(nil) // want `\*gorm\.DB reused: second branch from mutable root`
 }
 
 // methodValuePhiWithNil demonstrates Phi node with nil edge.
-// Tests isNilConst returning true and traceMakeClosureImpl finding MakeClosure.
-// [LIMITATION] Phi node tracing for bound methods with nil edge not fully supported.
+// The nil edge is skipped and the MakeClosure is found through the other edge.
 func methodValuePhiWithNil(db *gorm.DB, flag bool) {
 	q := db.Where("x = ?", 1)
 	var find func(dest interface{}, conds ...interface{}) *gorm.DB
@@ -991,21 +1231,57 @@ func methodValuePhiWithNil(db *gorm.DB, flag bool) {
 		find = q.Find // MakeClosure assigned
 	}
 	// find is Phi: [MakeClosure, nil]
-	// traceMakeClosureImpl should skip nil edge (isNilConst=true)
-	// and find MakeClosure through non-nil edge
 	if find != nil {
 		find(nil)
-		// [LIMITATION] FALSE NEGATIVE: Phi with nil edge not fully traced
-		find(nil) // Not detected - Phi with nil limitation
+		find(nil) // want `\*gorm\.DB reused: second branch from mutable root`
 	}
 }
 
+// methodValueStoredByClosure demonstrates a method value stored into a local
+// variable by a closure: the call loads it from the variable.
+func methodValueStoredByClosure(db *gorm.DB) {
+	q := db.Where("x = ?", 1)
+	var find func(dest interface{}, conds ...interface{}) *gorm.DB
+	setFind := func() {
+		find = q.Find
+	}
+	setFind()
+	find(nil)
+
+	q.Count(nil) // want `\*gorm\.DB reused: second branch from mutable root`
+}
+
+// methodValueStoredThroughPointer demonstrates a method value stored through a
+// pointer to the variable.
+func methodValueStoredThroughPointer(db *gorm.DB) {
+	q := db.Where("x = ?", 1)
+	var find func(dest interface{}, conds ...interface{}) *gorm.DB
+	p := &find
+	*p = q.Find
+	find(nil)
+	find(nil) // want `\*gorm\.DB reused: second branch from mutable root`
+}
+
+// methodValueConditionalImmutable demonstrates conditional method values bound
+// to an immutable root: reusing it is fine.
+func methodValueConditionalImmutable(db *gorm.DB, flag bool) {
+	q := db.Where("x = ?", 1).Session(&gorm.Session{})
+	var find func(dest interface{}, conds ...interface{}) *gorm.DB
+	if flag {
+		find = q.Find
+	} else {
+		find = q.First
+	}
+	find(nil)
+	q.Count(nil)
+}
+
 // =============================================================================
 // EVIL PATTERNS - Closure Modifying Captured Variable
 // =============================================================================
 
 // closureModifiesCaptured demonstrates closure modifying captured variable.
-// [LIMITATION] Cross-closure assignment tracking not fully supported.
+// The store through the captured variable makes the closure's Where the root.
 func closureModifiesCaptured(db *gorm.DB) {
 	var q *gorm.DB
 
@@ -1015,8 +1291,33 @@ func closureModifiesCaptured(db *gorm.DB) {
 	f()
 
 	q.Find(nil)
-	// [LIMITATION] FALSE NEGATIVE: Assignment in closure not tracked
-	q.Count(nil) // Not detected - closure assignment limitation
+	q.Count(nil) // want `\*gorm\.DB reused: second branch from mutable root`
+}
+
+// nestedClosureModifiesCaptured assigns the captured variable two closures deep.
+func nestedClosureModifiesCaptured(db *gorm.DB) {
+	var q *gorm.DB
+
+	func() {
+		func() {
+			q = db.Where("x = ?", 1)
+		}()
+	}()
+
+	q.Find(nil)
+	q.Count(nil) // want `\*gorm\.DB reused: second branch from mutable root`
+}
+
+// closureModifiesCapturedSingleUse finishes the closure-assigned root once.
+func closureModifiesCapturedSingleUse(db *gorm.DB) {
+	var q *gorm.DB
+
+	f := func() {
+		q = db.Where("x = ?", 1)
+	}
+	f()
+
+	q.Find(nil) // OK: single use
 }
 
 // =============================================================================
@@ -1140,12 +1441,12 @@ func combinedChaos(db *gorm.DB) {
 	defer func() {
 		go func() {
 			func() {
-				q.Find(nil)
+				q.Find(nil) // want `\*gorm\.DB reused: second branch from mutable root`
 			}()
 		}()
 	}()
 
-	q.Count(nil) // want `\*gorm\.DB reused: second branch from mutable root`
+	q.Count(nil) // First use - the deferred closure runs after this at function exit
 }
 
 // ultimateChaos demonstrates the ultimate evil pattern.
@@ -1164,11 +1465,11 @@ func ultimateChaos(db *gorm.DB) {
 					}
 				}
 			}
-			maker()()()()
+			maker()()()() // want `\*gorm\.DB reused: second branch from mutable root`
 		}()
 	}()
 
-	q.Count(nil) // want `\*gorm\.DB reused: second branch from mutable root`
+	q.Count(nil) // First use - the deferred closure runs after this at function exit
 }
 
 // =============================================================================
@@ -1419,7 +1720,37 @@ func deferInsideFor(db *gorm.DB, items []string) {
 		defer q.Count(nil) // want `\*gorm\.DB reused: second branch from mutable root`
 	}
 
-	q.Find(nil) // LIMITATION: Not detected (defer inside loop)
+	q.Find(nil) // OK: runs before the deferred Count calls
+}
+
+// deferInsideForOnly registers the same deferred use once per iteration; with
+// no other use of q, the defer alone runs q.Count repeatedly at exit.
+func deferInsideForOnly(db *gorm.DB, items []string) {
+	q := db.Where("x = ?", 1)
+
+	for range items {
+		defer q.Count(nil) // want `\*gorm\.DB reused: second branch from mutable root`
+	}
+}
+
+// deferInsideForLocalRoot defers a use of a root created in each iteration.
+func deferInsideForLocalRoot(db *gorm.DB, items []string) {
+	base := db.Session(&gorm.Session{})
+	for range items {
+		q := base.Where("x = ?", 1)
+		defer q.Count(nil) // OK: q is a fresh root per iteration
+	}
+}
+
+// deferClosureInsideForLocalRoot defers a closure capturing a per-iteration root.
+func deferClosureInsideForLocalRoot(db *gorm.DB, items []string) {
+	base := db.Session(&gorm.Session{})
+	for range items {
+		q := base.Where("x = ?", 1)
+		defer func() {
+			q.Count(nil) // OK: q is a fresh root per iteration
+		}()
+	}
 }
 
 // deferInsideForWithCondition demonstrates defer inside for with condition.
@@ -1460,12 +1791,12 @@ func forInsideDefer(db *gorm.DB, items []string) {
 
 	defer func() {
 		for range items {
-			//gormreuse:ignore // TEST FRAMEWORK ISSUE: diagnostic reported but want comment not matched
-			q.Count(nil)
+			// Runs at exit, after q.Find
+			q.Count(nil) // want `\*gorm\.DB reused: second branch from mutable root`
 		}
 	}()
 
-	q.Find(nil) // want `\*gorm\.DB reused: second branch from mutable root`
+	q.Find(nil) // First use - the deferred closure runs after this at function exit
 }
 
 // nestedForInsideDefer demonstrates nested for inside defer.
@@ -1475,17 +1806,17 @@ func nestedForInsideDefer(db *gorm.DB) {
 	defer func() {
 		for i := 0; i < 2; i++ {
 			for j := 0; j < 2; j++ {
-				//gormreuse:ignore // TEST FRAMEWORK ISSUE: diagnostic reported but want comment not matched
-				q.Find(nil)
+				// Runs at exit, after q.Find
+				q.Find(nil) // want `\*gorm\.DB reused: second branch from mutable root`
 			}
 		}
 	}()
 
-	q.Where("setup").Find(nil) // want `\*gorm\.DB reused: second branch from mutable root`
+	q.Where("setup").Find(nil) // First use - the deferred closure runs after this at function exit
 }
 
 // =============================================================================
-// EVIL PATTERNS - If Inside Defer
+// EVIL PATTERNS - If Inside Defer (each use runs at exit, after q.Find)
 // =============================================================================
 
 // ifInsideDefer demonstrates if inside defer closure.
@@ -1494,11 +1825,11 @@ func ifInsideDefer(db *gorm.DB, flag bool) {
 
 	defer func() {
 		if flag {
-			q.Count(nil) // LIMITATION: Not detected (inside defer closure with condition)
+			q.Count(nil) // want `\*gorm\.DB reused: second branch from mutable root`
 		}
 	}()
 
-	q.Find(nil) // want `\*gorm\.DB reused: second branch from mutable root`
+	q.Find(nil) // First use - the deferred closure runs after this at function exit
 }
 
 // ifElseInsideDefer demonstrates if-else inside defer closure.
@@ -1507,13 +1838,13 @@ func ifElseInsideDefer(db *gorm.DB, flag bool) {
 
 	defer func() {
 		if flag {
-			q.Count(nil) // LIMITATION: Not detected (inside defer closure with condition)
+			q.Count(nil) // want `\*gorm\.DB reused: second branch from mutable root`
 		} else {
-			q.First(nil) // LIMITATION: Not detected (inside defer closure with condition)
+			q.First(nil) // want `\*gorm\.DB reused: second branch from mutable root`
 		}
 	}()
 
-	q.Find(nil) // want `\*gorm\.DB reused: second branch from mutable root`
+	q.Find(nil) // First use - the deferred closure runs after this at function exit
 }
 
 // nestedIfInsideDefer demonstrates nested if inside defer.
@@ -1523,14 +1854,14 @@ func nestedIfInsideDefer(db *gorm.DB, a, b bool) {
 	defer func() {
 		if a {
 			if b {
-				q.Count(nil) // LIMITATION: Not detected (nested if inside defer)
+				q.Count(nil) // want `\*gorm\.DB reused: second branch from mutable root`
 			} else {
-				q.First(nil) // LIMITATION: Not detected (nested if inside defer)
+				q.First(nil) // want `\*gorm\.DB reused: second branch from mutable root`
 			}
 		}
 	}()
 
-	q.Find(nil) // want `\*gorm\.DB reused: second branch from mutable root`
+	q.Find(nil) // First use - the deferred closure runs after this at function exit
 }
 
 // =============================================================================
@@ -1571,13 +1902,13 @@ func deferIfFor(db *gorm.DB, flag bool, items []string) {
 	defer func() {
 		if flag {
 			for range items {
-				//gormreuse:ignore // TEST FRAMEWORK ISSUE
-				q.Count(nil)
+				// Runs at exit, after q.Find
+				q.Count(nil) // want `\*gorm\.DB reused: second branch from mutable root`
 			}
 		}
 	}()
 
-	q.Find(nil) // want `\*gorm\.DB reused: second branch from mutable root`
+	q.Find(nil) // First use - the deferred closure runs after this at function exit
 }
 
 // deferForIf demonstrates defer closure containing for containing if.
@@ -1587,47 +1918,47 @@ func deferForIf(db *gorm.DB, items []int) {
 	defer func() {
 		for _, item := range items {
 			if item > 0 {
-				//gormreuse:ignore // TEST FRAMEWORK ISSUE
-				q.Count(nil)
+				// Runs at exit, after q.Find
+				q.Count(nil) // want `\*gorm\.DB reused: second branch from mutable root`
 			}
 		}
 	}()
 
-	q.Find(nil) // want `\*gorm\.DB reused: second branch from mutable root`
+	q.Find(nil) // First use - the deferred closure runs after this at function exit
 }
 
 // tripleNestingIfForDefer demonstrates 3-level nesting: if -> for -> defer.
-// [LIMITATION] Defer execution order: defer closures execute at function exit,
-// but position-based detection reports at q.Find (textually later).
-// Runtime: q.Find runs first, then defer closures. Should flag defer, but flags q.Find instead.
+// The closure is deferred once per iteration, so its use of q is reused on its
+// own. The deferred closures run at exit, after q.Find, which is their first
+// branch.
 func tripleNestingIfForDefer(db *gorm.DB, flag bool, items []string) {
 	q := db.Where("x = ?", 1)
 
 	if flag {
 		for range items {
 			defer func() {
-				q.Count(nil)
+				q.Count(nil) // want `\*gorm\.DB reused: second branch from mutable root`
 			}()
 		}
 	}
 
-	q.Find(nil) // want `\*gorm\.DB reused: second branch from mutable root`
+	q.Find(nil) // First use - the deferred closure runs after this at function exit
 }
 
 // tripleNestingForIfDefer demonstrates 3-level nesting: for -> if -> defer.
-// [LIMITATION] Same as tripleNestingIfForDefer - defer execution order not tracked.
+// Same as tripleNestingIfForDefer: q.Find runs before the deferred closures.
 func tripleNestingForIfDefer(db *gorm.DB, items []int) {
 	q := db.Where("x = ?", 1)
 
 	for _, item := range items {
 		if item > 0 {
 			defer func(i int) {
-				q.Where("item = ?", i).Count(nil)
+				q.Where("item = ?", i).Count(nil) // want `\*gorm\.DB reused: second branch from mutable root`
 			}(item)
 		}
 	}
 
-	q.Find(nil) // want `\*gorm\.DB reused: second branch from mutable root`
+	q.Find(nil) // First use - the deferred closure runs after this at function exit
 }
 
 // tripleNestingDeferIfFor demonstrates 3-level nesting: defer -> if -> for.
@@ -1637,13 +1968,13 @@ func tripleNestingDeferIfFor(db *gorm.DB, flag bool, items []string) {
 	defer func() {
 		if flag {
 			for range items {
-				//gormreuse:ignore // TEST FRAMEWORK ISSUE
-				q.Count(nil)
+				// Runs at exit, after q.Find
+				q.Count(nil) // want `\*gorm\.DB reused: second branch from mutable root`
 			}
 		}
 	}()
 
-	q.Find(nil) // want `\*gorm\.DB reused: second branch from mutable root`
+	q.Find(nil) // First use - the deferred closure runs after this at function exit
 }
 
 // tripleNestingDeferForIf demonstrates 3-level nesting: defer -> for -> if.
@@ -1653,13 +1984,13 @@ func tripleNestingDeferForIf(db *gorm.DB, items []int) {
 	defer func() {
 		for _, item := range items {
 			if item > 0 {
-				//gormreuse:ignore // TEST FRAMEWORK ISSUE
-				q.Count(nil)
+				// Runs at exit, after q.Find
+				q.Count(nil) // want `\*gorm\.DB reused: second branch from mutable root`
 			}
 		}
 	}()
 
-	q.Find(nil) // want `\*gorm\.DB reused: second branch from mutable root`
+	q.Find(nil) // First use - the deferred closure runs after this at function exit
 }
 
 // =============================================================================
@@ -1704,14 +2035,14 @@ func quadNestingDeferIfForIf(db *gorm.DB, a bool, items []int) {
 		if a {
 			for _, item := range items {
 				if item > 0 {
-					//gormreuse:ignore // TEST FRAMEWORK ISSUE
-					q.Count(nil)
+					// Runs at exit, after q.Find
+					q.Count(nil) // want `\*gorm\.DB reused: second branch from mutable root`
 				}
 			}
 		}
 	}()
 
-	q.Find(nil) // want `\*gorm\.DB reused: second branch from mutable root`
+	q.Find(nil) // First use - the deferred closure runs after this at function exit
 }
 
 // quadNestingDeferForIfFor demonstrates 4-level: defer -> for -> if -> for.
@@ -1722,14 +2053,14 @@ func quadNestingDeferForIfFor(db *gorm.DB, outer []bool) {
 		for _, flag := range outer {
 			if flag {
 				for i := 0; i < 2; i++ {
-					//gormreuse:ignore // TEST FRAMEWORK ISSUE
-					q.Count(nil)
+					// Runs at exit, after q.Find
+					q.Count(nil) // want `\*gorm\.DB reused: second branch from mutable root`
 				}
 			}
 		}
 	}()
 
-	q.Find(nil) // want `\*gorm\.DB reused: second branch from mutable root`
+	q.Find(nil) // First use - the deferred closure runs after this at function exit
 }
 
 // =============================================================================
@@ -1786,7 +2117,7 @@ func earlyReturnWithDefer(db *gorm.DB, flag bool) {
 	q.First(nil)
 }
 
-// earlyReturnInLoopWithDefer demonstrates early return in loop with defer.
+// earlyReturnInLoopWithDefer returns early after earlier iterations branched q.
 func earlyReturnInLoopWithDefer(db *gorm.DB, items []int) {
 	q := db.Where("x = ?", 1)
 
@@ -1794,7 +2125,7 @@ func earlyReturnInLoopWithDefer(db *gorm.DB, items []int) {
 
 	for _, item := range items {
 		if item < 0 {
-			q.Find(nil) // reuse reported at the deferred call above; this post-loop/early-exit use is not separately flagged (#113: loop-block over-marking fixed)
+			q.Find(nil) // want `\*gorm\.DB reused: second branch from mutable root`
 			return
 		}
 		q.Where("item = ?", item).Find(nil) // want `\*gorm\.DB reused: second branch from mutable root`
@@ -1805,7 +2136,7 @@ func earlyReturnInLoopWithDefer(db *gorm.DB, items []int) {
 // EVIL PATTERNS - Labeled Break/Continue with Defer
 // =============================================================================
 
-// labeledBreakWithDefer demonstrates labeled break with defer.
+// labeledBreakWithDefer breaks out after earlier iterations branched q.
 func labeledBreakWithDefer(db *gorm.DB) {
 	q := db.Where("x = ?", 1)
 
@@ -1815,7 +2146,7 @@ outer:
 	for i := 0; i < 3; i++ {
 		for j := 0; j < 3; j++ {
 			if i == 1 && j == 1 {
-				q.Find(nil) // reuse reported at the deferred call above; this post-loop/early-exit use is not separately flagged (#113: loop-block over-marking fixed)
+				q.Find(nil) // want `\*gorm\.DB reused: second branch from mutable root`
 				break outer
 			}
 			q.Where("i = ? AND j = ?", i, j).Find(nil) // want `\*gorm\.DB reused: second branch from mutable root`
@@ -1869,7 +2200,7 @@ func foreverLoopWithBreak(db *gorm.DB, ch chan bool) {
 // EVIL PATTERNS - Closure Capturing Loop Variable
 // =============================================================================
 
-// closureCapturingLoopVar demonstrates closure capturing loop variable.
+// closureCapturingLoopVar calls stored closures that all share q in a loop.
 func closureCapturingLoopVar(db *gorm.DB, items []string) {
 	q := db.Where("x = ?", 1)
 
@@ -1877,12 +2208,12 @@ func closureCapturingLoopVar(db *gorm.DB, items []string) {
 	for _, item := range items {
 		item := item // Capture
 		funcs = append(funcs, func() {
-			q.Where("item = ?", item).Find(nil)
+			q.Where("item = ?", item).Find(nil) // want `\*gorm\.DB reused: second branch from mutable root`
 		})
 	}
 
 	for _, f := range funcs {
-		f() // LIMITATION: Not detected (closure invocation in loop)
+		f()
 	}
 }
 
@@ -1893,11 +2224,11 @@ func deferCapturingLoopVar(db *gorm.DB, items []string) {
 	for _, item := range items {
 		item := item // Capture
 		defer func() {
-			q.Where("item = ?", item).Count(nil) // LIMITATION: Not detected (defer in loop with captured var)
+			q.Where("item = ?", item).Count(nil) // want `\*gorm\.DB reused: second branch from mutable root`
 		}()
 	}
 
-	q.Find(nil) // want `\*gorm\.DB reused: second branch from mutable root`
+	q.Find(nil) // First use - the deferred closure runs after this at function exit
 }
 
 // =============================================================================
@@ -1910,11 +2241,11 @@ func nestedDeferClosures(db *gorm.DB) {
 
 	defer func() {
 		defer func() {
-			q.Count(nil) // LIMITATION: Not detected (nested defer closure)
+			q.Count(nil) // want `\*gorm\.DB reused: second branch from mutable root`
 		}()
 	}()
 
-	q.Find(nil) // want `\*gorm\.DB reused: second branch from mutable root`
+	q.Find(nil) // First use - the deferred closure runs after this at function exit
 }
 
 // tripleNestedDeferClosures demonstrates 3-level nested defer closures.
@@ -1924,12 +2255,12 @@ func tripleNestedDeferClosures(db *gorm.DB) {
 	defer func() {
 		defer func() {
 			defer func() {
-				q.Count(nil) // LIMITATION: Not detected (triple nested defer closure)
+				q.Count(nil) // want `\*gorm\.DB reused: second branch from mutable root`
 			}()
 		}()
 	}()
 
-	q.Find(nil) // want `\*gorm\.DB reused: second branch from mutable root`
+	q.Find(nil) // First use - the deferred closure runs after this at function exit
 }
 
 // nestedDeferWithFor demonstrates nested defer with for.
@@ -1939,12 +2270,12 @@ func nestedDeferWithFor(db *gorm.DB) {
 	defer func() {
 		for i := 0; i < 2; i++ {
 			defer func(n int) {
-				q.Where("n = ?", n).Count(nil) // LIMITATION: Not detected (defer in loop inside defer closure)
+				q.Where("n = ?", n).Count(nil) // want `\*gorm\.DB reused: second branch from mutable root`
 			}(i)
 		}
 	}()
 
-	q.Find(nil) // want `\*gorm\.DB reused: second branch from mutable root`
+	q.Find(nil) // First use - the deferred closure runs after this at function exit
 }
 
 // =============================================================================
@@ -2012,7 +2343,7 @@ func ultimateIfForDeferChaos(db *gorm.DB, a, b bool, outer []int, inner []string
 					for _, i := range inner {
 						defer func(x int, y string) {
 							if b {
-								q.Where("x = ? AND y = ?", x, y).Count(nil) // LIMITATION: Not detected (deeply nested defer)
+								q.Where("x = ? AND y = ?", x, y).Count(nil) // want `\*gorm\.DB reused: second branch from mutable root`
 							}
 						}(o, i)
 					}
@@ -2021,7 +2352,7 @@ func ultimateIfForDeferChaos(db *gorm.DB, a, b bool, outer []int, inner []string
 		}
 	}()
 
-	q.Find(nil) // want `\*gorm\.DB reused: second branch from mutable root`
+	q.Find(nil) // First use - the deferred closure runs after this at function exit
 }
 
 // =============================================================================
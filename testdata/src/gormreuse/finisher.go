@@ -0,0 +1,131 @@
+package internal
+
+import (
+	"gorm.io/gorm"
+)
+
+// =============================================================================
+// Custom Finisher Test Cases
+//
+// //gormreuse:finisher marks a helper as a terminal use of its *gorm.DB
+// receiver: the method receiver when it is *gorm.DB, otherwise the first
+// parameter. Calling it pollutes the root like Find, even when its result is
+// assigned (an ordinary *gorm.DB-returning helper would be a reassignment).
+// =============================================================================
+
+// mustFind is a custom finisher returning the finished *gorm.DB.
+//
+//gormreuse:finisher
+func mustFind(db *gorm.DB, dest interface{}) *gorm.DB {
+	res := db.Find(dest)
+	if res.Error != nil {
+		panic(res.Error)
+	}
+	return res
+}
+
+// scanner is a custom finisher declared with a same-line directive.
+func scanner(db *gorm.DB, dest interface{}) *gorm.DB { //gormreuse:finisher
+	return db.Scan(dest)
+}
+
+// finisherRepo wraps query helpers; its methods take the *gorm.DB as their first
+// parameter.
+type finisherRepo struct{}
+
+// MustFirst is a custom finisher method on a wrapper type.
+//
+//gormreuse:finisher
+func (finisherRepo) MustFirst(db *gorm.DB, dest interface{}) *gorm.DB {
+	return db.First(dest)
+}
+
+// =============================================================================
+// SHOULD REPORT - Reuse after a custom finisher
+// =============================================================================
+
+// finisherAssignedThenReuse finishes q through an assigned finisher call.
+func finisherAssignedThenReuse(db *gorm.DB) {
+	q := db.Where("x = ?", 1)
+	res := mustFind(q, nil) // First use (finisher)
+	_ = res
+	q.Count(nil) // want `\*gorm\.DB reused: second branch from mutable root`
+}
+
+// finisherSameLineThenReuse uses a finisher marked with a same-line directive.
+func finisherSameLineThenReuse(db *gorm.DB) {
+	q := db.Where("x = ?", 1)
+	res := scanner(q, nil) // First use (finisher)
+	_ = res
+	q.Find(nil) // want `\*gorm\.DB reused: second branch from mutable root`
+}
+
+// finisherMethodThenReuse uses a finisher method on a wrapper type.
+func finisherMethodThenReuse(db *gorm.DB) {
+	var repo finisherRepo
+	q := db.Where("x = ?", 1)
+	res := repo.MustFirst(q, nil) // First use (finisher)
+	_ = res
+	q.Count(nil) // want `\*gorm\.DB reused: second branch from mutable root`
+}
+
+// finisherTwice calls the same finisher twice on one root.
+func finisherTwice(db *gorm.DB) {
+	q := db.Where("x = ?", 1)
+	a := mustFind(q, nil) // First use (finisher)
+	b := mustFind(q, nil) // want `\*gorm\.DB reused: second branch from mutable root`
+	_, _ = a, b
+}
+
+// finisherInLoop finishes a root defined outside the loop on every iteration.
+func finisherInLoop(db *gorm.DB, ids []int) {
+	q := db.Where("x = ?", 1)
+	for range ids {
+		res := mustFind(q, nil) // want `\*gorm\.DB reused: second branch from mutable root`
+		_ = res
+	}
+}
+
+// =============================================================================
+// SHOULD NOT REPORT - Custom finisher used once or on immutable roots
+// =============================================================================
+
+// finisherOnce finishes q exactly once.
+func finisherOnce(db *gorm.DB) {
+	q := db.Where("x = ?", 1)
+	res := mustFind(q, nil)
+	_ = res
+}
+
+// finisherImmutable finishes an immutable root twice.
+func finisherImmutable(db *gorm.DB) {
+	q := db.Where("x = ?", 1).Session(&gorm.Session{})
+	a := mustFind(q, nil)
+	b := mustFind(q, nil) // OK: q is immutable
+	_, _ = a, b
+}
+
+// finisherResultChained chains on the finisher's result, not on q.
+func finisherResultChained(db *gorm.DB) {
+	q := db.Where("x = ?", 1)
+	res := mustFind(q, nil)
+	_ = res.Error
+}
+
+// =============================================================================
+// Unused finisher directives
+// =============================================================================
+
+// finisherWithoutReceiver has no *gorm.DB to finish.
+//
+//gormreuse:finisher // want `unused gormreuse:finisher directive`
+func finisherWithoutReceiver(dest interface{}) error {
+	return nil
+}
+
+// finisherSecondParam takes the *gorm.DB in a position other than the receiver.
+//
+//gormreuse:finisher // want `unused gormreuse:finisher directive`
+func finisherSecondParam(dest interface{}, db *gorm.DB) *gorm.DB {
+	return db.Find(dest)
+}
@@ -0,0 +1,64 @@
+package internal
+
+import (
+	"gorm.io/gorm"
+)
+
+// =============================================================================
+// Finisher Fix Test Cases
+//
+// Scan, Row, Rows and Pluck execute the statement like Find, so a reuse
+// through one of them is fixed with Session, never by reassigning the
+// finisher's result (q = q.Scan(&x) would keep the finished statement). A
+// chain method branched before the finisher is still reassigned.
+// =============================================================================
+
+// =============================================================================
+// SHOULD REPORT - Session fixes only
+// =============================================================================
+
+// finisherFixScan scans the root twice.
+func finisherFixScan(db *gorm.DB) {
+	var a, b int
+	q := db.Table("users").Where("x = ?", 1)
+	q.Scan(&a) // First use
+	q.Scan(&b) // want `\*gorm\.DB reused: second branch from mutable root`
+}
+
+// finisherFixPluck plucks after a count.
+func finisherFixPluck(db *gorm.DB) {
+	var n int64
+	var names []string
+	q := db.Table("users").Where("x = ?", 1)
+	q.Count(&n)             // First use
+	q.Pluck("name", &names) // want `\*gorm\.DB reused: second branch from mutable root`
+}
+
+// finisherFixRow reads a row after a scan.
+func finisherFixRow(db *gorm.DB) {
+	var a int
+	q := db.Table("users").Where("x = ?", 1)
+	q.Scan(&a) // First use
+	q.Row()    // want `\*gorm\.DB reused: second branch from mutable root`
+}
+
+// finisherFixRows iterates rows after a pluck.
+func finisherFixRows(db *gorm.DB) {
+	var names []string
+	q := db.Table("users").Where("x = ?", 1)
+	q.Pluck("name", &names) // First use
+	rows, _ := q.Rows()     // want `\*gorm\.DB reused: second branch from mutable root`
+	_ = rows
+}
+
+// =============================================================================
+// SHOULD REPORT - The chain method is reassigned, the finisher is not
+// =============================================================================
+
+// finisherFixWhereThenScan branches with Where before scanning the root.
+func finisherFixWhereThenScan(db *gorm.DB) {
+	var a int
+	q := db.Table("users")
+	q.Where("x = ?", 1) // First use
+	q.Scan(&a)          // want `\*gorm\.DB reused: second branch from mutable root`
+}
@@ -0,0 +1,112 @@
+package internal
+
+import (
+	"gorm.io/gorm"
+)
+
+// =============================================================================
+// Finisher Result Assigned Test Cases
+//
+// A finisher (Find, Count, Create, ...) executes the statement, so it is a use
+// of its receiver even when its result is assigned: res = q.Find(nil) is not a
+// reassignment creating a new root the way q = q.Where("x") is.
+// =============================================================================
+
+// =============================================================================
+// SHOULD REPORT - Finisher called again with its result assigned
+// =============================================================================
+
+// finisherResultAssignedTwice assigns both finisher results to one variable.
+func finisherResultAssignedTwice(db *gorm.DB) {
+	q := db.Where("x = ?", 1)
+	res := q.Find(nil) // First use
+	res = q.Find(nil)  // want `\*gorm\.DB reused: second branch from mutable root`
+	_ = res
+}
+
+// finisherResultDeclaredThenAssigned assigns to a variable declared up front.
+func finisherResultDeclaredThenAssigned(db *gorm.DB) {
+	q := db.Where("x = ?", 1)
+	var res *gorm.DB
+	res = q.Find(nil)  // First use
+	res = q.First(nil) // want `\*gorm\.DB reused: second branch from mutable root`
+	_ = res
+}
+
+// finisherResultCaptured stores both results in a variable a closure captures,
+// which the SSA form lowers to stores through an Alloc.
+func finisherResultCaptured(db *gorm.DB) {
+	q := db.Where("x = ?", 1)
+	var res *gorm.DB
+	defer func() { _ = res }()
+	res = q.Find(nil)  // First use
+	res = q.Count(nil) // want `\*gorm\.DB reused: second branch from mutable root`
+}
+
+// finisherResultThroughPointer assigns the second result through a pointer.
+func finisherResultThroughPointer(db *gorm.DB) {
+	q := db.Where("x = ?", 1)
+	res := q.Find(nil) // First use
+	p := &res
+	*p = q.Find(nil) // want `\*gorm\.DB reused: second branch from mutable root`
+}
+
+// finisherResultAfterChain assigns a finisher at the end of a chain.
+func finisherResultAfterChain(db *gorm.DB) {
+	q := db.Where("x = ?", 1)
+	var res *gorm.DB
+	defer func() { _ = res }()
+	res = q.Where("a").Find(nil) // First use
+	res = q.Where("b").Find(nil) // want `\*gorm\.DB reused: second branch from mutable root`
+}
+
+// finisherResultInLoop finishes an outer root on every iteration.
+func finisherResultInLoop(db *gorm.DB, ids []int) *gorm.DB {
+	q := db.Where("x = ?", 1)
+	var res *gorm.DB
+	for _, id := range ids {
+		res = q.Find(&id) // want `\*gorm\.DB reused: second branch from mutable root`
+	}
+	return res
+}
+
+// =============================================================================
+// SHOULD NOT REPORT - Single finisher or immutable receiver
+// =============================================================================
+
+// finisherResultAssignedOnce assigns the only finisher result.
+func finisherResultAssignedOnce(db *gorm.DB) error {
+	q := db.Where("x = ?", 1)
+	res := q.Find(nil)
+	return res.Error
+}
+
+// finisherResultBranches assigns a finisher result on exclusive paths.
+func finisherResultBranches(db *gorm.DB, first bool) *gorm.DB {
+	q := db.Where("x = ?", 1)
+	var res *gorm.DB
+	if first {
+		res = q.First(nil)
+	} else {
+		res = q.Find(nil)
+	}
+	return res
+}
+
+// finisherResultOfSession assigns finisher results of an immutable receiver.
+func finisherResultOfSession(db *gorm.DB) {
+	q := db.Where("x = ?", 1).Session(&gorm.Session{})
+	res := q.Find(nil)
+	res = q.Count(nil)
+	_ = res
+}
+
+// finisherResultReassignedRoot finishes a fresh root after each reassignment.
+func finisherResultReassignedRoot(db *gorm.DB) {
+	base := db.Session(&gorm.Session{})
+	q := base.Where("x = ?", 1)
+	res := q.Find(nil)
+	q = base.Where("y = ?", 2)
+	res = q.Find(nil)
+	_ = res
+}
@@ -0,0 +1,135 @@
+package internal
+
+import (
+	"gorm.io/gorm"
+)
+
+// =============================================================================
+// Generics Test Cases
+//
+// The body of a generic function or method is analyzed once, in its generic
+// form: a violation is reported once however many times it is instantiated.
+// Directives on generic functions and on methods of generic types apply to
+// every instantiation.
+// =============================================================================
+
+// GenericRepo is a repository over any model type.
+type GenericRepo[T any] struct {
+	db *gorm.DB
+}
+
+// GenericPairRepo is a repository keyed by K over V.
+type GenericPairRepo[K comparable, V any] struct {
+	db *gorm.DB
+}
+
+// base returns an immutable handle for the model.
+//
+//gormreuse:immutable-return
+func (r *GenericRepo[T]) base() *gorm.DB {
+	return r.db.Session(&gorm.Session{})
+}
+
+// scoped narrows db without polluting it.
+//
+//gormreuse:pure
+func (r *GenericPairRepo[K, V]) scoped(db *gorm.DB, key K) *gorm.DB {
+	return db.Session(&gorm.Session{}).Where("key = ?", key)
+}
+
+// genericLabel returns its argument unchanged.
+//
+//gormreuse:pure
+func genericLabel[T any](db *gorm.DB, label T) *gorm.DB {
+	_ = label
+	return db
+}
+
+// =============================================================================
+// SHOULD REPORT - Reuse inside generic bodies
+// =============================================================================
+
+// FindAll reuses a chain of the generic repository.
+func (r *GenericRepo[T]) FindAll() ([]T, int64) {
+	var out []T
+	var count int64
+	q := r.base().Where("deleted_at IS NULL")
+	q.Find(&out)
+	q.Count(&count) // want `\*gorm\.DB reused: second branch from mutable root`
+	return out, count
+}
+
+// FindByKeys branches the outer root inside the loop.
+func (r *GenericPairRepo[K, V]) FindByKeys(keys []K) {
+	q := r.db.Where("x = ?", 1)
+	for _, key := range keys {
+		var v V
+		q.Where("key = ?", key).First(&v) // want `\*gorm\.DB reused: second branch from mutable root`
+	}
+}
+
+// genericQuery reuses a chain in a generic function instantiated twice below.
+func genericQuery[T any](db *gorm.DB) {
+	var t T
+	q := db.Where("x = ?", 1)
+	q.First(&t)
+	q.Count(nil) // want `\*gorm\.DB reused: second branch from mutable root`
+}
+
+// genericInstantiations instantiates the generic code with several types.
+func genericInstantiations(db *gorm.DB) {
+	genericQuery[User](db.Session(&gorm.Session{}))
+	genericQuery[int](db.Session(&gorm.Session{}))
+	(&GenericRepo[User]{db: db.Session(&gorm.Session{})}).FindAll()
+	(&GenericRepo[int]{db: db.Session(&gorm.Session{})}).FindAll()
+}
+
+// genericIdentityThenUse uses both the alias a pure generic helper returns and
+// its argument.
+func genericIdentityThenUse(db *gorm.DB) {
+	q := db.Where("x = ?", 1)
+	r := genericLabel(q, "find")
+	r.Find(nil)
+	q.Count(nil) // want `\*gorm\.DB reused: second branch from mutable root`
+}
+
+// genericCallbackReuse reuses a chain captured by a closure given to a
+// generic function.
+func genericCallbackReuse(db *gorm.DB) {
+	q := db.Where("x = ?", 1)
+	q.Find(nil)
+	genericRun[int](func() {
+		q.Count(nil) // want `\*gorm\.DB reused: second branch from mutable root`
+	})
+}
+
+// genericRun calls f.
+func genericRun[T any](f func()) {
+	f()
+}
+
+// =============================================================================
+// SHOULD NOT REPORT - Directives on generic code
+// =============================================================================
+
+// CountAll branches the immutable handle of a generic method twice.
+func (r *GenericRepo[T]) CountAll() {
+	b := r.base()
+	b.Find(nil)
+	b.Count(nil) // OK: base is immutable-return
+}
+
+// genericScopedThenUse passes q to a pure method of a generic type.
+func genericScopedThenUse(db *gorm.DB) {
+	repo := &GenericPairRepo[string, User]{db: db}
+	q := db.Where("x = ?", 1)
+	_ = repo.scoped(q, "a")
+	_ = repo.scoped(q, "b")
+	q.Find(nil) // OK: scoped is pure
+}
+
+// genericLabelOnly uses only the alias a pure generic helper returns.
+func genericLabelOnly(db *gorm.DB) {
+	q := db.Where("x = ?", 1)
+	genericLabel(q, 1).Find(nil)
+}
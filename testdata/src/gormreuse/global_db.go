@@ -0,0 +1,49 @@
+package internal
+
+// =============================================================================
+// Package-level *gorm.DB Test Cases
+//
+// A load of a package-level *gorm.DB is an immutable source like a parameter:
+// branching the global itself is fine, while a chain derived from it is a
+// mutable root like any other.
+// =============================================================================
+
+// =============================================================================
+// SHOULD REPORT - chains derived from the global
+// =============================================================================
+
+// globalDBDerivedReuse derives a chain from the package-level DB and reuses it.
+// The global is immutable like a parameter; the chain is a mutable root.
+func globalDBDerivedReuse() {
+	q := DB.Where("x = ?", 1)
+	q.Find(nil)
+	q.Count(nil) // want `\*gorm\.DB reused: second branch from mutable root`
+}
+
+// globalDBDerivedLoop finishes a chain derived from the package-level DB in a
+// loop.
+func globalDBDerivedLoop(items []int) {
+	q := DB.Where("x = ?", 1)
+	for range items {
+		q.Find(nil) // want `\*gorm\.DB reused: second branch from mutable root`
+	}
+}
+
+// globalDBCopied copies the package-level DB into a local first.
+func globalDBCopied() {
+	db := DB
+	q := db.Where("x = ?", 1)
+	q.Find(nil)
+	db.Count(nil) // OK: db is the immutable global
+	q.Count(nil)  // want `\*gorm\.DB reused: second branch from mutable root`
+}
+
+// =============================================================================
+// SHOULD NOT REPORT - the global itself
+// =============================================================================
+
+// globalDBDirect branches the package-level DB itself twice.
+func globalDBDirect() {
+	DB.Where("a").Find(nil)
+	DB.Where("b").Find(nil) // OK: the global is immutable
+}
@@ -0,0 +1,98 @@
+package internal
+
+import (
+	"gorm.io/gorm"
+)
+
+// =============================================================================
+// Goroutine Argument Test Cases
+//
+// A function literal spawned with a *gorm.DB argument, go func(d *gorm.DB)
+// {...}(q), receives the caller's q as its parameter d, like a captured
+// variable. The goroutine body's branches of d are branches of q, so they
+// conflict with the caller's own uses of q (see iifeWithArgument in evil.go
+// for the synchronous counterpart).
+// =============================================================================
+
+// =============================================================================
+// SHOULD REPORT - Goroutine body and caller branch the same root
+// =============================================================================
+
+// goArgThenCallerUse branches q in the goroutine and in the caller.
+func goArgThenCallerUse(db *gorm.DB) {
+	q := db.Where("x = ?", 1)
+	go func(d *gorm.DB) {
+		d.Find(nil) // First use (in the goroutine)
+	}(q)
+	q.Count(nil) // want `\*gorm\.DB reused: second branch from mutable root`
+}
+
+// goArgAfterCallerUse branches q in the goroutine after the caller did.
+func goArgAfterCallerUse(db *gorm.DB) {
+	q := db.Where("x = ?", 1)
+	q.Find(nil) // First use
+	go func(d *gorm.DB) {
+		d.Count(nil) // want `\*gorm\.DB reused: second branch from mutable root`
+	}(q)
+}
+
+// goArgTwiceInBody branches the parameter twice in the goroutine.
+func goArgTwiceInBody(db *gorm.DB) {
+	q := db.Where("x = ?", 1)
+	go func(d *gorm.DB) {
+		d.Find(nil)  // First use
+		d.Count(nil) // want `\*gorm\.DB reused: second branch from mutable root`
+	}(q)
+}
+
+// goArgWithCapture also captures a non-DB variable.
+func goArgWithCapture(db *gorm.DB, id int) {
+	q := db.Where("x = ?", 1)
+	go func(d *gorm.DB) {
+		d.Where("id = ?", id).Find(nil) // First use (in the goroutine)
+	}(q)
+	q.Count(nil) // want `\*gorm\.DB reused: second branch from mutable root`
+}
+
+// goArgInLoop spawns a goroutine per iteration on an outer root.
+func goArgInLoop(db *gorm.DB, ids []int) {
+	q := db.Where("x = ?", 1)
+	for range ids {
+		go func(d *gorm.DB) { // want `\*gorm\.DB reused: second branch from mutable root`
+			d.Find(nil)
+		}(q)
+	}
+}
+
+// =============================================================================
+// SHOULD NOT REPORT - Immutable argument, unused parameter, or fresh root
+// =============================================================================
+
+// goArgSession passes an immutable copy to the goroutine.
+func goArgSession(db *gorm.DB) {
+	q := db.Where("x = ?", 1)
+	go func(d *gorm.DB) {
+		d.Find(nil)
+	}(q.Session(&gorm.Session{}))
+	q.Count(nil)
+}
+
+// goArgUnused spawns a goroutine that never branches its parameter.
+func goArgUnused(db *gorm.DB) {
+	q := db.Where("x = ?", 1)
+	go func(d *gorm.DB) {
+		_ = d
+	}(q)
+	q.Count(nil)
+}
+
+// goArgFreshInLoop spawns a goroutine per iteration on a fresh root.
+func goArgFreshInLoop(db *gorm.DB, ids []int) {
+	base := db.Session(&gorm.Session{})
+	for range ids {
+		q := base.Where("x = ?", 1)
+		go func(d *gorm.DB) {
+			d.Find(nil)
+		}(q)
+	}
+}
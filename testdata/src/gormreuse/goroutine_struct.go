@@ -0,0 +1,78 @@
+package internal
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+)
+
+// reqCtx carries a request context and its *gorm.DB.
+type reqCtx struct {
+	ctx context.Context
+	db  *gorm.DB
+}
+
+func handleReq(r reqCtx) { r.db.WithContext(r.ctx).Find(nil) }
+
+func handleReqPtr(r *reqCtx) { r.db.WithContext(r.ctx).Find(nil) }
+
+// =============================================================================
+// SHOULD REPORT - *gorm.DB handed to a goroutine inside a struct
+// The goroutine runs concurrently with the rest of the function, so the DB
+// carried by a struct argument escapes at the go statement.
+// =============================================================================
+
+// goStructThenUse passes a struct holding q to a goroutine, then uses q.
+func goStructThenUse(ctx context.Context, db *gorm.DB) {
+	q := db.Session(&gorm.Session{}).Where("x = ?", 1)
+	go handleReq(reqCtx{ctx: ctx, db: q})
+	q.Find(nil) // want `\*gorm\.DB reused: second branch from mutable root`
+}
+
+// goStructPointerThenUse passes a pointer to a struct holding q.
+func goStructPointerThenUse(ctx context.Context, db *gorm.DB) {
+	q := db.Session(&gorm.Session{}).Where("x = ?", 1)
+	go handleReqPtr(&reqCtx{ctx: ctx, db: q})
+	q.Count(nil) // want `\*gorm\.DB reused: second branch from mutable root`
+}
+
+// goStructVariableThenUse fills the struct before the go statement.
+func goStructVariableThenUse(ctx context.Context, db *gorm.DB) {
+	q := db.Session(&gorm.Session{}).Where("x = ?", 1)
+	r := reqCtx{ctx: ctx}
+	r.db = q
+	go handleReq(r)
+	q.Find(nil) // want `\*gorm\.DB reused: second branch from mutable root`
+}
+
+// goStructAfterUse uses q, then hands it to a goroutine.
+func goStructAfterUse(ctx context.Context, db *gorm.DB) {
+	q := db.Session(&gorm.Session{}).Where("x = ?", 1)
+	q.Find(nil)
+	go handleReq(reqCtx{ctx: ctx, db: q}) // want `\*gorm\.DB reused: second branch from mutable root`
+}
+
+// =============================================================================
+// SHOULD NOT REPORT - the goroutine is the only user
+// =============================================================================
+
+// goStructOnly hands q to the goroutine without using it afterwards.
+func goStructOnly(ctx context.Context, db *gorm.DB) {
+	q := db.Session(&gorm.Session{}).Where("x = ?", 1)
+	go handleReq(reqCtx{ctx: ctx, db: q})
+}
+
+// goStructImmutable hands an immutable DB to the goroutine.
+func goStructImmutable(ctx context.Context, db *gorm.DB) {
+	q := db.Where("x = ?", 1).Session(&gorm.Session{})
+	go handleReq(reqCtx{ctx: ctx, db: q})
+	q.Find(nil) // OK: q is immutable
+}
+
+// goStructOtherDB hands a different DB to the goroutine.
+func goStructOtherDB(ctx context.Context, db *gorm.DB) {
+	base := db.Session(&gorm.Session{})
+	q := base.Where("x = ?", 1)
+	go handleReq(reqCtx{ctx: ctx, db: base.Where("y = ?", 2)})
+	q.Find(nil) // OK: q stays in this goroutine
+}
@@ -0,0 +1,115 @@
+package internal
+
+import "gorm.io/gorm"
+
+// =============================================================================
+// goto Reachability Test Cases
+//
+// A goto may run a block again or run a later line first. Reuse is decided by
+// control flow rather than by source positions: a backward goto re-entering a
+// finisher is a loop, even when the goto enters the cycle at two places so
+// that no block dominates the other, and a use running after another through a
+// goto is the second branch wherever it is written. A path running the root's
+// definition again sees a fresh value.
+// =============================================================================
+
+// =============================================================================
+// SHOULD REPORT - goto re-runs or reorders the uses
+// =============================================================================
+
+// gotoBackRetry re-enters the finisher with a backward goto.
+func gotoBackRetry(db *gorm.DB) {
+	q := db.Where("x = ?", 1)
+	n := 0
+retry:
+	q.Find(nil) // want `\*gorm\.DB reused: second branch from mutable root`
+	n++
+	if n < 3 {
+		goto retry
+	}
+}
+
+// gotoIrreducibleCycle enters the cycle a/b either at a or at b, so that
+// neither label dominates the other.
+func gotoIrreducibleCycle(db *gorm.DB, skip bool) {
+	q := db.Where("x = ?", 1)
+	n := 0
+	if skip {
+		goto b
+	}
+a:
+	n++
+b:
+	q.Find(nil) // want `\*gorm\.DB reused: second branch from mutable root`
+	if n < 3 {
+		goto a
+	}
+}
+
+// gotoReordered runs q.Count before q.Find, written above it.
+func gotoReordered(db *gorm.DB) {
+	q := db.Where("x = ?", 1)
+	goto count
+find:
+	q.Find(nil) // want `\*gorm\.DB reused: second branch from mutable root`
+	return
+count:
+	q.Count(nil) // First use
+	goto find
+}
+
+// gotoReorderedConditional runs q.Count before q.Find on one path.
+func gotoReorderedConditional(db *gorm.DB, flag bool) {
+	q := db.Where("x = ?", 1)
+	if flag {
+		goto count
+	}
+find:
+	q.Find(nil) // want `\*gorm\.DB reused: second branch from mutable root`
+	return
+count:
+	q.Count(nil) // First use
+	goto find
+}
+
+// =============================================================================
+// SHOULD NOT REPORT - Each use sees its own value or runs once
+// =============================================================================
+
+// gotoForwardSkip skips a finisher with a forward goto.
+func gotoForwardSkip(db *gorm.DB, flag bool) {
+	q := db.Where("x = ?", 1)
+	if flag {
+		goto done
+	}
+	q.Find(nil)
+	return
+done:
+	q.Count(nil) // OK: q.Find is skipped on this path
+}
+
+// gotoBackRedefined re-runs the root's definition with a backward goto.
+func gotoBackRedefined(db *gorm.DB) {
+	n := 0
+retry:
+	q := db.Session(&gorm.Session{}).Where("x = ?", n)
+	q.Find(nil) // OK: a new q on each pass
+	n++
+	if n < 3 {
+		goto retry
+	}
+}
+
+// loopContinueRedefined finishes a root defined in the loop body on either
+// side of a continue.
+func loopContinueRedefined(db *gorm.DB, flag bool) {
+	base := db.Session(&gorm.Session{})
+	for i := 0; i < 3; i++ {
+		q := base.Where("i = ?", i)
+		if flag {
+			q.Find(nil)
+			continue
+		}
+		q.Count(nil) // OK: q.Find reaches it only through a new q
+	}
+}
@@ -0,0 +1,99 @@
+package internal
+
+import (
+	"gorm.io/gorm"
+)
+
+// =============================================================================
+// Helper Method Value Test Cases
+//
+// A helper method taken as a value, f := r.query, is called through a
+// synthesized $bound wrapper. The call is classified by the method itself, so
+// the directives of the method apply exactly as to a direct r.query() call.
+// See methodValue in evil.go for method values of *gorm.DB itself.
+// =============================================================================
+
+// methodValueRepo holds a *gorm.DB behind helper methods.
+type methodValueRepo struct {
+	db *gorm.DB
+}
+
+// query returns a fresh immutable handle.
+//
+//gormreuse:immutable-return
+func (r *methodValueRepo) query() *gorm.DB {
+	return r.db.Session(&gorm.Session{})
+}
+
+// scoped returns a mutable chain.
+func (r *methodValueRepo) scoped() *gorm.DB {
+	return r.db.Where("tenant = ?", 1)
+}
+
+// passthrough returns its argument unchanged.
+//
+//gormreuse:pure
+func (r *methodValueRepo) passthrough(db *gorm.DB) *gorm.DB {
+	return db
+}
+
+// methodValueQuery is a value-receiver counterpart of query.
+type methodValueQuery struct {
+	db *gorm.DB
+}
+
+//gormreuse:immutable-return
+func (r methodValueQuery) query() *gorm.DB {
+	return r.db.Session(&gorm.Session{})
+}
+
+// =============================================================================
+// SHOULD REPORT - Mutable result of a helper method value
+// =============================================================================
+
+// helperMethodValueMutable reuses the result of a mutable helper method value.
+func helperMethodValueMutable(r *methodValueRepo) {
+	f := r.scoped
+	q := f()
+	q.Find(nil)  // First use
+	q.Count(nil) // want `\*gorm\.DB reused: second branch from mutable root`
+}
+
+// helperMethodValuePassthrough branches the argument of a pure identity
+// method value and then the argument itself.
+func helperMethodValuePassthrough(r *methodValueRepo, db *gorm.DB) {
+	q := db.Where("x = ?", 1)
+	pass := r.passthrough
+	pass(q).Find(nil) // First use
+	q.Count(nil)      // want `\*gorm\.DB reused: second branch from mutable root`
+}
+
+// =============================================================================
+// SHOULD NOT REPORT - Immutable result of a helper method value
+// =============================================================================
+
+// helperMethodValueImmutable reuses the result of an immutable-return pointer
+// receiver method value.
+func helperMethodValueImmutable(r *methodValueRepo) {
+	f := r.query
+	q := f()
+	q.Find(nil)
+	q.Count(nil)
+}
+
+// helperMethodValueImmutableValueRecv reuses the result of an
+// immutable-return value receiver method value.
+func helperMethodValueImmutableValueRecv(r methodValueQuery) {
+	f := r.query
+	q := f()
+	q.Find(nil)
+	q.Count(nil)
+}
+
+// helperMethodValueImmutableInLoop calls the method value once per iteration.
+func helperMethodValueImmutableInLoop(r *methodValueRepo, ids []int) {
+	f := r.query
+	for _, id := range ids {
+		f().Where("id = ?", id).Find(nil)
+	}
+}
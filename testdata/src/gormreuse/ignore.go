@@ -53,10 +53,56 @@ func ignoredFunction(db *gorm.DB) {
 	q.First(nil) // Not reported - entire function ignored
 }
 
+// =============================================================================
+// SHOULD NOT REPORT - Root-level ignore
+// =============================================================================
+
+// ignoreOnRootDefinition suppresses every reuse of q with a single directive
+// on the line defining the root.
+func ignoreOnRootDefinition(db *gorm.DB) {
+	q := db.Where("active = ?", true) //gormreuse:ignore
+	q.Find(nil)
+	q.Count(nil)
+	q.First(nil)
+	q.Delete(nil)
+}
+
+// ignoreOnRootDefinitionBranches suppresses reuses of q in separate branches.
+func ignoreOnRootDefinitionBranches(db *gorm.DB, cond bool) {
+	q := db.Where("active = ?", true) //gormreuse:ignore // shared on purpose
+	q.Find(nil)
+	if cond {
+		q.Where("x = ?", 1).Count(nil)
+	} else {
+		q.Where("y = ?", 2).First(nil)
+	}
+}
+
+// =============================================================================
+// SHOULD REPORT - Root-level ignore does not cover other roots
+// =============================================================================
+
+// ignoreOnRootDefinitionOtherRoot only suppresses the reuses of q; the
+// independent root r is still reported.
+func ignoreOnRootDefinitionOtherRoot(db *gorm.DB) {
+	q := db.Where("active = ?", true) //gormreuse:ignore
+	r := db.Where("deleted = ?", false)
+	q.Find(nil)
+	q.Count(nil)
+	r.Find(nil)
+	r.Count(nil) // want `\*gorm\.DB reused: second branch from mutable root`
+}
+
 // =============================================================================
 // SHOULD REPORT - Unused ignore directives
 // =============================================================================
 
+// unusedIgnoreOnRootDefinition has a root-level ignore but q is never reused.
+func unusedIgnoreOnRootDefinition(db *gorm.DB) {
+	q := db.Where("x = ?", 1) //gormreuse:ignore // want `unused gormreuse:ignore directive`
+	q.Find(nil)
+}
+
 func unusedIgnoreOnSafeCode(db *gorm.DB) {
 	q := db.Where("x = ?", 1).Session(&gorm.Session{})
 	q.Find(nil)
@@ -0,0 +1,29 @@
+// Legacy queries kept as they were: every diagnostic in this file is
+// suppressed, whatever its kind.
+//
+//gormreuse:ignore-file // legacy module, reuse is intentional
+package internal
+
+import "gorm.io/gorm"
+
+// ignoreFileReuse: a second branch is not reported.
+func ignoreFileReuse(db *gorm.DB) {
+	q := db.Where("x = ?", 1)
+	q.Find(nil)
+	q.Count(nil) // suppressed by ignore-file
+}
+
+// ignoreFileLoop: loop reuse is not reported either.
+func ignoreFileLoop(db *gorm.DB, items []int) {
+	q := db.Where("x = ?", 1)
+	for range items {
+		q.Find(nil) // suppressed by ignore-file
+	}
+}
+
+// ignoreFileUnusedIgnore: an unused line-level ignore is a diagnostic of this
+// file too, so it is suppressed.
+func ignoreFileUnusedIgnore(db *gorm.DB) {
+	//gormreuse:ignore
+	db.Session(&gorm.Session{}).Find(nil)
+}
@@ -0,0 +1,107 @@
+package internal
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+)
+
+// =============================================================================
+// Immutable-Returning Method Arguments Test Cases
+//
+// Session and WithContext return an immutable *gorm.DB whatever their
+// arguments: gorm clones the statement regardless of the Session options
+// (NewDB included) or of the context passed, so the result can be branched
+// freely however the argument was built.
+// =============================================================================
+
+var sharedNewDBSession = &gorm.Session{NewDB: true}
+
+// =============================================================================
+// SHOULD REPORT - Chains started after the immutable-returning call
+// =============================================================================
+
+// newDBSessionThenWhere reuses a chain started from a NewDB session.
+func newDBSessionThenWhere(db *gorm.DB) {
+	q := db.Session(&gorm.Session{NewDB: true}).Where("x = ?", 1)
+	q.Find(nil)  // First use
+	q.Count(nil) // want `\*gorm\.DB reused: second branch from mutable root`
+}
+
+// contextThenWhere reuses a chain started from WithContext.
+func contextThenWhere(ctx context.Context, db *gorm.DB) {
+	q := db.WithContext(ctx).Where("x = ?", 1)
+	q.Find(nil)  // First use
+	q.Count(nil) // want `\*gorm\.DB reused: second branch from mutable root`
+}
+
+// =============================================================================
+// SHOULD NOT REPORT - Immutable whatever the arguments
+// =============================================================================
+
+// newDBSession branches a NewDB session twice.
+func newDBSession(db *gorm.DB) {
+	q := db.Where("x = ?", 1).Session(&gorm.Session{NewDB: true})
+	q.Find(nil)
+	q.Count(nil)
+}
+
+// newDBSessionBase starts two chains from a NewDB session.
+func newDBSessionBase(db *gorm.DB) {
+	base := db.Session(&gorm.Session{NewDB: true})
+	base.Where("a = ?", 1).Find(nil)
+	base.Where("b = ?", 2).Find(nil)
+}
+
+// storedSessionPointer passes a session stored in a local variable.
+func storedSessionPointer(db *gorm.DB) {
+	s := &gorm.Session{}
+	q := db.Where("x = ?", 1).Session(s)
+	q.Find(nil)
+	q.Count(nil)
+}
+
+// storedSessionValue passes the address of a local session value.
+func storedSessionValue(db *gorm.DB) {
+	s := gorm.Session{NewDB: true}
+	q := db.Where("x = ?", 1).Session(&s)
+	q.Find(nil)
+	q.Count(nil)
+}
+
+// sharedSession passes a package-level session.
+func sharedSession(db *gorm.DB) {
+	q := db.Where("x = ?", 1).Session(sharedNewDBSession)
+	q.Find(nil)
+	q.Count(nil)
+}
+
+// sessionParam passes a session received as a parameter.
+func sessionParam(db *gorm.DB, s *gorm.Session) {
+	q := db.Where("x = ?", 1).Session(s)
+	q.Find(nil)
+	q.Count(nil)
+}
+
+// sessionWithContext passes a context through the session options.
+func sessionWithContext(ctx context.Context, db *gorm.DB) {
+	q := db.Session(&gorm.Session{Context: ctx})
+	q.Find(nil)
+	q.Count(nil)
+}
+
+// contextParam passes a context received as a parameter.
+func contextParam(ctx context.Context, db *gorm.DB) {
+	q := db.Where("x = ?", 1).WithContext(ctx)
+	q.Find(nil)
+	q.Count(nil)
+}
+
+// derivedContext passes a context derived from a parameter.
+func derivedContext(ctx context.Context, db *gorm.DB) {
+	c, cancel := context.WithCancel(ctx)
+	defer cancel()
+	q := db.WithContext(c)
+	q.Find(nil)
+	q.Count(nil)
+}
@@ -0,0 +1,81 @@
+package internal
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+)
+
+// =============================================================================
+// Late Session Test Cases
+//
+// Session on a value an earlier branch already polluted copies the polluted
+// Statement, so it does not make the value reusable. These are reported in the
+// LATE-SESSION category with a message pointing at the root, at the position
+// of the Session call.
+// =============================================================================
+
+// =============================================================================
+// SHOULD REPORT - Session after the root was branched
+// =============================================================================
+
+// lateSessionAfterFinisher finishes q, then isolates it with Session.
+func lateSessionAfterFinisher(db *gorm.DB) {
+	q := db.Where("x = ?", 1)
+	q.Find(nil)                           // First use
+	q.Session(&gorm.Session{}).Count(nil) // want `\*gorm\.DB reused: Session on an already-polluted value does not undo the earlier branch \(root at late_session\.go:\d+, first branch at late_session\.go:\d+\); move \.Session\(&gorm\.Session\{\}\) to the root`
+}
+
+// lateSessionAfterBranch branches q with a builder, then isolates it.
+func lateSessionAfterBranch(db *gorm.DB) {
+	q := db.Where("x = ?", 1)
+	q.Where("a = ?", 1)             // First use
+	s := q.Session(&gorm.Session{}) // want `\*gorm\.DB reused: Session on an already-polluted value`
+	s.Find(nil)
+	s.Count(nil)
+}
+
+// lateSessionInClosure isolates a polluted capture inside a closure.
+func lateSessionInClosure(db *gorm.DB) {
+	q := db.Where("x = ?", 1)
+	q.Find(nil) // First use
+	func() {
+		q.Session(&gorm.Session{}).Count(nil) // want `\*gorm\.DB reused: Session on an already-polluted value`
+	}()
+}
+
+// lateSessionWithContext is a pure use other than Session, reported as a
+// plain second branch.
+func lateSessionWithContext(ctx context.Context, db *gorm.DB) {
+	q := db.Where("x = ?", 1)
+	q.Find(nil)                   // First use
+	q.WithContext(ctx).Count(nil) // want `\*gorm\.DB reused: second branch from mutable root`
+}
+
+// =============================================================================
+// SHOULD NOT REPORT - Session before any branch or on another path
+// =============================================================================
+
+// lateSessionAtRoot isolates q before it is branched.
+func lateSessionAtRoot(db *gorm.DB) {
+	q := db.Where("x = ?", 1).Session(&gorm.Session{})
+	q.Find(nil)
+	q.Session(&gorm.Session{}).Count(nil)
+}
+
+// lateSessionOtherPath isolates q on a path the branch does not reach.
+func lateSessionOtherPath(db *gorm.DB, cond bool) {
+	q := db.Where("x = ?", 1)
+	if cond {
+		q.Find(nil)
+	} else {
+		q.Session(&gorm.Session{}).Count(nil)
+	}
+}
+
+// lateSessionAllowed confirms the reuse with a directive.
+func lateSessionAllowed(db *gorm.DB) {
+	q := db.Where("x = ?", 1)
+	q.Find(nil)
+	q.Session(&gorm.Session{}).Count(nil) //gormreuse:allow-reuse
+}
@@ -0,0 +1,113 @@
+package internal
+
+import (
+	"gorm.io/gorm"
+)
+
+// =============================================================================
+// Loop Reassign/Finish Test Cases
+//
+// A loop body that reassigns the root on one path and finishes it on another
+// carries the root unchanged into the next iteration whenever the finishing
+// path runs, so the finisher may run twice on the same value. The finisher is
+// reported once; the reassignment is not.
+// =============================================================================
+
+// =============================================================================
+// SHOULD REPORT - Finisher on a path that does not reassign the root
+// =============================================================================
+
+// loopReassignOrFinish reassigns in one branch and finishes in the other.
+func loopReassignOrFinish(db *gorm.DB, ids []int) {
+	q := db.Where("x = ?", 1)
+	for _, id := range ids {
+		if id > 0 {
+			q = q.Where("id = ?", id)
+		} else {
+			q.Find(nil) // want `\*gorm\.DB reused: second branch from mutable root`
+		}
+	}
+}
+
+// loopReassignOrFinishForever is the same shape in an infinite loop.
+func loopReassignOrFinishForever(db *gorm.DB, next func() bool) {
+	q := db.Where("x = ?", 1)
+	for {
+		if next() {
+			q = q.Where("y = ?", 2)
+		} else {
+			q.Find(nil) // want `\*gorm\.DB reused: second branch from mutable root`
+		}
+	}
+}
+
+// loopReassignContinue reassigns and skips to the next iteration, or finishes.
+func loopReassignContinue(db *gorm.DB, ids []int) {
+	q := db.Where("x = ?", 1)
+	for _, id := range ids {
+		if id > 0 {
+			q = q.Where("id = ?", id)
+			continue
+		}
+		q.Find(nil) // want `\*gorm\.DB reused: second branch from mutable root`
+	}
+}
+
+// loopReassignSwitch reassigns in two cases and finishes in the default.
+// Case 2 branches again a value case 1 assigned and the default finished in
+// earlier iterations.
+func loopReassignSwitch(db *gorm.DB, ids []int) {
+	q := db.Where("x = ?", 1)
+	for _, id := range ids {
+		switch id {
+		case 1:
+			q = q.Where("a = ?", 1)
+		case 2:
+			q = q.Where("b = ?", 2) // want `\*gorm\.DB reused: second branch from mutable root`
+		default:
+			q.Count(nil) // want `\*gorm\.DB reused: second branch from mutable root`
+		}
+	}
+}
+
+// =============================================================================
+// SHOULD NOT REPORT - The finishing path leaves the loop or replaces the root
+// =============================================================================
+
+// loopReassignOrFinishBreak finishes once and leaves the loop.
+func loopReassignOrFinishBreak(db *gorm.DB, ids []int) {
+	q := db.Where("x = ?", 1)
+	for _, id := range ids {
+		if id > 0 {
+			q = q.Where("id = ?", id)
+		} else {
+			q.Find(nil)
+			break
+		}
+	}
+}
+
+// loopReassignOrFinishReturn finishes once and returns.
+func loopReassignOrFinishReturn(db *gorm.DB, ids []int) {
+	q := db.Where("x = ?", 1)
+	for _, id := range ids {
+		if id > 0 {
+			q = q.Where("id = ?", id)
+		} else {
+			q.Find(nil)
+			return
+		}
+	}
+}
+
+// loopFinishThenReplace finishes the root and replaces it on the same path.
+func loopFinishThenReplace(db *gorm.DB, ids []int) {
+	base := db.Session(&gorm.Session{})
+	q := base.Where("x = ?", 1)
+	for _, id := range ids {
+		if id > 0 {
+			q.Find(nil)
+			q = base.Where("id = ?", id)
+		}
+	}
+}
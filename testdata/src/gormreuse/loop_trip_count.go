@@ -0,0 +1,186 @@
+package internal
+
+import (
+	"gorm.io/gorm"
+)
+
+// =============================================================================
+// Loop Trip Count Test Cases
+//
+// A defer registered in a loop body runs once per iteration at exit, so it is
+// a reuse on its own when the loop may iterate more than once. A counter loop
+// with constant bounds that provably enters its body at most once registers
+// the defer only once, like a loop ending in an unconditional break.
+//
+// Likewise, a use in a loop of a root defined outside it is a reuse on its
+// own only when the loop may iterate more than once. Besides counter loops,
+// a range over an array or a slice literal of at most one element runs at
+// most once.
+// =============================================================================
+
+// =============================================================================
+// SHOULD REPORT - Defer in a loop that may iterate more than once
+// =============================================================================
+
+// loopTripTwoIterations defers in a counter loop running twice.
+func loopTripTwoIterations(db *gorm.DB) {
+	q := db.Where("x = ?", 1)
+	for i := 0; i < 2; i++ {
+		defer q.Count(nil) // want `\*gorm\.DB reused: second branch from mutable root`
+	}
+}
+
+// loopTripUnknownBound defers in a counter loop with a variable bound.
+func loopTripUnknownBound(db *gorm.DB, n int) {
+	q := db.Where("x = ?", 1)
+	for i := 0; i < n; i++ {
+		defer q.Count(nil) // want `\*gorm\.DB reused: second branch from mutable root`
+	}
+}
+
+// loopTripClosureTwoIterations defers a closure in a range loop running twice.
+func loopTripClosureTwoIterations(db *gorm.DB) {
+	q := db.Where("x = ?", 1)
+	for range 2 {
+		defer func() {
+			q.Count(nil) // want `\*gorm\.DB reused: second branch from mutable root`
+		}()
+	}
+}
+
+// loopTripSingleInsideMulti defers in a single-iteration loop nested in a
+// loop that may iterate more than once.
+func loopTripSingleInsideMulti(db *gorm.DB, ids []int) {
+	q := db.Where("x = ?", 1)
+	for range ids {
+		for i := 0; i < 1; i++ {
+			defer q.Count(nil) // want `\*gorm\.DB reused: second branch from mutable root`
+		}
+	}
+}
+
+// =============================================================================
+// SHOULD NOT REPORT - Defer registered at most once
+// =============================================================================
+
+// loopTripSingleIteration defers in a counter loop running once.
+func loopTripSingleIteration(db *gorm.DB) {
+	q := db.Where("x = ?", 1)
+	for i := 0; i < 1; i++ {
+		defer q.Count(nil)
+	}
+}
+
+// loopTripSingleIterationLEQ defers in a counter loop running once with <=.
+func loopTripSingleIterationLEQ(db *gorm.DB) {
+	q := db.Where("x = ?", 1)
+	for i := 1; i <= 1; i++ {
+		defer q.Count(nil)
+	}
+}
+
+// loopTripRangeOne defers a closure in a range over 1.
+func loopTripRangeOne(db *gorm.DB) {
+	q := db.Where("x = ?", 1)
+	for range 1 {
+		defer func() {
+			q.Count(nil)
+		}()
+	}
+}
+
+// loopTripUnconditionalBreak defers in a loop ending in an unconditional
+// break, which has no back-edge.
+func loopTripUnconditionalBreak(db *gorm.DB) {
+	q := db.Where("x = ?", 1)
+	for {
+		defer q.Count(nil)
+		break
+	}
+}
+
+// =============================================================================
+// SHOULD REPORT - Use in a loop that may iterate more than once
+// =============================================================================
+
+// loopTripRangeTwoLiteral uses an outer root in a range over two elements.
+func loopTripRangeTwoLiteral(db *gorm.DB) {
+	q := db.Where("x = ?", 1)
+	for _, id := range []int{1, 2} {
+		q.Where("id = ?", id).Find(nil) // want `\*gorm\.DB reused: second branch from mutable root`
+	}
+}
+
+// loopTripRangeUnknownSlice uses an outer root in a range over a slice of
+// unknown length.
+func loopTripRangeUnknownSlice(db *gorm.DB, ids []int) {
+	q := db.Where("x = ?", 1)
+	for _, id := range ids {
+		q.Where("id = ?", id).Find(nil) // want `\*gorm\.DB reused: second branch from mutable root`
+	}
+}
+
+// loopTripUseTwoIterations uses an outer root in a counter loop running
+// twice.
+func loopTripUseTwoIterations(db *gorm.DB) {
+	q := db.Where("x = ?", 1)
+	for i := 0; i < 2; i++ {
+		q.Find(nil) // want `\*gorm\.DB reused: second branch from mutable root`
+	}
+}
+
+// loopTripUseSingleThenAfter uses an outer root in a loop running once and
+// again after it.
+func loopTripUseSingleThenAfter(db *gorm.DB) {
+	q := db.Where("x = ?", 1)
+	for _, id := range []int{1} {
+		q.Where("id = ?", id).Find(nil)
+	}
+	q.Count(nil) // want `\*gorm\.DB reused: second branch from mutable root`
+}
+
+// =============================================================================
+// SHOULD NOT REPORT - Use in a loop running at most once
+// =============================================================================
+
+// loopTripRangeOneLiteral uses an outer root in a range over one element.
+func loopTripRangeOneLiteral(db *gorm.DB) {
+	q := db.Where("x = ?", 1)
+	for _, id := range []int{1} {
+		q.Where("id = ?", id).Find(nil)
+	}
+}
+
+// loopTripRangeOneArray uses an outer root in a range over a one-element
+// array.
+func loopTripRangeOneArray(db *gorm.DB) {
+	q := db.Where("x = ?", 1)
+	for _, id := range [...]int{1} {
+		q.Where("id = ?", id).Find(nil)
+	}
+}
+
+// loopTripRangeEmptyLiteral uses an outer root in a range over no elements.
+func loopTripRangeEmptyLiteral(db *gorm.DB) {
+	q := db.Where("x = ?", 1)
+	for _, id := range []int{} {
+		q.Where("id = ?", id).Find(nil)
+	}
+}
+
+// loopTripUseSingleIteration uses an outer root in a counter loop running
+// once.
+func loopTripUseSingleIteration(db *gorm.DB) {
+	q := db.Where("x = ?", 1)
+	for i := 0; i < 1; i++ {
+		q.Find(nil)
+	}
+}
+
+// loopTripUseRangeOne uses an outer root in a range over 1.
+func loopTripUseRangeOne(db *gorm.DB) {
+	q := db.Where("x = ?", 1)
+	for range 1 {
+		q.Find(nil)
+	}
+}
@@ -0,0 +1,58 @@
+package internal
+
+import "gorm.io/gorm"
+
+// =============================================================================
+// Map of **gorm.DB Test Cases
+//
+// A root whose address is stored in a map is reached through every read of
+// the map: a constant key reads back the address stored at that key, and a
+// variable key may read any stored address.
+// =============================================================================
+
+// =============================================================================
+// SHOULD REPORT - the same root reached through the map twice
+// =============================================================================
+
+// mapOfDBPointers reaches q through its address stored in a map.
+func mapOfDBPointers(db *gorm.DB) {
+	q := db.Where("x = ?", 1)
+	r := db.Where("y = ?", 1)
+	m := map[string]**gorm.DB{}
+	m["q"] = &q
+	m["r"] = &r
+	(*m["q"]).Find(nil)
+	(*m["r"]).Find(nil)  // OK: another key, another root
+	(*m["q"]).Count(nil) // want `\*gorm\.DB reused: second branch from mutable root`
+}
+
+// mapOfDBPointersThenDirect pollutes q through the map, then uses q itself.
+func mapOfDBPointersThenDirect(db *gorm.DB) {
+	q := db.Where("x = ?", 1)
+	m := map[string]**gorm.DB{"q": &q}
+	(*m["q"]).Find(nil)
+	q.Count(nil) // want `\*gorm\.DB reused: second branch from mutable root`
+}
+
+// mapOfDBPointersVariableKey reads the map with a variable key, which may
+// read any stored address.
+func mapOfDBPointersVariableKey(db *gorm.DB, key string) {
+	q := db.Where("x = ?", 1)
+	m := map[string]**gorm.DB{}
+	m[key] = &q
+	(*m[key]).Find(nil)
+	(*m[key]).Count(nil) // want `\*gorm\.DB reused: second branch from mutable root`
+}
+
+// =============================================================================
+// SHOULD NOT REPORT - each root reached once
+// =============================================================================
+
+// mapOfDBPointersDistinctKeys branches each root once through its own key.
+func mapOfDBPointersDistinctKeys(db *gorm.DB) {
+	q := db.Where("x = ?", 1)
+	r := db.Where("y = ?", 1)
+	m := map[string]**gorm.DB{"q": &q, "r": &r}
+	(*m["q"]).Find(nil) // OK: first use of q
+	(*m["r"]).Find(nil) // OK: first use of r
+}
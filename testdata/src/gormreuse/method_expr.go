@@ -0,0 +1,87 @@
+package internal
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+)
+
+// =============================================================================
+// Method Expression Test Cases
+//
+// A method expression, (*gorm.DB).Find, takes the receiver as its first
+// argument: (*gorm.DB).Find(q, nil) is q.Find(nil). Its calls are classified
+// like method calls, called directly or through a variable: Session and
+// WithContext return an immutable value, any other method branches its
+// receiver.
+// =============================================================================
+
+// =============================================================================
+// SHOULD REPORT - Branching method expressions
+// =============================================================================
+
+// methodExprFindTwice finishes the root twice through a method expression
+// variable.
+func methodExprFindTwice(db *gorm.DB) {
+	q := db.Where("x = ?", 1)
+	find := (*gorm.DB).Find
+	find(q, nil) // First use
+	find(q, nil) // want `\*gorm\.DB reused: second branch from mutable root`
+}
+
+// methodExprDirect calls method expressions directly.
+func methodExprDirect(db *gorm.DB) {
+	q := db.Where("x = ?", 1)
+	(*gorm.DB).Find(q, nil)  // First use
+	(*gorm.DB).Count(q, nil) // want `\*gorm\.DB reused: second branch from mutable root`
+}
+
+// methodExprWhereRoot reuses a root created by a Where method expression.
+func methodExprWhereRoot(db *gorm.DB) {
+	q := (*gorm.DB).Where(db, "x = ?", 1)
+	q.Find(nil)  // First use
+	q.Count(nil) // want `\*gorm\.DB reused: second branch from mutable root`
+}
+
+// methodExprLateSession isolates the root with a Session method expression
+// after it was already branched.
+func methodExprLateSession(db *gorm.DB) {
+	q := db.Where("x = ?", 1)
+	q.Find(nil)                                       // First use
+	(*gorm.DB).Session(q, &gorm.Session{}).Count(nil) // want `\*gorm\.DB reused: Session on an already-polluted value`
+}
+
+// =============================================================================
+// SHOULD NOT REPORT - Immutable-returning method expressions
+// =============================================================================
+
+// methodExprSession isolates the root with a Session method expression.
+func methodExprSession(db *gorm.DB) {
+	q := (*gorm.DB).Session(db.Where("x = ?", 1), &gorm.Session{})
+	q.Find(nil)
+	q.Count(nil) // OK: Session returns an immutable value
+}
+
+// methodExprSessionVar isolates the root through a Session method expression
+// variable.
+func methodExprSessionVar(db *gorm.DB) {
+	session := (*gorm.DB).Session
+	q := session(db.Where("x = ?", 1), &gorm.Session{})
+	q.Find(nil)
+	q.Count(nil) // OK: Session returns an immutable value
+}
+
+// methodExprWithContext isolates the root with a WithContext method
+// expression.
+func methodExprWithContext(ctx context.Context, db *gorm.DB) {
+	q := (*gorm.DB).WithContext(db.Where("x = ?", 1), ctx)
+	q.Find(nil)
+	q.Count(nil) // OK: WithContext returns an immutable value
+}
+
+// methodExprReassign narrows the root with a Where method expression.
+func methodExprReassign(db *gorm.DB) {
+	q := db.Where("x = ?", 1)
+	q = (*gorm.DB).Where(q, "y = ?", 2)
+	q.Find(nil) // OK: q is the new chain
+}
@@ -0,0 +1,160 @@
+package internal
+
+import (
+	"gorm.io/gorm"
+)
+
+// =============================================================================
+// Named Result Branches Test Cases
+//
+// A named *gorm.DB result assigned in several branches and returned by a bare
+// return is a Phi of its assignments at the return, or a load of the variable
+// when a deferred closure captures it. A closure called in place and chained
+// traces through either to the roots of every assignment. The result of a
+// named function is its own root unless it is //gormreuse:immutable-return,
+// whose contract sees every assignment.
+// =============================================================================
+
+// namedResultIfElse assigns its named result in both branches.
+func namedResultIfElse(db *gorm.DB, admin bool) (result *gorm.DB) {
+	if admin {
+		result = db.Where("role = ?", "admin")
+	} else {
+		result = db.Where("role = ?", "user")
+	}
+	return
+}
+
+// namedResultMaybeNarrowed assigns an immutable value, then narrows it in one
+// branch.
+func namedResultMaybeNarrowed(db *gorm.DB, admin bool) (result *gorm.DB) {
+	result = db.Session(&gorm.Session{})
+	if admin {
+		result = result.Where("role = ?", "admin")
+	}
+	return
+}
+
+// namedResultImmutableBranches assigns an immutable value in both branches.
+//
+//gormreuse:immutable-return
+func namedResultImmutableBranches(db *gorm.DB, admin bool) (result *gorm.DB) {
+	if admin {
+		result = db.Where("role = ?", "admin").Session(&gorm.Session{})
+	} else {
+		result = db.Where("role = ?", "user").Session(&gorm.Session{})
+	}
+	return
+}
+
+// namedResultMutableBranch breaks its contract in one branch.
+//
+//gormreuse:immutable-return
+func namedResultMutableBranch(db *gorm.DB, admin bool) (result *gorm.DB) { // want `immutable-return declared but function returns mutable \*gorm\.DB`
+	if admin {
+		result = db.Where("role = ?", "admin").Session(&gorm.Session{})
+	} else {
+		result = db.Where("role = ?", "user")
+	}
+	return
+}
+
+// =============================================================================
+// SHOULD REPORT - Result of a named result assigned in branches reused
+// =============================================================================
+
+// namedResultIfElseReuse reuses the result assigned in either branch.
+func namedResultIfElseReuse(db *gorm.DB, admin bool) {
+	q := namedResultIfElse(db.Session(&gorm.Session{}), admin)
+	q.Find(nil)
+	q.Count(nil) // want `\*gorm\.DB reused: second branch from mutable root`
+}
+
+// namedResultMaybeNarrowedReuse reuses a result mutable in one branch only.
+func namedResultMaybeNarrowedReuse(db *gorm.DB, admin bool) {
+	q := namedResultMaybeNarrowed(db, admin)
+	q.Find(nil)
+	q.Count(nil) // want `\*gorm\.DB reused: second branch from mutable root`
+}
+
+// namedResultIIFEReuse reuses the named result of a closure called in place.
+func namedResultIIFEReuse(db *gorm.DB, admin bool) {
+	base := db.Session(&gorm.Session{})
+	q := func() (r *gorm.DB) {
+		if admin {
+			r = base.Where("role = ?", "admin")
+		} else {
+			r = base.Where("role = ?", "user")
+		}
+		return
+	}()
+	q.Find(nil)
+	q.Count(nil) // want `\*gorm\.DB reused: second branch from mutable root`
+}
+
+// namedResultIIFEChained chains on the named result of a closure called in
+// place, assigned a polluted root in one branch.
+func namedResultIIFEChained(db *gorm.DB, admin bool) {
+	base := db.Session(&gorm.Session{})
+	q1 := base.Where("role = ?", "admin")
+	q2 := base.Where("role = ?", "user")
+	q2.Find(nil)
+	_ = func() (r *gorm.DB) {
+		if admin {
+			r = q1
+		} else {
+			r = q2
+		}
+		return
+	}().Count(nil) // want `\*gorm\.DB reused: second branch from mutable root`
+}
+
+// namedResultIIFEChainedDefer is namedResultIIFEChained with the named result
+// captured by a deferred closure.
+func namedResultIIFEChainedDefer(db *gorm.DB, admin bool) {
+	base := db.Session(&gorm.Session{})
+	q1 := base.Where("role = ?", "admin")
+	q2 := base.Where("role = ?", "user")
+	q2.Find(nil)
+	_ = func() (r *gorm.DB) {
+		defer func() {
+			if recover() != nil {
+				r = nil
+			}
+		}()
+		if admin {
+			r = q1
+		} else {
+			r = q2
+		}
+		return
+	}().Count(nil) // want `\*gorm\.DB reused: second branch from mutable root`
+}
+
+// =============================================================================
+// SHOULD NOT REPORT - Immutable or unused in every branch
+// =============================================================================
+
+// namedResultImmutableBranchesReuse reuses the result of an immutable-return
+// function assigning an immutable value in both branches.
+func namedResultImmutableBranchesReuse(db *gorm.DB, admin bool) {
+	q := namedResultImmutableBranches(db, admin)
+	q.Find(nil)
+	q.Count(nil)
+}
+
+// namedResultIIFEChainedUnpolluted chains on the named result of a closure
+// called in place, assigned an unused root in each branch.
+func namedResultIIFEChainedUnpolluted(db *gorm.DB, admin bool) {
+	base := db.Session(&gorm.Session{})
+	q1 := base.Where("role = ?", "admin")
+	q2 := base.Where("role = ?", "user")
+	_ = func() (r *gorm.DB) {
+		if admin {
+			r = q1
+		} else {
+			r = q2
+		}
+		return
+	}().Count(nil)
+}
@@ -674,11 +674,12 @@ func chaosPolluteInLoop(db *gorm.DB, items []int, threshold int) {
 }
 
 // chaosNestedLoopsWithPollution demonstrates nested loops with pollution.
+// The outer Where branches again a value the previous outer iteration finished.
 func chaosNestedLoopsWithPollution(db *gorm.DB, outer, inner []int) {
 	q := db.Where("base")
 
 	for _, o := range outer {
-		q = q.Where("outer", o)
+		q = q.Where("outer", o) // want `\*gorm\.DB reused: second branch from mutable root`
 
 		for _, i := range inner {
 			if i%2 == 0 {
@@ -969,6 +970,44 @@ func chaosModernSwapLoop(db *gorm.DB, flags []bool) {
 	q2.Count(nil) // want `\*gorm\.DB reused: second branch from mutable root`
 }
 
+// chaosRotationLoop demonstrates a three-way rotation in a loop. The rotation
+// has no swap pair, but its Phis permute the loop variables all the same.
+func chaosRotationLoop(db *gorm.DB, items []int) {
+	q1 := db.Where("q1")
+	q2 := db.Where("q2")
+	q3 := db.Where("q3")
+
+	for _, item := range items {
+		if item%3 == 0 {
+			q1, q2, q3 = q3, q1, q2 // Rotate on every third iteration
+		}
+		q1 = q1.Where("item", item)
+	}
+
+	q1.Find(nil) // OK: first use after loop with assignments only
+	q2.Find(nil) // OK: first use
+	q3.Find(nil) // OK: first use
+}
+
+// chaosRotationLoopPolluted demonstrates that pollution before the loop still
+// reaches every rotated variable.
+func chaosRotationLoopPolluted(db *gorm.DB, items []int) {
+	q1 := db.Where("q1")
+	q2 := db.Where("q2")
+	q3 := db.Where("q3")
+
+	q3.Find(nil) // Pollute q3
+
+	for _, item := range items {
+		if item%3 == 0 {
+			q1, q2, q3 = q3, q1, q2
+		}
+		q1 = q1.Where("item", item) // want `\*gorm\.DB reused: second branch from mutable root`
+	}
+
+	q2.Find(nil) // want `\*gorm\.DB reused: second branch from mutable root`
+}
+
 // chaosUltimateStressTest demonstrates the ultimate stress test with everything mixed.
 func chaosUltimateStressTest(db *gorm.DB, flags []bool) {
 	q := db.Where("base")
@@ -0,0 +1,87 @@
+package internal
+
+import (
+	"gorm.io/gorm"
+)
+
+// =============================================================================
+// Nested Loop Test Cases
+//
+// A root defined in an outer loop is fresh for each outer iteration, but it is
+// still defined outside a loop nested in it. A use in the inner loop, labeled
+// or not, is a reuse on its own when the inner loop may iterate more than once.
+// =============================================================================
+
+// =============================================================================
+// SHOULD REPORT - Root from an outer loop used in an inner loop
+// =============================================================================
+
+// nestedLoopLabeled uses a root from the labeled outer loop in the labeled
+// inner loop, continuing and breaking by label.
+func nestedLoopLabeled(db *gorm.DB, groups [][]int) {
+	base := db.Session(&gorm.Session{})
+outer:
+	for _, g := range groups {
+		q := base.Where("x")
+	inner:
+		for _, id := range g {
+			q.Where("id = ?", id).Find(nil) // want `\*gorm\.DB reused: second branch from mutable root`
+			if id < 0 {
+				continue outer
+			}
+			if id == 0 {
+				break inner
+			}
+		}
+	}
+}
+
+// nestedLoopUnlabeled is the same without labels.
+func nestedLoopUnlabeled(db *gorm.DB, groups [][]int) {
+	base := db.Session(&gorm.Session{})
+	for _, g := range groups {
+		q := base.Where("x")
+		for _, id := range g {
+			q.Where("id = ?", id).Find(nil) // want `\*gorm\.DB reused: second branch from mutable root`
+		}
+	}
+}
+
+// =============================================================================
+// SHOULD NOT REPORT - Inner loop runs at most once, or the root is fresh
+// =============================================================================
+
+// nestedLoopInnerOnce breaks out of the inner loop unconditionally.
+func nestedLoopInnerOnce(db *gorm.DB, groups [][]int) {
+	base := db.Session(&gorm.Session{})
+	for _, g := range groups {
+		q := base.Where("x")
+		for _, id := range g {
+			q.Where("id = ?", id).Find(nil)
+			break
+		}
+	}
+}
+
+// nestedLoopInnerRoot defines the root in the inner loop.
+func nestedLoopInnerRoot(db *gorm.DB, groups [][]int) {
+	base := db.Session(&gorm.Session{})
+	for _, g := range groups {
+		for _, id := range g {
+			q := base.Where("x")
+			q.Where("id = ?", id).Find(nil)
+		}
+	}
+}
+
+// nestedLoopOuterUse uses the root once per outer iteration, after the inner
+// loop.
+func nestedLoopOuterUse(db *gorm.DB, groups [][]int) {
+	base := db.Session(&gorm.Session{})
+	for _, g := range groups {
+		q := base.Where("x")
+		for range g {
+		}
+		q.Find(nil)
+	}
+}
@@ -0,0 +1,124 @@
+package internal
+
+import (
+	"errors"
+
+	"gorm.io/gorm"
+)
+
+// =============================================================================
+// SHOULD NOT REPORT - golangci-lint //nolint suppressions
+// =============================================================================
+
+func nolintGormreuseOnSameLine(db *gorm.DB) {
+	q := db.Where("active = ?", true)
+	q.Find(nil)
+	q.Count(nil) //nolint:gormreuse
+}
+
+func nolintBareOnSameLine(db *gorm.DB) {
+	q := db.Where("active = ?", true)
+	q.Find(nil)
+	q.Count(nil) //nolint
+}
+
+func nolintOnPreviousLine(db *gorm.DB) {
+	q := db.Where("active = ?", true)
+	q.Find(nil)
+	//nolint:gormreuse // intentional reuse for pagination
+	q.Count(nil)
+}
+
+func nolintAmongLinters(db *gorm.DB) {
+	q := db.Where("active = ?", true)
+	q.Find(nil)
+	q.Count(nil) //nolint:errcheck,gormreuse
+}
+
+func nolintAll(db *gorm.DB) {
+	q := db.Where("active = ?", true)
+	q.Find(nil)
+	q.Count(nil) //nolint:all
+}
+
+// nolintStatementLine: a //nolint on the first line of a statement covers the
+// lines it continues on.
+func nolintStatementLine(db *gorm.DB) error {
+	q := db.Where("active = ?", true)
+	var n int64
+	return errors.Join(q.Find(nil).Error, //nolint:gormreuse
+		q.Count(&n).Error)
+}
+
+// nolintBeforeStatement: a //nolint on its own line covers the whole
+// statement below it.
+func nolintBeforeStatement(db *gorm.DB) error {
+	q := db.Where("active = ?", true)
+	var n int64
+	//nolint
+	return errors.Join(q.Find(nil).Error,
+		q.Count(&n).Error)
+}
+
+// nolintUnused: unlike //gormreuse:ignore, a //nolint suppressing nothing is
+// not reported.
+func nolintUnused(db *gorm.DB) {
+	q := db.Where("active = ?", true)
+	q.Find(nil) //nolint:gormreuse
+}
+
+// nolintFuncDoc: a //nolint in the doc comment of a function covers the whole
+// function.
+//
+//nolint:gormreuse
+func nolintFuncDoc(db *gorm.DB) {
+	q := db.Where("active = ?", true)
+	q.Find(nil)
+	if q != nil {
+		q.Count(nil)
+	}
+}
+
+// =============================================================================
+// SHOULD REPORT - //nolint not covering the violation
+// =============================================================================
+
+func nolintOtherLinter(db *gorm.DB) {
+	q := db.Where("active = ?", true)
+	q.Find(nil)
+	q.Count(nil) //nolint:errcheck // want `\*gorm\.DB reused: second branch from mutable root`
+}
+
+func nolintSimilarName(db *gorm.DB) {
+	q := db.Where("active = ?", true)
+	q.Find(nil)
+	q.Count(nil) //nolint:gormreusex // want `\*gorm\.DB reused: second branch from mutable root`
+}
+
+// nolintOnRootLine: unlike //gormreuse:ignore, a //nolint on the line defining
+// a root covers that line only, not every reuse of the root.
+func nolintOnRootLine(db *gorm.DB) {
+	q := db.Where("active = ?", true) //nolint:gormreuse
+	q.Find(nil)
+	q.Count(nil) // want `\*gorm\.DB reused: second branch from mutable root`
+}
+
+// nolintPastStatement: a //nolint covering a multi-line statement does not
+// reach the line after it.
+func nolintPastStatement(db *gorm.DB) error {
+	q := db.Where("active = ?", true)
+	var n int64
+	err := errors.Join(q.Find(nil).Error, //nolint:gormreuse
+		q.Count(&n).Error)
+	q.First(nil) // want `\*gorm\.DB reused: second branch from mutable root`
+	return err
+}
+
+// nolintTrailingNextLine: a //nolint trailing a one-line statement does not
+// reach the line below it.
+func nolintTrailingNextLine(db *gorm.DB) {
+	q := db.Where("active = ?", true)
+	q.Find(nil)
+	q.Count(nil) //nolint:gormreuse
+	q.First(nil) // want `\*gorm\.DB reused: second branch from mutable root`
+}
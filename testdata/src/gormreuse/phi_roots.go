@@ -0,0 +1,61 @@
+package internal
+
+import "gorm.io/gorm"
+
+// =============================================================================
+// SHOULD REPORT - Phi merging several polluted roots
+// The receiver may be any of the merged roots; each polluted one is a reuse at
+// the same position, reported as a single diagnostic.
+// =============================================================================
+
+// phiRootsBothPolluted: both merged roots are finished before the merge.
+func phiRootsBothPolluted(db *gorm.DB, cond bool) {
+	q1 := db.Where("a = ?", 1)
+	q2 := db.Where("b = ?", 2)
+	q1.Find(nil)
+	q2.Find(nil)
+	var q *gorm.DB
+	if cond {
+		q = q1
+	} else {
+		q = q2
+	}
+	q.Count(nil) // want `\*gorm\.DB reused: second branch from mutable root`
+}
+
+// phiRootsThreeWay: a switch merges three polluted roots.
+func phiRootsThreeWay(db *gorm.DB, n int) {
+	q1 := db.Where("a = ?", 1)
+	q2 := db.Where("b = ?", 2)
+	q3 := db.Where("c = ?", 3)
+	q1.Find(nil)
+	q2.Find(nil)
+	q3.Find(nil)
+	var q *gorm.DB
+	switch n {
+	case 1:
+		q = q1
+	case 2:
+		q = q2
+	default:
+		q = q3
+	}
+	q.Count(nil) // want `\*gorm\.DB reused: second branch from mutable root`
+}
+
+// =============================================================================
+// SHOULD NOT REPORT - Phi merging several roots
+// =============================================================================
+
+// phiRootsNonePolluted: neither merged root is used before the merge.
+func phiRootsNonePolluted(db *gorm.DB, cond bool) {
+	q1 := db.Where("a = ?", 1)
+	q2 := db.Where("b = ?", 2)
+	var q *gorm.DB
+	if cond {
+		q = q1
+	} else {
+		q = q2
+	}
+	q.Count(nil)
+}
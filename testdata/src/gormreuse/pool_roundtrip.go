@@ -0,0 +1,90 @@
+package internal
+
+import (
+	"sync"
+
+	"gorm.io/gorm"
+)
+
+// =============================================================================
+// SHOULD REPORT - sync.Pool round trips
+// Put boxes the *gorm.DB into the pool; Get().(*gorm.DB) extracts the SAME
+// value, so within one function the extracted value stays tied to its root.
+// =============================================================================
+
+// poolRoundtripReuse: put a chain, get it back, then reuse it.
+func poolRoundtripReuse(db *gorm.DB) {
+	var pool sync.Pool
+	q := db.Where("x = ?", 1)
+	pool.Put(q)
+	q2 := pool.Get().(*gorm.DB)
+	q2.Find(nil)
+	q2.Count(nil) // want `\*gorm\.DB reused: second branch from mutable root`
+}
+
+// poolRoundtripPointer: a *sync.Pool is traced the same way.
+func poolRoundtripPointer(db *gorm.DB, pool *sync.Pool) {
+	q := db.Where("x = ?", 1)
+	pool.Put(q)
+	q2 := pool.Get().(*gorm.DB)
+	q2.Find(nil)
+	q2.Count(nil) // want `\*gorm\.DB reused: second branch from mutable root`
+}
+
+// poolRoundtripCommaOk: the comma-ok extraction form keeps tracking.
+func poolRoundtripCommaOk(db *gorm.DB) {
+	var pool sync.Pool
+	q := db.Where("x = ?", 1)
+	pool.Put(q)
+	if q2, ok := pool.Get().(*gorm.DB); ok {
+		q2.Find(nil)
+		q2.Count(nil) // want `\*gorm\.DB reused: second branch from mutable root`
+	}
+}
+
+// poolRoundtripTwoGets: two values got back from one Put are the same chain.
+func poolRoundtripTwoGets(db *gorm.DB) {
+	var pool sync.Pool
+	pool.Put(db.Where("x = ?", 1))
+	pool.Get().(*gorm.DB).Find(nil)
+	pool.Get().(*gorm.DB).Count(nil) // want `\*gorm\.DB reused: second branch from mutable root`
+}
+
+// poolRoundtripOriginalReused: the original is reused after the extracted copy.
+func poolRoundtripOriginalReused(db *gorm.DB) {
+	var pool sync.Pool
+	q := db.Where("x = ?", 1)
+	pool.Put(q)
+	pool.Get().(*gorm.DB).Find(nil)
+	q.Count(nil) // want `\*gorm\.DB reused: second branch from mutable root`
+}
+
+// =============================================================================
+// SHOULD NOT REPORT - sync.Pool round trips
+// =============================================================================
+
+// poolRoundtripSession: the pooled value is immutable (Session) - no report.
+func poolRoundtripSession(db *gorm.DB) {
+	var pool sync.Pool
+	q := db.Where("x = ?", 1).Session(&gorm.Session{})
+	pool.Put(q)
+	q2 := pool.Get().(*gorm.DB)
+	q2.Find(nil)
+	q2.Count(nil) // OK: extracted value is immutable
+}
+
+// poolRoundtripSingleUse: the extracted value is finished once.
+func poolRoundtripSingleUse(db *gorm.DB) {
+	var pool sync.Pool
+	pool.Put(db.Where("x = ?", 1))
+	pool.Get().(*gorm.DB).Find(nil)
+}
+
+// poolRoundtripOtherPool: Get from a different pool is not tied to the Put.
+func poolRoundtripOtherPool(db *gorm.DB) {
+	var a, b sync.Pool
+	b.Put(db.Session(&gorm.Session{}))
+	a.Put(db.Where("x = ?", 1))
+	b.Get().(*gorm.DB).Find(nil)
+	b.Get().(*gorm.DB).Count(nil) // OK: b holds an immutable Session
+}
@@ -0,0 +1,103 @@
+package internal
+
+import "gorm.io/gorm"
+
+// pureIdentity returns its argument unchanged.
+//
+//gormreuse:pure
+func pureIdentity(q *gorm.DB) *gorm.DB {
+	return q
+}
+
+// pureIdentityLabeled returns its first argument unchanged on every path.
+//
+//gormreuse:pure
+func pureIdentityLabeled(q *gorm.DB, label string) *gorm.DB {
+	if label == "" {
+		return q
+	}
+	println(label)
+	return q
+}
+
+// pureSession returns an immutable copy, not its argument.
+//
+//gormreuse:pure
+func pureSession(q *gorm.DB) *gorm.DB {
+	return q.Session(&gorm.Session{})
+}
+
+// pureIdentityOrNil returns its argument on one path only.
+//
+//gormreuse:pure
+func pureIdentityOrNil(q *gorm.DB, ok bool) *gorm.DB {
+	if ok {
+		return q
+	}
+	return nil
+}
+
+// =============================================================================
+// SHOULD REPORT - identity-return pure helper aliases its argument
+// The result of a pure function returning its argument unchanged shares the
+// argument's root, so finishing both is a double finish.
+// =============================================================================
+
+// pureIdentityAliasFirst finishes the returned alias, then the original.
+func pureIdentityAliasFirst(db *gorm.DB) {
+	q := db.Where("x = ?", 1)
+	r := pureIdentity(q)
+	r.Find(nil)
+	q.Count(nil) // want `\*gorm\.DB reused: second branch from mutable root`
+}
+
+// pureIdentityOriginalFirst finishes the original, then the returned alias.
+func pureIdentityOriginalFirst(db *gorm.DB) {
+	q := db.Where("x = ?", 1)
+	r := pureIdentity(q)
+	q.Find(nil)
+	r.Count(nil) // want `\*gorm\.DB reused: second branch from mutable root`
+}
+
+// pureIdentityAliasChained branches twice from the alias.
+func pureIdentityAliasChained(db *gorm.DB) {
+	q := db.Where("x = ?", 1)
+	q.Find(nil)
+	pureIdentityLabeled(q, "again").Where("y = ?", 2).Find(nil) // want `\*gorm\.DB reused: second branch from mutable root`
+}
+
+// pureIdentityAliasStored keeps the alias in a variable captured by a closure.
+func pureIdentityAliasStored(db *gorm.DB) {
+	q := db.Where("x = ?", 1)
+	var r *gorm.DB
+	func() { r = pureIdentity(q) }()
+	r.Find(nil)
+	q.Count(nil) // want `\*gorm\.DB reused: second branch from mutable root`
+}
+
+// =============================================================================
+// SHOULD NOT REPORT - results that do not alias the argument
+// =============================================================================
+
+// pureIdentitySingleUse finishes only the alias.
+func pureIdentitySingleUse(db *gorm.DB) {
+	q := db.Where("x = ?", 1)
+	r := pureIdentity(q)
+	r.Find(nil)
+}
+
+// pureSessionResult returns a new immutable value.
+func pureSessionResult(db *gorm.DB) {
+	q := db.Where("x = ?", 1)
+	r := pureSession(q)
+	r.Find(nil)
+	q.Count(nil) // OK: r is not q
+}
+
+// pureIdentityOrNilResult may return nil, so its result is its own root.
+func pureIdentityOrNilResult(db *gorm.DB) {
+	q := db.Where("x = ?", 1)
+	r := pureIdentityOrNil(q, true)
+	r.Find(nil)
+	q.Count(nil) // OK: result not proven to alias q
+}
@@ -0,0 +1,120 @@
+package internal
+
+import (
+	"gorm.io/gorm"
+)
+
+// =============================================================================
+// Pure Interface Methods and Function-Typed Fields
+//
+// //gormreuse:pure on an interface method or a function-typed struct field
+// exempts calls through it from polluting their *gorm.DB arguments. The call
+// is dynamic, so the directive is an assertion about every implementation,
+// which the analysis does not verify.
+// =============================================================================
+
+// QueryBuilder builds queries.
+type QueryBuilder interface {
+	// Build only adds conditions.
+	//
+	//gormreuse:pure
+	Build(db *gorm.DB) *gorm.DB
+
+	Scope(db *gorm.DB) *gorm.DB //gormreuse:pure
+
+	// Run is not marked, so it pollutes.
+	Run(db *gorm.DB)
+}
+
+// ExtendedBuilder embeds QueryBuilder; its methods keep their directives.
+type ExtendedBuilder interface {
+	QueryBuilder
+	Extra(db *gorm.DB)
+}
+
+// memberHooks holds query hooks.
+type memberHooks struct {
+	//gormreuse:pure
+	Filter func(db *gorm.DB) *gorm.DB
+
+	Trace, Log func(db *gorm.DB) //gormreuse:pure
+
+	// Save is not marked, so it pollutes.
+	Save func(db *gorm.DB)
+}
+
+// =============================================================================
+// SHOULD NOT REPORT - Pure members
+// =============================================================================
+
+func pureInterfaceMethod(db *gorm.DB, qb QueryBuilder) {
+	q := db.Where("x = ?", 1)
+	qb.Build(q)
+	q.Find(nil)
+}
+
+func pureInterfaceMethodSameLine(db *gorm.DB, qb QueryBuilder) {
+	q := db.Where("x = ?", 1)
+	qb.Scope(q)
+	q.Find(nil)
+}
+
+func pureEmbeddedInterfaceMethod(db *gorm.DB, eb ExtendedBuilder) {
+	q := db.Where("x = ?", 1)
+	eb.Build(q)
+	q.Find(nil)
+}
+
+func pureFuncField(db *gorm.DB, h *memberHooks) {
+	q := db.Where("x = ?", 1)
+	h.Filter(q)
+	q.Find(nil)
+}
+
+func pureFuncFieldValue(db *gorm.DB, h memberHooks) {
+	q := db.Where("x = ?", 1)
+	h.Trace(q)
+	h.Log(q)
+	q.Find(nil)
+}
+
+// =============================================================================
+// SHOULD REPORT - Unmarked members
+// =============================================================================
+
+func impureInterfaceMethod(db *gorm.DB, qb QueryBuilder) {
+	q := db.Where("x = ?", 1)
+	qb.Run(q)
+	q.Find(nil) // want `\*gorm\.DB reused: second branch from mutable root`
+}
+
+func impureEmbeddingInterfaceMethod(db *gorm.DB, eb ExtendedBuilder) {
+	q := db.Where("x = ?", 1)
+	eb.Extra(q)
+	q.Find(nil) // want `\*gorm\.DB reused: second branch from mutable root`
+}
+
+func impureFuncField(db *gorm.DB, h *memberHooks) {
+	q := db.Where("x = ?", 1)
+	h.Save(q)
+	q.Find(nil) // want `\*gorm\.DB reused: second branch from mutable root`
+}
+
+// pureMemberMethodValue calls a pure interface method through a method
+// value, which is not a call through the interface.
+func pureMemberMethodValue(db *gorm.DB, qb QueryBuilder) {
+	build := qb.Build
+	q := db.Where("x = ?", 1)
+	build(q)
+	q.Find(nil) // want `\*gorm\.DB reused: second branch from mutable root`
+}
+
+// =============================================================================
+// SHOULD REPORT - Unused directives on members
+// =============================================================================
+
+// memberNoDB has members without a *gorm.DB parameter.
+type memberNoDB interface {
+	//gormreuse:pure // want `unused gormreuse:pure directive`
+	Name() string
+}
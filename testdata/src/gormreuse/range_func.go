@@ -0,0 +1,151 @@
+package internal
+
+import (
+	"gorm.io/gorm"
+)
+
+// =============================================================================
+// Range-over-func Test Cases
+//
+// A range over an iterator function lowers its body to a synthesized yield
+// closure that the iterator calls once per element. The body has no back-edge
+// of its own, but it is a loop body all the same: a root defined outside it
+// and branched inside it is reused on every call, like in a for loop.
+// =============================================================================
+
+// rangeFuncDBSeq yields a *gorm.DB per element.
+type rangeFuncDBSeq func(yield func(*gorm.DB) bool)
+
+// rangeFuncIntSeq yields an int per element.
+type rangeFuncIntSeq func(yield func(int) bool)
+
+// rangeFuncPairSeq yields an int and a string per element.
+type rangeFuncPairSeq func(yield func(int, string) bool)
+
+// rangeFuncQueries yields a fresh chain from an immutable base per element.
+func rangeFuncQueries(db *gorm.DB, ids []int) rangeFuncDBSeq {
+	base := db.Session(&gorm.Session{})
+	return func(yield func(*gorm.DB) bool) {
+		for _, id := range ids {
+			if !yield(base.Where("id = ?", id)) {
+				return
+			}
+		}
+	}
+}
+
+// =============================================================================
+// SHOULD REPORT - External root branched in the range-over-func body
+// =============================================================================
+
+// rangeFuncExternalRoot branches an outer root on every element.
+func rangeFuncExternalRoot(db *gorm.DB, seq rangeFuncIntSeq) {
+	base := db.Where("x = ?", 1)
+	for id := range seq {
+		base.Where("id = ?", id).Find(nil) // want `\*gorm\.DB reused: second branch from mutable root`
+	}
+}
+
+// rangeFuncExternalRootPair ranges with two loop variables.
+func rangeFuncExternalRootPair(db *gorm.DB, seq rangeFuncPairSeq) {
+	base := db.Where("x = ?", 1)
+	for id, name := range seq {
+		base.Where("id = ? AND name = ?", id, name).Find(nil) // want `\*gorm\.DB reused: second branch from mutable root`
+	}
+}
+
+// rangeFuncExternalRootNoVars ranges without loop variables.
+func rangeFuncExternalRootNoVars(db *gorm.DB, seq rangeFuncIntSeq) {
+	base := db.Where("x = ?", 1)
+	for range seq {
+		base.Find(nil) // want `\*gorm\.DB reused: second branch from mutable root`
+	}
+}
+
+// rangeFuncExternalRootWithYielded uses the yielded value once, but also an
+// outer root.
+func rangeFuncExternalRootWithYielded(db *gorm.DB, ids []int) {
+	base := db.Where("x = ?", 1)
+	for q := range rangeFuncQueries(db, ids) {
+		q.Find(nil)
+		base.Count(nil) // want `\*gorm\.DB reused: second branch from mutable root`
+	}
+}
+
+// rangeFuncYieldedTwice uses the yielded value twice in one iteration.
+func rangeFuncYieldedTwice(db *gorm.DB, ids []int) {
+	for q := range rangeFuncQueries(db, ids) {
+		q.Find(nil)  // First use
+		q.Count(nil) // want `\*gorm\.DB reused: second branch from mutable root`
+	}
+}
+
+// rangeFuncGo spawns a goroutine on an outer root on every element.
+func rangeFuncGo(db *gorm.DB, seq rangeFuncIntSeq) {
+	base := db.Where("x = ?", 1)
+	for range seq {
+		go base.Find(nil) // want `\*gorm\.DB reused: second branch from mutable root`
+	}
+}
+
+// rangeFuncNested branches a root of the outer body in the inner body.
+func rangeFuncNested(db *gorm.DB, seq rangeFuncIntSeq) {
+	base := db.Session(&gorm.Session{})
+	for range seq {
+		q := base.Where("x = ?", 1)
+		for id := range seq {
+			q.Where("id = ?", id).Find(nil) // want `\*gorm\.DB reused: second branch from mutable root`
+		}
+	}
+}
+
+// =============================================================================
+// SHOULD NOT REPORT - Fresh per element, immutable, or at most once
+// =============================================================================
+
+// rangeFuncYieldedOnce uses each yielded value once.
+func rangeFuncYieldedOnce(db *gorm.DB, ids []int) {
+	for q := range rangeFuncQueries(db, ids) {
+		q.Find(nil)
+	}
+}
+
+// rangeFuncSession ranges with an immutable base.
+func rangeFuncSession(db *gorm.DB, seq rangeFuncIntSeq) {
+	base := db.Where("x = ?", 1).Session(&gorm.Session{})
+	for id := range seq {
+		base.Where("id = ?", id).Find(nil)
+	}
+}
+
+// rangeFuncFreshRoot defines the root inside the body.
+func rangeFuncFreshRoot(db *gorm.DB, seq rangeFuncIntSeq) {
+	base := db.Session(&gorm.Session{})
+	for id := range seq {
+		q := base.Where("x = ?", 1)
+		q.Where("id = ?", id).Find(nil)
+	}
+}
+
+// rangeFuncBreak uses the outer root only on the path that breaks out, so at
+// most once.
+func rangeFuncBreak(db *gorm.DB, seq rangeFuncIntSeq) {
+	base := db.Where("x = ?", 1)
+	for id := range seq {
+		if id > 10 {
+			base.Where("id = ?", id).Find(nil)
+			break
+		}
+	}
+}
+
+// rangeFuncReturn uses the outer root only on the path that returns.
+func rangeFuncReturn(db *gorm.DB, seq rangeFuncIntSeq) error {
+	base := db.Where("x = ?", 1)
+	for id := range seq {
+		if id > 10 {
+			return base.Where("id = ?", id).Find(nil).Error
+		}
+	}
+	return nil
+}
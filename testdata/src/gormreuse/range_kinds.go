@@ -0,0 +1,126 @@
+package internal
+
+import (
+	"gorm.io/gorm"
+)
+
+// =============================================================================
+// Range Kinds Test Cases
+//
+// Every range source kind lowers to the same loop shape in SSA, so a root
+// defined outside the loop and branched inside it is reported whatever is
+// ranged over. Each kind below has the same body: base.Where(...).Find(nil).
+// =============================================================================
+
+// =============================================================================
+// SHOULD REPORT - External root branched in the loop body
+// =============================================================================
+
+// rangeKindString ranges over the runes of a string.
+func rangeKindString(db *gorm.DB, s string) {
+	base := db.Where("x = ?", 1)
+	for _, c := range s {
+		base.Where("c = ?", c).Find(nil) // want `\*gorm\.DB reused: second branch from mutable root`
+	}
+}
+
+// rangeKindStringConst ranges over a string constant.
+func rangeKindStringConst(db *gorm.DB) {
+	base := db.Where("x = ?", 1)
+	for _, c := range "abc" {
+		base.Where("c = ?", c).Find(nil) // want `\*gorm\.DB reused: second branch from mutable root`
+	}
+}
+
+// rangeKindBytes ranges over a byte slice.
+func rangeKindBytes(db *gorm.DB, b []byte) {
+	base := db.Where("x = ?", 1)
+	for _, c := range b {
+		base.Where("c = ?", c).Find(nil) // want `\*gorm\.DB reused: second branch from mutable root`
+	}
+}
+
+// rangeKindSlice ranges over a slice.
+func rangeKindSlice(db *gorm.DB, ids []int) {
+	base := db.Where("x = ?", 1)
+	for _, id := range ids {
+		base.Where("id = ?", id).Find(nil) // want `\*gorm\.DB reused: second branch from mutable root`
+	}
+}
+
+// rangeKindArray ranges over an array value.
+func rangeKindArray(db *gorm.DB, ids [3]int) {
+	base := db.Where("x = ?", 1)
+	for _, id := range ids {
+		base.Where("id = ?", id).Find(nil) // want `\*gorm\.DB reused: second branch from mutable root`
+	}
+}
+
+// rangeKindArrayPointer ranges over a pointer to an array.
+func rangeKindArrayPointer(db *gorm.DB, ids *[3]int) {
+	base := db.Where("x = ?", 1)
+	for i := range ids {
+		base.Where("id = ?", i).Find(nil) // want `\*gorm\.DB reused: second branch from mutable root`
+	}
+}
+
+// rangeKindMap ranges over a map.
+func rangeKindMap(db *gorm.DB, names map[string]int) {
+	base := db.Where("x = ?", 1)
+	for name := range names {
+		base.Where("name = ?", name).Find(nil) // want `\*gorm\.DB reused: second branch from mutable root`
+	}
+}
+
+// rangeKindChannel ranges over a channel.
+func rangeKindChannel(db *gorm.DB, ids chan int) {
+	base := db.Where("x = ?", 1)
+	for id := range ids {
+		base.Where("id = ?", id).Find(nil) // want `\*gorm\.DB reused: second branch from mutable root`
+	}
+}
+
+// rangeKindInt ranges over an integer.
+func rangeKindInt(db *gorm.DB, n int) {
+	base := db.Where("x = ?", 1)
+	for i := range n {
+		base.Where("i = ?", i).Find(nil) // want `\*gorm\.DB reused: second branch from mutable root`
+	}
+}
+
+// rangeKindNoVars ranges over a string without loop variables.
+func rangeKindNoVars(db *gorm.DB, s string) {
+	base := db.Where("x = ?", 1)
+	for range s {
+		base.Find(nil) // want `\*gorm\.DB reused: second branch from mutable root`
+	}
+}
+
+// =============================================================================
+// SHOULD NOT REPORT - Root defined per iteration or immutable
+// =============================================================================
+
+// rangeKindStringFreshRoot defines the root inside the loop.
+func rangeKindStringFreshRoot(db *gorm.DB, s string) {
+	base := db.Session(&gorm.Session{})
+	for _, c := range s {
+		q := base.Where("x = ?", 1)
+		q.Where("c = ?", c).Find(nil)
+	}
+}
+
+// rangeKindMapSession ranges with an immutable base.
+func rangeKindMapSession(db *gorm.DB, names map[string]int) {
+	base := db.Where("x = ?", 1).Session(&gorm.Session{})
+	for name := range names {
+		base.Where("name = ?", name).Find(nil)
+	}
+}
+
+// rangeKindChannelSession ranges over a channel with an immutable base.
+func rangeKindChannelSession(db *gorm.DB, ids chan int) {
+	base := db.Where("x = ?", 1).Session(&gorm.Session{})
+	for id := range ids {
+		base.Where("id = ?", id).Find(nil)
+	}
+}
@@ -0,0 +1,138 @@
+package internal
+
+import (
+	"gorm.io/gorm"
+)
+
+// =============================================================================
+// Recover Closure Test Cases
+//
+// A deferred closure recovering from a panic is analyzed like any function:
+// two uses of a captured *gorm.DB inside the recover block are a reuse on
+// their own, reported at the second use even without a use outside it.
+// =============================================================================
+
+// =============================================================================
+// SHOULD REPORT - Captured root used twice in the recover block
+// =============================================================================
+
+// recoverTwoFinishers finishes the captured root twice.
+func recoverTwoFinishers(db *gorm.DB) {
+	q := db.Session(&gorm.Session{}).Where("x")
+	defer func() {
+		if recover() != nil {
+			q.Find(nil)
+			q.Count(nil) // want `\*gorm\.DB reused: second branch from mutable root`
+		}
+	}()
+}
+
+// recoverTwoChains branches the captured root twice.
+func recoverTwoChains(db *gorm.DB) {
+	q := db.Session(&gorm.Session{}).Where("x")
+	defer func() {
+		if r := recover(); r != nil {
+			q.Where("a").Find(nil)
+			q.Where("b").Find(nil) // want `\*gorm\.DB reused: second branch from mutable root`
+		}
+	}()
+}
+
+// recoverNamedResult assigns the error of the first use to a named result.
+func recoverNamedResult(db *gorm.DB) (err error) {
+	q := db.Session(&gorm.Session{}).Where("x")
+	defer func() {
+		if r := recover(); r != nil {
+			err = q.Find(nil).Error
+			q.Count(nil) // want `\*gorm\.DB reused: second branch from mutable root`
+		}
+	}()
+	return nil
+}
+
+// recoverUseBeforeCheck uses the root before and after checking recover.
+func recoverUseBeforeCheck(db *gorm.DB) {
+	q := db.Session(&gorm.Session{}).Where("x")
+	defer func() {
+		r := recover()
+		q.Find(nil)
+		if r != nil {
+			q.Count(nil) // want `\*gorm\.DB reused: second branch from mutable root`
+		}
+	}()
+}
+
+// recoverLoop uses the root in a loop in the recover block.
+func recoverLoop(db *gorm.DB, ids []int) {
+	q := db.Session(&gorm.Session{}).Where("x")
+	defer func() {
+		if recover() != nil {
+			for range ids {
+				q.Find(nil) // want `\*gorm\.DB reused: second branch from mutable root`
+			}
+		}
+	}()
+}
+
+// recoverLocalRoot defines the root in the recover block.
+func recoverLocalRoot(db *gorm.DB) {
+	defer func() {
+		if recover() != nil {
+			q := db.Session(&gorm.Session{}).Where("x")
+			q.Find(nil)
+			q.Count(nil) // want `\*gorm\.DB reused: second branch from mutable root`
+		}
+	}()
+}
+
+// recoverHelper recovers in a deferred function receiving the root.
+func recoverHelper(db *gorm.DB) {
+	q := db.Session(&gorm.Session{}).Where("x")
+	defer recoverHelperHandle(q)
+}
+
+func recoverHelperHandle(q *gorm.DB) {
+	if recover() != nil {
+		q.Find(nil)
+		q.Count(nil) // want `\*gorm\.DB reused: second branch from mutable root`
+	}
+}
+
+// =============================================================================
+// SHOULD NOT REPORT - One use per path in the recover block
+// =============================================================================
+
+// recoverSingleUse uses the captured root once.
+func recoverSingleUse(db *gorm.DB) {
+	q := db.Session(&gorm.Session{}).Where("x")
+	defer func() {
+		if recover() != nil {
+			q.Find(nil)
+		}
+	}()
+}
+
+// recoverExclusiveUses uses the captured root in exclusive branches.
+func recoverExclusiveUses(db *gorm.DB, flag bool) {
+	q := db.Session(&gorm.Session{}).Where("x")
+	defer func() {
+		if recover() != nil {
+			if flag {
+				q.Find(nil)
+			} else {
+				q.Count(nil)
+			}
+		}
+	}()
+}
+
+// recoverImmutableRoot uses an immutable root twice.
+func recoverImmutableRoot(db *gorm.DB) {
+	q := db.Where("x").Session(&gorm.Session{})
+	defer func() {
+		if recover() != nil {
+			q.Where("a").Find(nil)
+			q.Where("b").Find(nil)
+		}
+	}()
+}
@@ -0,0 +1,85 @@
+package internal
+
+import (
+	"reflect"
+
+	"gorm.io/gorm"
+)
+
+// =============================================================================
+// SHOULD REPORT - closures invoked through reflect
+// Reflection is not traced: a closure capturing a mutable *gorm.DB that is
+// passed to a reflect function is assumed to be invoked there, so its captured
+// roots are polluted at that call.
+// =============================================================================
+
+// reflectCallAfterFinish: the root is finished, then reused by a closure that
+// reflect invokes.
+func reflectCallAfterFinish(db *gorm.DB) {
+	q := db.Where("x = ?", 1)
+	q.Find(nil)
+	f := func() { q.Count(nil) }
+	reflect.ValueOf(f).Call(nil) // want `\*gorm\.DB reused: second branch from mutable root`
+}
+
+// reflectCallReturnsRoot: the closure hands the root to the caller of Call,
+// which may branch it, so a later finish is a reuse.
+func reflectCallReturnsRoot(db *gorm.DB) {
+	q := db.Where("x = ?", 1)
+	f := func() *gorm.DB { return q }
+	reflect.ValueOf(f).Call(nil)
+	q.Find(nil) // want `\*gorm\.DB reused: second branch from mutable root`
+}
+
+// reflectCallDefinedEarly: the closure body is ordered at the reflect call, not
+// where it is written, so the reuse is reported there.
+func reflectCallDefinedEarly(db *gorm.DB) {
+	q := db.Where("x = ?", 1)
+	f := func() { q.Find(nil) }
+	q.Count(nil)
+	reflect.ValueOf(f).Call(nil) // want `\*gorm\.DB reused: second branch from mutable root`
+}
+
+// reflectCallInline: a closure literal passed inline is polluting too.
+func reflectCallInline(db *gorm.DB) {
+	q := db.Where("x = ?", 1)
+	reflect.ValueOf(func() *gorm.DB { return q }).Call(nil)
+	q.Count(nil) // want `\*gorm\.DB reused: second branch from mutable root`
+}
+
+// reflectCallCapturedVariable: a variable reassigned inside the closure is
+// captured by reference and traced to its stored value.
+func reflectCallCapturedVariable(db *gorm.DB) {
+	q := db.Where("x = ?", 1)
+	f := func() { q = q.Where("y = ?", 2) }
+	reflect.ValueOf(f).Call(nil)
+	q.Find(nil) // want `\*gorm\.DB reused: second branch from mutable root`
+}
+
+// =============================================================================
+// SHOULD NOT REPORT - closures invoked through reflect
+// =============================================================================
+
+// reflectCallSingleUse: the reflect invocation is the only branch.
+func reflectCallSingleUse(db *gorm.DB) {
+	q := db.Where("x = ?", 1)
+	f := func() { q.Find(nil) }
+	reflect.ValueOf(f).Call(nil)
+}
+
+// reflectCallImmutable: the captured value is immutable (Session).
+func reflectCallImmutable(db *gorm.DB) {
+	q := db.Where("x = ?", 1).Session(&gorm.Session{})
+	f := func() *gorm.DB { return q }
+	reflect.ValueOf(f).Call(nil)
+	q.Find(nil) // OK: q is immutable
+}
+
+// reflectCallNoCapture: a closure capturing no *gorm.DB pollutes nothing.
+func reflectCallNoCapture(db *gorm.DB) {
+	q := db.Where("x = ?", 1)
+	n := 0
+	f := func() { n++ }
+	reflect.ValueOf(f).Call(nil)
+	q.Find(nil) // OK: first branch
+}
@@ -0,0 +1,109 @@
+package internal
+
+import "gorm.io/gorm"
+
+// =============================================================================
+// Channel Receive Test Cases
+//
+// A *gorm.DB received from a channel, by a select case or a plain receive, is
+// an immutable source like a parameter: the sender's chain state is unknown,
+// so branching it twice is not reported. A chain started from it is a mutable
+// root as usual.
+// =============================================================================
+
+type dbMailbox struct {
+	ch chan *gorm.DB
+}
+
+// =============================================================================
+// SHOULD REPORT - Chains started from a received value
+// =============================================================================
+
+// selectReceiveThenWhere reuses a chain started from a select-case value.
+func selectReceiveThenWhere(ch chan *gorm.DB, done chan struct{}) {
+	select {
+	case db := <-ch:
+		q := db.Where("x = ?", 1)
+		q.Find(nil)  // First use
+		q.Count(nil) // want `\*gorm\.DB reused: second branch from mutable root`
+	case <-done:
+	}
+}
+
+// commaOkReceiveThenWhere reuses a chain started from a comma-ok receive.
+func commaOkReceiveThenWhere(ch chan *gorm.DB) {
+	db, ok := <-ch
+	if !ok {
+		return
+	}
+	q := db.Where("x = ?", 1)
+	q.Find(nil)  // First use
+	q.Count(nil) // want `\*gorm\.DB reused: second branch from mutable root`
+}
+
+// =============================================================================
+// SHOULD NOT REPORT - Received values are immutable sources
+// =============================================================================
+
+// selectReceiveTwice finishes a select-case value twice.
+func selectReceiveTwice(ch chan *gorm.DB, done chan struct{}) {
+	select {
+	case db := <-ch:
+		db.Find(nil)
+		db.Count(nil) // OK: received value
+	case <-done:
+	}
+}
+
+// selectCommaOkReceiveTwice finishes a comma-ok select-case value twice.
+func selectCommaOkReceiveTwice(ch chan *gorm.DB) {
+	select {
+	case db, ok := <-ch:
+		if !ok {
+			return
+		}
+		db.Find(nil)
+		db.Count(nil) // OK: received value
+	default:
+	}
+}
+
+// receiveTwice finishes a plainly received value twice.
+func receiveTwice(ch chan *gorm.DB) {
+	db := <-ch
+	db.Find(nil)
+	db.Count(nil) // OK: received value
+}
+
+// selectReceiveFromField finishes a value received from a struct field's
+// channel twice.
+func selectReceiveFromField(m *dbMailbox) {
+	select {
+	case db := <-m.ch:
+		db.Find(nil)
+		db.Count(nil) // OK: received value
+	}
+}
+
+// selectReceiveInLoop finishes the value of each iteration once.
+func selectReceiveInLoop(ch chan *gorm.DB, done chan struct{}) {
+	for {
+		select {
+		case db := <-ch:
+			db.Find(nil) // OK: a new value each iteration
+		case <-done:
+			return
+		}
+	}
+}
+
+// selectReceiveInClosure finishes a select-case value twice in a closure.
+func selectReceiveInClosure(ch chan *gorm.DB) {
+	func() {
+		select {
+		case db := <-ch:
+			db.Find(nil)
+			db.Count(nil) // OK: received value
+		}
+	}()
+}
@@ -0,0 +1,73 @@
+package internal
+
+import (
+	"gorm.io/gorm"
+)
+
+// =============================================================================
+// Session Per Finisher Fix Test Cases
+//
+// When every use of a root is a statement-level finisher called directly on
+// its variable, a fix isolating each use is offered as an alternative:
+// q.Count(nil) becomes q.Session(&gorm.Session{}).Count(nil). All uses are
+// rewritten, as the first one pollutes the root too; uses that already call
+// Session are left as is.
+// =============================================================================
+
+// =============================================================================
+// SHOULD REPORT - Fix offered
+// =============================================================================
+
+// sessionPerFinisherTwo finishes the root twice.
+func sessionPerFinisherTwo(db *gorm.DB) {
+	q := db.Where("x = ?", 1)
+	q.Find(nil)  // First use
+	q.Count(nil) // want `\*gorm\.DB reused: second branch from mutable root`
+}
+
+// sessionPerFinisherMixed already isolates its last use.
+func sessionPerFinisherMixed(db *gorm.DB) {
+	q := db.Where("x = ?", 1)
+	q.First(nil)                         // First use
+	q.Count(nil)                         // want `\*gorm\.DB reused: second branch from mutable root`
+	q.Session(&gorm.Session{}).Find(nil) // want `\*gorm\.DB reused: Session on an already-polluted value`
+}
+
+// sessionPerFinisherInLoop finishes an outer root on every iteration.
+func sessionPerFinisherInLoop(db *gorm.DB, ids []int) {
+	q := db.Where("x = ?", 1)
+	for _, id := range ids {
+		q.Find(&id) // want `\*gorm\.DB reused: second branch from mutable root`
+	}
+}
+
+// =============================================================================
+// SHOULD REPORT - Fix withheld
+// =============================================================================
+
+// sessionPerFinisherChain uses the root in a chain, which is not a direct
+// finisher call.
+func sessionPerFinisherChain(db *gorm.DB) {
+	q := db.Where("x = ?", 1)
+	q.Where("a = ?", 1).Find(nil) // First use
+	q.Count(nil)                  // want `\*gorm\.DB reused: second branch from mutable root`
+}
+
+// sessionPerFinisherAssigned uses a finisher result, which is not a statement-
+// level call.
+func sessionPerFinisherAssigned(db *gorm.DB) error {
+	q := db.Where("x = ?", 1)
+	q.Find(nil)               // First use
+	return q.Count(nil).Error // want `\*gorm\.DB reused: second branch from mutable root`
+}
+
+// =============================================================================
+// SHOULD NOT REPORT - Fix applied
+// =============================================================================
+
+// sessionPerFinisherApplied is sessionPerFinisherTwo with the fix applied.
+func sessionPerFinisherApplied(db *gorm.DB) {
+	q := db.Where("x = ?", 1)
+	q.Session(&gorm.Session{}).Find(nil)
+	q.Session(&gorm.Session{}).Count(nil)
+}
@@ -0,0 +1,74 @@
+package internal
+
+import (
+	"gorm.io/gorm"
+)
+
+// =============================================================================
+// Session Root Fix Test Cases
+//
+// Besides the reassignment fix, a loop use or a finisher reuse is offered a
+// second fix that makes the root immutable where it is defined:
+// q := db.Where("x").Session(&gorm.Session{}). It only appears when it differs
+// from the primary fix, so the golden file has one section per fix message.
+// =============================================================================
+
+// =============================================================================
+// SHOULD REPORT - Alternative fix offered
+// =============================================================================
+
+// sessionRootFixLoopChain narrows the outer root inside the loop. Reassignment
+// would accumulate conditions across iterations.
+func sessionRootFixLoopChain(db *gorm.DB, ids []int) {
+	q := db.Where("x = ?", 1)
+	for _, id := range ids {
+		q.Where("id = ?", id) // want `\*gorm\.DB reused: second branch from mutable root`
+	}
+}
+
+// sessionRootFixFinisher finishes the root after a non-finisher branch.
+func sessionRootFixFinisher(db *gorm.DB) {
+	q := db.Where("x = ?", 1)
+	q.Where("a = ?", 1) // First use
+	q.Find(nil)         // want `\*gorm\.DB reused: second branch from mutable root`
+}
+
+// sessionRootFixChainedFinisher finishes a chain on the root after a branch.
+func sessionRootFixChainedFinisher(db *gorm.DB) {
+	q := db.Where("x = ?", 1)
+	q.Order("id")                 // First use
+	q.Where("b = ?", 2).Find(nil) // want `\*gorm\.DB reused: second branch from mutable root`
+}
+
+// =============================================================================
+// SHOULD REPORT - Alternative identical to the primary fix, not offered
+// =============================================================================
+
+// sessionRootFixLoopFinisher finishes the outer root in the loop, which the
+// primary fix already solves with Session at the root.
+func sessionRootFixLoopFinisher(db *gorm.DB, ids []int) {
+	q := db.Where("x = ?", 1)
+	for _, id := range ids {
+		q.Find(&id) // want `\*gorm\.DB reused: second branch from mutable root`
+	}
+}
+
+// sessionRootFixNonFinisher branches twice without a finisher or a loop, so
+// only the reassignment fix applies.
+func sessionRootFixNonFinisher(db *gorm.DB) {
+	q := db.Where("x = ?", 1)
+	q.Where("a = ?", 1) // First use
+	q.Where("b = ?", 2) // want `\*gorm\.DB reused: second branch from mutable root`
+}
+
+// =============================================================================
+// SHOULD NOT REPORT - Root already immutable
+// =============================================================================
+
+// sessionRootFixApplied is sessionRootFixLoopChain with the alternative fix.
+func sessionRootFixApplied(db *gorm.DB, ids []int) {
+	q := db.Where("x = ?", 1).Session(&gorm.Session{})
+	for _, id := range ids {
+		q.Where("id = ?", id)
+	}
+}
@@ -0,0 +1,128 @@
+package internal
+
+import (
+	"gorm.io/gorm"
+)
+
+// =============================================================================
+// Sink Test Cases
+//
+// //gormreuse:sink marks a helper as intentionally consuming its *gorm.DB
+// argument. For pollution it behaves exactly like an unannotated helper: the
+// argument is polluted, even when the helper is also marked pure. The directive
+// requires a *gorm.DB parameter and is reported unused otherwise.
+// =============================================================================
+
+// logQuery only reads metadata but is declared as consuming its argument.
+//
+//gormreuse:sink
+func logQuery(db *gorm.DB) {
+	_ = db.Error
+}
+
+// logQuerySameLine is a sink declared with a same-line directive.
+func logQuerySameLine(db *gorm.DB) { //gormreuse:sink
+	_ = db.Error
+}
+
+// sinkRepo wraps logging helpers.
+type sinkRepo struct{}
+
+// Log is a sink method on a wrapper type.
+//
+//gormreuse:sink
+func (sinkRepo) Log(db *gorm.DB) {
+	_ = db.Error
+}
+
+// pureSink is marked both pure and sink: sink wins at call sites.
+//
+//gormreuse:pure,sink
+func pureSink(db *gorm.DB) {
+	_ = db.Error
+}
+
+// unannotatedLog behaves like the sinks above without declaring it.
+func unannotatedLog(db *gorm.DB) {
+	_ = db.Error
+}
+
+// sinkWithoutDB has no *gorm.DB parameter to consume.
+//
+//gormreuse:sink // want `unused gormreuse:sink directive: no \*gorm\.DB parameter`
+func sinkWithoutDB(msg string) {
+	_ = msg
+}
+
+// sinkImmutableReturn has no *gorm.DB parameter, but its immutable-return part
+// is used, so the combined directive is not reported.
+//
+//gormreuse:sink,immutable-return
+func sinkImmutableReturn() *gorm.DB {
+	db, _ := gorm.Open(nil)
+	return db.Session(&gorm.Session{})
+}
+
+// =============================================================================
+// SHOULD REPORT - Reuse after a sink
+// =============================================================================
+
+// sinkThenReuse pollutes q through a sink.
+func sinkThenReuse(db *gorm.DB) {
+	q := db.Where("x = ?", 1)
+	logQuery(q) // First use (sink)
+	q.Find(nil) // want `\*gorm\.DB reused: second branch from mutable root`
+}
+
+// sinkSameLineThenReuse uses a sink marked with a same-line directive.
+func sinkSameLineThenReuse(db *gorm.DB) {
+	q := db.Where("x = ?", 1)
+	logQuerySameLine(q) // First use (sink)
+	q.Count(nil)        // want `\*gorm\.DB reused: second branch from mutable root`
+}
+
+// sinkMethodThenReuse uses a sink method on a wrapper type.
+func sinkMethodThenReuse(db *gorm.DB) {
+	var repo sinkRepo
+	q := db.Where("x = ?", 1)
+	repo.Log(q) // First use (sink)
+	q.Find(nil) // want `\*gorm\.DB reused: second branch from mutable root`
+}
+
+// pureSinkThenReuse pollutes q although the sink is also marked pure.
+func pureSinkThenReuse(db *gorm.DB) {
+	q := db.Where("x = ?", 1)
+	pureSink(q) // First use (sink overrides pure)
+	q.Find(nil) // want `\*gorm\.DB reused: second branch from mutable root`
+}
+
+// unannotatedThenReuse shows the identical behavior without the directive.
+func unannotatedThenReuse(db *gorm.DB) {
+	q := db.Where("x = ?", 1)
+	unannotatedLog(q) // First use (unannotated helper)
+	q.Find(nil)       // want `\*gorm\.DB reused: second branch from mutable root`
+}
+
+// =============================================================================
+// SHOULD NOT REPORT - Single use or immutable argument
+// =============================================================================
+
+// sinkOnly consumes q once.
+func sinkOnly(db *gorm.DB) {
+	q := db.Where("x = ?", 1)
+	logQuery(q)
+}
+
+// sinkOfSession consumes an immutable value, which stays reusable.
+func sinkOfSession(db *gorm.DB) {
+	q := db.Where("x = ?", 1).Session(&gorm.Session{})
+	logQuery(q)
+	q.Find(nil)
+	q.Count(nil)
+}
+
+// sinkFirstUseAfterFinish logs a fresh chain derived from q's finisher result.
+func sinkFirstUseAfterFinish(db *gorm.DB) {
+	q := db.Where("x = ?", 1)
+	logQuery(q.Find(nil))
+}
@@ -0,0 +1,97 @@
+package internal
+
+import (
+	"gorm.io/gorm"
+)
+
+// =============================================================================
+// Slice Index Test Cases
+//
+// A local slice or array written with constant indices and only read back by
+// indexing is just another name for its elements, like a local constant-key
+// map: s[0] is traced to the *gorm.DB stored at index 0, and the store is an
+// assignment rather than a use. A variable-index read may see any element.
+// Any other use of the slice keeps the "storing in a slice pollutes" rule.
+// =============================================================================
+
+func consumeSliceDBs([]*gorm.DB) {}
+
+// =============================================================================
+// SHOULD REPORT - Element read back and reused
+// =============================================================================
+
+// sliceIndexLiteralReuse reuses the element of a slice literal.
+func sliceIndexLiteralReuse(db *gorm.DB) {
+	q := db.Where("x = ?", 1)
+	s := []*gorm.DB{q}
+	s[0].Find(nil)  // First use
+	s[0].Count(nil) // want `\*gorm\.DB reused: second branch from mutable root`
+}
+
+// sliceIndexMakeReuse reuses an element stored into a made slice.
+func sliceIndexMakeReuse(db *gorm.DB) {
+	q := db.Where("x = ?", 1)
+	s := make([]*gorm.DB, 2)
+	s[0] = q
+	s[0].Find(nil) // First use
+	q.Count(nil)   // want `\*gorm\.DB reused: second branch from mutable root`
+}
+
+// sliceIndexArrayReuse reuses the element of a local array.
+func sliceIndexArrayReuse(db *gorm.DB) {
+	q := db.Where("x = ?", 1)
+	var arr [2]*gorm.DB
+	arr[1] = q
+	arr[1].Find(nil)  // First use
+	arr[1].Count(nil) // want `\*gorm\.DB reused: second branch from mutable root`
+}
+
+// sliceIndexVariableReuse reads an element with a variable index twice.
+func sliceIndexVariableReuse(db *gorm.DB, i int) {
+	base := db.Session(&gorm.Session{})
+	s := []*gorm.DB{base.Where("a = ?", 1), base.Where("b = ?", 2)}
+	s[i].Find(nil)  // First use
+	s[i].Count(nil) // want `\*gorm\.DB reused: second branch from mutable root`
+}
+
+// sliceIndexInLoop reads an element defined outside a loop on every iteration.
+func sliceIndexInLoop(db *gorm.DB, n int) {
+	s := []*gorm.DB{db.Where("x = ?", 1)}
+	for i := 0; i < n; i++ {
+		s[0].Find(nil) // want `\*gorm\.DB reused: second branch from mutable root`
+	}
+}
+
+// sliceIndexPassed passes the slice on, which pollutes its elements.
+func sliceIndexPassed(db *gorm.DB) {
+	q := db.Where("x = ?", 1)
+	s := []*gorm.DB{q}
+	consumeSliceDBs(s) // First use (q escapes with s)
+	q.Count(nil)       // want `\*gorm\.DB reused: second branch from mutable root`
+}
+
+// =============================================================================
+// SHOULD NOT REPORT - Each element used once
+// =============================================================================
+
+// sliceIndexSingleUse uses the element of a slice literal once.
+func sliceIndexSingleUse(db *gorm.DB) {
+	q := db.Where("x = ?", 1)
+	s := []*gorm.DB{q}
+	s[0].Find(nil)
+}
+
+// sliceIndexDistinct uses distinct elements once each.
+func sliceIndexDistinct(db *gorm.DB) {
+	base := db.Session(&gorm.Session{})
+	s := []*gorm.DB{base.Where("a = ?", 1), base.Where("b = ?", 2)}
+	s[0].Find(nil)
+	s[1].Find(nil)
+}
+
+// sliceIndexImmutable reuses an immutable element.
+func sliceIndexImmutable(db *gorm.DB) {
+	s := []*gorm.DB{db.Where("x = ?", 1).Session(&gorm.Session{})}
+	s[0].Find(nil)
+	s[0].Count(nil)
+}
@@ -0,0 +1,209 @@
+package internal
+
+import (
+	"gorm.io/gorm"
+)
+
+// =============================================================================
+// Struct Field Escape Test Cases
+//
+// Storing a *gorm.DB into a field of a local struct is not a use by itself: a
+// discarded struct, or one whose field is only read back, leaves the root
+// alone (see structFieldPollution in evil.go). Once the struct escapes to a
+// call, an interface, another struct or a map, whoever receives it may branch
+// the field, so the root is polluted at the escape.
+// =============================================================================
+
+type escapeHolder struct {
+	db *gorm.DB
+}
+
+func (h *escapeHolder) run() {
+	h.db.Find(nil)
+}
+
+func consumeEscapeHolder(*escapeHolder)    {}
+func consumeEscapeHolderValue(escapeHolder) {}
+func consumeEscapeAny(interface{})          {}
+
+// escapeWrapper nests an escapeHolder.
+type escapeWrapper struct {
+	h *escapeHolder
+}
+
+// =============================================================================
+// SHOULD REPORT - Use after the holder escapes
+// =============================================================================
+
+// structEscapePointerArg passes the holder pointer to a function.
+func structEscapePointerArg(db *gorm.DB) {
+	q := db.Where("x = ?", 1)
+	h := &escapeHolder{db: q}
+	consumeEscapeHolder(h) // First use (h escapes)
+	q.Count(nil)           // want `\*gorm\.DB reused: second branch from mutable root`
+}
+
+// structEscapeValueArg passes a holder copy to a function.
+func structEscapeValueArg(db *gorm.DB) {
+	q := db.Where("x = ?", 1)
+	h := escapeHolder{db: q}
+	consumeEscapeHolderValue(h) // First use (h escapes)
+	q.Count(nil)                // want `\*gorm\.DB reused: second branch from mutable root`
+}
+
+// structEscapeInterface passes the holder boxed in interface{}.
+func structEscapeInterface(db *gorm.DB) {
+	q := db.Where("x = ?", 1)
+	h := &escapeHolder{db: q}
+	consumeEscapeAny(h) // First use (h escapes)
+	q.Count(nil)        // want `\*gorm\.DB reused: second branch from mutable root`
+}
+
+// structEscapeMethod calls a method that branches the field.
+func structEscapeMethod(db *gorm.DB) {
+	q := db.Where("x = ?", 1)
+	h := &escapeHolder{db: q}
+	h.run()      // First use (h escapes as the receiver)
+	q.Count(nil) // want `\*gorm\.DB reused: second branch from mutable root`
+}
+
+// structEscapeFieldAssigned fills the field after construction.
+func structEscapeFieldAssigned(db *gorm.DB) {
+	q := db.Where("x = ?", 1)
+	h := &escapeHolder{}
+	h.db = q
+	consumeEscapeHolder(h) // First use (h escapes)
+	q.Count(nil)           // want `\*gorm\.DB reused: second branch from mutable root`
+}
+
+// structEscapeNested stores the holder into another struct that escapes.
+func structEscapeNested(db *gorm.DB, w *escapeWrapper) {
+	q := db.Where("x = ?", 1)
+	h := &escapeHolder{db: q}
+	w.h = h      // First use (h escapes into w)
+	q.Count(nil) // want `\*gorm\.DB reused: second branch from mutable root`
+}
+
+// structEscapeMap stores the holder into a map.
+func structEscapeMap(db *gorm.DB, m map[string]*escapeHolder) {
+	q := db.Where("x = ?", 1)
+	h := &escapeHolder{db: q}
+	m["h"] = h   // First use (h escapes into m)
+	q.Count(nil) // want `\*gorm\.DB reused: second branch from mutable root`
+}
+
+// structEscapeAfterUse passes the holder after its DB was already used.
+func structEscapeAfterUse(db *gorm.DB) {
+	q := db.Where("x = ?", 1)
+	h := &escapeHolder{db: q}
+	q.Count(nil)           // First use
+	consumeEscapeHolder(h) // want `\*gorm\.DB reused: second branch from mutable root`
+}
+
+// =============================================================================
+// SHOULD REPORT - Holder launched as a goroutine receiver
+//
+// The goroutine branches the field in another method, concurrently with the
+// rest of the function, so the go statement is where the holder escapes.
+// =============================================================================
+
+// escapeWorker runs its query in the background.
+type escapeWorker struct {
+	db *gorm.DB
+}
+
+func (w *escapeWorker) Run() {
+	w.db.Find(nil)
+}
+
+// escapeRunner is implemented by escapeWorker.
+type escapeRunner interface {
+	Run()
+}
+
+// structEscapeGoMethod launches the worker's method directly.
+func structEscapeGoMethod(db *gorm.DB) {
+	q := db.Where("x = ?", 1)
+	w := &escapeWorker{}
+	w.db = q
+	go w.Run()   // First use (w escapes to the goroutine)
+	q.Count(nil) // want `\*gorm\.DB reused: second branch from mutable root`
+}
+
+// structEscapeGoMethodValue launches the worker's method through a method value.
+func structEscapeGoMethodValue(db *gorm.DB) {
+	q := db.Where("x = ?", 1)
+	w := &escapeWorker{db: q}
+	run := w.Run
+	go run()     // First use (w is bound to run)
+	q.Count(nil) // want `\*gorm\.DB reused: second branch from mutable root`
+}
+
+// structEscapeGoClosure launches a closure calling the worker's method.
+func structEscapeGoClosure(db *gorm.DB) {
+	q := db.Where("x = ?", 1)
+	w := &escapeWorker{}
+	w.db = q
+	go func() {
+		w.Run()
+	}() // First use (w is captured by the goroutine)
+	q.Count(nil) // want `\*gorm\.DB reused: second branch from mutable root`
+}
+
+// structEscapeGoInterface launches the worker's method through an interface.
+func structEscapeGoInterface(db *gorm.DB) {
+	q := db.Where("x = ?", 1)
+	w := &escapeWorker{db: q}
+	var r escapeRunner = w
+	go r.Run()   // First use (w escapes to the goroutine)
+	q.Count(nil) // want `\*gorm\.DB reused: second branch from mutable root`
+}
+
+// =============================================================================
+// SHOULD NOT REPORT - Holder never escapes, or escapes without the DB
+// =============================================================================
+
+// structEscapeDiscarded builds a holder and drops it.
+func structEscapeDiscarded(db *gorm.DB) {
+	q := db.Where("x = ?", 1)
+	_ = &escapeHolder{db: q}
+	q.Count(nil)
+}
+
+// structEscapeFieldRead only reads the field back.
+func structEscapeFieldRead(db *gorm.DB) {
+	q := db.Where("x = ?", 1)
+	h := &escapeHolder{db: q}
+	h.db.Find(nil)
+}
+
+// structEscapeReturned hands the holder to the caller as the last step.
+func structEscapeReturned(db *gorm.DB) *escapeHolder {
+	q := db.Where("x = ?", 1)
+	q.Count(nil)
+	return &escapeHolder{db: q}
+}
+
+// structEscapeBeforeStore passes the holder before the DB is stored.
+func structEscapeBeforeStore(db *gorm.DB) {
+	q := db.Where("x = ?", 1)
+	h := &escapeHolder{}
+	consumeEscapeHolder(h)
+	q.Count(nil)
+}
+
+// structEscapeSession stores an immutable DB.
+func structEscapeSession(db *gorm.DB) {
+	q := db.Where("x = ?", 1).Session(&gorm.Session{})
+	h := &escapeHolder{db: q}
+	consumeEscapeHolder(h)
+	q.Count(nil)
+}
+
+// structEscapeGoSession launches a worker holding an immutable DB.
+func structEscapeGoSession(db *gorm.DB) {
+	q := db.Where("x = ?", 1).Session(&gorm.Session{})
+	w := &escapeWorker{db: q}
+	go w.Run()
+	q.Count(nil)
+}
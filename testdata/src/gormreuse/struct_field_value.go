@@ -0,0 +1,88 @@
+package internal
+
+import (
+	"gorm.io/gorm"
+)
+
+// =============================================================================
+// Struct Field Reads by Value
+//
+// A field of an addressable struct is read through its address (FieldAddr),
+// but a field of a struct value that is not addressable, such as a composite
+// literal used in place, is read from the value itself (Field). Both resolve to the *gorm.DB stored into the
+// field, so its root is kept.
+// =============================================================================
+
+// valueRepo is passed and read by value.
+type valueRepo struct {
+	db   *gorm.DB
+	name string
+}
+
+// =============================================================================
+// SHOULD REPORT
+// =============================================================================
+
+// fieldValueLiteralReuse reads the field of a literal used in place.
+func fieldValueLiteralReuse(db *gorm.DB) {
+	q := db.Where("x = ?", 1)
+	d := valueRepo{db: q, name: "users"}.db
+	d.Find(nil)
+	q.Count(nil) // want `\*gorm\.DB reused: second branch from mutable root`
+}
+
+// fieldValueLiteralTwice reads the field of a literal used in place twice.
+func fieldValueLiteralTwice(db *gorm.DB) {
+	q := db.Where("x = ?", 1)
+	valueRepo{db: q}.db.Find(nil)
+	valueRepo{db: q}.db.Count(nil) // want `\*gorm\.DB reused: second branch from mutable root`
+}
+
+// fieldValueConditionalReuse reads the field of one of two literals.
+func fieldValueConditionalReuse(db *gorm.DB, cond bool) {
+	base := db.Session(&gorm.Session{})
+	q1 := base.Where("x = ?", 1)
+	q2 := base.Where("y = ?", 2)
+	q2.Find(nil)
+	var d *gorm.DB
+	if cond {
+		d = valueRepo{db: q1}.db
+	} else {
+		d = valueRepo{db: q2}.db
+	}
+	d.Count(nil) // want `\*gorm\.DB reused: second branch from mutable root`
+}
+
+// fieldValueCopyReuse reads the field of a copy of a struct variable.
+func fieldValueCopyReuse(db *gorm.DB) {
+	r := valueRepo{db: db.Where("x = ?", 1)}
+	c := r
+	c.db.Find(nil)
+	r.db.Count(nil) // want `\*gorm\.DB reused: second branch from mutable root`
+}
+
+// =============================================================================
+// SHOULD NOT REPORT
+// =============================================================================
+
+// fieldValueLiteralOnce reads the field of a literal used in place once.
+func fieldValueLiteralOnce(db *gorm.DB) {
+	q := db.Where("x = ?", 1)
+	valueRepo{db: q}.db.Find(nil)
+}
+
+// fieldValueSessionLiteral reads the field of a literal holding an isolated
+// session.
+func fieldValueSessionLiteral(db *gorm.DB) {
+	q := db.Where("x = ?", 1).Session(&gorm.Session{})
+	d := valueRepo{db: q}.db
+	d.Find(nil)
+	d.Count(nil)
+}
+
+// fieldValueParam reads the field of a struct parameter, which is of
+// unknown origin like a *gorm.DB field of a pointer parameter.
+func fieldValueParam(r valueRepo) {
+	r.db.Find(nil)
+	r.db.Count(nil)
+}
@@ -0,0 +1,143 @@
+package internal
+
+import "gorm.io/gorm"
+
+// =============================================================================
+// Transaction callback bodies
+//
+// Transaction hands its callback a fresh tx (clone>0), so tx itself is an
+// immutable source inside the callback: using it directly any number of times
+// is safe. A chain derived from tx is an ordinary mutable root, and reusing it
+// is reported like anywhere else. The callback is analyzed as a function of
+// its own whether or not it captures anything, including callbacks of nested
+// transactions and callbacks stored in a variable or declared as methods.
+// =============================================================================
+
+// =============================================================================
+// SHOULD REPORT
+// =============================================================================
+
+// TX001: Reuse of a chain derived from tx inside the callback.
+func transactionChainReuse(db *gorm.DB) {
+	_ = db.Transaction(func(tx *gorm.DB) error {
+		q := tx.Where("x = ?", 1)
+		q.Find(nil)
+		q.Count(nil) // want `\*gorm\.DB reused: second branch from mutable root`
+		return nil
+	})
+}
+
+// TX002: Reuse in the returned error expression.
+func transactionChainReuseInReturn(db *gorm.DB) error {
+	return db.Transaction(func(tx *gorm.DB) error {
+		q := tx.Where("x = ?", 1)
+		q.Find(nil)
+		return q.Count(nil).Error // want `\*gorm\.DB reused: second branch from mutable root`
+	})
+}
+
+// TX003: Reuse inside the callback of a nested transaction.
+func transactionNestedChainReuse(db *gorm.DB) {
+	_ = db.Transaction(func(tx *gorm.DB) error {
+		return tx.Transaction(func(tx2 *gorm.DB) error {
+			q := tx2.Where("x = ?", 1)
+			q.Find(nil)
+			q.Count(nil) // want `\*gorm\.DB reused: second branch from mutable root`
+			return nil
+		})
+	})
+}
+
+// TX004: Reuse inside a callback stored in a variable before the call.
+func transactionStoredCallbackReuse(db *gorm.DB) {
+	fn := func(tx *gorm.DB) error {
+		q := tx.Where("x = ?", 1)
+		q.Find(nil)
+		q.Count(nil) // want `\*gorm\.DB reused: second branch from mutable root`
+		return nil
+	}
+	_ = db.Transaction(fn)
+}
+
+// TX005: Reuse inside a loop of the callback.
+func transactionLoopReuse(db *gorm.DB, ids []int) {
+	_ = db.Transaction(func(tx *gorm.DB) error {
+		q := tx.Where("active = ?", true)
+		for _, id := range ids {
+			q.Where("id = ?", id).Find(nil) // want `\*gorm\.DB reused: second branch from mutable root`
+		}
+		return nil
+	})
+}
+
+// txRepo runs its queries in transactions.
+type txRepo struct{}
+
+// TX006: Reuse inside a method used as the callback.
+func (r *txRepo) inTx(tx *gorm.DB) error {
+	q := tx.Where("x = ?", 1)
+	q.Find(nil)
+	q.Count(nil) // want `\*gorm\.DB reused: second branch from mutable root`
+	return nil
+}
+
+func transactionMethodCallback(db *gorm.DB, r *txRepo) {
+	_ = db.Transaction(r.inTx)
+}
+
+// TX007: A chain of the enclosing function captured by the callback is used
+// there, so using it again after the transaction is a reuse.
+func transactionCapturedOuterChain(db *gorm.DB) {
+	q := db.Where("x = ?", 1)
+	_ = db.Transaction(func(tx *gorm.DB) error {
+		q.Find(nil)
+		return nil
+	})
+	q.Count(nil) // want `\*gorm\.DB reused: second branch from mutable root`
+}
+
+// =============================================================================
+// SHOULD NOT REPORT
+// =============================================================================
+
+// TX101: tx used directly several times.
+func transactionDirectUse(db *gorm.DB) {
+	_ = db.Transaction(func(tx *gorm.DB) error {
+		tx.Find(nil)
+		tx.Count(nil)
+		return tx.First(nil).Error
+	})
+}
+
+// TX102: Fresh chains from tx next to a chain used once.
+func transactionFreshChains(db *gorm.DB) {
+	_ = db.Transaction(func(tx *gorm.DB) error {
+		q := tx.Where("x = ?", 1)
+		q.Find(nil)
+		tx.Where("y = ?", 2).Find(nil)
+		tx.Count(nil)
+		return nil
+	})
+}
+
+// TX103: A chain derived from tx, isolated with Session before its branches.
+func transactionSessionChain(db *gorm.DB) {
+	_ = db.Transaction(func(tx *gorm.DB) error {
+		q := tx.Where("x = ?", 1).Session(&gorm.Session{})
+		q.Find(nil)
+		q.Count(nil)
+		return nil
+	})
+}
+
+// TX104: Each iteration of a loop in the callback starts a fresh chain.
+func transactionLoopFreshChains(db *gorm.DB, ids []int) {
+	_ = db.Transaction(func(tx *gorm.DB) error {
+		for _, id := range ids {
+			if err := tx.Where("id = ?", id).Find(nil).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
@@ -0,0 +1,88 @@
+package internal
+
+import (
+	"gorm.io/gorm"
+)
+
+// =============================================================================
+// Tuple Return Test Cases
+//
+// A helper returning (*gorm.DB, error) hands out a mutable chain like one
+// returning *gorm.DB alone: the *gorm.DB element of its results is a mutable
+// root, and each call of the helper is a root of its own.
+// =============================================================================
+
+// tupleBuildQuery returns a mutable chain with an error.
+func tupleBuildQuery(db *gorm.DB) (*gorm.DB, error) {
+	return db.Where("x = ?", 1), nil
+}
+
+// tupleBuildPair returns two mutable chains.
+func tupleBuildPair(db *gorm.DB) (*gorm.DB, *gorm.DB) {
+	base := db.Session(&gorm.Session{})
+	return base.Where("a = ?", 1), base.Where("b = ?", 2)
+}
+
+// tupleBuildSafe returns an immutable handle with an error.
+//
+//gormreuse:immutable-return
+func tupleBuildSafe(db *gorm.DB) (*gorm.DB, error) {
+	return db.Where("x = ?", 1).Session(&gorm.Session{}), nil
+}
+
+// =============================================================================
+// SHOULD REPORT - Mutable element of a tuple reused
+// =============================================================================
+
+// tupleReuse reuses the *gorm.DB of a (db, error) result.
+func tupleReuse(db *gorm.DB) error {
+	q, err := tupleBuildQuery(db)
+	if err != nil {
+		return err
+	}
+	q.Find(nil)  // First use
+	q.Count(nil) // want `\*gorm\.DB reused: second branch from mutable root`
+	return nil
+}
+
+// tupleReuseIgnoredError reuses the *gorm.DB with the error discarded.
+func tupleReuseIgnoredError(db *gorm.DB) {
+	q, _ := tupleBuildQuery(db)
+	q.Find(nil)  // First use
+	q.Count(nil) // want `\*gorm\.DB reused: second branch from mutable root`
+}
+
+// tuplePairSecondReused reuses the second of two *gorm.DB results.
+func tuplePairSecondReused(db *gorm.DB) {
+	a, b := tupleBuildPair(db)
+	a.Find(nil)
+	b.Find(nil)  // First use of b
+	b.Count(nil) // want `\*gorm\.DB reused: second branch from mutable root`
+}
+
+// =============================================================================
+// SHOULD NOT REPORT - Used once, separate results, or immutable
+// =============================================================================
+
+// tupleSingleUse uses the *gorm.DB of a (db, error) result once.
+func tupleSingleUse(db *gorm.DB) error {
+	q, err := tupleBuildQuery(db)
+	if err != nil {
+		return err
+	}
+	return q.Find(nil).Error
+}
+
+// tuplePairSeparate uses each of two *gorm.DB results once.
+func tuplePairSeparate(db *gorm.DB) {
+	a, b := tupleBuildPair(db)
+	a.Find(nil)
+	b.Find(nil)
+}
+
+// tupleImmutable reuses an immutable-return (db, error) result.
+func tupleImmutable(db *gorm.DB) {
+	q, _ := tupleBuildSafe(db)
+	q.Find(nil)
+	q.Count(nil)
+}
@@ -0,0 +1,92 @@
+package internal
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+)
+
+// =============================================================================
+// WithContext anywhere in a chain
+//
+// WithContext returns an immutable clone, wherever it appears in the chain:
+// the value it returns is an immutable source, so tracing stops at it even
+// when it is not the call the receiver is assigned from directly. Chains
+// derived from it are mutable roots of their own again.
+// =============================================================================
+
+// =============================================================================
+// SHOULD REPORT
+// =============================================================================
+
+// WC001: A chain derived from a mid-chain WithContext, reused.
+func withContextDerivedReuse(ctx context.Context, db *gorm.DB) {
+	base := db.Where("x = ?", 1).WithContext(ctx)
+	q := base.Where("y = ?", 2)
+	q.Find(nil)
+	q.Count(nil) // want `\*gorm\.DB reused: second branch from mutable root`
+}
+
+// WC002: A chain continuing after WithContext is mutable again.
+func withContextThenWhereReuse(ctx context.Context, db *gorm.DB) {
+	q := db.Where("x = ?", 1).WithContext(ctx).Where("y = ?", 2)
+	q.Find(nil)
+	q.Count(nil) // want `\*gorm\.DB reused: second branch from mutable root`
+}
+
+// WC003: WithContext after a use is itself a branch of the used root.
+func withContextAfterUse(ctx context.Context, db *gorm.DB) {
+	q := db.Where("x = ?", 1)
+	q.Find(nil)
+	base := q.WithContext(ctx) // want `\*gorm\.DB reused: second branch from mutable root`
+	base.Count(nil)
+}
+
+// =============================================================================
+// SHOULD NOT REPORT
+// =============================================================================
+
+// WC101: A mid-chain WithContext result branched twice.
+func withContextMidChainBase(ctx context.Context, db *gorm.DB) {
+	base := db.Where("x = ?", 1).WithContext(ctx)
+	base.Where("a = ?", 1).Find(nil)
+	base.Where("b = ?", 2).Find(nil)
+}
+
+// WC102: A mid-chain WithContext result used directly twice.
+func withContextMidChainDirect(ctx context.Context, db *gorm.DB) {
+	base := db.Where("x = ?", 1).Order("id").WithContext(ctx)
+	base.Find(nil)
+	base.Count(nil)
+}
+
+// WC103: WithContext twice in one chain.
+func withContextTwiceInChain(ctx context.Context, db *gorm.DB) {
+	base := db.Where("x = ?", 1).WithContext(ctx).Where("y = ?", 2).WithContext(ctx)
+	base.Find(nil)
+	base.Count(nil)
+}
+
+// WC104: A mid-chain WithContext result merged with another immutable value.
+func withContextMerged(ctx context.Context, db *gorm.DB, debug bool) {
+	base := db.Where("x = ?", 1).WithContext(ctx)
+	if debug {
+		base = base.Debug()
+	}
+	base.Find(nil)
+	base.Count(nil)
+}
+
+// WC105: A mid-chain WithContext result captured by a closure.
+func withContextCaptured(ctx context.Context, db *gorm.DB) {
+	base := db.Where("x = ?", 1).WithContext(ctx)
+	count := func() { base.Count(nil) }
+	base.Find(nil)
+	count()
+}
+
+// WC106: Each branch of a root takes its own WithContext.
+func withContextPerBranch(ctx context.Context, db *gorm.DB) {
+	q := db.Where("x = ?", 1)
+	q.WithContext(ctx).Find(nil)
+}
@@ -0,0 +1,67 @@
+// Package gormtype tests -gorm-type and -gorm-type-underlying: a vendored GORM
+// type and named types over *gorm.DB are tracked like *gorm.DB itself.
+package gormtype
+
+import (
+	"github.com/acme/db"
+	"gorm.io/gorm"
+)
+
+// DB is an alias of gorm.DB declared in an internal module.
+type DB = gorm.DB
+
+// Conn is a named type whose underlying type is gorm.DB (-gorm-type-underlying).
+type Conn gorm.DB
+
+// Where forwards to gorm.DB.Where, keeping the Conn type.
+func (c *Conn) Where(query interface{}, args ...interface{}) *Conn {
+	return (*Conn)((*gorm.DB)(c).Where(query, args...))
+}
+
+// Find forwards to gorm.DB.Find, keeping the Conn type.
+func (c *Conn) Find(dest interface{}, conds ...interface{}) *Conn {
+	return (*Conn)((*gorm.DB)(c).Find(dest, conds...))
+}
+
+// =============================================================================
+// SHOULD REPORT - vendored and wrapped DB types
+// =============================================================================
+
+// vendoredReuse branches a vendored handle twice (-gorm-type).
+func vendoredReuse(h *db.Handle) {
+	q := h.Where("x")
+	q.Find(nil)
+	q.Count(nil) // want `\*gorm\.DB reused: second branch from mutable root`
+}
+
+// aliasReuse branches through an alias of gorm.DB.
+func aliasReuse(d *DB) {
+	q := d.Where("x")
+	q.Find(nil)
+	q.Find(nil) // want `\*gorm\.DB reused: second branch from mutable root`
+}
+
+// connReuse branches a named type over gorm.DB (-gorm-type-underlying).
+func connReuse(c *Conn) {
+	q := c.Where("x")
+	q.Find(nil)
+	q.Find(nil) // want `\*gorm\.DB reused: second branch from mutable root`
+}
+
+// =============================================================================
+// SHOULD NOT REPORT - immutable vendored handles
+// =============================================================================
+
+// vendoredSession freezes the vendored handle before branching.
+func vendoredSession(h *db.Handle) {
+	q := h.Where("x").Session(&db.Session{})
+	q.Find(nil)
+	q.Count(nil)
+}
+
+// vendoredOpen branches a fresh handle from the vendored Open.
+func vendoredOpen() {
+	h, _ := db.Open(nil)
+	h.Find(nil)
+	h.Count(nil)
+}
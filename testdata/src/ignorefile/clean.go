@@ -0,0 +1,11 @@
+//gormreuse:ignore-file // want `unused gormreuse:ignore-file directive`
+package ignorefile
+
+import "gorm.io/gorm"
+
+// cleanSession has nothing to suppress.
+func cleanSession(db *gorm.DB) {
+	q := db.Where("x = ?", 1).Session(&gorm.Session{})
+	q.Find(nil)
+	q.Count(nil)
+}
@@ -0,0 +1,20 @@
+// Package ignorefile tests //gormreuse:ignore-file under -strict-ignore-file.
+//
+//gormreuse:ignore-file
+package ignorefile
+
+import "gorm.io/gorm"
+
+// legacyReuse is suppressed, so the directive above is used.
+func legacyReuse(db *gorm.DB) {
+	q := db.Where("x = ?", 1)
+	q.Find(nil)
+	q.Count(nil)
+}
+
+// legacyPure breaks its pure contract; that is suppressed as well.
+//
+//gormreuse:pure
+func legacyPure(db *gorm.DB) {
+	db.Find(nil)
+}
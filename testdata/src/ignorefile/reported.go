@@ -0,0 +1,10 @@
+package ignorefile
+
+import "gorm.io/gorm"
+
+// reportedReuse is in a file without the directive and is still reported.
+func reportedReuse(db *gorm.DB) {
+	q := db.Where("x = ?", 1)
+	q.Find(nil)
+	q.Count(nil) // want `\*gorm\.DB reused: second branch from mutable root`
+}
@@ -0,0 +1,14 @@
+/* want `gormreuse:ignore directive without a reason` */ //gormreuse:ignore
+
+package ignorereason
+
+import (
+	"gorm.io/gorm"
+)
+
+// fileIgnore is covered by the reasonless file-level ignore above.
+func fileIgnore(db *gorm.DB) {
+	q := db.Where("x = ?", 1)
+	q.Find(nil)
+	q.Count(nil)
+}
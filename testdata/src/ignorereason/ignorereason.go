@@ -0,0 +1,88 @@
+package ignorereason
+
+import (
+	"gorm.io/gorm"
+)
+
+// =============================================================================
+// With -require-ignore-reason, every //gormreuse:ignore must say why it
+// suppresses the reuse, after a colon or in a trailing comment.
+// =============================================================================
+
+// =============================================================================
+// SHOULD REPORT - Ignores without a reason
+// =============================================================================
+
+// lineIgnore suppresses the reuse on the next line.
+func lineIgnore(db *gorm.DB) {
+	q := db.Where("x = ?", 1)
+	q.Find(nil)
+	/* want `gormreuse:ignore directive without a reason` */ //gormreuse:ignore
+	q.Count(nil)
+}
+
+// rootIgnore suppresses every reuse of its root.
+func rootIgnore(db *gorm.DB) {
+	q := db.Where("x = ?", 1) /* want `gormreuse:ignore directive without a reason` */ //gormreuse:ignore
+	q.Find(nil)
+	q.Count(nil)
+}
+
+// funcIgnore suppresses every reuse of the function.
+//
+/* want `gormreuse:ignore directive without a reason` */ //gormreuse:ignore
+func funcIgnore(db *gorm.DB) {
+	q := db.Where("x = ?", 1)
+	q.Find(nil)
+	q.Count(nil)
+}
+
+// unusedIgnore suppresses nothing and gives no reason either.
+func unusedIgnore(db *gorm.DB) {
+	q := db.Where("x = ?", 1)
+	/* want `unused gormreuse:ignore directive` `gormreuse:ignore directive without a reason` */ //gormreuse:ignore
+	q.Find(nil)
+}
+
+// emptyReason gives an empty reason.
+func emptyReason(db *gorm.DB) {
+	q := db.Where("x = ?", 1)
+	q.Find(nil)
+	/* want `gormreuse:ignore directive without a reason` */ //gormreuse:ignore:
+	q.Count(nil)
+}
+
+// =============================================================================
+// SHOULD NOT REPORT - Ignores with a reason
+// =============================================================================
+
+// colonReason gives the reason after a colon.
+func colonReason(db *gorm.DB) {
+	q := db.Where("x = ?", 1)
+	q.Find(nil)
+	//gormreuse:ignore: the second query only reads the count
+	q.Count(nil)
+}
+
+// trailingReason gives the reason in a trailing comment.
+func trailingReason(db *gorm.DB) {
+	q := db.Where("x = ?", 1)
+	q.Find(nil)
+	q.Count(nil) //gormreuse:ignore // the second query only reads the count
+}
+
+// rootReason gives the reason of a root-level ignore.
+func rootReason(db *gorm.DB) {
+	q := db.Where("x = ?", 1) //gormreuse:ignore: legacy report, see #12
+	q.Find(nil)
+	q.Count(nil)
+}
+
+// funcReason gives the reason of a function-level ignore.
+//
+//gormreuse:ignore: legacy report, rewritten in v2
+func funcReason(db *gorm.DB) {
+	q := db.Where("x = ?", 1)
+	q.Find(nil)
+	q.Count(nil)
+}
@@ -0,0 +1,40 @@
+package off
+
+import (
+	"gorm.io/gorm"
+)
+
+// =============================================================================
+// Without -require-ignore-reason, an ignore needs no reason, and one giving a
+// reason after a colon suppresses like any other.
+// =============================================================================
+
+// lineIgnore suppresses the reuse without a reason.
+func lineIgnore(db *gorm.DB) {
+	q := db.Where("x = ?", 1)
+	q.Find(nil)
+	//gormreuse:ignore
+	q.Count(nil)
+}
+
+// colonReason suppresses the reuse with a reason.
+func colonReason(db *gorm.DB) {
+	q := db.Where("x = ?", 1)
+	q.Find(nil)
+	//gormreuse:ignore: the second query only reads the count
+	q.Count(nil)
+}
+
+// rootReason suppresses every reuse of its root with a reason.
+func rootReason(db *gorm.DB) {
+	q := db.Where("x = ?", 1) //gormreuse:ignore: legacy report
+	q.Find(nil)
+	q.Count(nil)
+}
+
+// unusedReason suppresses nothing, reason or not.
+func unusedReason(db *gorm.DB) {
+	q := db.Where("x = ?", 1)
+	//gormreuse:ignore: nothing to suppress // want `unused gormreuse:ignore directive`
+	q.Find(nil)
+}
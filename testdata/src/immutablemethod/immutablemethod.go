@@ -0,0 +1,55 @@
+// Package immutablemethod tests -immutable-method: a configured method of a
+// GORM type returns an immutable copy like Session, while a same-named method
+// of any other type does not.
+package immutablemethod
+
+import (
+	"github.com/acme/db"
+	"gorm.io/gorm"
+)
+
+// repo is not a GORM type; its ReadOnly method is not configured.
+type repo struct {
+	db *gorm.DB
+}
+
+// ReadOnly returns the stored handle as is.
+func (r *repo) ReadOnly() *gorm.DB {
+	return r.db.Where("replica = ?", true)
+}
+
+// =============================================================================
+// SHOULD REPORT - mutable handles
+// =============================================================================
+
+// readOnlyBeforeWhere branches a chain built on top of the immutable copy.
+func readOnlyBeforeWhere(h *db.Handle) {
+	q := h.ReadOnly().Where("x")
+	q.Find(nil)
+	q.Count(nil) // want `\*gorm\.DB reused: second branch from mutable root`
+}
+
+// nonGormReadOnly calls a same-named method of a non-GORM type.
+func nonGormReadOnly(r *repo) {
+	q := r.ReadOnly().Where("x")
+	q.Find(nil)
+	q.Find(nil) // want `\*gorm\.DB reused: second branch from mutable root`
+}
+
+// =============================================================================
+// SHOULD NOT REPORT - immutable copies
+// =============================================================================
+
+// readOnlyAfterWhere freezes the chain with the configured method.
+func readOnlyAfterWhere(h *db.Handle) {
+	q := h.Where("x").ReadOnly()
+	q.Find(nil)
+	q.Count(nil)
+}
+
+// readOnlyOnly branches the immutable copy directly.
+func readOnlyOnly(h *db.Handle) {
+	q := h.ReadOnly()
+	q.Where("a").Find(nil)
+	q.Where("b").Find(nil)
+}
@@ -16,20 +16,66 @@ type Orm struct{}
 // This simulates the pattern: new(orm.Orm).DB(ctx)
 //
 //gormreuse:pure
-func (o *Orm) DB(ctx context.Context) *gorm.DB {
+func (o *Orm) DB(ctx context.Context) *gorm.DB { // want DB:"pure"
 	return DB.WithContext(ctx)
 }
 
 // GetDB is a pure method that returns a new *gorm.DB.
 //
 //gormreuse:pure
-func (o *Orm) GetDB() *gorm.DB {
+func (o *Orm) GetDB() *gorm.DB { // want GetDB:"pure"
 	return DB.WithContext(nil)
 }
 
 // PureFactory is a pure function that returns a new *gorm.DB.
 //
 //gormreuse:pure
-func PureFactory() *gorm.DB {
+func PureFactory() *gorm.DB { // want PureFactory:"pure"
 	return DB.WithContext(nil)
 }
+
+// Scoped returns a fresh session the caller may branch freely.
+//
+//gormreuse:immutable-return
+func Scoped() *gorm.DB { // want Scoped:"immutable-return"
+	return DB.Session(&gorm.Session{})
+}
+
+// Count counts the rows of db without polluting it.
+//
+//gormreuse:pure
+func Count(db *gorm.DB) int64 { // want Count:"pure"
+	var n int64
+	db.Session(&gorm.Session{}).Count(&n)
+	return n
+}
+
+// Load finds into dest, polluting db.
+func Load(db *gorm.DB, dest any) {
+	db.Find(dest)
+}
+
+// Save creates dest, finishing db.
+//
+//gormreuse:sink
+func Save(db *gorm.DB, dest any) { // want Save:"sink"
+	db.Create(dest)
+}
+
+// rawDB is unexported, so no fact is exported for it.
+//
+//gormreuse:pure
+func rawDB() *gorm.DB {
+	return DB
+}
+
+// Builder builds queries on a *gorm.DB.
+type Builder interface {
+	// Build adds conditions to db without polluting it.
+	//
+	//gormreuse:pure
+	Build(db *gorm.DB) *gorm.DB
+
+	// Apply runs db.
+	Apply(db *gorm.DB)
+}
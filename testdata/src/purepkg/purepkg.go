@@ -0,0 +1,62 @@
+// Package purepkg tests the default -pure-pkg: functions of testify's require
+// and assert packages never pollute their *gorm.DB arguments, while other
+// packages' functions still do.
+package purepkg
+
+import (
+	"github.com/acme/check"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+// =============================================================================
+// SHOULD NOT REPORT - *gorm.DB passed to a pure package
+// =============================================================================
+
+// assertThenFind checks q before its only use.
+func assertThenFind(t assert.TestingT, db *gorm.DB) {
+	q := db.Where("x = ?", 1)
+	assert.NotNil(t, q)
+	q.Find(nil) // OK: assert is a pure package
+}
+
+// requireThenFind checks q with require before its only use.
+func requireThenFind(t require.TestingT, db *gorm.DB) {
+	q := db.Where("x = ?", 1)
+	require.NotNil(t, q)
+	require.NotNil(t, q)
+	q.Find(nil) // OK: require is a pure package
+}
+
+// assertEqualThenFind compares q before its only use.
+func assertEqualThenFind(t assert.TestingT, db *gorm.DB, want *gorm.DB) {
+	q := db.Where("x = ?", 1)
+	assert.Equal(t, want, q)
+	q.Find(nil) // OK: assert is a pure package
+}
+
+// pureHelperAsserts is a pure helper that hands its argument to assert.
+//
+//gormreuse:pure
+func pureHelperAsserts(t assert.TestingT, db *gorm.DB) {
+	assert.NotNil(t, db) // OK: assert is a pure package
+}
+
+// =============================================================================
+// SHOULD REPORT - *gorm.DB passed to another package
+// =============================================================================
+
+// checkThenFind hands q to an unlisted package before using it.
+func checkThenFind(db *gorm.DB) {
+	q := db.Where("x = ?", 1)
+	check.NotNil(q)
+	q.Find(nil) // want `\*gorm\.DB reused: second branch from mutable root`
+}
+
+// assertAfterFind still reports reuse by gorm methods.
+func assertAfterFind(t assert.TestingT, db *gorm.DB) {
+	q := db.Where("x = ?", 1)
+	assert.NoError(t, q.Find(nil).Error)
+	q.Count(nil) // want `\*gorm\.DB reused: second branch from mutable root`
+}
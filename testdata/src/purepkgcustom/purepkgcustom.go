@@ -0,0 +1,23 @@
+// Package purepkgcustom tests -pure-pkg=github.com/acme/check: the listed
+// package replaces the default, so testify's assert pollutes again.
+package purepkgcustom
+
+import (
+	"github.com/acme/check"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+// checkThenFind hands q to the listed package before its only use.
+func checkThenFind(db *gorm.DB) {
+	q := db.Where("x = ?", 1)
+	check.NotNil(q)
+	q.Find(nil) // OK: check is a pure package
+}
+
+// assertThenFind hands q to a package that is no longer listed.
+func assertThenFind(t assert.TestingT, db *gorm.DB) {
+	q := db.Where("x = ?", 1)
+	assert.NotNil(t, q)
+	q.Find(nil) // want `\*gorm\.DB reused: second branch from mutable root`
+}
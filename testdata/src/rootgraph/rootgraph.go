@@ -0,0 +1,22 @@
+// Package rootgraph is a small example for the -report-root-graph output: one
+// root that is reused, and one root conditionally derived by assignment.
+package rootgraph
+
+import "gorm.io/gorm"
+
+// reuse branches q twice.
+func reuse(db *gorm.DB) {
+	q := db.Where("x")
+	q.Find(nil)
+	q.Count(nil) // want `\*gorm\.DB reused: second branch from mutable root`
+}
+
+// derive conditionally extends q; the extension is an assignment, not a branch.
+func derive(db *gorm.DB, cond bool) {
+	q := db.Where("x")
+	r := q.Where("y")
+	if cond {
+		r = r.Order("id")
+	}
+	r.Find(nil)
+}
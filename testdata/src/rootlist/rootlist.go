@@ -0,0 +1,12 @@
+// Package rootlist is a small example for the -list-roots-json output: one
+// root that is reused once.
+package rootlist
+
+import "gorm.io/gorm"
+
+// reuse branches q twice.
+func reuse(db *gorm.DB) {
+	q := db.Where("x")
+	q.Find(nil)
+	q.Count(nil) // want `\*gorm\.DB reused: second branch from mutable root`
+}
@@ -0,0 +1,25 @@
+// Package severity tests -severity=BRANCH=warning,PURE=error: mapped
+// categories are prefixed with their level, others are reported unchanged.
+package severity
+
+import "gorm.io/gorm"
+
+// reuse is a BRANCH diagnostic, mapped to warning.
+func reuse(db *gorm.DB) {
+	q := db.Where("x")
+	q.Find(nil)
+	q.Count(nil) // want `^warning: \*gorm\.DB reused: second branch from mutable root`
+}
+
+// brokenPure is a PURE diagnostic, mapped to error.
+//
+//gormreuse:pure
+func brokenPure(db *gorm.DB) {
+	db.Find(nil) // want `^error: pure function pollutes \*gorm\.DB argument by calling Find`
+}
+
+// unusedIgnore is an UNUSED-IGNORE diagnostic, which is not mapped.
+func unusedIgnore(db *gorm.DB) {
+	//gormreuse:ignore // want `^unused gormreuse:ignore directive`
+	db.Session(&gorm.Session{}).Find(nil)
+}
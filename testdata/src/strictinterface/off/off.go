@@ -0,0 +1,33 @@
+package off
+
+import (
+	"fmt"
+
+	"gorm.io/gorm"
+)
+
+func consume(interface{}) {}
+
+// =============================================================================
+// Without -strict-interface, a conversion to an interface is reported only
+// through the reuse it causes.
+// =============================================================================
+
+// escapeThenUse passes the root as interface{} before branching it.
+func escapeThenUse(db *gorm.DB) {
+	q := db.Where("x = ?", 1)
+	consume(q)
+	q.Count(nil) // want `\*gorm\.DB reused: second branch from mutable root`
+}
+
+// escapeOnly passes the root as interface{} without a later use.
+func escapeOnly(db *gorm.DB) {
+	q := db.Where("x = ?", 1)
+	consume(q)
+}
+
+// escapeVariadic passes the root among variadic interface{} arguments.
+func escapeVariadic(db *gorm.DB) {
+	q := db.Where("x = ?", 1)
+	fmt.Println("query:", q)
+}
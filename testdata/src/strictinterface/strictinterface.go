@@ -0,0 +1,79 @@
+package strictinterface
+
+import (
+	"fmt"
+	"sync"
+
+	"gorm.io/gorm"
+)
+
+func consume(interface{}) {}
+
+// =============================================================================
+// SHOULD REPORT - Mutable *gorm.DB converted to an interface
+// =============================================================================
+
+// escapeThenUse passes the root as interface{} before branching it; the
+// conversion is reported as well as the reuse it causes.
+func escapeThenUse(db *gorm.DB) {
+	q := db.Where("x = ?", 1)
+	consume(q)   // want `\*gorm\.DB escapes into interface\{\} \(root at strictinterface\.go:19\); any later use of the root is unsafe`
+	q.Count(nil) // want `\*gorm\.DB reused: second branch from mutable root`
+}
+
+// escapeOnly passes the root as interface{} without a later use.
+func escapeOnly(db *gorm.DB) {
+	q := db.Where("x = ?", 1)
+	consume(q) // want `\*gorm\.DB escapes into interface\{\}`
+}
+
+// escapeExplicit converts the root explicitly.
+func escapeExplicit(db *gorm.DB) interface{} {
+	q := db.Where("x = ?", 1)
+	return interface{}(q) // want `\*gorm\.DB escapes into interface\{\}`
+}
+
+// escapeVariadic passes the root among variadic interface{} arguments.
+func escapeVariadic(db *gorm.DB) {
+	q := db.Where("x = ?", 1)
+	fmt.Println("query:", q) // want `\*gorm\.DB escapes into interface\{\}`
+}
+
+// escapeSend sends the root on a chan interface{}.
+func escapeSend(db *gorm.DB, ch chan interface{}) {
+	q := db.Where("x = ?", 1)
+	ch <- q // want `\*gorm\.DB escapes into interface\{\}`
+}
+
+// escapeSubquery passes a mutable subquery to Where.
+func escapeSubquery(db *gorm.DB) {
+	base := db.Session(&gorm.Session{})
+	sub := base.Model(nil).Select("id")
+	base.Where("id IN (?)", sub).Find(nil) // want `\*gorm\.DB escapes into interface\{\}`
+}
+
+// =============================================================================
+// SHOULD NOT REPORT - Immutable, already reused, or handed over
+// =============================================================================
+
+// immutableConverted converts an immutable *gorm.DB.
+func immutableConverted(db *gorm.DB) {
+	q := db.Where("x = ?", 1).Session(&gorm.Session{})
+	consume(q)
+	q.Find(nil)
+}
+
+// reuseConverted converts a root already branched: the conversion is reported
+// as the reuse it is, not as an escape.
+func reuseConverted(db *gorm.DB) {
+	q := db.Where("x = ?", 1)
+	q.Find(nil)
+	consume(q) // want `\*gorm\.DB reused: second branch from mutable root`
+}
+
+// pooled hands the root over through a sync.Pool read back in place.
+func pooled(db *gorm.DB, p *sync.Pool) {
+	q := db.Where("x = ?", 1)
+	p.Put(q)
+	p.Get().(*gorm.DB).Find(nil)
+}
@@ -0,0 +1,95 @@
+package suggestpure
+
+import "gorm.io/gorm"
+
+// =============================================================================
+// SHOULD REPORT - Unannotated helpers proven pure
+// =============================================================================
+
+// countRows only reads from an isolated Session of its argument.
+func countRows(db *gorm.DB) int64 { // want `countRows never pollutes its \*gorm\.DB argument; consider marking it //gormreuse:pure`
+	var n int64
+	db.Session(&gorm.Session{}).Count(&n)
+	return n
+}
+
+// lastError only reads a field of its argument.
+func lastError(db *gorm.DB) error { // want `lastError never pollutes its \*gorm\.DB argument`
+	return db.Error
+}
+
+// withSession returns an immutable copy of its argument.
+func withSession(db *gorm.DB) *gorm.DB { // want `withSession never pollutes its \*gorm\.DB argument`
+	return db.Session(&gorm.Session{})
+}
+
+// callsPure forwards its argument to a //gormreuse:pure helper only.
+func callsPure(db *gorm.DB) error { // want `callsPure never pollutes its \*gorm\.DB argument`
+	return alreadyPure(db)
+}
+
+// repo is a wrapper type with helper methods.
+type repo struct{}
+
+// debugOf is a method returning a debug copy of its argument.
+func (repo) debugOf(db *gorm.DB) *gorm.DB { // want `debugOf never pollutes its \*gorm\.DB argument`
+	return db.Debug()
+}
+
+// =============================================================================
+// SHOULD NOT REPORT - Polluting, annotated, or without a *gorm.DB parameter
+// =============================================================================
+
+// alreadyPure is annotated, so there is nothing to suggest.
+//
+//gormreuse:pure
+func alreadyPure(db *gorm.DB) error {
+	return db.Error
+}
+
+// chains pollutes its argument with a chain method.
+func chains(db *gorm.DB) *gorm.DB {
+	return db.Where("x = ?", 1)
+}
+
+// finds pollutes its argument with a finisher.
+func finds(db *gorm.DB) {
+	db.Find(nil)
+}
+
+// forwards passes its argument to an unannotated helper.
+func forwards(db *gorm.DB) {
+	finds(db)
+}
+
+// leaks sends its argument on a channel.
+func leaks(db *gorm.DB, ch chan *gorm.DB) {
+	ch <- db
+}
+
+// consumes only reads its argument but declares it consumed.
+//
+//gormreuse:sink
+func consumes(db *gorm.DB) {
+	_ = db.Error
+}
+
+// noDB has no *gorm.DB parameter.
+func noDB(n int) int {
+	return n + 1
+}
+
+// closureOnly holds its pure-looking logic in a function literal, which is
+// not suggested.
+func closureOnly() func(*gorm.DB) error {
+	return func(db *gorm.DB) error {
+		return db.Error
+	}
+}
+
+// ignored is excluded from analysis.
+//
+//gormreuse:ignore
+func ignored(db *gorm.DB) error {
+	return db.Error
+}
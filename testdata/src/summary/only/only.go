@@ -0,0 +1,12 @@
+// Package only is the -summary example run with -summary-only: its reuse is
+// counted in the summary but not reported.
+package only
+
+import "gorm.io/gorm"
+
+// twice branches q twice.
+func twice(db *gorm.DB) {
+	q := db.Where("x")
+	q.Find(nil)
+	q.Count(nil)
+}
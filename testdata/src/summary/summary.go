@@ -0,0 +1,31 @@
+// Package summary is a small example for the -summary output: branches in two
+// functions, one of them in a closure, and an unused directive outside any
+// function.
+package summary
+
+import "gorm.io/gorm"
+
+/* want `unused gormreuse:ignore directive` */ //gormreuse:ignore
+var _ = 0
+
+// twice branches q twice in a row, then once more.
+func twice(db *gorm.DB) {
+	q := db.Where("x")
+	q.Find(nil)
+	q.Count(nil) // want `\*gorm\.DB reused: second branch from mutable root`
+	q.First(nil) // want `\*gorm\.DB reused: second branch from mutable root`
+}
+
+// inClosure branches q in a closure after branching it outside.
+func inClosure(db *gorm.DB) {
+	q := db.Where("x")
+	q.Find(nil)
+	func() {
+		q.Count(nil) // want `\*gorm\.DB reused: second branch from mutable root`
+	}()
+}
+
+// clean branches nothing.
+func clean(db *gorm.DB) {
+	db.Where("x").Find(nil)
+}
@@ -0,0 +1,59 @@
+package testhelpers
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+// =============================================================================
+// SHOULD NOT REPORT - finishers wrapped in test assertions (-no-test-helpers)
+// =============================================================================
+
+// repeatedRequireNoError finishes tx repeatedly inside require.NoError.
+func repeatedRequireNoError(tx *gorm.DB, t require.TestingT) {
+	require.NoError(t, tx.Create(nil).Error)
+	require.NoError(t, tx.Create(nil).Error) // OK: wrapped in require
+	require.NoError(t, tx.Create(nil).Error) // OK: wrapped in require
+}
+
+// repeatedAssert finishes tx inside assert functions.
+func repeatedAssert(tx *gorm.DB, t assert.TestingT) {
+	assert.NoError(t, tx.Create(nil).Error)
+	assert.Equal(t, nil, tx.Where("x").Find(nil).Error) // OK: wrapped in assert
+}
+
+// =============================================================================
+// SHOULD REPORT - bare finishers outside test assertions
+// =============================================================================
+
+// bareAfterRequire reuses tx outside an assertion.
+func bareAfterRequire(tx *gorm.DB, t require.TestingT) {
+	require.NoError(t, tx.Create(nil).Error)
+	tx.Create(nil) // want `\*gorm\.DB reused: second branch from mutable root`
+}
+
+// bareReuse reuses tx without any assertion.
+func bareReuse(tx *gorm.DB) {
+	tx.Create(nil)
+	tx.Create(nil) // want `\*gorm\.DB reused: second branch from mutable root`
+}
+
+// checkErr is a local helper, not a configured assertion package.
+func checkErr(err error) {}
+
+// localHelperReuse wraps the finisher in a function outside the configured
+// packages.
+func localHelperReuse(tx *gorm.DB) {
+	checkErr(tx.Create(nil).Error)
+	checkErr(tx.Create(nil).Error) // want `\*gorm\.DB reused: second branch from mutable root`
+}
+
+// closureInsideAssertion finishes tx in a closure passed through an assertion;
+// the closure body is not itself an assertion argument.
+func closureInsideAssertion(tx *gorm.DB, t require.TestingT) {
+	require.NoError(t, tx.Create(nil).Error)
+	require.NoError(t, func() error {
+		return tx.Create(nil).Error // want `\*gorm\.DB reused: second branch from mutable root`
+	}())
+}
@@ -0,0 +1,27 @@
+package tracevalue
+
+import "gorm.io/gorm"
+
+// tricky merges two roots through a swap in a loop, then captures the
+// result in a closure: the receiver on the traced line goes through a free
+// variable, an Alloc store and the swap Phis before reaching its roots.
+func tricky(db *gorm.DB, items []int, cond bool) {
+	q1 := db.Where("q1")
+	q2 := db.Where("q2")
+	for _, item := range items {
+		if item%2 == 0 {
+			q1, q2 = q2, q1
+		}
+		q1 = q1.Where("item = ?", item)
+	}
+	var q *gorm.DB
+	if cond {
+		q = q1
+	} else {
+		q = q2
+	}
+	func() {
+		q.Find(nil) // traced
+	}()
+	q.Count(nil) // want `\*gorm\.DB reused: second branch from mutable root`
+}
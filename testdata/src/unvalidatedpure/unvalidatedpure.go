@@ -0,0 +1,69 @@
+package unvalidatedpure
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+
+	"puregen"
+	"purelib"
+)
+
+// =============================================================================
+// SHOULD REPORT - Pure function of an imported package whose source cannot be
+// read, trusted by its fact alone
+// =============================================================================
+
+func countThenFind(db *gorm.DB) {
+	q := db.Where("x = ?", 1)
+	puregen.Count(q) // want `puregen\.Count is trusted as //gormreuse:pure without validation`
+	q.Find(nil)
+}
+
+func countTwice(db *gorm.DB) {
+	q := db.Where("x = ?", 1)
+	puregen.Count(q) // Reported once, at the first call
+	puregen.Count(q)
+}
+
+func ormDB(ctx context.Context) {
+	new(puregen.Orm).DB(ctx).Find(nil) // want `\(\*puregen\.Orm\)\.DB is trusted as //gormreuse:pure without validation`
+}
+
+// =============================================================================
+// SHOULD NOT REPORT
+// =============================================================================
+
+// localCount is validated in this package.
+//
+//gormreuse:pure
+func localCount(db *gorm.DB) int64 {
+	var n int64
+	db.Session(&gorm.Session{}).Count(&n)
+	return n
+}
+
+func localCountThenFind(db *gorm.DB) {
+	q := db.Where("x = ?", 1)
+	localCount(q)
+	q.Find(nil)
+}
+
+// Pure functions of an imported package whose source is readable: their
+// bodies are validated when their own package is analyzed.
+func libCountThenFind(db *gorm.DB) {
+	q := db.Where("x = ?", 1)
+	purelib.Count(q)
+	q.Find(nil)
+}
+
+func libOrmDB(ctx context.Context) {
+	new(purelib.Orm).DB(ctx).Find(nil)
+}
+
+// Unmarked function of an imported package: not trusted as pure at all.
+func loadThenFind(db *gorm.DB) {
+	q := db.Where("x = ?", 1)
+	purelib.Load(q, nil) // First use
+	q.Find(nil)          // want `\*gorm\.DB reused: second branch from mutable root`
+}
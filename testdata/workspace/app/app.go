@@ -0,0 +1,50 @@
+package app
+
+import (
+	"gorm.io/gorm"
+
+	"example.com/gormwrap"
+)
+
+// =============================================================================
+// SHOULD NOT REPORT - Pure functions from a sibling workspace module
+// =============================================================================
+
+// siblingDocPure uses a pure function declared with a doc comment directive.
+func siblingDocPure(db *gorm.DB) {
+	q := db.Where("x")
+	gormwrap.DocPure(q)
+	q.Find(nil) // OK: DocPure is pure
+}
+
+// siblingSameLinePure uses a pure function declared with a same-line directive.
+func siblingSameLinePure(db *gorm.DB) {
+	q := db.Where("x")
+	gormwrap.SameLinePure(q)
+	q.Find(nil) // OK: SameLinePure is pure
+}
+
+// siblingGenericPure uses an instantiation of a pure generic function.
+func siblingGenericPure(db *gorm.DB) {
+	q := db.Where("x")
+	gormwrap.GenericPure(q, 1)
+	q.Find(nil) // OK: GenericPure[int] is pure
+}
+
+// siblingPureMethod uses a pure method.
+func siblingPureMethod(db *gorm.DB) {
+	q := db.Where("x")
+	gormwrap.Repo{}.Scoped(q)
+	q.Find(nil) // OK: Scoped is pure
+}
+
+// =============================================================================
+// SHOULD REPORT - Non-pure functions from a sibling workspace module
+// =============================================================================
+
+// siblingImpure passes the root to a function without a directive.
+func siblingImpure(db *gorm.DB) {
+	q := db.Where("x")
+	gormwrap.Filter(q)
+	q.Find(nil) // want `\*gorm\.DB reused: second branch from mutable root`
+}
@@ -0,0 +1,3 @@
+module example.com/gormwrap
+
+go 1.24.0
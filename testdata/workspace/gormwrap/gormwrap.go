@@ -0,0 +1,41 @@
+// Package gormwrap is an internal gorm wrapper living in a sibling module of
+// the go.work workspace. Its directives are only visible to the analyzer by
+// locating the declarations through the loaded file set.
+package gormwrap
+
+import "gorm.io/gorm"
+
+// DocPure is marked pure with a doc comment directive.
+//
+//gormreuse:pure
+func DocPure(db *gorm.DB) *gorm.DB {
+	return db.Session(&gorm.Session{})
+}
+
+// SameLinePure is marked pure with a directive after the opening brace.
+func SameLinePure(db *gorm.DB) *gorm.DB { //gormreuse:pure
+	return db.Session(&gorm.Session{})
+}
+
+// GenericPure is a pure generic function. Its instantiations are named
+// GenericPure[T] in SSA, which does not match the declaration by name.
+//
+//gormreuse:pure
+func GenericPure[T any](db *gorm.DB, _ T) *gorm.DB {
+	return db.Session(&gorm.Session{})
+}
+
+// Repo is a repository wrapping *gorm.DB.
+type Repo struct{}
+
+// Scoped is a pure method.
+//
+//gormreuse:pure
+func (Repo) Scoped(db *gorm.DB) *gorm.DB {
+	return db.Session(&gorm.Session{})
+}
+
+// Filter has no directive, so it pollutes its argument.
+func Filter(db *gorm.DB) *gorm.DB {
+	return db.Where("x")
+}